@@ -0,0 +1,95 @@
+// Command import-products mengimpor katalog produk dari file CSV langsung ke database, tanpa lewat HTTP.
+// Berguna saat onboarding toko baru (ratusan produk sekaligus).
+//
+// Pemakaian:
+//
+//	go run ./cmd/import-products [-dry-run] [-db kasir.db] produk.csv
+//
+// Format CSV sama dengan POST /api/v1/products/import: header name, price, stock, category (nama atau ID), sku.
+// Koneksi database diambil dari flag -db, atau DB_CONN di .env / environment variable seperti server utama.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"codeWithUmam/database"
+	"codeWithUmam/repositories"
+	"codeWithUmam/services"
+
+	"github.com/spf13/viper"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "validasi & simulasi saja, tidak ada yang disimpan")
+	dbConn := flag.String("db", "", "path database SQLite (default: DB_CONN dari .env / environment)")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Pemakaian: import-products [-dry-run] [-db kasir.db] produk.csv")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if *dbConn == "" {
+		viper.AutomaticEnv()
+		if _, err := os.Stat(".env"); err == nil {
+			viper.SetConfigFile(".env")
+			if err := viper.ReadInConfig(); err != nil {
+				log.Fatal("Error membaca file config:", err)
+			}
+		}
+		*dbConn = viper.GetString("DB_CONN")
+	}
+
+	file, err := os.Open(flag.Arg(0))
+	if err != nil {
+		log.Fatal("Gagal membuka file CSV:", err)
+	}
+	defer file.Close()
+
+	db, err := database.InitDB(*dbConn)
+	if err != nil {
+		log.Fatal("Gagal menginisialisasi database:", err)
+	}
+	defer db.Close()
+
+	productService := services.NewProductService(repositories.NewProductRepository(db))
+	result, err := productService.Import(file, *dryRun)
+	if err != nil {
+		log.Fatal("Import gagal: ", err)
+	}
+
+	for _, row := range result.Rows {
+		fmt.Printf("baris %d: %s %s", row.Line, row.Action, row.Name)
+		if row.SKU != "" {
+			fmt.Printf(" (SKU %s)", row.SKU)
+		}
+		fmt.Println()
+	}
+	for _, rowErr := range result.Errors {
+		field := ""
+		if rowErr.Field != "" {
+			field = " [" + rowErr.Field + "]"
+		}
+		fmt.Fprintf(os.Stderr, "baris %d%s: %s\n", rowErr.Line, field, rowErr.Message)
+	}
+
+	fmt.Printf("\n%d baris: %d produk baru, %d diperbarui, %d kategori baru, %d error\n",
+		result.TotalRows, result.Created, result.Updated, result.CategoriesCreated, len(result.Errors))
+	switch {
+	case result.Applied:
+		fmt.Println("Import berhasil disimpan.")
+	case result.DryRun:
+		fmt.Println("Dry run: tidak ada perubahan yang disimpan.")
+	default:
+		fmt.Println("Import dibatalkan karena ada error; tidak ada perubahan yang disimpan.")
+	}
+	if len(result.Errors) > 0 {
+		os.Exit(1)
+	}
+}
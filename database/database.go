@@ -76,10 +76,23 @@ func createTables(db *sql.DB) {
 	queryTransactions := `
 	CREATE TABLE IF NOT EXISTS transactions (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		gross_amount INTEGER NOT NULL DEFAULT 0,
+		discount_amount INTEGER NOT NULL DEFAULT 0,
+		subtotal_amount INTEGER NOT NULL DEFAULT 0,
+		service_charge_amount INTEGER NOT NULL DEFAULT 0,
+		tax_amount INTEGER NOT NULL DEFAULT 0,
+		tax_inclusive BOOLEAN NOT NULL DEFAULT 0,
 		total_amount INTEGER NOT NULL,
 		paid_amount INTEGER,
 		change INTEGER,
 		payment_method TEXT,
+		status TEXT NOT NULL DEFAULT 'COMPLETED',
+		refunded_amount INTEGER NOT NULL DEFAULT 0,
+		voided_at DATETIME,
+		void_reason TEXT,
+		voided_by TEXT,
+		idempotency_key TEXT,
+		request_hash TEXT,
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
 	);`
 
@@ -90,8 +103,17 @@ func createTables(db *sql.DB) {
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
 		transaction_id INTEGER,
 		product_id INTEGER,
+		product_name TEXT,
+		category_id INTEGER,
+		category_name TEXT,
+		unit_price INTEGER,
 		quantity INTEGER NOT NULL,
+		discount_amount INTEGER NOT NULL DEFAULT 0,
 		subtotal INTEGER NOT NULL,
+		service_charge_amount INTEGER NOT NULL DEFAULT 0,
+		tax_rate REAL NOT NULL DEFAULT 0,
+		tax_amount INTEGER NOT NULL DEFAULT 0,
+		refunded_quantity INTEGER NOT NULL DEFAULT 0,
 		FOREIGN KEY(transaction_id) REFERENCES transactions(id) ON DELETE CASCADE,
 		FOREIGN KEY(product_id) REFERENCES products(id)
 	);`
@@ -103,4 +125,756 @@ func createTables(db *sql.DB) {
 	if _, err := db.Exec(queryTransactionDetails); err != nil {
 		log.Fatal("Gagal membuat tabel transaction_details:", err)
 	}
+
+	// ==========================================
+	// Void & Refund
+	// ==========================================
+
+	// Database lama dibuat sebelum kolom status/refund ada.
+	// CREATE TABLE IF NOT EXISTS tidak akan menambah kolom baru ke tabel yang sudah ada,
+	// jadi kita tambahkan manual dengan ALTER TABLE.
+	addColumnIfNotExists(db, "transactions", "status", "TEXT NOT NULL DEFAULT 'COMPLETED'")
+	addColumnIfNotExists(db, "transactions", "refunded_amount", "INTEGER NOT NULL DEFAULT 0")
+	addColumnIfNotExists(db, "transactions", "voided_at", "DATETIME")
+	addColumnIfNotExists(db, "transactions", "void_reason", "TEXT")
+	addColumnIfNotExists(db, "transactions", "voided_by", "TEXT")
+	addColumnIfNotExists(db, "transaction_details", "refunded_quantity", "INTEGER NOT NULL DEFAULT 0")
+
+	// Query untuk membuat tabel transaction_refunds
+	// Mencatat setiap pengembalian barang (refund per baris detail maupun void seluruh transaksi):
+	// siapa yang melakukan, alasannya, dan berapa uang yang dikembalikan.
+	queryTransactionRefunds := `
+	CREATE TABLE IF NOT EXISTS transaction_refunds (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		transaction_id INTEGER NOT NULL,
+		transaction_detail_id INTEGER NOT NULL,
+		product_id INTEGER,
+		quantity INTEGER NOT NULL,
+		amount INTEGER NOT NULL,
+		reason TEXT,
+		refunded_by TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY(transaction_id) REFERENCES transactions(id) ON DELETE CASCADE,
+		FOREIGN KEY(transaction_detail_id) REFERENCES transaction_details(id) ON DELETE CASCADE
+	);`
+
+	if _, err := db.Exec(queryTransactionRefunds); err != nil {
+		log.Fatal("Gagal membuat tabel transaction_refunds:", err)
+	}
+
+	// ==========================================
+	// Snapshot Produk di transaction_details
+	// ==========================================
+
+	// Nama, kategori, dan harga satuan disalin saat checkout,
+	// supaya struk lama tidak berubah ketika produk diganti nama, diubah harganya, atau dihapus.
+	addColumnIfNotExists(db, "transaction_details", "product_name", "TEXT")
+	addColumnIfNotExists(db, "transaction_details", "category_id", "INTEGER")
+	addColumnIfNotExists(db, "transaction_details", "category_name", "TEXT")
+	addColumnIfNotExists(db, "transaction_details", "unit_price", "INTEGER")
+	backfillTransactionDetailSnapshots(db)
+
+	// ==========================================
+	// Idempotent Checkout
+	// ==========================================
+
+	// idempotency_key: key unik dari header Idempotency-Key yang dikirim client (tablet kasir).
+	// request_hash: sidik jari isi CheckoutRequest, untuk menolak key yang dipakai ulang dengan isi berbeda.
+	// UNIQUE tidak bisa ditambahkan lewat ALTER TABLE, jadi kita pakai UNIQUE INDEX (NULL boleh lebih dari satu).
+	addColumnIfNotExists(db, "transactions", "idempotency_key", "TEXT")
+	addColumnIfNotExists(db, "transactions", "request_hash", "TEXT")
+	if _, err := db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_transactions_idempotency_key ON transactions(idempotency_key)"); err != nil {
+		log.Fatal("Gagal membuat index idempotency_key:", err)
+	}
+
+	// ==========================================
+	// Promotions (Diskon)
+	// ==========================================
+
+	// Query untuk membuat tabel promotions
+	// product_id/category_id bernilai 0 jika promosi tidak dibatasi produk/kategori tertentu.
+	queryPromotions := `
+	CREATE TABLE IF NOT EXISTS promotions (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		type TEXT NOT NULL,
+		value INTEGER NOT NULL DEFAULT 0,
+		product_id INTEGER NOT NULL DEFAULT 0,
+		category_id INTEGER NOT NULL DEFAULT 0,
+		buy_qty INTEGER NOT NULL DEFAULT 0,
+		get_qty INTEGER NOT NULL DEFAULT 0,
+		min_spend INTEGER NOT NULL DEFAULT 0,
+		start_at DATETIME,
+		end_at DATETIME,
+		active BOOLEAN NOT NULL DEFAULT 1
+	);`
+
+	// Query untuk membuat tabel transaction_promotions
+	// Mencatat promosi apa saja yang dipakai di setiap transaksi (nama & tipe disalin agar riwayat tidak berubah).
+	// transaction_detail_id NULL berarti promosi keranjang.
+	queryTransactionPromotions := `
+	CREATE TABLE IF NOT EXISTS transaction_promotions (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		transaction_id INTEGER NOT NULL,
+		transaction_detail_id INTEGER,
+		promotion_id INTEGER NOT NULL,
+		promotion_name TEXT NOT NULL,
+		promotion_type TEXT NOT NULL,
+		amount INTEGER NOT NULL,
+		FOREIGN KEY(transaction_id) REFERENCES transactions(id) ON DELETE CASCADE,
+		FOREIGN KEY(transaction_detail_id) REFERENCES transaction_details(id) ON DELETE CASCADE
+	);`
+
+	if _, err := db.Exec(queryPromotions); err != nil {
+		log.Fatal("Gagal membuat tabel promotions:", err)
+	}
+
+	if _, err := db.Exec(queryTransactionPromotions); err != nil {
+		log.Fatal("Gagal membuat tabel transaction_promotions:", err)
+	}
+
+	// gross_amount: total sebelum diskon, discount_amount: total potongan.
+	// total_amount tetap berarti "yang harus dibayar" (setelah diskon), jadi query omset lama tetap benar.
+	addColumnIfNotExists(db, "transactions", "gross_amount", "INTEGER NOT NULL DEFAULT 0")
+	addColumnIfNotExists(db, "transactions", "discount_amount", "INTEGER NOT NULL DEFAULT 0")
+	addColumnIfNotExists(db, "transaction_details", "discount_amount", "INTEGER NOT NULL DEFAULT 0")
+
+	// Transaksi lama belum pernah kena diskon, jadi gross = total.
+	if _, err := db.Exec("UPDATE transactions SET gross_amount = total_amount WHERE gross_amount = 0 AND discount_amount = 0"); err != nil {
+		log.Fatal("Gagal backfill gross_amount:", err)
+	}
+
+	// ==========================================
+	// Pajak (PPN) & Service Charge
+	// ==========================================
+
+	// Rincian pajak disimpan terpisah agar laporan pajak bulanan bisa diambil langsung dari data transaksi.
+	// total_amount = subtotal_amount + service_charge_amount + tax_amount
+	addColumnIfNotExists(db, "transactions", "subtotal_amount", "INTEGER NOT NULL DEFAULT 0")
+	addColumnIfNotExists(db, "transactions", "service_charge_amount", "INTEGER NOT NULL DEFAULT 0")
+	addColumnIfNotExists(db, "transactions", "tax_amount", "INTEGER NOT NULL DEFAULT 0")
+	addColumnIfNotExists(db, "transactions", "tax_inclusive", "BOOLEAN NOT NULL DEFAULT 0")
+	addColumnIfNotExists(db, "transaction_details", "service_charge_amount", "INTEGER NOT NULL DEFAULT 0")
+	addColumnIfNotExists(db, "transaction_details", "tax_rate", "REAL NOT NULL DEFAULT 0")
+	addColumnIfNotExists(db, "transaction_details", "tax_amount", "INTEGER NOT NULL DEFAULT 0")
+
+	// Transaksi lama dibuat tanpa pajak, jadi subtotal = total.
+	if _, err := db.Exec("UPDATE transactions SET subtotal_amount = total_amount WHERE subtotal_amount = 0 AND tax_amount = 0 AND service_charge_amount = 0"); err != nil {
+		log.Fatal("Gagal backfill subtotal_amount:", err)
+	}
+
+	// ==========================================
+	// Split Payment (Multi Tender)
+	// ==========================================
+
+	// Query untuk membuat tabel payments
+	// Satu transaksi bisa punya banyak tender (misal sebagian CASH, sebagian QRIS).
+	// amount: nilai yang dipakai membayar tagihan, tendered_amount: uang yang diserahkan (beda hanya untuk CASH yang ada kembalian).
+	queryPayments := `
+	CREATE TABLE IF NOT EXISTS payments (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		transaction_id INTEGER NOT NULL,
+		method TEXT NOT NULL,
+		amount INTEGER NOT NULL,
+		tendered_amount INTEGER NOT NULL,
+		reference TEXT,
+		status TEXT NOT NULL DEFAULT 'PAID',
+		provider_reference TEXT,
+		qr_string TEXT,
+		expires_at DATETIME,
+		paid_at DATETIME,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY(transaction_id) REFERENCES transactions(id) ON DELETE CASCADE
+	);`
+
+	if _, err := db.Exec(queryPayments); err != nil {
+		log.Fatal("Gagal membuat tabel payments:", err)
+	}
+
+	// Transaksi lama hanya punya satu metode pembayaran di header, salin menjadi satu baris payments
+	// supaya laporan per metode pembayaran juga mencakup data lama.
+	backfillPayments := `
+	INSERT INTO payments (transaction_id, method, amount, tendered_amount, created_at)
+	SELECT id, COALESCE(NULLIF(UPPER(payment_method), ''), 'CASH'), total_amount, COALESCE(paid_amount, total_amount), created_at
+	FROM transactions
+	WHERE id NOT IN (SELECT transaction_id FROM payments)`
+
+	if _, err := db.Exec(backfillPayments); err != nil {
+		log.Fatal("Gagal backfill payments:", err)
+	}
+
+	// ==========================================
+	// Payment Gateway (pending -> paid -> expired)
+	// ==========================================
+
+	// Pembayaran lama semuanya sudah lunas, jadi default status = PAID.
+	addColumnIfNotExists(db, "payments", "status", "TEXT NOT NULL DEFAULT 'PAID'")
+	addColumnIfNotExists(db, "payments", "provider_reference", "TEXT")
+	addColumnIfNotExists(db, "payments", "qr_string", "TEXT")
+	addColumnIfNotExists(db, "payments", "expires_at", "DATETIME")
+	addColumnIfNotExists(db, "payments", "paid_at", "DATETIME")
+	if _, err := db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_payments_provider_reference ON payments(provider_reference)"); err != nil {
+		log.Fatal("Gagal membuat index provider_reference:", err)
+	}
+
+	// ==========================================
+	// Ledger Stok
+	// ==========================================
+	// Setiap perubahan products.stock dicatat di sini (quantity bertanda: + masuk, - keluar).
+	// product_id sengaja tanpa FOREIGN KEY supaya riwayat tetap ada walaupun produknya dihapus.
+	queryStockMovements := `
+	CREATE TABLE IF NOT EXISTS stock_movements (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		product_id INTEGER NOT NULL,
+		type TEXT NOT NULL,
+		quantity INTEGER NOT NULL,
+		reference_type TEXT,
+		reference_id INTEGER,
+		note TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);`
+
+	if _, err := db.Exec(queryStockMovements); err != nil {
+		log.Fatal("Gagal membuat tabel stock_movements:", err)
+	}
+	if _, err := db.Exec("CREATE INDEX IF NOT EXISTS idx_stock_movements_product ON stock_movements(product_id, id)"); err != nil {
+		log.Fatal("Gagal membuat index stock_movements:", err)
+	}
+
+	// Backfill: produk yang sudah punya stok sebelum ledger ada dicatat sebagai saldo awal,
+	// supaya hitung ulang dari ledger langsung cocok dengan products.stock.
+	backfillStockMovements := `
+	INSERT INTO stock_movements (product_id, type, quantity, note)
+	SELECT p.id, 'ADJUSTMENT', p.stock, 'Saldo awal (migrasi ledger stok)'
+	FROM products p
+	WHERE p.stock != 0 AND NOT EXISTS (SELECT 1 FROM stock_movements sm WHERE sm.product_id = p.id)`
+	if _, err := db.Exec(backfillStockMovements); err != nil {
+		log.Fatal("Gagal backfill stock_movements:", err)
+	}
+
+	// ==========================================
+	// Supplier & Pembelian
+	// ==========================================
+	querySuppliers := `
+	CREATE TABLE IF NOT EXISTS suppliers (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		phone TEXT,
+		email TEXT,
+		address TEXT
+	);`
+
+	if _, err := db.Exec(querySuppliers); err != nil {
+		log.Fatal("Gagal membuat tabel suppliers:", err)
+	}
+
+	queryPurchaseOrders := `
+	CREATE TABLE IF NOT EXISTS purchase_orders (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		supplier_id INTEGER NOT NULL,
+		status TEXT NOT NULL DEFAULT 'OPEN',
+		note TEXT,
+		total_cost INTEGER NOT NULL DEFAULT 0,
+		received_cost INTEGER NOT NULL DEFAULT 0,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY(supplier_id) REFERENCES suppliers(id)
+	);`
+
+	if _, err := db.Exec(queryPurchaseOrders); err != nil {
+		log.Fatal("Gagal membuat tabel purchase_orders:", err)
+	}
+
+	queryPurchaseOrderItems := `
+	CREATE TABLE IF NOT EXISTS purchase_order_items (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		purchase_order_id INTEGER NOT NULL,
+		product_id INTEGER NOT NULL,
+		product_name TEXT NOT NULL DEFAULT '',
+		quantity INTEGER NOT NULL,
+		received_quantity INTEGER NOT NULL DEFAULT 0,
+		unit_cost INTEGER NOT NULL DEFAULT 0,
+		FOREIGN KEY(purchase_order_id) REFERENCES purchase_orders(id) ON DELETE CASCADE
+	);`
+
+	if _, err := db.Exec(queryPurchaseOrderItems); err != nil {
+		log.Fatal("Gagal membuat tabel purchase_order_items:", err)
+	}
+
+	// Satu PO bisa diterima beberapa kali (barang datang bertahap)
+	queryGoodsReceipts := `
+	CREATE TABLE IF NOT EXISTS goods_receipts (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		purchase_order_id INTEGER NOT NULL,
+		received_by TEXT,
+		note TEXT,
+		total_cost INTEGER NOT NULL DEFAULT 0,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY(purchase_order_id) REFERENCES purchase_orders(id) ON DELETE CASCADE
+	);`
+
+	if _, err := db.Exec(queryGoodsReceipts); err != nil {
+		log.Fatal("Gagal membuat tabel goods_receipts:", err)
+	}
+
+	queryGoodsReceiptItems := `
+	CREATE TABLE IF NOT EXISTS goods_receipt_items (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		goods_receipt_id INTEGER NOT NULL,
+		purchase_order_item_id INTEGER NOT NULL,
+		product_id INTEGER NOT NULL,
+		quantity INTEGER NOT NULL,
+		unit_cost INTEGER NOT NULL DEFAULT 0,
+		FOREIGN KEY(goods_receipt_id) REFERENCES goods_receipts(id) ON DELETE CASCADE
+	);`
+
+	if _, err := db.Exec(queryGoodsReceiptItems); err != nil {
+		log.Fatal("Gagal membuat tabel goods_receipt_items:", err)
+	}
+
+	// ==========================================
+	// Harga Pokok (HPP)
+	// ==========================================
+	// cost_price = harga pokok rata-rata tertimbang (0 = belum diketahui).
+	addColumnIfNotExists(db, "products", "cost_price", "INTEGER NOT NULL DEFAULT 0")
+	addColumnIfNotExists(db, "stock_movements", "unit_cost", "INTEGER NOT NULL DEFAULT 0")
+	// cogs = total HPP baris transaksi saat checkout (menurut metode HPP yang berlaku saat itu).
+	addColumnIfNotExists(db, "transaction_details", "cogs", "INTEGER NOT NULL DEFAULT 0")
+
+	// Lapisan harga beli untuk metode FIFO: setiap barang masuk membuat satu lapisan,
+	// barang keluar mengurangi remaining_quantity lapisan paling lama.
+	queryCostLayers := `
+	CREATE TABLE IF NOT EXISTS cost_layers (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		product_id INTEGER NOT NULL,
+		unit_cost INTEGER NOT NULL,
+		quantity INTEGER NOT NULL,
+		remaining_quantity INTEGER NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);`
+
+	if _, err := db.Exec(queryCostLayers); err != nil {
+		log.Fatal("Gagal membuat tabel cost_layers:", err)
+	}
+	if _, err := db.Exec("CREATE INDEX IF NOT EXISTS idx_cost_layers_product ON cost_layers(product_id, remaining_quantity)"); err != nil {
+		log.Fatal("Gagal membuat index cost_layers:", err)
+	}
+
+	// Backfill: stok yang sudah ada sebelum fitur HPP menjadi satu lapisan dengan harga pokok produk saat ini.
+	backfillCostLayers := `
+	INSERT INTO cost_layers (product_id, unit_cost, quantity, remaining_quantity)
+	SELECT p.id, p.cost_price, p.stock, p.stock
+	FROM products p
+	WHERE p.stock > 0 AND NOT EXISTS (SELECT 1 FROM cost_layers cl WHERE cl.product_id = p.id)`
+	if _, err := db.Exec(backfillCostLayers); err != nil {
+		log.Fatal("Gagal backfill cost_layers:", err)
+	}
+
+	// ==========================================
+	// Stok Opname (hitung fisik)
+	// ==========================================
+	queryStockOpnames := `
+	CREATE TABLE IF NOT EXISTS stock_opnames (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		status TEXT NOT NULL DEFAULT 'OPEN',
+		category_id INTEGER NOT NULL DEFAULT 0,
+		note TEXT,
+		opened_by TEXT,
+		approved_by TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		approved_at DATETIME
+	);`
+
+	if _, err := db.Exec(queryStockOpnames); err != nil {
+		log.Fatal("Gagal membuat tabel stock_opnames:", err)
+	}
+
+	// Snapshot produk saat sesi dibuka
+	queryStockOpnameItems := `
+	CREATE TABLE IF NOT EXISTS stock_opname_items (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		stock_opname_id INTEGER NOT NULL,
+		product_id INTEGER NOT NULL,
+		product_name TEXT NOT NULL DEFAULT '',
+		category_id INTEGER NOT NULL DEFAULT 0,
+		category_name TEXT NOT NULL DEFAULT '',
+		unit_price INTEGER NOT NULL DEFAULT 0,
+		snapshot_stock INTEGER NOT NULL,
+		expected_stock INTEGER NOT NULL,
+		FOREIGN KEY(stock_opname_id) REFERENCES stock_opnames(id) ON DELETE CASCADE,
+		UNIQUE(stock_opname_id, product_id)
+	);`
+
+	if _, err := db.Exec(queryStockOpnameItems); err != nil {
+		log.Fatal("Gagal membuat tabel stock_opname_items:", err)
+	}
+
+	// Hitungan per kasir; satu kasir hanya punya satu hitungan per produk (dikirim ulang = ditimpa)
+	queryStockOpnameCounts := `
+	CREATE TABLE IF NOT EXISTS stock_opname_counts (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		stock_opname_id INTEGER NOT NULL,
+		product_id INTEGER NOT NULL,
+		quantity INTEGER NOT NULL,
+		counted_by TEXT NOT NULL,
+		counted_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY(stock_opname_id) REFERENCES stock_opnames(id) ON DELETE CASCADE,
+		UNIQUE(stock_opname_id, product_id, counted_by)
+	);`
+
+	if _, err := db.Exec(queryStockOpnameCounts); err != nil {
+		log.Fatal("Gagal membuat tabel stock_opname_counts:", err)
+	}
+
+	// ==========================================
+	// Shift Kasir
+	// ==========================================
+	// z_report berisi Z-report (JSON) yang dibekukan saat shift ditutup.
+	queryShifts := `
+	CREATE TABLE IF NOT EXISTS shifts (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		cashier TEXT NOT NULL,
+		status TEXT NOT NULL DEFAULT 'OPEN',
+		opening_float INTEGER NOT NULL DEFAULT 0,
+		note TEXT,
+		opened_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		closed_at DATETIME,
+		counted_cash INTEGER,
+		z_report TEXT
+	);`
+
+	if _, err := db.Exec(queryShifts); err != nil {
+		log.Fatal("Gagal membuat tabel shifts:", err)
+	}
+
+	// Satu kasir hanya boleh punya satu shift OPEN (partial unique index)
+	if _, err := db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_shifts_open_cashier ON shifts(cashier) WHERE status = 'OPEN'"); err != nil {
+		log.Fatal("Gagal membuat index shifts:", err)
+	}
+
+	// Setiap transaksi dicatat di shift & kasir yang sedang aktif (NULL untuk transaksi lama / tanpa kasir)
+	addColumnIfNotExists(db, "transactions", "shift_id", "INTEGER")
+	addColumnIfNotExists(db, "transactions", "cashier", "TEXT")
+	if _, err := db.Exec("CREATE INDEX IF NOT EXISTS idx_transactions_shift ON transactions(shift_id)"); err != nil {
+		log.Fatal("Gagal membuat index transactions.shift_id:", err)
+	}
+
+	// ==========================================
+	// SKU Produk
+	// ==========================================
+	// SKU opsional (NULL = belum punya SKU), tapi jika diisi harus unik. Dipakai sebagai kunci upsert import CSV.
+	addColumnIfNotExists(db, "products", "sku", "TEXT")
+	if _, err := db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_products_sku ON products(sku)"); err != nil {
+		log.Fatal("Gagal membuat index products.sku:", err)
+	}
+
+	// Barcode produk: satu produk boleh punya banyak barcode, tapi satu barcode hanya milik satu produk
+	queryProductBarcodes := `
+	CREATE TABLE IF NOT EXISTS product_barcodes (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		product_id INTEGER NOT NULL,
+		barcode TEXT NOT NULL UNIQUE,
+		FOREIGN KEY(product_id) REFERENCES products(id) ON DELETE CASCADE
+	);`
+
+	if _, err := db.Exec(queryProductBarcodes); err != nil {
+		log.Fatal("Gagal membuat tabel product_barcodes:", err)
+	}
+	if _, err := db.Exec("CREATE INDEX IF NOT EXISTS idx_product_barcodes_product ON product_barcodes(product_id)"); err != nil {
+		log.Fatal("Gagal membuat index product_barcodes:", err)
+	}
+
+	// ==========================================
+	// Varian Produk
+	// ==========================================
+	// Varian (ukuran, rasa) disimpan sebagai baris products dengan parent_id = produk induknya,
+	// sehingga SKU, barcode, harga, stok, ledger & HPP otomatis berjalan per varian.
+	// variant_name berisi nama varian saja (misal "L"); products.name varian = "<nama induk> - <variant_name>".
+	addColumnIfNotExists(db, "products", "parent_id", "INTEGER REFERENCES products(id)")
+	addColumnIfNotExists(db, "products", "variant_name", "TEXT")
+	if _, err := db.Exec("CREATE INDEX IF NOT EXISTS idx_products_parent ON products(parent_id)"); err != nil {
+		log.Fatal("Gagal membuat index products.parent_id:", err)
+	}
+	if _, err := db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_products_variant_name ON products(parent_id, variant_name) WHERE parent_id IS NOT NULL"); err != nil {
+		log.Fatal("Gagal membuat index products.variant_name:", err)
+	}
+
+	// ==========================================
+	// Satuan (Unit of Measure)
+	// ==========================================
+	// products.stock selalu dalam satuan dasar (products.unit, misal "pcs").
+	// Satuan lain (misal "pak" isi 5, "karton" isi 40) disimpan di product_units dengan faktor konversi ke satuan dasar.
+	addColumnIfNotExists(db, "products", "unit", "TEXT NOT NULL DEFAULT 'pcs'")
+
+	queryProductUnits := `
+	CREATE TABLE IF NOT EXISTS product_units (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		product_id INTEGER NOT NULL,
+		name TEXT NOT NULL,
+		factor INTEGER NOT NULL,          -- jumlah satuan dasar per 1 satuan ini
+		price INTEGER NOT NULL DEFAULT 0, -- harga jual per satuan ini; 0 = factor x harga satuan dasar
+		FOREIGN KEY(product_id) REFERENCES products(id) ON DELETE CASCADE
+	);`
+
+	if _, err := db.Exec(queryProductUnits); err != nil {
+		log.Fatal("Gagal membuat tabel product_units:", err)
+	}
+	if _, err := db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_product_units_name ON product_units(product_id, name COLLATE NOCASE)"); err != nil {
+		log.Fatal("Gagal membuat index product_units:", err)
+	}
+
+	// Baris transaksi & PO mencatat satuan yang dipakai; quantity x unit_factor = jumlah dalam satuan dasar.
+	addColumnIfNotExists(db, "transaction_details", "unit", "TEXT")
+	addColumnIfNotExists(db, "transaction_details", "unit_factor", "INTEGER NOT NULL DEFAULT 1")
+	addColumnIfNotExists(db, "purchase_order_items", "unit", "TEXT")
+	addColumnIfNotExists(db, "purchase_order_items", "unit_factor", "INTEGER NOT NULL DEFAULT 1")
+
+	// ==========================================
+	// Paket (Bundle) & Resep
+	// ==========================================
+	// Produk yang punya komponen tidak punya stok sendiri: menjual 1 paket mengurangi stok setiap komponennya
+	// sebanyak quantity (satuan dasar komponen).
+	queryProductComponents := `
+	CREATE TABLE IF NOT EXISTS product_components (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		product_id INTEGER NOT NULL,   -- produk paket/resep
+		component_id INTEGER NOT NULL, -- produk isi/bahan
+		quantity INTEGER NOT NULL,     -- per 1 satuan dasar paket
+		UNIQUE(product_id, component_id),
+		FOREIGN KEY(product_id) REFERENCES products(id) ON DELETE CASCADE,
+		FOREIGN KEY(component_id) REFERENCES products(id)
+	);`
+
+	if _, err := db.Exec(queryProductComponents); err != nil {
+		log.Fatal("Gagal membuat tabel product_components:", err)
+	}
+	if _, err := db.Exec("CREATE INDEX IF NOT EXISTS idx_product_components_component ON product_components(component_id)"); err != nil {
+		log.Fatal("Gagal membuat index product_components:", err)
+	}
+
+	// Pemakaian komponen per baris transaksi (snapshot), untuk laporan pemakaian bahan dan pengembalian stok saat refund.
+	// quantity = total satuan dasar komponen yang keluar untuk seluruh baris, cogs = HPP-nya.
+	queryDetailComponents := `
+	CREATE TABLE IF NOT EXISTS transaction_detail_components (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		transaction_id INTEGER NOT NULL,
+		transaction_detail_id INTEGER NOT NULL,
+		component_id INTEGER NOT NULL,
+		component_name TEXT NOT NULL,
+		quantity INTEGER NOT NULL,
+		cogs INTEGER NOT NULL DEFAULT 0,
+		FOREIGN KEY(transaction_id) REFERENCES transactions(id) ON DELETE CASCADE,
+		FOREIGN KEY(transaction_detail_id) REFERENCES transaction_details(id) ON DELETE CASCADE
+	);`
+
+	if _, err := db.Exec(queryDetailComponents); err != nil {
+		log.Fatal("Gagal membuat tabel transaction_detail_components:", err)
+	}
+	if _, err := db.Exec("CREATE INDEX IF NOT EXISTS idx_transaction_detail_components_detail ON transaction_detail_components(transaction_detail_id)"); err != nil {
+		log.Fatal("Gagal membuat index transaction_detail_components:", err)
+	}
+
+	// ==========================================
+	// Batch & Tanggal Kedaluwarsa
+	// ==========================================
+	// Produk dengan track_batches = 1 (makanan, obat) menyimpan stoknya per batch: SUM(remaining_quantity) = products.stock.
+	// Penjualan mengambil batch yang paling cepat kedaluwarsa (FEFO) dan tidak boleh mengambil batch yang sudah kedaluwarsa.
+	addColumnIfNotExists(db, "products", "track_batches", "INTEGER NOT NULL DEFAULT 0")
+
+	queryStockBatches := `
+	CREATE TABLE IF NOT EXISTS stock_batches (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		product_id INTEGER NOT NULL,
+		batch_number TEXT,           -- Nomor lot dari supplier (opsional)
+		expiry_date TEXT,            -- YYYY-MM-DD; NULL = tanpa tanggal kedaluwarsa (dijual paling akhir)
+		quantity INTEGER NOT NULL,   -- Jumlah masuk awal (satuan dasar)
+		remaining_quantity INTEGER NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY(product_id) REFERENCES products(id) ON DELETE CASCADE
+	);`
+
+	if _, err := db.Exec(queryStockBatches); err != nil {
+		log.Fatal("Gagal membuat tabel stock_batches:", err)
+	}
+	if _, err := db.Exec("CREATE INDEX IF NOT EXISTS idx_stock_batches_product ON stock_batches(product_id, expiry_date)"); err != nil {
+		log.Fatal("Gagal membuat index stock_batches:", err)
+	}
+
+	// Setiap baris ledger produk ber-batch menunjuk batch yang berubah (satu pergerakan bisa dipecah ke beberapa batch).
+	addColumnIfNotExists(db, "stock_movements", "batch_id", "INTEGER")
+	addColumnIfNotExists(db, "goods_receipt_items", "batch_number", "TEXT")
+	addColumnIfNotExists(db, "goods_receipt_items", "expiry_date", "TEXT")
+
+	// ==========================================
+	// Stok Minimum & Pesan Ulang
+	// ==========================================
+	// min_stock: stok <= min_stock masuk daftar stok menipis (0 = tidak dipantau).
+	// reorder_qty: jumlah pesan ulang minimum yang disarankan, dalam satuan dasar.
+	addColumnIfNotExists(db, "products", "min_stock", "INTEGER NOT NULL DEFAULT 0")
+	addColumnIfNotExists(db, "products", "reorder_qty", "INTEGER NOT NULL DEFAULT 0")
+
+	// ==========================================
+	// Outlet & Stok per Outlet
+	// ==========================================
+	queryOutlets := `
+	CREATE TABLE IF NOT EXISTS outlets (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		code TEXT NOT NULL UNIQUE,
+		name TEXT NOT NULL,
+		address TEXT,
+		active INTEGER NOT NULL DEFAULT 1,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);`
+
+	if _, err := db.Exec(queryOutlets); err != nil {
+		log.Fatal("Gagal membuat tabel outlets:", err)
+	}
+	// Outlet utama (id 1) menampung semua stok & transaksi sebelum fitur multi-outlet
+	if _, err := db.Exec("INSERT OR IGNORE INTO outlets (id, code, name) VALUES (1, 'UTAMA', 'Outlet Utama')"); err != nil {
+		log.Fatal("Gagal membuat outlet utama:", err)
+	}
+
+	// Stok per outlet adalah sumber kebenaran; products.stock tinggal total semua outlet (= SUM(outlet_stock.stock)),
+	// dan keduanya selalu diubah bersama oleh ledger stok.
+	queryOutletStock := `
+	CREATE TABLE IF NOT EXISTS outlet_stock (
+		outlet_id INTEGER NOT NULL,
+		product_id INTEGER NOT NULL,
+		stock INTEGER NOT NULL DEFAULT 0,
+		PRIMARY KEY(outlet_id, product_id),
+		FOREIGN KEY(outlet_id) REFERENCES outlets(id),
+		FOREIGN KEY(product_id) REFERENCES products(id) ON DELETE CASCADE
+	);`
+
+	if _, err := db.Exec(queryOutletStock); err != nil {
+		log.Fatal("Gagal membuat tabel outlet_stock:", err)
+	}
+	if _, err := db.Exec("CREATE INDEX IF NOT EXISTS idx_outlet_stock_product ON outlet_stock(product_id)"); err != nil {
+		log.Fatal("Gagal membuat index outlet_stock:", err)
+	}
+
+	// Backfill: stok yang sudah ada dipindahkan ke outlet utama.
+	backfillOutletStock := `
+	INSERT INTO outlet_stock (outlet_id, product_id, stock)
+	SELECT 1, p.id, p.stock
+	FROM products p
+	WHERE p.stock != 0 AND NOT EXISTS (SELECT 1 FROM outlet_stock os WHERE os.product_id = p.id)`
+	if _, err := db.Exec(backfillOutletStock); err != nil {
+		log.Fatal("Gagal backfill outlet_stock:", err)
+	}
+
+	// Data lama semuanya milik outlet utama
+	addColumnIfNotExists(db, "stock_movements", "outlet_id", "INTEGER NOT NULL DEFAULT 1")
+	addColumnIfNotExists(db, "stock_batches", "outlet_id", "INTEGER NOT NULL DEFAULT 1")
+	addColumnIfNotExists(db, "transactions", "outlet_id", "INTEGER NOT NULL DEFAULT 1")
+	addColumnIfNotExists(db, "purchase_orders", "outlet_id", "INTEGER NOT NULL DEFAULT 1")
+	addColumnIfNotExists(db, "stock_opnames", "outlet_id", "INTEGER NOT NULL DEFAULT 1")
+	if _, err := db.Exec("CREATE INDEX IF NOT EXISTS idx_transactions_outlet ON transactions(outlet_id, created_at)"); err != nil {
+		log.Fatal("Gagal membuat index transactions outlet:", err)
+	}
+	if _, err := db.Exec("CREATE INDEX IF NOT EXISTS idx_stock_batches_outlet ON stock_batches(outlet_id, product_id, expiry_date)"); err != nil {
+		log.Fatal("Gagal membuat index stock_batches outlet:", err)
+	}
+
+	// ==========================================
+	// Transfer Stok antar Outlet
+	// ==========================================
+	// Stok keluar dari outlet asal saat transfer dibuat (IN_TRANSIT) dan masuk ke outlet tujuan saat diterima (RECEIVED).
+	queryStockTransfers := `
+	CREATE TABLE IF NOT EXISTS stock_transfers (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		from_outlet_id INTEGER NOT NULL,
+		to_outlet_id INTEGER NOT NULL,
+		status TEXT NOT NULL DEFAULT 'IN_TRANSIT',
+		note TEXT,
+		sent_by TEXT,
+		received_by TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		received_at DATETIME,
+		cancelled_at DATETIME,
+		FOREIGN KEY(from_outlet_id) REFERENCES outlets(id),
+		FOREIGN KEY(to_outlet_id) REFERENCES outlets(id)
+	);`
+
+	if _, err := db.Exec(queryStockTransfers); err != nil {
+		log.Fatal("Gagal membuat tabel stock_transfers:", err)
+	}
+
+	queryStockTransferItems := `
+	CREATE TABLE IF NOT EXISTS stock_transfer_items (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		stock_transfer_id INTEGER NOT NULL,
+		product_id INTEGER NOT NULL,
+		product_name TEXT NOT NULL,  -- Snapshot nama produk saat dikirim
+		quantity INTEGER NOT NULL,   -- Satuan dasar
+		unit_cost INTEGER NOT NULL DEFAULT 0,
+		FOREIGN KEY(stock_transfer_id) REFERENCES stock_transfers(id) ON DELETE CASCADE
+	);`
+
+	if _, err := db.Exec(queryStockTransferItems); err != nil {
+		log.Fatal("Gagal membuat tabel stock_transfer_items:", err)
+	}
+}
+
+// backfillTransactionDetailSnapshots mengisi kolom snapshot untuk baris transaction_details lama
+// (dibuat sebelum kolom snapshot ada).
+// - unit_price dihitung dari subtotal / quantity (satu-satunya data harga yang tersimpan saat itu).
+// - product_name dan kategori diambil dari data produk saat ini (jika produknya masih ada).
+// Query hanya menyentuh baris yang masih NULL, jadi aman dijalankan berulang kali setiap startup.
+func backfillTransactionDetailSnapshots(db *sql.DB) {
+	queries := []string{
+		`UPDATE transaction_details
+		SET unit_price = subtotal / quantity
+		WHERE unit_price IS NULL AND quantity > 0`,
+
+		`UPDATE transaction_details
+		SET product_name = (SELECT p.name FROM products p WHERE p.id = transaction_details.product_id)
+		WHERE product_name IS NULL`,
+
+		`UPDATE transaction_details
+		SET category_id = (SELECT p.category_id FROM products p WHERE p.id = transaction_details.product_id),
+			category_name = (
+				SELECT c.name FROM products p
+				JOIN categories c ON c.id = p.category_id
+				WHERE p.id = transaction_details.product_id
+			)
+		WHERE category_id IS NULL`,
+	}
+
+	for _, query := range queries {
+		if _, err := db.Exec(query); err != nil {
+			log.Fatal("Gagal backfill snapshot transaction_details:", err)
+		}
+	}
+}
+
+// addColumnIfNotExists menambahkan kolom ke tabel yang sudah ada (migration sederhana).
+// SQLite tidak punya "ADD COLUMN IF NOT EXISTS", jadi kita cek dulu lewat PRAGMA table_info.
+func addColumnIfNotExists(db *sql.DB, table, column, definition string) {
+	rows, err := db.Query("PRAGMA table_info(" + table + ")")
+	if err != nil {
+		log.Fatal("Gagal membaca struktur tabel "+table+":", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid        int
+			name       string
+			colType    string
+			notNull    int
+			defaultVal sql.NullString
+			primaryKey int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultVal, &primaryKey); err != nil {
+			log.Fatal("Gagal membaca struktur tabel "+table+":", err)
+		}
+		if name == column {
+			return // Kolom sudah ada, tidak perlu diubah
+		}
+	}
+	rows.Close()
+
+	if _, err := db.Exec("ALTER TABLE " + table + " ADD COLUMN " + column + " " + definition); err != nil {
+		log.Fatal("Gagal menambah kolom "+table+"."+column+":", err)
+	}
 }
@@ -205,6 +205,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/models.CheckoutRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key per checkout attempt; retries with the same key return the original transaction",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -223,6 +229,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -235,41 +250,30 @@ const docTemplate = `{
                 }
             }
         },
-        "/products": {
+        "/outlets": {
             "get": {
-                "description": "Get list of all products",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Get all outlets, including inactive ones",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "products"
-                ],
-                "summary": "Get all products",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Product Name Filter",
-                        "name": "name",
-                        "in": "query"
-                    }
+                    "outlets"
                 ],
+                "summary": "List outlets",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Product"
+                                "$ref": "#/definitions/models.Outlet"
                             }
                         }
                     }
                 }
             },
             "post": {
-                "description": "Create a new product",
+                "description": "Create a new outlet (store/branch); it starts active and without stock",
                 "consumes": [
                     "application/json"
                 ],
@@ -277,17 +281,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "products"
+                    "outlets"
                 ],
-                "summary": "Create a new product",
+                "summary": "Create an outlet",
                 "parameters": [
                     {
-                        "description": "Product Data",
-                        "name": "product",
+                        "description": "Outlet Data",
+                        "name": "outlet",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Product"
+                            "$ref": "#/definitions/models.Outlet"
                         }
                     }
                 ],
@@ -295,29 +299,35 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Product"
+                            "$ref": "#/definitions/models.Outlet"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
         },
-        "/products/{id}": {
+        "/outlets/{id}": {
             "get": {
-                "description": "Get a single product by its ID",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Get a single outlet by its ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "products"
+                    "outlets"
                 ],
-                "summary": "Get product by ID",
+                "summary": "Get outlet by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Product ID",
+                        "description": "Outlet ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -327,13 +337,22 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Product"
+                            "$ref": "#/definitions/models.Outlet"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             },
             "put": {
-                "description": "Update an existing product",
+                "description": "Update an outlet; set active=false to close it (not allowed for the main outlet or with transfers in transit)",
                 "consumes": [
                     "application/json"
                 ],
@@ -341,24 +360,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "products"
+                    "outlets"
                 ],
-                "summary": "Update a product",
+                "summary": "Update an outlet",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Product ID",
+                        "description": "Outlet ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Product Data",
-                        "name": "product",
+                        "description": "Outlet Data",
+                        "name": "outlet",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Product"
+                            "$ref": "#/definitions/models.Outlet"
                         }
                     }
                 ],
@@ -366,27 +385,44 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Product"
+                            "$ref": "#/definitions/models.Outlet"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete a product by ID",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/outlets/{id}/stock": {
+            "get": {
+                "description": "Get the stock of every product at an outlet, plus quantities in transit to it",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "products"
+                    "outlets"
                 ],
-                "summary": "Delete a product",
+                "summary": "Get outlet stock",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Product ID",
+                        "description": "Outlet ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -396,97 +432,192 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "boolean"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.OutletStockItem"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
         },
-        "/report/hari-ini": {
-            "get": {
-                "description": "Get sales summary for today",
+        "/payments/callback/{provider}": {
+            "post": {
+                "description": "Webhook called by the payment gateway to mark a pending payment as PAID or EXPIRED",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "transactions"
+                    "payments"
+                ],
+                "summary": "Payment Gateway Callback",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider name, e.g. qris-simulator",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signature of the raw body",
+                        "name": "X-Callback-Signature",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Callback payload",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PaymentCallback"
+                        }
+                    }
                 ],
-                "summary": "Get Daily Report",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.SalesSummary"
+                            "$ref": "#/definitions/models.Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
         },
-        "/transactions": {
-            "get": {
-                "description": "Get list of transactions with optional date filter",
+        "/payments/simulator/pay": {
+            "post": {
+                "description": "Development only: sends a signed simulator callback for a pending QRIS payment",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "transactions"
+                    "payments"
                 ],
-                "summary": "Get Transaction History",
+                "summary": "Simulate QRIS Payment",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Start Date (YYYY-MM-DD)",
-                        "name": "start_date",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "End Date (YYYY-MM-DD)",
-                        "name": "end_date",
-                        "in": "query"
+                        "description": "Reference and status (PAID or EXPIRED)",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PaymentCallback"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Transaction"
+                            "$ref": "#/definitions/models.Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     }
                 }
             }
         },
-        "/transactions/{id}": {
+        "/products": {
             "get": {
-                "description": "Get detailed transaction by ID",
-                "produces": [
+                "description": "Get list of all products with their variants nested, or export the whole catalogue (one row per variant) as CSV/XLSX",
+                "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
                 "tags": [
-                    "transactions"
+                    "products"
                 ],
-                "summary": "Get Transaction Detail",
+                "summary": "Get all products",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Transaction ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "string",
+                        "description": "Product Name Filter",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default), csv or xlsx",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Transaction"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Product"
+                            }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -495,30 +626,4232 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "models.Category": {
-            "type": "object",
-            "properties": {
-                "description": {
-                    "description": "Deskripsi singkat kategori.",
-                    "type": "string"
-                },
-                "id": {
-                    "description": "ID unik kategori.\nTag ` + "`" + `json:\"id\"` + "`" + ` berarti saat diubah jadi JSON (API response), field ini akan bernama \"id\".",
-                    "type": "integer"
-                },
-                "name": {
-                    "description": "Nama kategori.",
+            },
+            "post": {
+                "description": "Create a new product, optionally with variants (each with its own SKU, price and stock; the parent's stock must be 0)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Create a new product",
+                "parameters": [
+                    {
+                        "description": "Product Data",
+                        "name": "product",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/barcode/{code}": {
+            "get": {
+                "description": "Look up a product by a scanned EAN-8/EAN-13/UPC-A barcode (check digit validated) or by SKU",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Get product by barcode or SKU",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Barcode or SKU",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/products/import": {
+            "post": {
+                "description": "Upsert products by SKU from a CSV file (name, price, stock, category name/ID, sku) in a single DB transaction. Unknown category names are created. With dry_run=true nothing is saved and per-row validation errors are returned.",
+                "consumes": [
+                    "text/csv",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Bulk import products from CSV",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV file (multipart)",
+                        "name": "file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Validate and simulate only, without saving",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductImportResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/products/labels": {
+            "get": {
+                "description": "Lay out shelf labels (name, price, barcode) for a category and/or a selection of products on A4 PDF sheets (24 labels per page)",
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Print shelf labels",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated product IDs, e.g. 1,2,3",
+                        "name": "product_ids",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/products/low-stock": {
+            "get": {
+                "description": "List products and variants whose stock is at or below their minimum stock, most critical first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Get low-stock products",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Outlet ID (default total of all outlets)",
+                        "name": "outlet_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.LowStockItem"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}": {
+            "get": {
+                "description": "Get a single product by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Get product by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update an existing product. Variants are managed through /products/{id}/variants; a product with variants keeps stock 0. Stock is the total across outlets; the difference is recorded at outlet_id (default: main outlet), which is required when other outlets hold stock. No outlet may go below zero.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Update a product",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Product Data",
+                        "name": "product",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a product by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Delete a product",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "boolean"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/barcode": {
+            "get": {
+                "description": "Render a product barcode (EAN-13, EAN-8 or Code128) as SVG (default) or PNG. The code defaults to the product's first barcode, falling back to its SKU.",
+                "produces": [
+                    "image/svg+xml",
+                    "image/png"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Render product barcode",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "svg (default) or png",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ean13, ean8 or code128 (default: from the code)",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "One of the product's barcodes or its SKU",
+                        "name": "code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Module width in pixels (1-10, default 2)",
+                        "name": "scale",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Bar height in pixels (20-500, default 80)",
+                        "name": "height",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/variants": {
+            "post": {
+                "description": "Add a variant (size, flavour) with its own SKU, barcodes, price and stock under a parent product",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Add a product variant",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Parent product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Variant Data",
+                        "name": "variant",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductVariant"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductVariant"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/variants/{variantID}": {
+            "put": {
+                "description": "Update a variant's name, SKU, barcodes, price or stock (stock changes are recorded in the stock ledger at outlet_id, like product updates)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Update a product variant",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Parent product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Variant ID",
+                        "name": "variantID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Variant Data",
+                        "name": "variant",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductVariant"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductVariant"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a variant (and its barcodes) from a product",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Delete a product variant",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Parent product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Variant ID",
+                        "name": "variantID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "boolean"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/promotions": {
+            "get": {
+                "description": "Get list of all promotions (active and inactive)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promotions"
+                ],
+                "summary": "Get all promotions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Promotion"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new promotion (ITEM_PERCENTAGE, ITEM_NOMINAL, BUY_X_GET_Y, CART_PERCENTAGE, CART_NOMINAL)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promotions"
+                ],
+                "summary": "Create a new promotion",
+                "parameters": [
+                    {
+                        "description": "Promotion Data",
+                        "name": "promotion",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Promotion"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Promotion"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/promotions/{id}": {
+            "get": {
+                "description": "Get a single promotion by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promotions"
+                ],
+                "summary": "Get promotion by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Promotion ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Promotion"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update an existing promotion",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promotions"
+                ],
+                "summary": "Update a promotion",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Promotion ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Promotion Data",
+                        "name": "promotion",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Promotion"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Promotion"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a promotion by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promotions"
+                ],
+                "summary": "Delete a promotion",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Promotion ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "boolean"
+                        }
+                    }
+                }
+            }
+        },
+        "/purchase-orders": {
+            "get": {
+                "description": "Get purchase orders, optionally filtered by supplier and status",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "purchase-orders"
+                ],
+                "summary": "List purchase orders",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Supplier ID",
+                        "name": "supplier_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "OPEN, PARTIALLY_RECEIVED, RECEIVED or CANCELLED",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PurchaseOrder"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a purchase order with line items (quantity and unit cost per product)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "purchase-orders"
+                ],
+                "summary": "Create a purchase order",
+                "parameters": [
+                    {
+                        "description": "Purchase Order",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreatePurchaseOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PurchaseOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/purchase-orders/{id}": {
+            "get": {
+                "description": "Get a purchase order with items and goods receipts",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "purchase-orders"
+                ],
+                "summary": "Get purchase order by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Purchase Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PurchaseOrder"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/purchase-orders/{id}/cancel": {
+            "post": {
+                "description": "Cancel the outstanding quantities of a purchase order; received goods stay in stock",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "purchase-orders"
+                ],
+                "summary": "Cancel a purchase order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Purchase Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PurchaseOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/purchase-orders/{id}/receive": {
+            "post": {
+                "description": "Record a (partial) goods receipt for a purchase order; stock is incremented in the same database transaction",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "purchase-orders"
+                ],
+                "summary": "Receive goods",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Purchase Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Received items",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReceiveGoodsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PurchaseOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/report/hari-ini": {
+            "get": {
+                "description": "Get sales summary for today",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Get Daily Report",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Outlet ID (default all outlets)",
+                        "name": "outlet_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SalesSummary"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/report/laba": {
+            "get": {
+                "description": "Get revenue, cost of goods sold and gross margin per product, category and day",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Get Profit Report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "From Date (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "To Date (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Outlet ID (default all outlets)",
+                        "name": "outlet_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default), csv or xlsx",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProfitReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/bundles": {
+            "get": {
+                "description": "Get bundle/recipe sales and the component stock they consumed",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Get Bundle Report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "From Date (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "To Date (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Outlet ID (default all outlets)",
+                        "name": "outlet_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default), csv or xlsx",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BundleReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/expiring": {
+            "get": {
+                "description": "Get remaining batches that expire within N days (including already expired ones), grouped per category",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "stock"
+                ],
+                "summary": "Get Expiring Stock Report",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Days ahead (default 30, max 365)",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default), csv or xlsx",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ExpiringStockReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/reorder": {
+            "get": {
+                "description": "Suggest reorder quantities from average daily sales velocity, minimum stock and reorder quantity",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Get Reorder Report",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sales history in days (default 30, max 365)",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Days of stock to plan for (default 14, max 365)",
+                        "name": "cover_days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Outlet ID (default all outlets)",
+                        "name": "outlet_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default), csv or xlsx",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReorderReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/sales": {
+            "get": {
+                "description": "Get sales time series or breakdown, top-N best sellers, average basket size and items per transaction",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Get Sales Report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "From Date (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "To Date (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "day, week, month, hour, category, payment_method or product",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of best sellers (default 10)",
+                        "name": "top",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Outlet ID (default all outlets)",
+                        "name": "outlet_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default), csv or xlsx (rows only)",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SalesReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/shifts": {
+            "get": {
+                "description": "Get cashier shifts, optionally filtered by status and cashier",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shifts"
+                ],
+                "summary": "List shifts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OPEN or CLOSED",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cashier name",
+                        "name": "cashier",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Shift"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Open a cash drawer shift for a cashier with an opening float; checkouts with this cashier are recorded to the shift",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shifts"
+                ],
+                "summary": "Open a shift",
+                "parameters": [
+                    {
+                        "description": "Cashier and opening float",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.OpenShiftRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Shift"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/shifts/{id}": {
+            "get": {
+                "description": "Get a shift with its running X-report, or its frozen Z-report once closed",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shifts"
+                ],
+                "summary": "Get shift",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Shift ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Shift"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/shifts/{id}/close": {
+            "post": {
+                "description": "Close a shift with counted cash; produces an immutable Z-report with expected vs counted per tender, variance and void count",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shifts"
+                ],
+                "summary": "Close a shift",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Shift ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Counted cash",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CloseShiftRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Shift"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/stock-batches": {
+            "get": {
+                "description": "Get the remaining batches of a batch-tracked product in first-expiry-first-out order, flagging expired ones",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stock"
+                ],
+                "summary": "List stock batches",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "product_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.StockBatch"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/stock-movements": {
+            "get": {
+                "description": "Get the stock ledger of a product with running balance",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stock"
+                ],
+                "summary": "List stock movements",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "product_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.StockMovement"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/stock-movements/consistency": {
+            "get": {
+                "description": "Recompute stock from the ledger and list products whose stock column does not match",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stock"
+                ],
+                "summary": "Check stock consistency",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StockConsistencyReport"
+                        }
+                    }
+                }
+            }
+        },
+        "/stock-opnames": {
+            "get": {
+                "description": "Get all stock count sessions with their variance summary",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stock-opnames"
+                ],
+                "summary": "List stock opname sessions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.StockOpname"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Snapshot current stock of all products (or one category) and start counting; checkout keeps working during the count",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stock-opnames"
+                ],
+                "summary": "Open a stock opname session",
+                "parameters": [
+                    {
+                        "description": "Session info",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.OpenStockOpnameRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StockOpname"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/stock-opnames/{id}": {
+            "get": {
+                "description": "Get a stock count session with counts and variances per product and category",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stock-opnames"
+                ],
+                "summary": "Get stock opname session",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Stock Opname ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StockOpname"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/stock-opnames/{id}/approve": {
+            "post": {
+                "description": "Post variances of counted products as stock adjustment movements",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stock-opnames"
+                ],
+                "summary": "Approve stock opname",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Stock Opname ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Approver",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ApproveStockOpnameRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StockOpname"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/stock-opnames/{id}/cancel": {
+            "post": {
+                "description": "Cancel an open stock count session without touching stock",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stock-opnames"
+                ],
+                "summary": "Cancel stock opname",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Stock Opname ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StockOpname"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/stock-opnames/{id}/counts": {
+            "post": {
+                "description": "Submit a cashier's counted quantities; counts from different cashiers for the same product are summed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stock-opnames"
+                ],
+                "summary": "Submit counted quantities",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Stock Opname ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Counted quantities",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SubmitStockCountRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StockOpname"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/stock-transfers": {
+            "get": {
+                "description": "Get stock transfers, optionally filtered by outlet (as source or destination) and status",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stock-transfers"
+                ],
+                "summary": "List stock transfers",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Outlet ID",
+                        "name": "outlet_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IN_TRANSIT, RECEIVED or CANCELLED",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.StockTransfer"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Send stock from one outlet to another; stock leaves the source outlet immediately and stays in transit until received",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stock-transfers"
+                ],
+                "summary": "Create a stock transfer",
+                "parameters": [
+                    {
+                        "description": "Stock Transfer",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateStockTransferRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StockTransfer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/stock-transfers/{id}": {
+            "get": {
+                "description": "Get a stock transfer with its items",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stock-transfers"
+                ],
+                "summary": "Get stock transfer by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Stock Transfer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StockTransfer"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/stock-transfers/{id}/cancel": {
+            "post": {
+                "description": "Cancel an in-transit transfer; stock returns to the source outlet",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stock-transfers"
+                ],
+                "summary": "Cancel a stock transfer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Stock Transfer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StockTransfer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/stock-transfers/{id}/receive": {
+            "post": {
+                "description": "Mark an in-transit transfer as received; stock is added to the destination outlet",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stock-transfers"
+                ],
+                "summary": "Receive a stock transfer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Stock Transfer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Receiver",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReceiveStockTransferRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StockTransfer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/suppliers": {
+            "get": {
+                "description": "Get list of all suppliers",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "suppliers"
+                ],
+                "summary": "Get all suppliers",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Supplier"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new supplier",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "suppliers"
+                ],
+                "summary": "Create a new supplier",
+                "parameters": [
+                    {
+                        "description": "Supplier Data",
+                        "name": "supplier",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Supplier"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Supplier"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/suppliers/{id}": {
+            "get": {
+                "description": "Get a single supplier by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "suppliers"
+                ],
+                "summary": "Get supplier by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Supplier ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Supplier"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update an existing supplier",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "suppliers"
+                ],
+                "summary": "Update a supplier",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Supplier ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Supplier Data",
+                        "name": "supplier",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Supplier"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Supplier"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a supplier by ID (suppliers with purchase orders cannot be deleted)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "suppliers"
+                ],
+                "summary": "Delete a supplier",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Supplier ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "boolean"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions": {
+            "get": {
+                "description": "Get list of transactions with optional date filter, or export them as CSV/XLSX",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Get Transaction History",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start Date (YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End Date (YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Outlet ID (default all outlets)",
+                        "name": "outlet_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default), csv or xlsx",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Transaction"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/{id}": {
+            "get": {
+                "description": "Get detailed transaction by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Get Transaction Detail",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transaction"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/{id}/receipt": {
+            "get": {
+                "description": "Render a transaction receipt with the configured store header/footer as plain text, raw ESC/POS bytes for thermal printers, or PDF",
+                "produces": [
+                    "text/plain",
+                    "application/octet-stream",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Print Receipt",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "text (default), escpos or pdf",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Paper width in mm: 58 or 80 (default from RECEIPT_PAPER_WIDTH)",
+                        "name": "width",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/{id}/refund": {
+            "post": {
+                "description": "Refund some items of a transaction per detail line and restore their stock",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Refund Transaction Items",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Refund Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RefundRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/{id}/void": {
+            "post": {
+                "description": "Cancel a whole transaction, restore stock and exclude it from revenue",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Void Transaction",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Void Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.VoidRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.AppliedPromotion": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "promotion_id": {
+                    "type": "integer"
+                },
+                "promotion_name": {
+                    "type": "string"
+                },
+                "promotion_type": {
+                    "type": "string"
+                },
+                "transaction_detail_id": {
+                    "type": "integer"
+                },
+                "transaction_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ApproveStockOpnameRequest": {
+            "type": "object",
+            "properties": {
+                "approved_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BundleReport": {
+            "type": "object",
+            "properties": {
+                "bundles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProfitLine"
+                    }
+                },
+                "components": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ComponentUsageLine"
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "outlet_id": {
+                    "description": "0 = semua outlet",
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Category": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "description": "Deskripsi singkat kategori.",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID unik kategori.\nTag ` + "`" + `json:\"id\"` + "`" + ` berarti saat diubah jadi JSON (API response), field ini akan bernama \"id\".",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Nama kategori.",
+                    "type": "string"
+                }
+            }
+        },
+        "models.CheckoutItem": {
+            "type": "object",
+            "properties": {
+                "barcode": {
+                    "description": "Hasil scan (barcode atau SKU), dipakai jika product_id kosong",
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "unit": {
+                    "description": "Satuan jual (misal \"pak\"); kosong = satuan dasar produk",
+                    "type": "string"
+                },
+                "variant_id": {
+                    "description": "Wajib untuk produk yang punya varian (product_id boleh diisi ID induknya)",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CheckoutRequest": {
+            "type": "object",
+            "properties": {
+                "cashier": {
+                    "description": "Cashier adalah kasir yang melayani. Jika diisi, kasir ini wajib punya shift OPEN\ndan transaksinya dicatat ke shift tersebut (masuk Z-report).",
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CheckoutItem"
+                    }
+                },
+                "outlet_id": {
+                    "description": "OutletID adalah outlet tempat transaksi terjadi; stok outlet ini yang dicek \u0026 dikurangi (0 = outlet utama).",
+                    "type": "integer"
+                },
+                "paid_amount": {
+                    "description": "Cara bayar lama (satu metode saja). Tetap didukung jika Payments kosong.",
+                    "type": "integer"
+                },
+                "payment_method": {
+                    "description": "\"CASH\", \"QRIS\"",
+                    "type": "string"
+                },
+                "payments": {
+                    "description": "Payments dipakai untuk split payment, misal sebagian CASH dan sebagian QRIS.\nJika diisi, PaidAmount dan PaymentMethod diabaikan.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PaymentInput"
+                    }
+                }
+            }
+        },
+        "models.CloseShiftRequest": {
+            "type": "object",
+            "properties": {
+                "counted": {
+                    "description": "Counted adalah hasil hitung tender non-tunai (opsional), misal total slip EDC per metode.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TenderCount"
+                    }
+                },
+                "counted_cash": {
+                    "description": "CountedCash adalah uang tunai hasil hitung di laci (termasuk modal awal).",
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ComponentUsageLine": {
+            "type": "object",
+            "properties": {
+                "cogs": {
+                    "type": "integer"
+                },
+                "component_id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "description": "Dalam satuan dasar komponen",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CreatePurchaseOrderRequest": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PurchaseOrderItemRequest"
+                    }
+                },
+                "note": {
+                    "type": "string"
+                },
+                "outlet_id": {
+                    "description": "Opsional: default outlet utama",
+                    "type": "integer"
+                },
+                "supplier_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CreateStockTransferRequest": {
+            "type": "object",
+            "properties": {
+                "from_outlet_id": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StockTransferItemRequest"
+                    }
+                },
+                "note": {
+                    "type": "string"
+                },
+                "sent_by": {
+                    "type": "string"
+                },
+                "to_outlet_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ExpiringBatch": {
+            "type": "object",
+            "properties": {
+                "batch_id": {
+                    "type": "integer"
+                },
+                "batch_number": {
+                    "type": "string"
+                },
+                "days_left": {
+                    "description": "Negatif = sudah kedaluwarsa sekian hari",
+                    "type": "integer"
+                },
+                "expiry_date": {
+                    "type": "string"
+                },
+                "outlet_id": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ExpiringStockCategory": {
+            "type": "object",
+            "properties": {
+                "batches": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ExpiringBatch"
+                    }
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "category_name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ExpiringStockReport": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ExpiringStockCategory"
+                    }
+                },
+                "days": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "today": {
+                    "type": "string"
+                },
+                "until": {
+                    "type": "string"
+                },
+                "value": {
+                    "description": "Nilai persediaan (harga pokok rata-rata saat ini)",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.GoodsReceipt": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.GoodsReceiptItem"
+                    }
+                },
+                "note": {
+                    "type": "string"
+                },
+                "purchase_order_id": {
+                    "type": "integer"
+                },
+                "received_by": {
+                    "type": "string"
+                },
+                "total_cost": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.GoodsReceiptItem": {
+            "type": "object",
+            "properties": {
+                "batch_number": {
+                    "type": "string"
+                },
+                "expiry_date": {
+                    "type": "string"
+                },
+                "goods_receipt_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "purchase_order_item_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "unit_cost": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.LowStockItem": {
+            "type": "object",
+            "properties": {
+                "category_name": {
+                    "type": "string"
+                },
+                "min_stock": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "reorder_qty": {
+                    "type": "integer"
+                },
+                "shortage": {
+                    "description": "MinStock - Stock (0 jika tepat di batas)",
+                    "type": "integer"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "stock": {
+                    "type": "integer"
+                },
+                "unit": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.OpenShiftRequest": {
+            "type": "object",
+            "properties": {
+                "cashier": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "opening_float": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.OpenStockOpnameRequest": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "description": "Opsional: hanya hitung satu kategori",
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "opened_by": {
+                    "type": "string"
+                },
+                "outlet_id": {
+                    "description": "Opsional: default outlet utama",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Outlet": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "description": "Active: outlet nonaktif tidak bisa dipakai checkout atau menjadi tujuan transfer stok.\nOutlet tidak pernah dihapus supaya riwayat transaksi \u0026 stoknya tetap utuh.",
+                    "type": "boolean"
+                },
+                "address": {
+                    "type": "string"
+                },
+                "code": {
+                    "description": "Kode singkat yang unik, misal \"JKT1\"",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.OutletStockItem": {
+            "type": "object",
+            "properties": {
+                "in_transit": {
+                    "description": "InTransit adalah jumlah yang sedang dikirim ke outlet ini (transfer yang belum diterima).",
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "stock": {
+                    "type": "integer"
+                },
+                "unit": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Payment": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Amount adalah nilai yang dipakai untuk membayar tagihan (untuk tunai: sudah dikurangi kembalian).",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "method": {
+                    "description": "\"CASH\", \"QRIS\", \"DEBIT\", dst",
+                    "type": "string"
+                },
+                "paid_at": {
+                    "type": "string"
+                },
+                "provider_reference": {
+                    "description": "ID tagihan di payment gateway",
+                    "type": "string"
+                },
+                "qr_string": {
+                    "description": "Isi QR yang ditampilkan ke pelanggan",
+                    "type": "string"
+                },
+                "reference": {
+                    "description": "Nomor referensi EDC/QRIS (opsional)",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Field di bawah ini hanya terisi untuk pembayaran lewat payment gateway.",
+                    "type": "string"
+                },
+                "tendered_amount": {
+                    "description": "TenderedAmount adalah uang yang diserahkan pelanggan. Untuk non-tunai selalu sama dengan Amount.",
+                    "type": "integer"
+                },
+                "transaction_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PaymentCallback": {
+            "type": "object",
+            "properties": {
+                "reference": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "PAID atau EXPIRED",
+                    "type": "string"
+                }
+            }
+        },
+        "models.PaymentInput": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "reference": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Product": {
+            "type": "object",
+            "properties": {
+                "barcodes": {
+                    "description": "Barcode EAN-8/EAN-13/UPC-A (satu produk boleh punya beberapa, misal kemasan lama \u0026 baru).\nUPC-A disimpan sebagai EAN-13 berawalan 0. Saat update, nil = barcode tidak diubah, [] = hapus semua.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "category": {
+                    "description": "Category adalah relasi (join).\nPointer (*) berarti field ini bisa bernilai nil (kosong) jika tidak ada datanya.\n` + "`" + `omitempty` + "`" + `: Field ini tidak akan muncul di JSON jika nil.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Category"
+                        }
+                    ]
+                },
+                "category_id": {
+                    "description": "Foreign Key: ID dari kategori produk ini.",
+                    "type": "integer"
+                },
+                "components": {
+                    "description": "Components berisi isi paket (bundle) atau bahan resep. Produk yang punya komponen tidak punya stok sendiri:\nsetiap penjualan mengurangi stok komponennya. Saat update, nil = tidak diubah, [] = bukan paket lagi.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProductComponent"
+                    }
+                },
+                "cost_price": {
+                    "description": "Harga pokok (HPP) rata-rata per unit. Dihitung ulang otomatis setiap ada penerimaan barang.",
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "ID unik produk.\nTag ` + "`" + `json:\"id\"` + "`" + ` berarti saat diubah jadi JSON (API response), field ini akan bernama \"id\".",
+                    "type": "integer"
+                },
+                "min_stock": {
+                    "description": "MinStock adalah batas stok minimum: stok \u003c= MinStock masuk daftar stok menipis (0 = tidak dipantau).\nReorderQty adalah jumlah pesan ulang yang biasa dipakai (misal 1 karton), dalam satuan dasar.\nProduk yang punya varian dipantau per varian, jadi batas di induknya diabaikan.\nSaat update, nil = tidak diubah.",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Nama produk.",
+                    "type": "string"
+                },
+                "outlet_id": {
+                    "description": "OutletID adalah outlet tempat selisih stok dicatat saat produk dibuat/diubah (0 = outlet utama).\nWajib diisi untuk mengubah stok produk yang stoknya juga ada di outlet lain.",
+                    "type": "integer"
+                },
+                "parent_id": {
+                    "description": "ParentID \u0026 VariantName hanya terisi jika baris ini adalah varian (misal \"Es Teh - L\" dengan VariantName \"L\").",
+                    "type": "integer"
+                },
+                "price": {
+                    "description": "Harga produk dalam integer (Rupiah tidak punya desimal penting).",
+                    "type": "integer"
+                },
+                "reorder_qty": {
+                    "type": "integer"
+                },
+                "sku": {
+                    "description": "SKU (kode barang) opsional. Jika diisi harus unik; dipakai sebagai kunci saat import CSV.",
+                    "type": "string"
+                },
+                "stock": {
+                    "description": "Jumlah stok tersedia, dalam satuan dasar (Unit). Ini total semua outlet; stok per outlet ada di tabel outlet_stock.",
+                    "type": "integer"
+                },
+                "track_batches": {
+                    "description": "TrackBatches: stok dicatat per batch beserta tanggal kedaluwarsanya (makanan, obat).\nCheckout mengambil batch yang paling cepat kedaluwarsa (FEFO) dan menolak batch yang sudah kedaluwarsa.\nSaat update, nil = tidak diubah (mematikannya menghapus semua batch produk ini).",
+                    "type": "boolean"
+                },
+                "unit": {
+                    "description": "Unit adalah satuan dasar produk (default \"pcs\"). Harga \u0026 stok produk selalu dalam satuan ini.\nSaat update, kosong = satuan dasar tidak diubah.",
+                    "type": "string"
+                },
+                "units": {
+                    "description": "Units adalah satuan jual/beli lain (misal \"pak\" isi 5, \"karton\" isi 40).\nSaat update, nil = satuan tidak diubah, [] = hapus semua.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProductUnit"
+                    }
+                },
+                "variant_name": {
+                    "type": "string"
+                },
+                "variants": {
+                    "description": "Variants berisi varian produk (ukuran, rasa). Produk yang punya varian dijual \u0026 distok per varian,\njadi stok produk induknya selalu 0.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProductVariant"
+                    }
+                }
+            }
+        },
+        "models.ProductComponent": {
+            "type": "object",
+            "properties": {
+                "component_id": {
+                    "type": "integer"
+                },
+                "component_name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "description": "Dalam satuan dasar komponen, per 1 satuan dasar paket",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ProductImportError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "line": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ProductImportResult": {
+            "type": "object",
+            "properties": {
+                "applied": {
+                    "description": "true jika perubahan benar-benar disimpan",
+                    "type": "boolean"
+                },
+                "categories_created": {
+                    "type": "integer"
+                },
+                "created": {
+                    "type": "integer"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProductImportError"
+                    }
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProductImportRowResult"
+                    }
+                },
+                "total_rows": {
+                    "type": "integer"
+                },
+                "updated": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ProductImportRowResult": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "CREATE / UPDATE",
+                    "type": "string"
+                },
+                "line": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "description": "Kosong untuk produk baru saat dry run",
+                    "type": "integer"
+                },
+                "sku": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ProductSales": {
+            "type": "object",
+            "properties": {
+                "nama": {
+                    "type": "string"
+                },
+                "qty_terjual": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ProductUnit": {
+            "type": "object",
+            "properties": {
+                "factor": {
+                    "description": "Jumlah satuan dasar per 1 satuan ini, misal 40 pcs per karton",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Misal \"pak\" atau \"karton\"",
+                    "type": "string"
+                },
+                "price": {
+                    "description": "Harga jual per satuan ini; 0 = Factor x harga satuan dasar",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ProductVariant": {
+            "type": "object",
+            "properties": {
+                "barcodes": {
+                    "description": "Saat update, nil = tidak diubah, [] = hapus semua",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "cost_price": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "min_stock": {
+                    "description": "Batas stok minimum \u0026 jumlah pesan ulang varian ini (lihat Product.MinStock; saat update, nil = tidak diubah).",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Nama varian saja, misal \"L\" atau \"Pedas\"",
+                    "type": "string"
+                },
+                "outlet_id": {
+                    "description": "Outlet tempat selisih stok dicatat (lihat Product.OutletID)",
+                    "type": "integer"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "reorder_qty": {
+                    "type": "integer"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "stock": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ProfitLine": {
+            "type": "object",
+            "properties": {
+                "cogs": {
+                    "type": "integer"
+                },
+                "gross_profit": {
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "ID produk/kategori (kosong untuk baris per hari)",
+                    "type": "integer"
+                },
+                "margin_percent": {
+                    "type": "number"
+                },
+                "name": {
+                    "description": "Nama produk/kategori, atau tanggal YYYY-MM-DD",
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "revenue": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ProfitReport": {
+            "type": "object",
+            "properties": {
+                "by_category": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProfitLine"
+                    }
+                },
+                "by_day": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProfitLine"
+                    }
+                },
+                "by_product": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProfitLine"
+                    }
+                },
+                "cogs": {
+                    "type": "integer"
+                },
+                "costing_method": {
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "gross_profit": {
+                    "type": "integer"
+                },
+                "margin_percent": {
+                    "description": "GrossProfit / Revenue x 100",
+                    "type": "number"
+                },
+                "outlet_id": {
+                    "description": "0 = semua outlet",
+                    "type": "integer"
+                },
+                "revenue": {
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Promotion": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "buy_qty": {
+                    "description": "Khusus BUY_X_GET_Y: beli BuyQty, gratis GetQty (dalam satuan dasar produk).",
+                    "type": "integer"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "end_at": {
+                    "type": "string"
+                },
+                "get_qty": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "min_spend": {
+                    "description": "Khusus promosi keranjang (CART_*): minimal belanja setelah diskon item.",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "description": "Target promosi item. Jika keduanya 0, promosi berlaku untuk semua produk.\nIsi CategoryID untuk diskon satu kategori penuh.",
+                    "type": "integer"
+                },
+                "start_at": {
+                    "description": "Masa berlaku promosi (opsional). nil berarti tidak dibatasi.",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Salah satu dari konstanta PromotionType...",
+                    "type": "string"
+                },
+                "value": {
+                    "description": "Value adalah besar diskon: persen (1-100) untuk tipe *_PERCENTAGE, atau rupiah untuk tipe *_NOMINAL.\nTidak dipakai untuk BUY_X_GET_Y.",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PurchaseOrder": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PurchaseOrderItem"
+                    }
+                },
+                "note": {
+                    "type": "string"
+                },
+                "outlet_id": {
+                    "description": "Outlet tujuan barang diterima",
+                    "type": "integer"
+                },
+                "receipts": {
+                    "description": "Riwayat penerimaan barang",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.GoodsReceipt"
+                    }
+                },
+                "received_cost": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "supplier_id": {
+                    "type": "integer"
+                },
+                "supplier_name": {
+                    "type": "string"
+                },
+                "total_cost": {
+                    "description": "TotalCost adalah nilai pesanan (quantity x unit_cost dari PO),\nReceivedCost adalah nilai barang yang benar-benar diterima (pakai harga beli saat penerimaan).",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PurchaseOrderItem": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "description": "Snapshot nama produk saat PO dibuat",
+                    "type": "string"
+                },
+                "purchase_order_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "received_quantity": {
+                    "type": "integer"
+                },
+                "unit": {
+                    "description": "Unit adalah satuan beli (misal \"karton\"); Quantity, ReceivedQuantity \u0026 UnitCost dalam satuan ini.\nStok bertambah Quantity x UnitFactor satuan dasar saat barang diterima.",
+                    "type": "string"
+                },
+                "unit_cost": {
+                    "description": "Harga beli per unit yang disepakati di PO",
+                    "type": "integer"
+                },
+                "unit_factor": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PurchaseOrderItemRequest": {
+            "type": "object",
+            "properties": {
+                "product_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "unit": {
+                    "description": "Satuan beli (misal \"karton\"); kosong = satuan dasar produk",
+                    "type": "string"
+                },
+                "unit_cost": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ReceiveGoodsItemRequest": {
+            "type": "object",
+            "properties": {
+                "batch_number": {
+                    "type": "string"
+                },
+                "expiry_date": {
+                    "type": "string"
+                },
+                "purchase_order_item_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "unit_cost": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ReceiveGoodsRequest": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReceiveGoodsItemRequest"
+                    }
+                },
+                "note": {
+                    "type": "string"
+                },
+                "received_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReceiveStockTransferRequest": {
+            "type": "object",
+            "properties": {
+                "received_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Refund": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Uang yang dikembalikan ke pelanggan",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "refunded_by": {
+                    "type": "string"
+                },
+                "transaction_detail_id": {
+                    "type": "integer"
+                },
+                "transaction_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.RefundItem": {
+            "type": "object",
+            "properties": {
+                "detail_id": {
+                    "description": "ID dari TransactionDetail",
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.RefundRequest": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RefundItem"
+                    }
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "refunded_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReorderReport": {
+            "type": "object",
+            "properties": {
+                "cover_days": {
+                    "type": "integer"
+                },
+                "days": {
+                    "type": "integer"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReorderSuggestion"
+                    }
+                },
+                "outlet_id": {
+                    "description": "0 = semua outlet",
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReorderSuggestion": {
+            "type": "object",
+            "properties": {
+                "category_name": {
+                    "type": "string"
+                },
+                "daily_sales": {
+                    "description": "Sold / Days",
+                    "type": "number"
+                },
+                "days_of_cover": {
+                    "description": "DaysOfCover adalah perkiraan berapa hari stok sekarang akan habis; null jika tidak ada penjualan.",
+                    "type": "number"
+                },
+                "min_stock": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "reorder_qty": {
+                    "type": "integer"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "sold": {
+                    "description": "Terjual bersih (setelah refund) selama periode, termasuk sebagai isi paket",
+                    "type": "integer"
+                },
+                "stock": {
+                    "type": "integer"
+                },
+                "suggested_qty": {
+                    "type": "integer"
+                },
+                "unit": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SalesReport": {
+            "type": "object",
+            "properties": {
+                "average_basket": {
+                    "description": "AverageBasket = TotalRevenue / TotalTransactions (rata-rata belanja per transaksi).",
+                    "type": "number"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "group_by": {
+                    "type": "string"
+                },
+                "items_per_transaction": {
+                    "description": "ItemsPerTransaction = TotalItems / TotalTransactions.",
+                    "type": "number"
+                },
+                "outlet_id": {
+                    "description": "0 = semua outlet",
+                    "type": "integer"
+                },
+                "rows": {
+                    "description": "Rows adalah rincian sesuai GroupBy: deret waktu untuk hour/day/week/month, atau breakdown untuk yang lain.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SalesReportRow"
+                    }
+                },
+                "to": {
+                    "type": "string"
+                },
+                "top_products": {
+                    "description": "TopProducts adalah N produk terlaris (berdasarkan qty) di rentang tanggal ini.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TopProduct"
+                    }
+                },
+                "total_items": {
+                    "description": "Jumlah barang terjual (setelah refund)",
+                    "type": "integer"
+                },
+                "total_revenue": {
+                    "type": "integer"
+                },
+                "total_transactions": {
+                    "description": "Transaksi yang di-void tidak dihitung",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SalesReportRow": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "ID kategori/produk (hanya untuk group_by category/product)",
+                    "type": "integer"
+                },
+                "items": {
+                    "description": "Jumlah barang (0 untuk payment_method)",
+                    "type": "integer"
+                },
+                "key": {
+                    "description": "Periode (misal \"2026-01-31\"), nama kategori/produk, atau metode pembayaran",
+                    "type": "string"
+                },
+                "revenue": {
+                    "type": "integer"
+                },
+                "transactions": {
+                    "description": "Jumlah transaksi yang mengandung baris ini",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SalesSummary": {
+            "type": "object",
+            "properties": {
+                "gross_revenue": {
+                    "description": "Omset kotor (harga normal sebelum diskon)",
+                    "type": "integer"
+                },
+                "net_sales": {
+                    "description": "DPP: penjualan setelah diskon tanpa pajak \u0026 service charge",
+                    "type": "integer"
+                },
+                "payment_breakdown": {
+                    "description": "Rincian uang masuk per metode pembayaran (CASH, QRIS, dst).",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TenderSummary"
+                    }
+                },
+                "produk_terlaris": {
+                    "$ref": "#/definitions/models.ProductSales"
+                },
+                "total_discount": {
+                    "description": "Total potongan promosi",
+                    "type": "integer"
+                },
+                "total_revenue": {
+                    "description": "Uang yang diterima (setelah diskon \u0026 refund, termasuk pajak \u0026 service charge)",
+                    "type": "integer"
+                },
+                "total_service_charge": {
+                    "description": "Total service charge",
+                    "type": "integer"
+                },
+                "total_tax": {
+                    "description": "Total pajak yang dipungut",
+                    "type": "integer"
+                },
+                "total_transaksi": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Shift": {
+            "type": "object",
+            "properties": {
+                "cashier": {
+                    "type": "string"
+                },
+                "closed_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "opened_at": {
+                    "type": "string"
+                },
+                "opening_float": {
+                    "description": "Modal awal (uang kembalian) di laci saat shift dibuka",
+                    "type": "integer"
+                },
+                "report": {
+                    "description": "Report adalah X-report berjalan selama shift OPEN, dan Z-report yang sudah dibekukan setelah CLOSED.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ShiftReport"
+                        }
+                    ]
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ShiftReport": {
+            "type": "object",
+            "properties": {
+                "cash_variance": {
+                    "description": "Counted - Expected (+ lebih, - kurang)",
+                    "type": "integer"
+                },
+                "cashier": {
+                    "type": "string"
+                },
+                "closed_at": {
+                    "type": "string"
+                },
+                "counted_cash": {
+                    "type": "integer"
+                },
+                "expected_cash": {
+                    "description": "Ringkasan laci kas (tender CASH): modal awal + penjualan tunai - refund.",
+                    "type": "integer"
+                },
+                "final": {
+                    "description": "true = Z-report (shift sudah tutup)",
+                    "type": "boolean"
+                },
+                "opened_at": {
+                    "type": "string"
+                },
+                "opening_float": {
+                    "type": "integer"
+                },
+                "refund_amount": {
+                    "description": "Dianggap dibayar tunai dari laci",
+                    "type": "integer"
+                },
+                "refund_count": {
+                    "description": "Transaksi yang sebagian/seluruh barangnya di-refund",
+                    "type": "integer"
+                },
+                "shift_id": {
+                    "type": "integer"
+                },
+                "tenders": {
+                    "description": "Tenders adalah uang yang seharusnya ada per metode pembayaran, dibandingkan dengan hasil hitung saat tutup.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ShiftTender"
+                    }
+                },
+                "total_sales": {
+                    "description": "Omset bersih (setelah refund)",
+                    "type": "integer"
+                },
+                "total_transactions": {
+                    "description": "Tanpa transaksi yang di-void",
+                    "type": "integer"
+                },
+                "void_amount": {
+                    "type": "integer"
+                },
+                "void_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ShiftTender": {
+            "type": "object",
+            "properties": {
+                "counted": {
+                    "type": "integer"
+                },
+                "expected": {
+                    "description": "Untuk CASH: ditambah modal awal dan dikurangi refund",
+                    "type": "integer"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "sales": {
+                    "description": "Uang masuk dari penjualan dengan metode ini (tunai sudah dikurangi kembalian)",
+                    "type": "integer"
+                },
+                "transactions": {
+                    "type": "integer"
+                },
+                "variance": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.StockBatch": {
+            "type": "object",
+            "properties": {
+                "batch_number": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expired": {
+                    "type": "boolean"
+                },
+                "expiry_date": {
+                    "description": "Kosong = tanpa tanggal kedaluwarsa",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "outlet_id": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "description": "Jumlah masuk awal",
+                    "type": "integer"
+                },
+                "remaining_quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.StockConsistencyReport": {
+            "type": "object",
+            "properties": {
+                "checked_products": {
+                    "type": "integer"
+                },
+                "consistent": {
+                    "type": "boolean"
+                },
+                "discrepancies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StockDiscrepancy"
+                    }
+                }
+            }
+        },
+        "models.StockCountItem": {
+            "type": "object",
+            "properties": {
+                "product_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.StockDiscrepancy": {
+            "type": "object",
+            "properties": {
+                "batch_stock": {
+                    "description": "BatchStock adalah total sisa batch, hanya diisi jika produk ber-batch dan totalnya berbeda dengan Stock.",
+                    "type": "integer"
+                },
+                "difference": {
+                    "description": "Stock - LedgerStock",
+                    "type": "integer"
+                },
+                "ledger_stock": {
+                    "description": "SUM(quantity) dari stock_movements",
+                    "type": "integer"
+                },
+                "outlet_stock": {
+                    "description": "OutletStock adalah total stok semua outlet, hanya diisi jika berbeda dengan Stock.",
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "stock": {
+                    "description": "Nilai di kolom products.stock",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.StockMovement": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "description": "Balance adalah saldo berjalan (running balance) setelah pergerakan ini, dihitung dari ledger.",
+                    "type": "integer"
+                },
+                "batch_id": {
+                    "description": "Batch yang berubah (hanya produk ber-batch). Untuk barang masuk, BatchNumber \u0026 ExpiryDate membuat batch baru,\natau BatchID menambah batch yang sudah ada.",
+                    "type": "integer"
+                },
+                "batch_number": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expiry_date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "outlet_id": {
+                    "description": "OutletID adalah outlet yang stoknya berubah (0 saat mencatat = outlet utama).",
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "description": "Quantity bertanda: positif = stok masuk, negatif = stok keluar.",
+                    "type": "integer"
+                },
+                "reference_id": {
+                    "type": "integer"
+                },
+                "reference_type": {
+                    "description": "ReferenceType \u0026 ReferenceID menunjuk dokumen sumbernya, misal \"transaction\" #12.",
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "unit_cost": {
+                    "description": "UnitCost adalah harga pokok per unit: harga beli untuk barang masuk, harga rata-rata untuk barang keluar.",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.StockOpname": {
+            "type": "object",
+            "properties": {
+                "approved_at": {
+                    "type": "string"
+                },
+                "approved_by": {
+                    "type": "string"
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StockOpnameCategoryVariance"
+                    }
+                },
+                "category_id": {
+                    "description": "0 = semua produk",
+                    "type": "integer"
+                },
+                "counted_products": {
+                    "type": "integer"
+                },
+                "created_at": {
                     "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StockOpnameItem"
+                    }
+                },
+                "note": {
+                    "type": "string"
+                },
+                "opened_by": {
+                    "type": "string"
+                },
+                "outlet_id": {
+                    "description": "Outlet yang stoknya dihitung",
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "total_products": {
+                    "description": "Ringkasan selisih (hanya produk yang sudah dihitung)",
+                    "type": "integer"
+                },
+                "total_variance": {
+                    "description": "Jumlah unit selisih (+ lebih, - kurang)",
+                    "type": "integer"
+                },
+                "total_variance_value": {
+                    "description": "Nilai rupiah selisih (variance x harga)",
+                    "type": "integer"
                 }
             }
         },
-        "models.CheckoutItem": {
+        "models.StockOpnameCategoryVariance": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "integer"
+                },
+                "category_name": {
+                    "type": "string"
+                },
+                "counted_products": {
+                    "type": "integer"
+                },
+                "variance": {
+                    "type": "integer"
+                },
+                "variance_value": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.StockOpnameCount": {
             "type": "object",
             "properties": {
+                "counted_at": {
+                    "type": "string"
+                },
+                "counted_by": {
+                    "type": "string"
+                },
                 "product_id": {
                     "type": "integer"
                 },
@@ -527,85 +4860,222 @@ const docTemplate = `{
                 }
             }
         },
-        "models.CheckoutRequest": {
+        "models.StockOpnameItem": {
             "type": "object",
             "properties": {
-                "items": {
+                "category_id": {
+                    "type": "integer"
+                },
+                "category_name": {
+                    "type": "string"
+                },
+                "counted_quantity": {
+                    "description": "CountedQuantity adalah total hitungan semua kasir untuk produk ini, nil jika belum dihitung.",
+                    "type": "integer"
+                },
+                "counts": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.CheckoutItem"
+                        "$ref": "#/definitions/models.StockOpnameCount"
                     }
                 },
-                "paid_amount": {
+                "expected_stock": {
+                    "description": "products.stock saat hitungan terakhir dikirim",
                     "type": "integer"
                 },
-                "payment_method": {
-                    "description": "\"CASH\", \"QRIS\"",
+                "id": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
                     "type": "string"
+                },
+                "snapshot_stock": {
+                    "description": "products.stock saat sesi dibuka",
+                    "type": "integer"
+                },
+                "stock_opname_id": {
+                    "type": "integer"
+                },
+                "unit_price": {
+                    "description": "Snapshot harga untuk menilai selisih",
+                    "type": "integer"
+                },
+                "variance": {
+                    "description": "CountedQuantity - ExpectedStock",
+                    "type": "integer"
+                },
+                "variance_value": {
+                    "description": "Variance x UnitPrice",
+                    "type": "integer"
                 }
             }
         },
-        "models.Product": {
+        "models.StockTransfer": {
             "type": "object",
             "properties": {
-                "category": {
-                    "description": "Category adalah relasi (join).\nPointer (*) berarti field ini bisa bernilai nil (kosong) jika tidak ada datanya.\n` + "`" + `omitempty` + "`" + `: Field ini tidak akan muncul di JSON jika nil.",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.Category"
-                        }
-                    ]
+                "cancelled_at": {
+                    "type": "string"
                 },
-                "category_id": {
-                    "description": "Foreign Key: ID dari kategori produk ini.",
+                "created_at": {
+                    "type": "string"
+                },
+                "from_outlet_id": {
                     "type": "integer"
                 },
+                "from_outlet_name": {
+                    "type": "string"
+                },
                 "id": {
-                    "description": "ID unik produk.\nTag ` + "`" + `json:\"id\"` + "`" + ` berarti saat diubah jadi JSON (API response), field ini akan bernama \"id\".",
                     "type": "integer"
                 },
-                "name": {
-                    "description": "Nama produk.",
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StockTransferItem"
+                    }
+                },
+                "note": {
                     "type": "string"
                 },
-                "price": {
-                    "description": "Harga produk dalam integer (Rupiah tidak punya desimal penting).",
+                "received_at": {
+                    "type": "string"
+                },
+                "received_by": {
+                    "type": "string"
+                },
+                "sent_by": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "to_outlet_id": {
                     "type": "integer"
                 },
-                "stock": {
-                    "description": "Jumlah stok tersedia.",
+                "to_outlet_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.StockTransferItem": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "description": "Snapshot nama saat transfer dibuat",
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "unit_cost": {
+                    "description": "Harga pokok per unit saat keluar dari outlet asal",
                     "type": "integer"
                 }
             }
         },
-        "models.ProductSales": {
+        "models.StockTransferItemRequest": {
             "type": "object",
             "properties": {
-                "nama": {
+                "product_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "description": "Dalam satuan dasar produk",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SubmitStockCountRequest": {
+            "type": "object",
+            "properties": {
+                "counted_by": {
                     "type": "string"
                 },
-                "qty_terjual": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StockCountItem"
+                    }
+                }
+            }
+        },
+        "models.Supplier": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
                     "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
                 }
             }
         },
-        "models.SalesSummary": {
+        "models.TenderCount": {
             "type": "object",
             "properties": {
-                "produk_terlaris": {
-                    "$ref": "#/definitions/models.ProductSales"
+                "amount": {
+                    "type": "integer"
                 },
-                "total_revenue": {
+                "method": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TenderSummary": {
+            "type": "object",
+            "properties": {
+                "amount": {
                     "type": "integer"
                 },
+                "method": {
+                    "type": "string"
+                },
                 "total_transaksi": {
                     "type": "integer"
                 }
             }
         },
+        "models.TopProduct": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "revenue": {
+                    "type": "integer"
+                }
+            }
+        },
         "models.Transaction": {
             "type": "object",
             "properties": {
+                "cashier": {
+                    "type": "string"
+                },
                 "change": {
                     "type": "integer"
                 },
@@ -619,23 +5089,108 @@ const docTemplate = `{
                         "$ref": "#/definitions/models.TransactionDetail"
                     }
                 },
+                "discount_amount": {
+                    "description": "Total potongan dari semua promosi",
+                    "type": "integer"
+                },
+                "gross_amount": {
+                    "description": "Total harga sebelum diskon",
+                    "type": "integer"
+                },
                 "id": {
                     "type": "integer"
                 },
+                "net_amount": {
+                    "description": "TotalAmount - RefundedAmount (omset bersih transaksi ini)",
+                    "type": "integer"
+                },
+                "outlet_id": {
+                    "description": "Outlet tempat transaksi terjadi",
+                    "type": "integer"
+                },
                 "paid_amount": {
                     "type": "integer"
                 },
                 "payment_method": {
                     "type": "string"
                 },
+                "payments": {
+                    "description": "Rincian pembayaran per metode (split payment)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Payment"
+                    }
+                },
+                "promotions": {
+                    "description": "Promosi yang dipakai di transaksi ini",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AppliedPromotion"
+                    }
+                },
+                "refunded_amount": {
+                    "type": "integer"
+                },
+                "refunds": {
+                    "description": "Riwayat refund/void untuk transaksi ini",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Refund"
+                    }
+                },
+                "service_charge_amount": {
+                    "type": "integer"
+                },
+                "shift_id": {
+                    "description": "Shift kasir saat transaksi terjadi (0 = tanpa shift)",
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subtotal_amount": {
+                    "description": "Rincian pajak (untuk laporan pajak bulanan):\nTotalAmount = SubtotalAmount + ServiceChargeAmount + TaxAmount",
+                    "type": "integer"
+                },
+                "tax_amount": {
+                    "type": "integer"
+                },
+                "tax_inclusive": {
+                    "description": "Mode harga saat transaksi terjadi (harga sudah termasuk pajak atau belum)",
+                    "type": "boolean"
+                },
                 "total_amount": {
+                    "description": "Yang harus dibayar pelanggan",
                     "type": "integer"
+                },
+                "void_reason": {
+                    "type": "string"
+                },
+                "voided_at": {
+                    "type": "string"
+                },
+                "voided_by": {
+                    "type": "string"
                 }
             }
         },
         "models.TransactionDetail": {
             "type": "object",
             "properties": {
+                "category_id": {
+                    "type": "integer"
+                },
+                "category_name": {
+                    "type": "string"
+                },
+                "cogs": {
+                    "description": "COGS adalah total harga pokok (HPP) barang di baris ini saat checkout, sesuai metode HPP yang berlaku.",
+                    "type": "integer"
+                },
+                "discount_amount": {
+                    "description": "Potongan untuk baris ini: diskon item + bagian dari diskon keranjang.",
+                    "type": "integer"
+                },
                 "id": {
                     "type": "integer"
                 },
@@ -649,12 +5204,50 @@ const docTemplate = `{
                 "quantity": {
                     "type": "integer"
                 },
+                "refunded_quantity": {
+                    "description": "Jumlah barang di baris ini yang sudah dikembalikan (refund/void).",
+                    "type": "integer"
+                },
+                "service_charge_amount": {
+                    "type": "integer"
+                },
                 "subtotal": {
-                    "description": "Harga satuan * Quantity saat transaksi terjadi",
+                    "description": "Subtotal adalah DPP baris ini: harga satuan * Quantity - DiscountAmount, tanpa pajak \u0026 service charge.\nYang dibayar pelanggan untuk baris ini = Subtotal + ServiceChargeAmount + TaxAmount.",
+                    "type": "integer"
+                },
+                "tax_amount": {
                     "type": "integer"
                 },
+                "tax_rate": {
+                    "description": "Tarif pajak (persen) yang dipakai saat transaksi",
+                    "type": "number"
+                },
                 "transaction_id": {
                     "type": "integer"
+                },
+                "unit": {
+                    "description": "Unit adalah satuan jual baris ini (UnitPrice \u0026 Quantity dalam satuan ini).\nUnitFactor = jumlah satuan dasar per Unit, jadi stok yang keluar = Quantity x UnitFactor.",
+                    "type": "string"
+                },
+                "unit_factor": {
+                    "type": "integer"
+                },
+                "unit_price": {
+                    "description": "Harga satuan saat transaksi terjadi (snapshot, tidak ikut berubah jika harga produk diubah)",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.VoidRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "description": "Wajib diisi, misal: \"salah input\"",
+                    "type": "string"
+                },
+                "voided_by": {
+                    "description": "Nama/ID kasir atau supervisor yang melakukan void",
+                    "type": "string"
                 }
             }
         }
@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"codeWithUmam/models"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+)
+
+// Simbologi barcode yang bisa dirender.
+const (
+	symbologyEAN13   = "ean13"
+	symbologyEAN8    = "ean8"
+	symbologyCode128 = "code128"
+)
+
+// barcodeQuietZone adalah lebar area kosong (dalam modul) di kiri & kanan barcode, supaya scanner bisa membaca awal/akhirnya.
+const barcodeQuietZone = 10
+
+// barcodeSymbol adalah hasil encode barcode: deretan modul (true = batang hitam) dan teks yang dicetak di bawahnya.
+type barcodeSymbol struct {
+	Symbology string
+	Modules   []bool
+	Text      string
+}
+
+// productBarcodeCode memilih kode yang dicetak untuk produk: barcode EAN pertama, jika tidak ada SKU.
+// Kode kosong berarti produk belum punya barcode maupun SKU.
+func productBarcodeCode(p models.Product) string {
+	if len(p.Barcodes) > 0 {
+		return p.Barcodes[0]
+	}
+	return p.SKU
+}
+
+// defaultSymbology menentukan simbologi yang cocok untuk kode: EAN-13/EAN-8 untuk kode angka 13/8 digit, selain itu Code128.
+func defaultSymbology(code string) string {
+	if isDigitString(code) {
+		switch len(code) {
+		case 13:
+			return symbologyEAN13
+		case 8:
+			return symbologyEAN8
+		}
+	}
+	return symbologyCode128
+}
+
+// encodeBarcode mengubah kode menjadi deretan modul sesuai simbologi.
+func encodeBarcode(symbology, code string) (*barcodeSymbol, error) {
+	switch symbology {
+	case symbologyEAN13:
+		if len(code) != 13 || !isDigitString(code) {
+			return nil, fmt.Errorf("EAN-13 butuh 13 digit angka, kode %q tidak bisa dipakai", code)
+		}
+		return &barcodeSymbol{Symbology: symbology, Modules: encodeEAN(code), Text: code}, nil
+	case symbologyEAN8:
+		if len(code) != 8 || !isDigitString(code) {
+			return nil, fmt.Errorf("EAN-8 butuh 8 digit angka, kode %q tidak bisa dipakai", code)
+		}
+		return &barcodeSymbol{Symbology: symbology, Modules: encodeEAN(code), Text: code}, nil
+	case symbologyCode128:
+		modules, err := encodeCode128(code)
+		if err != nil {
+			return nil, err
+		}
+		return &barcodeSymbol{Symbology: symbology, Modules: modules, Text: code}, nil
+	}
+	return nil, fmt.Errorf("tipe barcode harus salah satu dari %s, %s, %s", symbologyEAN13, symbologyEAN8, symbologyCode128)
+}
+
+func isDigitString(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Pola 7 modul tiap digit EAN. L = set A (ganjil), G = set B (genap), R = sisi kanan (kebalikan L).
+var (
+	eanL = [10]string{"0001101", "0011001", "0010011", "0111101", "0100011", "0110001", "0101111", "0111011", "0110111", "0001011"}
+	eanG = [10]string{"0100111", "0110011", "0011011", "0100001", "0011101", "0111001", "0000101", "0010001", "0001001", "0010111"}
+	eanR = [10]string{"1110010", "1100110", "1101100", "1000010", "1011100", "1001110", "1010000", "1000100", "1001000", "1110100"}
+
+	// eanParity: digit pertama EAN-13 tidak dicetak sebagai batang, tapi menentukan pola L/G enam digit kiri.
+	eanParity = [10]string{"LLLLLL", "LLGLGG", "LLGGLG", "LLGGGL", "LGLLGG", "LGGLLG", "LGGGLL", "LGLGLG", "LGLGGL", "LGGLGL"}
+)
+
+// encodeEAN meng-encode EAN-13 (95 modul) atau EAN-8 (67 modul). Kode diasumsikan sudah divalidasi (angka, panjang benar).
+func encodeEAN(code string) []bool {
+	var b strings.Builder
+	b.WriteString("101") // guard kiri
+
+	left, right, parity := code[:len(code)/2], code[len(code)/2:], "LLLL"
+	if len(code) == 13 {
+		left, right, parity = code[1:7], code[7:], eanParity[code[0]-'0']
+	}
+	for i := range left {
+		d := left[i] - '0'
+		if parity[i] == 'G' {
+			b.WriteString(eanG[d])
+		} else {
+			b.WriteString(eanL[d])
+		}
+	}
+	b.WriteString("01010") // guard tengah
+	for i := range right {
+		b.WriteString(eanR[right[i]-'0'])
+	}
+	b.WriteString("101") // guard kanan
+
+	modules := make([]bool, 0, b.Len())
+	for _, c := range b.String() {
+		modules = append(modules, c == '1')
+	}
+	return modules
+}
+
+// code128Patterns adalah lebar batang/spasi (bergantian, dimulai batang) untuk nilai simbol 0..105; indeks 106 = stop.
+var code128Patterns = [107]string{
+	"212222", "222122", "222221", "121223", "121322", "131222", "122213", "122312", "132212", "221213",
+	"221312", "231212", "112232", "122132", "122231", "113222", "123122", "123221", "223211", "221132",
+	"221231", "213212", "223112", "312131", "311222", "321122", "321221", "312212", "322112", "322211",
+	"212123", "212321", "232121", "111323", "131123", "131321", "112313", "132113", "132311", "211313",
+	"231113", "231311", "112133", "112331", "132131", "113123", "113321", "133121", "313121", "211331",
+	"231131", "213113", "213311", "213131", "311123", "311321", "331121", "312113", "312311", "332111",
+	"314111", "221411", "431111", "111224", "111422", "121124", "121421", "141122", "141221", "112214",
+	"112412", "122114", "122411", "142112", "142211", "241211", "221114", "413111", "241112", "134111",
+	"111242", "121142", "121241", "114212", "124112", "124211", "411212", "421112", "421211", "212141",
+	"214121", "412121", "111143", "111341", "131141", "114113", "114311", "411113", "411311", "113141",
+	"114131", "311141", "411131", "211412", "211214", "211232", "2331112",
+}
+
+const (
+	code128StartB = 104
+	code128StartC = 105
+	code128Stop   = 106
+)
+
+// encodeCode128 meng-encode teks ASCII (spasi s/d ~) dengan Code128.
+// Kode angka dengan jumlah digit genap memakai set C (dua digit per simbol, barcode lebih pendek); selain itu set B.
+func encodeCode128(code string) ([]bool, error) {
+	if code == "" {
+		return nil, fmt.Errorf("kode barcode kosong")
+	}
+
+	var values []int
+	if isDigitString(code) && len(code)%2 == 0 {
+		values = append(values, code128StartC)
+		for i := 0; i < len(code); i += 2 {
+			values = append(values, int(code[i]-'0')*10+int(code[i+1]-'0'))
+		}
+	} else {
+		values = append(values, code128StartB)
+		for _, r := range code {
+			if r < ' ' || r > '~' {
+				return nil, fmt.Errorf("karakter %q tidak bisa di-encode Code128", r)
+			}
+			values = append(values, int(r-' '))
+		}
+	}
+
+	// Checksum: nilai start + jumlah (nilai x posisi), modulo 103
+	checksum := values[0]
+	for i, v := range values[1:] {
+		checksum += v * (i + 1)
+	}
+	values = append(values, checksum%103, code128Stop)
+
+	var modules []bool
+	for _, v := range values {
+		for i, width := range code128Patterns[v] {
+			for n := 0; n < int(width-'0'); n++ {
+				modules = append(modules, i%2 == 0)
+			}
+		}
+	}
+	return modules, nil
+}
+
+// writeBarcodePNG menggambar barcode sebagai PNG hitam-putih (tanpa teks, karena library standar tidak punya font).
+// scale = lebar satu modul dalam pixel, height = tinggi batang dalam pixel.
+func writeBarcodePNG(w io.Writer, symbol *barcodeSymbol, scale, height int) error {
+	width := (len(symbol.Modules) + 2*barcodeQuietZone) * scale
+	img := image.NewPaletted(image.Rect(0, 0, width, height), color.Palette{color.White, color.Black})
+	for i, bar := range symbol.Modules {
+		if !bar {
+			continue
+		}
+		x0 := (barcodeQuietZone + i) * scale
+		for x := x0; x < x0+scale; x++ {
+			for y := 0; y < height; y++ {
+				img.SetColorIndex(x, y, 1)
+			}
+		}
+	}
+	return png.Encode(w, img)
+}
+
+// writeBarcodeSVG menggambar barcode sebagai SVG (batang + teks kode di bawahnya). Batang yang berurutan digabung satu <rect>.
+func writeBarcodeSVG(w io.Writer, symbol *barcodeSymbol, scale, height int) error {
+	textSize := 4 * scale
+	if textSize < 10 {
+		textSize = 10
+	}
+	width := (len(symbol.Modules) + 2*barcodeQuietZone) * scale
+	total := height + textSize + 4
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`, width, total, width, total)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="#fff"/><g fill="#000">`, width, total)
+	for i := 0; i < len(symbol.Modules); {
+		if !symbol.Modules[i] {
+			i++
+			continue
+		}
+		run := 1
+		for i+run < len(symbol.Modules) && symbol.Modules[i+run] {
+			run++
+		}
+		fmt.Fprintf(&b, `<rect x="%d" y="0" width="%d" height="%d"/>`, (barcodeQuietZone+i)*scale, run*scale, height)
+		i += run
+	}
+	fmt.Fprintf(&b, `</g><text x="%d" y="%d" font-family="monospace" font-size="%d" text-anchor="middle">`, width/2, height+textSize, textSize)
+	xmlEscape(&b, symbol.Text)
+	b.WriteString("</text></svg>")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func xmlEscape(b *strings.Builder, s string) {
+	strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;").WriteString(b, s)
+}
@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"bytes"
+	"codeWithUmam/models"
+	"image/png"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestCode128PatternTable(t *testing.T) {
+	seen := make(map[string]bool)
+	for value, pattern := range code128Patterns {
+		sum, bars := 0, 0
+		for i, c := range pattern {
+			sum += int(c - '0')
+			if i%2 == 0 {
+				bars += int(c - '0')
+			}
+		}
+		want := 11
+		if value == code128Stop {
+			want = 13
+		}
+		// Setiap simbol 11 modul (stop 13) dan jumlah modul batangnya genap
+		if sum != want || bars%2 != 0 || seen[pattern] {
+			t.Errorf("invalid pattern %d: %s", value, pattern)
+		}
+		seen[pattern] = true
+	}
+}
+
+// decodeCode128 membaca kembali nilai simbol dari deretan modul.
+func decodeCode128(t *testing.T, modules []bool) []int {
+	var widths []byte
+	for i := 0; i < len(modules); {
+		run := 1
+		for i+run < len(modules) && modules[i+run] == modules[i] {
+			run++
+		}
+		widths = append(widths, byte('0'+run))
+		i += run
+	}
+	index := make(map[string]int)
+	for v, p := range code128Patterns {
+		index[p] = v
+	}
+
+	var values []int
+	for i := 0; i+6 <= len(widths); i += 6 {
+		chunk := string(widths[i : i+6])
+		if i+7 == len(widths) {
+			chunk = string(widths[i:])
+		}
+		v, ok := index[chunk]
+		if !ok {
+			t.Fatalf("unknown symbol %s", chunk)
+		}
+		values = append(values, v)
+		if v == code128Stop {
+			break
+		}
+	}
+	return values
+}
+
+func TestEncodeCode128(t *testing.T) {
+	tests := []struct {
+		code  string
+		start int
+		data  []int
+	}{
+		{"SKU-01", code128StartB, []int{'S' - ' ', 'K' - ' ', 'U' - ' ', '-' - ' ', '0' - ' ', '1' - ' '}},
+		{"123456", code128StartC, []int{12, 34, 56}},
+		{"12345", code128StartB, []int{17, 18, 19, 20, 21}},
+	}
+	for _, tt := range tests {
+		modules, err := encodeCode128(tt.code)
+		if err != nil {
+			t.Fatalf("encodeCode128(%s) failed: %v", tt.code, err)
+		}
+		values := decodeCode128(t, modules)
+		if len(values) != len(tt.data)+3 || values[0] != tt.start || values[len(values)-1] != code128Stop {
+			t.Fatalf("%s: unexpected symbols %v", tt.code, values)
+		}
+		checksum := tt.start
+		for i, v := range tt.data {
+			if values[i+1] != v {
+				t.Errorf("%s: symbol %d expected %d, got %d", tt.code, i+1, v, values[i+1])
+			}
+			checksum += v * (i + 1)
+		}
+		if values[len(values)-2] != checksum%103 {
+			t.Errorf("%s: expected checksum %d, got %d", tt.code, checksum%103, values[len(values)-2])
+		}
+	}
+
+	if _, err := encodeCode128("Kopi ☕"); err == nil {
+		t.Error("expected non-ASCII text to be rejected")
+	}
+}
+
+func TestEncodeEAN13(t *testing.T) {
+	// R = kebalikan L, G = R dibaca terbalik
+	for d := 0; d < 10; d++ {
+		var inverted, reversed strings.Builder
+		for i := range eanL[d] {
+			inverted.WriteByte('0' + '1' - eanL[d][i])
+			reversed.WriteByte(eanR[d][6-i])
+		}
+		if inverted.String() != eanR[d] || reversed.String() != eanG[d] {
+			t.Errorf("inconsistent EAN tables for digit %d", d)
+		}
+	}
+
+	symbol, err := encodeBarcode(symbologyEAN13, "4006381333931")
+	if err != nil {
+		t.Fatalf("encodeBarcode failed: %v", err)
+	}
+	if len(symbol.Modules) != 95 {
+		t.Fatalf("expected 95 modules, got %d", len(symbol.Modules))
+	}
+
+	// Decode kembali: enam digit kiri (L/G -> digit pertama dari paritasnya), enam digit kanan (R)
+	bits := make([]byte, len(symbol.Modules))
+	for i, m := range symbol.Modules {
+		bits[i] = '0'
+		if m {
+			bits[i] = '1'
+		}
+	}
+	s := string(bits)
+	if s[:3] != "101" || s[45:50] != "01010" || s[92:] != "101" {
+		t.Fatalf("invalid guards: %s", s)
+	}
+	find := func(table [10]string, pattern string) int {
+		for d, p := range table {
+			if p == pattern {
+				return d
+			}
+		}
+		return -1
+	}
+	var digits, parity strings.Builder
+	for i := 0; i < 6; i++ {
+		pattern := s[3+7*i : 10+7*i]
+		if d := find(eanL, pattern); d >= 0 {
+			digits.WriteString(strconv.Itoa(d))
+			parity.WriteByte('L')
+		} else {
+			digits.WriteString(strconv.Itoa(find(eanG, pattern)))
+			parity.WriteByte('G')
+		}
+	}
+	for i := 0; i < 6; i++ {
+		digits.WriteString(strconv.Itoa(find(eanR, s[50+7*i:57+7*i])))
+	}
+	first := -1
+	for d, p := range eanParity {
+		if p == parity.String() {
+			first = d
+		}
+	}
+	if got := strconv.Itoa(first) + digits.String(); got != "4006381333931" {
+		t.Errorf("decoded %s, want 4006381333931", got)
+	}
+
+	if _, err := encodeBarcode(symbologyEAN13, "SKU-01"); err == nil {
+		t.Error("expected EAN-13 to reject non numeric code")
+	}
+	if ean8, err := encodeBarcode(symbologyEAN8, "96385074"); err != nil || len(ean8.Modules) != 67 {
+		t.Errorf("expected 67 EAN-8 modules, got %v", err)
+	}
+}
+
+func TestBarcodeImages(t *testing.T) {
+	symbol, _ := encodeBarcode(symbologyEAN13, "4006381333931")
+
+	var buf bytes.Buffer
+	if err := writeBarcodePNG(&buf, symbol, 2, 50); err != nil {
+		t.Fatalf("writeBarcodePNG failed: %v", err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("invalid png: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != (95+2*barcodeQuietZone)*2 || b.Dy() != 50 {
+		t.Errorf("unexpected png size %v", b)
+	}
+
+	buf.Reset()
+	if err := writeBarcodeSVG(&buf, symbol, 2, 50); err != nil {
+		t.Fatalf("writeBarcodeSVG failed: %v", err)
+	}
+	if svg := buf.String(); !strings.HasPrefix(svg, "<svg") || !strings.Contains(svg, ">4006381333931</text>") {
+		t.Errorf("unexpected svg: %s", svg)
+	}
+}
+
+func TestWriteShelfLabels(t *testing.T) {
+	products := make([]models.Product, 30) // 2 halaman
+	for i := range products {
+		products[i] = models.Product{ID: i + 1, Name: "Kopi (Susu) Gula Aren Ukuran Jumbo Sekali", Price: 15000, Barcodes: []string{"4006381333931"}}
+	}
+	products[1].Barcodes, products[1].SKU = nil, "KOP-01"
+	products[2].Barcodes = nil
+
+	var buf bytes.Buffer
+	if err := writeShelfLabels(&buf, products); err != nil {
+		t.Fatalf("writeShelfLabels failed: %v", err)
+	}
+	pdf := buf.String()
+	if !strings.HasPrefix(pdf, "%PDF-1.4") || !strings.Contains(pdf, "/Count 2") || !strings.Contains(pdf, "(Rp 15.000) Tj") {
+		t.Errorf("unexpected pdf content")
+	}
+	if !strings.Contains(pdf, `Kopi \(Susu\)`) {
+		t.Error("expected parentheses in text to be escaped")
+	}
+
+	// startxref harus menunjuk tepat ke tabel xref
+	m := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(pdf)
+	if m == nil {
+		t.Fatal("missing startxref")
+	}
+	offset, _ := strconv.Atoi(m[1])
+	if !strings.HasPrefix(pdf[offset:], "xref") {
+		t.Errorf("startxref %d does not point to xref table", offset)
+	}
+}
+
+func TestFormatRupiah(t *testing.T) {
+	for amount, want := range map[int]string{0: "Rp 0", 500: "Rp 500", 15000: "Rp 15.000", 1250000: "Rp 1.250.000", -2500: "-Rp 2.500"} {
+		if got := formatRupiah(amount); got != want {
+			t.Errorf("formatRupiah(%d) = %q, want %q", amount, got, want)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package handlers
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format export yang didukung endpoint daftar & laporan.
+const (
+	formatJSON = "json"
+	formatCSV  = "csv"
+	formatXLSX = "xlsx"
+
+	contentTypeCSV  = "text/csv"
+	contentTypeXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// exportFormat menentukan format response dari ?format=csv|xlsx|json, atau dari header Accept jika ?format kosong.
+// Default JSON, jadi client lama tidak terpengaruh.
+func exportFormat(r *http.Request) (string, error) {
+	if format := strings.ToLower(r.URL.Query().Get("format")); format != "" {
+		switch format {
+		case formatJSON, formatCSV, formatXLSX:
+			return format, nil
+		}
+		return "", fmt.Errorf("format harus salah satu dari json, csv, xlsx")
+	}
+
+	accept := r.Header.Get("Accept")
+	switch {
+	case strings.Contains(accept, contentTypeCSV):
+		return formatCSV, nil
+	case strings.Contains(accept, contentTypeXLSX):
+		return formatXLSX, nil
+	}
+	return formatJSON, nil
+}
+
+// tableWriter menulis data tabular (baris per baris) langsung ke response.
+type tableWriter interface {
+	WriteRow(cells ...interface{}) error
+	Close() error
+}
+
+// newTableWriter menyiapkan header HTTP (Content-Type & nama file download) lalu menulis baris judul kolom.
+func newTableWriter(w http.ResponseWriter, format, filename string, header []string) (tableWriter, error) {
+	var tw tableWriter
+	switch format {
+	case formatCSV:
+		w.Header().Set("Content-Type", contentTypeCSV+"; charset=utf-8")
+		tw = &csvTableWriter{w: csv.NewWriter(w)}
+	case formatXLSX:
+		w.Header().Set("Content-Type", contentTypeXLSX)
+		xw, err := newXLSXTableWriter(w)
+		if err != nil {
+			return nil, err
+		}
+		tw = xw
+	default:
+		return nil, fmt.Errorf("format %s bukan format tabel", format)
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, filename, format))
+
+	cells := make([]interface{}, len(header))
+	for i, h := range header {
+		cells[i] = h
+	}
+	return tw, tw.WriteRow(cells...)
+}
+
+// streamTable menulis baris hasil cursor repository satu per satu, tanpa menampung semuanya di memory.
+// Header HTTP baru dikirim saat baris pertama ditulis, jadi error sebelum itu (misal filter tanggal salah)
+// masih bisa dikirim sebagai response error JSON biasa.
+type streamTable struct {
+	w        http.ResponseWriter
+	format   string
+	filename string
+	header   []string
+	tw       tableWriter
+}
+
+func newStreamTable(w http.ResponseWriter, format, filename string, header ...string) *streamTable {
+	return &streamTable{w: w, format: format, filename: filename, header: header}
+}
+
+// Row menulis satu baris (membuka tabel lebih dulu jika ini baris pertama).
+func (s *streamTable) Row(cells ...interface{}) error {
+	if s.tw == nil {
+		tw, err := newTableWriter(s.w, s.format, s.filename, s.header)
+		if err != nil {
+			return err
+		}
+		s.tw = tw
+	}
+	return s.tw.WriteRow(cells...)
+}
+
+// Finish menutup tabel. err adalah hasil dari cursor repository:
+// jika belum ada baris yang terkirim, err dikirim sebagai response error; jika sudah, response hanya bisa diputus.
+func (s *streamTable) Finish(err error) {
+	if err != nil {
+		if s.tw == nil {
+			sendServiceError(s.w, err)
+			return
+		}
+		log.Println("Export terputus:", err)
+		s.tw.Close()
+		return
+	}
+	if s.tw == nil {
+		tw, err := newTableWriter(s.w, s.format, s.filename, s.header)
+		if err != nil {
+			sendError(s.w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		s.tw = tw
+	}
+	if err := s.tw.Close(); err != nil {
+		log.Println("Gagal menutup export:", err)
+	}
+}
+
+// writeTable menulis data yang sudah ada di memory (misal laporan yang sudah diagregasi) sebagai CSV/XLSX.
+func writeTable(w http.ResponseWriter, format, filename string, header []string, rows [][]interface{}) {
+	table := newStreamTable(w, format, filename, header...)
+	for _, row := range rows {
+		if err := table.Row(row...); err != nil {
+			table.Finish(err)
+			return
+		}
+	}
+	table.Finish(nil)
+}
+
+// cellText mengubah nilai sel menjadi teks. Waktu ditulis "YYYY-MM-DD HH:MM:SS" (zona waktu sesuai nilai time-nya).
+func cellText(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		return v.Format("2006-01-02 15:04:05")
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// csvTableWriter menulis tabel sebagai CSV.
+type csvTableWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvTableWriter) WriteRow(cells ...interface{}) error {
+	record := make([]string, len(cells))
+	for i, cell := range cells {
+		text := cellText(cell)
+		// Cegah CSV/formula injection: teks yang diawali = + - @ tidak boleh dieksekusi spreadsheet sebagai rumus
+		if _, isText := cell.(string); isText && text != "" && strings.ContainsRune("=+-@", rune(text[0])) {
+			text = "'" + text
+		}
+		record[i] = text
+	}
+	return c.w.Write(record)
+}
+
+func (c *csvTableWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// xlsxTableWriter menulis tabel sebagai file Excel (.xlsx) dengan satu sheet.
+// File .xlsx adalah ZIP berisi beberapa file XML; sheet-nya ditulis bertahap (streaming) baris per baris.
+// Teks memakai inline string supaya tidak perlu tabel shared strings yang baru bisa ditulis di akhir.
+type xlsxTableWriter struct {
+	zip   *zip.Writer
+	sheet io.Writer
+}
+
+// xlsxStaticParts adalah isi file XML minimal untuk workbook dengan satu sheet.
+var xlsxStaticParts = []struct{ name, body string }{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets>` +
+		`</workbook>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`},
+}
+
+func newXLSXTableWriter(w io.Writer) (*xlsxTableWriter, error) {
+	zw := zip.NewWriter(w)
+	for _, part := range xlsxStaticParts {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(f, part.body); err != nil {
+			return nil, err
+		}
+	}
+
+	// Sheet harus file terakhir di ZIP, karena isinya ditulis terus sampai Close
+	sheet, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	_, err = io.WriteString(sheet, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`+
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	if err != nil {
+		return nil, err
+	}
+	return &xlsxTableWriter{zip: zw, sheet: sheet}, nil
+}
+
+func (x *xlsxTableWriter) WriteRow(cells ...interface{}) error {
+	var b strings.Builder
+	b.WriteString("<row>")
+	for _, cell := range cells {
+		switch cell.(type) {
+		case int, float64:
+			// Angka ditulis sebagai angka supaya bisa langsung dijumlahkan di Excel
+			b.WriteString("<c><v>" + cellText(cell) + "</v></c>")
+		default:
+			b.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+			xml.EscapeText(&b, []byte(cellText(cell)))
+			b.WriteString("</t></is></c>")
+		}
+	}
+	b.WriteString("</row>")
+	_, err := io.WriteString(x.sheet, b.String())
+	return err
+}
+
+func (x *xlsxTableWriter) Close() error {
+	if _, err := io.WriteString(x.sheet, "</sheetData></worksheet>"); err != nil {
+		return err
+	}
+	return x.zip.Close()
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestExportFormat(t *testing.T) {
+	tests := []struct {
+		url, accept, want string
+		wantErr           bool
+	}{
+		{"/x", "", formatJSON, false},
+		{"/x?format=CSV", "", formatCSV, false},
+		{"/x?format=xlsx", "application/json", formatXLSX, false},
+		{"/x", "text/csv", formatCSV, false},
+		{"/x", contentTypeXLSX, formatXLSX, false},
+		{"/x?format=pdf", "", "", true},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest("GET", tt.url, nil)
+		req.Header.Set("Accept", tt.accept)
+		got, err := exportFormat(req)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("exportFormat(%s, %q) = %q, %v; want %q", tt.url, tt.accept, got, err, tt.want)
+		}
+	}
+}
+
+func TestWriteTable_CSV(t *testing.T) {
+	rr := httptest.NewRecorder()
+	writeTable(rr, formatCSV, "produk", []string{"name", "price"}, [][]interface{}{
+		{"Kopi, Susu", 15000},
+		{"=HYPERLINK(\"x\")", 2.5},
+	})
+
+	if ct := rr.Header().Get("Content-Type"); !strings.HasPrefix(ct, contentTypeCSV) {
+		t.Errorf("unexpected content type %q", ct)
+	}
+	if cd := rr.Header().Get("Content-Disposition"); !strings.Contains(cd, `filename="produk.csv"`) {
+		t.Errorf("unexpected content disposition %q", cd)
+	}
+
+	records, err := csv.NewReader(rr.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid csv: %v", err)
+	}
+	want := [][]string{{"name", "price"}, {"Kopi, Susu", "15000"}, {"'=HYPERLINK(\"x\")", "2.5"}}
+	if len(records) != len(want) {
+		t.Fatalf("expected %d records, got %d", len(want), len(records))
+	}
+	for i := range want {
+		if strings.Join(records[i], "|") != strings.Join(want[i], "|") {
+			t.Errorf("record %d: expected %v, got %v", i, want[i], records[i])
+		}
+	}
+}
+
+func TestWriteTable_XLSX(t *testing.T) {
+	rr := httptest.NewRecorder()
+	writeTable(rr, formatXLSX, "laporan", []string{"name", "qty"}, [][]interface{}{{"Teh <Manis>", 3}})
+
+	if ct := rr.Header().Get("Content-Type"); ct != contentTypeXLSX {
+		t.Errorf("unexpected content type %q", ct)
+	}
+
+	body := rr.Body.Bytes()
+	zr, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		t.Fatalf("xlsx is not a valid zip: %v", err)
+	}
+	var sheet string
+	for _, f := range zr.File {
+		if f.Name == "xl/worksheets/sheet1.xml" {
+			rc, _ := f.Open()
+			b, _ := io.ReadAll(rc)
+			rc.Close()
+			sheet = string(b)
+		}
+	}
+	for _, want := range []string{"Teh &lt;Manis&gt;", "<c><v>3</v></c>", "</sheetData></worksheet>"} {
+		if !strings.Contains(sheet, want) {
+			t.Errorf("sheet1.xml missing %q: %s", want, sheet)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"codeWithUmam/models"
+	"io"
+	"strconv"
+)
+
+// Layout label rak di kertas A4: 3 kolom x 8 baris (24 label per halaman), masing-masing kira-kira 62 x 35 mm.
+const (
+	labelColumns = 3
+	labelRows    = 8
+	labelMargin  = 20.0 // pt, di semua sisi kertas
+	labelPadding = 6.0  // pt, jarak isi ke garis potong
+)
+
+// writeShelfLabels menyusun label rak (nama, harga, barcode) untuk setiap produk menjadi PDF A4.
+// Produk tanpa barcode/SKU tetap dapat label, hanya tanpa barcode.
+func writeShelfLabels(w io.Writer, products []models.Product) error {
+	doc := newPDFDocument(pdfA4Width, pdfA4Height)
+	labelWidth := (pdfA4Width - 2*labelMargin) / labelColumns
+	labelHeight := (pdfA4Height - 2*labelMargin) / labelRows
+
+	for i, p := range products {
+		if i%(labelColumns*labelRows) == 0 {
+			doc.AddPage()
+		}
+		slot := i % (labelColumns * labelRows)
+		x := labelMargin + float64(slot%labelColumns)*labelWidth
+		y := labelMargin + float64(slot/labelColumns)*labelHeight
+		drawShelfLabel(doc, p, x, y, labelWidth, labelHeight)
+	}
+
+	_, err := doc.WriteTo(w)
+	return err
+}
+
+// drawShelfLabel menggambar satu label dengan sudut kiri-atas (x, y).
+func drawShelfLabel(doc *pdfDocument, p models.Product, x, y, width, height float64) {
+	doc.StrokeRect(x, y, width, height)
+	inner := width - 2*labelPadding
+	left := x + labelPadding
+
+	doc.Text(left, y+labelPadding+9, pdfFontBold, 9, pdfFitText(pdfFontBold, 9, inner, p.Name))
+	doc.Text(left, y+labelPadding+28, pdfFontBold, 16, formatRupiah(p.Price))
+
+	code := productBarcodeCode(p)
+	if code == "" {
+		return
+	}
+	symbol, err := encodeBarcode(defaultSymbology(code), code)
+	if err != nil {
+		// Kode yang tidak bisa di-encode (misal SKU berisi karakter non-ASCII) dicetak sebagai teks saja
+		doc.Text(left, y+height-labelPadding, pdfFontMono, 7, pdfFitText(pdfFontMono, 7, inner, code))
+		return
+	}
+
+	// Batang barcode mengisi sisa tinggi label; lebar modul dikecilkan jika kodenya panjang
+	barsTop := y + labelPadding + 34
+	barsHeight := height - 2*labelPadding - 34 - 9
+	module := inner / float64(len(symbol.Modules)+2*barcodeQuietZone)
+	if module > 1.5 {
+		module = 1.5
+	}
+	barsLeft := left + (inner-module*float64(len(symbol.Modules)))/2
+	for i := 0; i < len(symbol.Modules); {
+		if !symbol.Modules[i] {
+			i++
+			continue
+		}
+		run := 1
+		for i+run < len(symbol.Modules) && symbol.Modules[i+run] {
+			run++
+		}
+		doc.Rect(barsLeft+float64(i)*module, barsTop, float64(run)*module, barsHeight)
+		i += run
+	}
+
+	textWidth := pdfTextWidth(pdfFontMono, 7, symbol.Text)
+	doc.Text(x+(width-textWidth)/2, barsTop+barsHeight+8, pdfFontMono, 7, symbol.Text)
+}
+
+// formatRupiah memformat angka menjadi "Rp 15.000".
+func formatRupiah(amount int) string {
+	if amount < 0 {
+		return "-Rp " + formatAmount(-amount)
+	}
+	return "Rp " + formatAmount(amount)
+}
+
+// formatAmount memformat angka dengan titik ribuan, misal 1250000 -> "1.250.000".
+func formatAmount(amount int) string {
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	digits := strconv.Itoa(amount)
+	var out []byte
+	for i := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			out = append(out, '.')
+		}
+		out = append(out, digits[i])
+	}
+	return sign + string(out)
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"codeWithUmam/models"
+	"codeWithUmam/services"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// OutletHandler menangani request HTTP terkait outlet (toko/cabang) dan stok per outlet.
+type OutletHandler struct {
+	service services.OutletService
+}
+
+func NewOutletHandler(service services.OutletService) *OutletHandler {
+	return &OutletHandler{service: service}
+}
+
+// HandleOutlets adalah "router" untuk semua URL outlet.
+// - GET  /api/v1/outlets            -> GetAll
+// - POST /api/v1/outlets            -> Create
+// - GET  /api/v1/outlets/{id}       -> GetByID
+// - PUT  /api/v1/outlets/{id}       -> Update
+// - GET  /api/v1/outlets/{id}/stock -> GetStock
+// Outlet tidak bisa dihapus, hanya dinonaktifkan lewat Update (active=false).
+func (h *OutletHandler) HandleOutlets(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/api/v1/outlets" {
+		switch r.Method {
+		case "GET":
+			h.GetAll(w, r)
+		case "POST":
+			h.Create(w, r)
+		default:
+			sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	// Contoh: "/api/v1/outlets/2/stock" -> ["2", "stock"]
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/outlets/"), "/"), "/")
+	id, err := strconv.Atoi(parts[0])
+	if err != nil {
+		sendError(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	switch {
+	case len(parts) == 1 && r.Method == "GET":
+		h.GetByID(w, id)
+	case len(parts) == 1 && r.Method == "PUT":
+		h.Update(w, r, id)
+	case len(parts) == 2 && parts[1] == "stock" && r.Method == "GET":
+		h.GetStock(w, id)
+	case len(parts) <= 2:
+		sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+	default:
+		sendError(w, "Not found", http.StatusNotFound)
+	}
+}
+
+// GetAll mengambil semua outlet.
+// @Summary List outlets
+// @Description Get all outlets, including inactive ones
+// @Tags outlets
+// @Produce  json
+// @Success 200 {array} models.Outlet
+// @Router /outlets [get]
+func (h *OutletHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	outlets, err := h.service.GetAll()
+	if err != nil {
+		sendError(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	sendJSON(w, outlets)
+}
+
+// Create membuat outlet baru.
+// @Summary Create an outlet
+// @Description Create a new outlet (store/branch); it starts active and without stock
+// @Tags outlets
+// @Accept  json
+// @Produce  json
+// @Param outlet body models.Outlet true "Outlet Data"
+// @Success 200 {object} models.Outlet
+// @Failure 400 {object} map[string]string
+// @Router /outlets [post]
+func (h *OutletHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var outlet models.Outlet
+	if err := json.NewDecoder(r.Body).Decode(&outlet); err != nil {
+		sendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.Create(&outlet); err != nil {
+		sendServiceError(w, err)
+		return
+	}
+	sendJSON(w, outlet)
+}
+
+// GetByID mengambil satu outlet berdasarkan ID di URL.
+// @Summary Get outlet by ID
+// @Description Get a single outlet by its ID
+// @Tags outlets
+// @Produce  json
+// @Param id path int true "Outlet ID"
+// @Success 200 {object} models.Outlet
+// @Failure 404 {object} map[string]string
+// @Router /outlets/{id} [get]
+func (h *OutletHandler) GetByID(w http.ResponseWriter, id int) {
+	outlet, err := h.service.GetByID(id)
+	if err != nil {
+		sendServiceError(w, err)
+		return
+	}
+	sendJSON(w, outlet)
+}
+
+// Update mengubah data outlet, termasuk mengaktifkan/menonaktifkannya.
+// @Summary Update an outlet
+// @Description Update an outlet; set active=false to close it (not allowed for the main outlet or with transfers in transit)
+// @Tags outlets
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Outlet ID"
+// @Param outlet body models.Outlet true "Outlet Data"
+// @Success 200 {object} models.Outlet
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /outlets/{id} [put]
+func (h *OutletHandler) Update(w http.ResponseWriter, r *http.Request, id int) {
+	var outlet models.Outlet
+	if err := json.NewDecoder(r.Body).Decode(&outlet); err != nil {
+		sendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	// Pastikan ID di struct sama dengan ID di URL
+	outlet.ID = id
+
+	if err := h.service.Update(&outlet); err != nil {
+		sendServiceError(w, err)
+		return
+	}
+	sendJSON(w, outlet)
+}
+
+// GetStock mengambil stok setiap produk di satu outlet.
+// @Summary Get outlet stock
+// @Description Get the stock of every product at an outlet, plus quantities in transit to it
+// @Tags outlets
+// @Produce  json
+// @Param id path int true "Outlet ID"
+// @Success 200 {array} models.OutletStockItem
+// @Failure 404 {object} map[string]string
+// @Router /outlets/{id}/stock [get]
+func (h *OutletHandler) GetStock(w http.ResponseWriter, id int) {
+	items, err := h.service.GetStock(id)
+	if err != nil {
+		sendServiceError(w, err)
+		return
+	}
+	sendJSON(w, items)
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"codeWithUmam/models"
+	"codeWithUmam/services"
+)
+
+// maxCallbackBodySize membatasi ukuran body webhook yang dibaca (payload gateway hanya beberapa ratus byte).
+const maxCallbackBodySize = 64 << 10
+
+// PaymentHandler menangani webhook dari payment gateway.
+type PaymentHandler struct {
+	service   services.PaymentService
+	simulator *services.QRISSimulator // nil jika simulator QRIS tidak diaktifkan
+}
+
+// NewPaymentHandler adalah Constructor.
+// simulator boleh nil; endpoint simulator hanya aktif jika simulator diberikan.
+func NewPaymentHandler(service services.PaymentService, simulator *services.QRISSimulator) *PaymentHandler {
+	return &PaymentHandler{service: service, simulator: simulator}
+}
+
+// HandleCallback menerima webhook hasil pembayaran dari payment gateway.
+// Endpoint: POST /api/payments/callback/{provider}
+// Tanda tangan dikirim di header X-Callback-Signature dan diverifikasi oleh provider.
+// @Summary      Payment Gateway Callback
+// @Description  Webhook called by the payment gateway to mark a pending payment as PAID or EXPIRED
+// @Tags         payments
+// @Accept       json
+// @Produce      json
+// @Param        provider path string true "Provider name, e.g. qris-simulator"
+// @Param        X-Callback-Signature header string true "Signature of the raw body"
+// @Param        request body models.PaymentCallback true "Callback payload"
+// @Success      200  {object}  models.Transaction
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /payments/callback/{provider} [post]
+func (h *PaymentHandler) HandleCallback(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	provider := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/payments/callback/"), "/")
+	if provider == "" {
+		sendError(w, "Provider wajib diisi", http.StatusBadRequest)
+		return
+	}
+
+	// Body dibaca mentah (bukan langsung di-decode) karena signature dihitung dari byte aslinya.
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxCallbackBodySize))
+	if err != nil {
+		sendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	transaction, err := h.service.HandleCallback(provider, body, r.Header.Get("X-Callback-Signature"))
+	if err != nil {
+		sendServiceError(w, err)
+		return
+	}
+
+	sendJSON(w, transaction)
+}
+
+// HandleSimulatorPay mensimulasikan pelanggan membayar (atau membiarkan kedaluwarsa) tagihan QRIS.
+// Endpoint: POST /api/payments/simulator/pay (hanya aktif jika QRIS_PROVIDER=simulator)
+// Body JSON: { "reference": "SIMQRIS-1-1-1", "status": "PAID" }
+// Webhook bertanda tangan dibuat oleh simulator lalu diproses lewat jalur yang sama dengan callback gateway.
+// @Summary      Simulate QRIS Payment
+// @Description  Development only: sends a signed simulator callback for a pending QRIS payment
+// @Tags         payments
+// @Accept       json
+// @Produce      json
+// @Param        request body models.PaymentCallback true "Reference and status (PAID or EXPIRED)"
+// @Success      200  {object}  models.Transaction
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /payments/simulator/pay [post]
+func (h *PaymentHandler) HandleSimulatorPay(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.simulator == nil {
+		sendError(w, "Simulator QRIS tidak aktif", http.StatusNotFound)
+		return
+	}
+
+	var req models.PaymentCallback
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Status == "" {
+		req.Status = models.PaymentStatusPaid
+	}
+
+	body, signature, err := h.simulator.Callback(req.ProviderReference, req.Status)
+	if err != nil {
+		sendServiceError(w, err)
+		return
+	}
+
+	transaction, err := h.service.HandleCallback(h.simulator.Name(), body, signature)
+	if err != nil {
+		sendServiceError(w, err)
+		return
+	}
+
+	sendJSON(w, transaction)
+}
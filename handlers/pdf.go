@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Font standar PDF (tidak perlu di-embed, semua PDF reader pasti punya).
+const (
+	pdfFontRegular = "F1" // Helvetica
+	pdfFontBold    = "F2" // Helvetica-Bold
+	pdfFontMono    = "F3" // Courier
+)
+
+// Ukuran kertas dalam point (1 pt = 1/72 inch).
+const (
+	pdfA4Width  = 595.28
+	pdfA4Height = 841.89
+	pdfMMToPt   = 72 / 25.4
+)
+
+// pdfDocument adalah penulis PDF minimal tanpa library luar: halaman berisi teks (font standar) dan kotak.
+// Koordinat memakai titik kiri-atas halaman sebagai (0, 0) supaya layout mudah dihitung dari atas ke bawah;
+// konversi ke koordinat PDF (kiri-bawah) dilakukan di sini.
+type pdfDocument struct {
+	width, height float64
+	pages         []*bytes.Buffer
+}
+
+func newPDFDocument(width, height float64) *pdfDocument {
+	return &pdfDocument{width: width, height: height}
+}
+
+// AddPage menambah halaman baru; gambar berikutnya masuk ke halaman ini.
+func (d *pdfDocument) AddPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+}
+
+func (d *pdfDocument) page() *bytes.Buffer {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+	return d.pages[len(d.pages)-1]
+}
+
+// Text menulis teks dengan baseline di y (dari atas halaman).
+func (d *pdfDocument) Text(x, y float64, font string, size float64, text string) {
+	fmt.Fprintf(d.page(), "BT /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, d.height-y, pdfEscape(text))
+}
+
+// Rect mengisi kotak hitam (dipakai untuk batang barcode). y adalah sisi atas kotak.
+func (d *pdfDocument) Rect(x, y, w, h float64) {
+	fmt.Fprintf(d.page(), "%.3f %.3f %.3f %.3f re f\n", x, d.height-y-h, w, h)
+}
+
+// StrokeRect menggambar garis tepi kotak tipis abu-abu (misal garis potong label).
+func (d *pdfDocument) StrokeRect(x, y, w, h float64) {
+	fmt.Fprintf(d.page(), "q 0.75 G 0.3 w %.2f %.2f %.2f %.2f re S Q\n", x, d.height-y-h, w, h)
+}
+
+// WriteTo menulis file PDF lengkap (objek, tabel xref, trailer).
+func (d *pdfDocument) WriteTo(w io.Writer) (int64, error) {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n")
+	// Objek 1: katalog, 2: daftar halaman, 3-5: font. Halaman & isinya mulai dari objek 6.
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 6+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	for _, base := range []string{"Helvetica", "Helvetica-Bold", "Courier"} {
+		object(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", base))
+	}
+	for i, content := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R /F3 5 0 R >> >> /Contents %d 0 R >>", d.width, d.height, 7+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	n, err := w.Write(out.Bytes())
+	return int64(n), err
+}
+
+// pdfEscape mengubah teks UTF-8 ke WinAnsi (Latin-1) dan meng-escape karakter khusus string PDF.
+// Karakter di luar Latin-1 (misal emoji) diganti '?'.
+func pdfEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// pdfTextWidth memperkirakan lebar teks dalam point. Courier pasti 0,6 em per karakter;
+// untuk Helvetica dipakai rata-rata lebar huruf (cukup untuk memotong/menengahkan teks label).
+func pdfTextWidth(font string, size float64, text string) float64 {
+	perChar := 0.52
+	switch font {
+	case pdfFontMono:
+		perChar = 0.6
+	case pdfFontBold:
+		perChar = 0.56
+	}
+	return float64(utf8.RuneCountInString(text)) * perChar * size
+}
+
+// pdfFitText memotong teks (dengan "...") supaya muat di lebar maxWidth.
+func pdfFitText(font string, size, maxWidth float64, text string) string {
+	if pdfTextWidth(font, size, text) <= maxWidth {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && pdfTextWidth(font, size, string(runes)+"...") > maxWidth {
+		runes = runes[:len(runes)-1]
+	}
+	return strings.TrimSpace(string(runes)) + "..."
+}
@@ -4,6 +4,9 @@ import (
 	"codeWithUmam/models"
 	"codeWithUmam/services"
 	"encoding/json"
+	"fmt"
+	"io"
+	"log"
 	"net/http"
 	"strconv"
 	"strings"
@@ -49,6 +52,62 @@ func (h *ProductHandler) HandleProducts(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// Import CSV: POST /api/v1/products/import
+	if r.URL.Path == "/api/v1/products/import" {
+		if r.Method != "POST" {
+			sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		h.Import(w, r)
+		return
+	}
+
+	// Scan barcode: GET /api/v1/products/barcode/{code}
+	if strings.HasPrefix(r.URL.Path, "/api/v1/products/barcode/") {
+		if r.Method != "GET" {
+			sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		h.GetByBarcode(w, r)
+		return
+	}
+
+	// Label rak (PDF): GET /api/v1/products/labels
+	if r.URL.Path == "/api/v1/products/labels" {
+		if r.Method != "GET" {
+			sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		h.Labels(w, r)
+		return
+	}
+
+	// Stok menipis: GET /api/v1/products/low-stock
+	if r.URL.Path == "/api/v1/products/low-stock" {
+		if r.Method != "GET" {
+			sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		h.LowStock(w, r)
+		return
+	}
+
+	// Varian produk: /api/v1/products/{id}/variants[/{variantID}]
+	if strings.Contains(r.URL.Path, "/variants") {
+		h.HandleVariants(w, r)
+		return
+	}
+
+	// Gambar barcode: GET /api/v1/products/{id}/barcode
+	if strings.HasSuffix(r.URL.Path, "/barcode") {
+		if r.Method != "GET" {
+			sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		h.BarcodeImage(w, r)
+		return
+	}
+
 	// Jika URL diawali "/api/v1/products/" -> berarti ada ID di belakangnya (misal /api/v1/products/123).
 	// Ini untuk GetByID, Update, atau Delete.
 	if strings.HasPrefix(r.URL.Path, "/api/v1/products/") {
@@ -68,15 +127,39 @@ func (h *ProductHandler) HandleProducts(w http.ResponseWriter, r *http.Request)
 }
 
 // GetAll mengambil semua data produk.
+// Dengan ?format=csv|xlsx (atau header Accept), seluruh katalog dikirim sebagai file spreadsheet.
 // @Summary Get all products
-// @Description Get list of all products
+// @Description Get list of all products with their variants nested, or export the whole catalogue (one row per variant) as CSV/XLSX
 // @Tags products
 // @Accept  json
-// @Produce  json
+// @Produce  json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
 // @Success 200 {array} models.Product
 // @Param name query string false "Product Name Filter"
+// @Param format query string false "json (default), csv or xlsx"
+// @Failure 400 {object} map[string]string
 // @Router /products [get]
 func (h *ProductHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	format, err := exportFormat(r)
+	if err != nil {
+		sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if format != formatJSON {
+		table := newStreamTable(w, format, "produk", "id", "parent_id", "variant", "sku", "barcodes", "name", "category_id", "category", "price", "cost_price", "stock", "unit")
+		table.Finish(h.service.Export(func(p models.Product) error {
+			category := ""
+			if p.Category != nil {
+				category = p.Category.Name
+			}
+			parentID := ""
+			if p.ParentID != 0 {
+				parentID = strconv.Itoa(p.ParentID)
+			}
+			return table.Row(p.ID, parentID, p.VariantName, p.SKU, strings.Join(p.Barcodes, " "), p.Name, p.CategoryID, category, p.Price, p.CostPrice, p.Stock, p.Unit)
+		}))
+		return
+	}
+
 	// Ambil query param "name" (misal: /products?name=indomie)
 	name := r.URL.Query().Get("name")
 
@@ -91,7 +174,7 @@ func (h *ProductHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary Create a new product
-// @Description Create a new product
+// @Description Create a new product, optionally with variants (each with its own SKU, price and stock; the parent's stock must be 0)
 // @Tags products
 // @Accept  json
 // @Produce  json
@@ -108,13 +191,60 @@ func (h *ProductHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 	// Panggil service untuk simpan data
 	if err := h.service.Create(&product); err != nil {
-		sendError(w, err.Error(), http.StatusInternalServerError)
+		sendServiceError(w, err)
 		return
 	}
 	// Kembalikan data yang baru dibuat (lengkap dengan ID baru)
 	sendJSON(w, product)
 }
 
+// maxImportSize adalah batas ukuran file CSV import produk (10 MB, cukup untuk puluhan ribu produk).
+const maxImportSize = 10 << 20
+
+// Import menerima file CSV produk (kolom: name, price, stock, category, sku) dan menyimpannya dalam satu transaksi DB.
+// File dikirim sebagai multipart form field "file", atau langsung sebagai body (Content-Type: text/csv).
+// Jika ada baris yang salah, tidak ada yang disimpan (applied = false) dan daftar error per baris dikembalikan.
+// @Summary Bulk import products from CSV
+// @Description Upsert products by SKU from a CSV file (name, price, stock, category name/ID, sku) in a single DB transaction. Unknown category names are created. With dry_run=true nothing is saved and per-row validation errors are returned.
+// @Tags products
+// @Accept  text/csv,multipart/form-data
+// @Produce  json
+// @Param file formData file false "CSV file (multipart)"
+// @Param dry_run query bool false "Validate and simulate only, without saving"
+// @Success 200 {object} models.ProductImportResult
+// @Failure 400 {object} map[string]string
+// @Router /products/import [post]
+func (h *ProductHandler) Import(w http.ResponseWriter, r *http.Request) {
+	dryRun := false
+	if raw := r.URL.Query().Get("dry_run"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			sendError(w, "dry_run harus true atau false", http.StatusBadRequest)
+			return
+		}
+		dryRun = parsed
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+	var file io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		upload, _, err := r.FormFile("file")
+		if err != nil {
+			sendError(w, "File CSV wajib dikirim di field \"file\"", http.StatusBadRequest)
+			return
+		}
+		defer upload.Close()
+		file = upload
+	}
+
+	result, err := h.service.Import(file, dryRun)
+	if err != nil {
+		sendServiceError(w, err)
+		return
+	}
+	sendJSON(w, result)
+}
+
 // @Summary Get product by ID
 // @Description Get a single product by its ID
 // @Tags products
@@ -140,8 +270,318 @@ func (h *ProductHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	sendJSON(w, product)
 }
 
+// GetByBarcode mencari produk dari hasil scan scanner kasir.
+// @Summary Get product by barcode or SKU
+// @Description Look up a product by a scanned EAN-8/EAN-13/UPC-A barcode (check digit validated) or by SKU
+// @Tags products
+// @Produce  json
+// @Param code path string true "Barcode or SKU"
+// @Success 200 {object} models.Product
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /products/barcode/{code} [get]
+func (h *ProductHandler) GetByBarcode(w http.ResponseWriter, r *http.Request) {
+	code := strings.TrimPrefix(r.URL.Path, "/api/v1/products/barcode/")
+
+	product, err := h.service.GetByBarcode(code)
+	if err != nil {
+		sendServiceError(w, err)
+		return
+	}
+	sendJSON(w, product)
+}
+
+// BarcodeImage merender barcode produk sebagai gambar PNG atau SVG.
+// Default: barcode pertama produk (EAN-13/EAN-8), atau SKU sebagai Code128 jika produk belum punya barcode.
+// @Summary Render product barcode
+// @Description Render a product barcode (EAN-13, EAN-8 or Code128) as SVG (default) or PNG. The code defaults to the product's first barcode, falling back to its SKU.
+// @Tags products
+// @Produce  image/svg+xml,image/png
+// @Param id path int true "Product ID"
+// @Param format query string false "svg (default) or png"
+// @Param type query string false "ean13, ean8 or code128 (default: from the code)"
+// @Param code query string false "One of the product's barcodes or its SKU"
+// @Param scale query int false "Module width in pixels (1-10, default 2)"
+// @Param height query int false "Bar height in pixels (20-500, default 80)"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /products/{id}/barcode [get]
+func (h *ProductHandler) BarcodeImage(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/v1/products/"), "/barcode")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		sendError(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	q := r.URL.Query()
+	format := strings.ToLower(q.Get("format"))
+	if format == "" {
+		format = "svg"
+	}
+	if format != "svg" && format != "png" {
+		sendError(w, "format harus svg atau png", http.StatusBadRequest)
+		return
+	}
+	scale, err := queryIntInRange(q.Get("scale"), 2, 1, 10)
+	if err != nil {
+		sendError(w, "scale "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	height, err := queryIntInRange(q.Get("height"), 80, 20, 500)
+	if err != nil {
+		sendError(w, "height "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	product, err := h.service.GetByID(id)
+	if err != nil {
+		sendError(w, "Product not found", http.StatusNotFound)
+		return
+	}
+
+	code := q.Get("code")
+	if code == "" {
+		code = productBarcodeCode(*product)
+		if code == "" {
+			sendError(w, "Produk belum punya barcode atau SKU", http.StatusNotFound)
+			return
+		}
+	} else if !productHasCode(*product, code) {
+		sendError(w, "Kode "+code+" bukan barcode/SKU produk ini", http.StatusBadRequest)
+		return
+	}
+
+	symbology := strings.ToLower(q.Get("type"))
+	if symbology == "" {
+		symbology = defaultSymbology(code)
+	}
+	symbol, err := encodeBarcode(symbology, code)
+	if err != nil {
+		sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if format == "png" {
+		w.Header().Set("Content-Type", "image/png")
+		err = writeBarcodePNG(w, symbol, scale, height)
+	} else {
+		w.Header().Set("Content-Type", "image/svg+xml")
+		err = writeBarcodeSVG(w, symbol, scale, height)
+	}
+	if err != nil {
+		log.Println("Gagal menulis gambar barcode:", err)
+	}
+}
+
+// productHasCode mengecek apakah code adalah salah satu barcode atau SKU produk.
+func productHasCode(p models.Product, code string) bool {
+	if code == p.SKU {
+		return true
+	}
+	for _, barcode := range p.Barcodes {
+		if code == barcode {
+			return true
+		}
+	}
+	return false
+}
+
+// queryIntInRange membaca query param angka dengan nilai default (jika kosong) dan batas min..max.
+func queryIntInRange(raw string, def, min, max int) (int, error) {
+	if raw == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < min || n > max {
+		return 0, fmt.Errorf("harus angka %d-%d", min, max)
+	}
+	return n, nil
+}
+
+// Labels mencetak label rak (nama, harga, barcode) untuk satu kategori dan/atau daftar produk sebagai PDF A4.
+// @Summary Print shelf labels
+// @Description Lay out shelf labels (name, price, barcode) for a category and/or a selection of products on A4 PDF sheets (24 labels per page)
+// @Tags products
+// @Produce  application/pdf
+// @Param category_id query int false "Category ID"
+// @Param product_ids query string false "Comma separated product IDs, e.g. 1,2,3"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Router /products/labels [get]
+func (h *ProductHandler) Labels(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	categoryID := 0
+	if raw := q.Get("category_id"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil {
+			sendError(w, "category_id harus angka", http.StatusBadRequest)
+			return
+		}
+		categoryID = id
+	}
+	var ids []int
+	if raw := q.Get("product_ids"); raw != "" {
+		for _, part := range strings.Split(raw, ",") {
+			id, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil {
+				sendError(w, "product_ids harus daftar angka dipisah koma", http.StatusBadRequest)
+				return
+			}
+			ids = append(ids, id)
+		}
+	}
+
+	products, err := h.service.GetForLabels(categoryID, ids)
+	if err != nil {
+		sendServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", `inline; filename="label-rak.pdf"`)
+	if err := writeShelfLabels(w, products); err != nil {
+		log.Println("Gagal menulis PDF label:", err)
+	}
+}
+
+// LowStock menampilkan produk & varian yang stoknya sudah mencapai batas minimum (min_stock), supaya bisa dipesan
+// sebelum habis. Produk dengan min_stock 0 tidak dipantau.
+// Params: ?outlet_id=N (stok satu outlet, default total semua outlet)
+// @Summary Get low-stock products
+// @Description List products and variants whose stock is at or below their minimum stock, most critical first
+// @Tags products
+// @Produce  json
+// @Param outlet_id query int false "Outlet ID (default total of all outlets)"
+// @Success 200 {array} models.LowStockItem
+// @Failure 400 {object} map[string]string
+// @Router /products/low-stock [get]
+func (h *ProductHandler) LowStock(w http.ResponseWriter, r *http.Request) {
+	outletID, err := queryOutletID(r)
+	if err != nil {
+		sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	items, err := h.service.GetLowStock(outletID)
+	if err != nil {
+		sendServiceError(w, err)
+		return
+	}
+	sendJSON(w, items)
+}
+
+// HandleVariants merutekan endpoint varian:
+// POST /api/v1/products/{id}/variants, PUT & DELETE /api/v1/products/{id}/variants/{variantID}.
+func (h *ProductHandler) HandleVariants(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/v1/products/"), "/")
+	if len(parts) < 2 || len(parts) > 3 || parts[1] != "variants" {
+		sendError(w, "Not found", http.StatusNotFound)
+		return
+	}
+	parentID, err := strconv.Atoi(parts[0])
+	if err != nil {
+		sendError(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	if len(parts) == 2 {
+		if r.Method != "POST" {
+			sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		h.CreateVariant(w, r, parentID)
+		return
+	}
+
+	variantID, err := strconv.Atoi(parts[2])
+	if err != nil {
+		sendError(w, "Invalid variant ID", http.StatusBadRequest)
+		return
+	}
+	switch r.Method {
+	case "PUT":
+		h.UpdateVariant(w, r, parentID, variantID)
+	case "DELETE":
+		h.DeleteVariant(w, parentID, variantID)
+	default:
+		sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// CreateVariant menambah varian ke produk. Stok produk induk harus 0 (stok selanjutnya dicatat per varian).
+// @Summary Add a product variant
+// @Description Add a variant (size, flavour) with its own SKU, barcodes, price and stock under a parent product
+// @Tags products
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Parent product ID"
+// @Param variant body models.ProductVariant true "Variant Data"
+// @Success 200 {object} models.ProductVariant
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /products/{id}/variants [post]
+func (h *ProductHandler) CreateVariant(w http.ResponseWriter, r *http.Request, parentID int) {
+	var variant models.ProductVariant
+	if err := json.NewDecoder(r.Body).Decode(&variant); err != nil {
+		sendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.CreateVariant(parentID, &variant); err != nil {
+		sendServiceError(w, err)
+		return
+	}
+	sendJSON(w, variant)
+}
+
+// @Summary Update a product variant
+// @Description Update a variant's name, SKU, barcodes, price or stock (stock changes are recorded in the stock ledger at outlet_id, like product updates)
+// @Tags products
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Parent product ID"
+// @Param variantID path int true "Variant ID"
+// @Param variant body models.ProductVariant true "Variant Data"
+// @Success 200 {object} models.ProductVariant
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /products/{id}/variants/{variantID} [put]
+func (h *ProductHandler) UpdateVariant(w http.ResponseWriter, r *http.Request, parentID, variantID int) {
+	var variant models.ProductVariant
+	if err := json.NewDecoder(r.Body).Decode(&variant); err != nil {
+		sendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	variant.ID = variantID
+
+	if err := h.service.UpdateVariant(parentID, &variant); err != nil {
+		sendServiceError(w, err)
+		return
+	}
+	sendJSON(w, variant)
+}
+
+// @Summary Delete a product variant
+// @Description Delete a variant (and its barcodes) from a product
+// @Tags products
+// @Produce  json
+// @Param id path int true "Parent product ID"
+// @Param variantID path int true "Variant ID"
+// @Success 200 {boolean} true
+// @Failure 404 {object} map[string]string
+// @Router /products/{id}/variants/{variantID} [delete]
+func (h *ProductHandler) DeleteVariant(w http.ResponseWriter, parentID, variantID int) {
+	if err := h.service.DeleteVariant(parentID, variantID); err != nil {
+		sendServiceError(w, err)
+		return
+	}
+	sendJSON(w, true)
+}
+
 // @Summary Update a product
-// @Description Update an existing product
+// @Description Update an existing product. Variants are managed through /products/{id}/variants; a product with variants keeps stock 0. Stock is the total across outlets; the difference is recorded at outlet_id (default: main outlet), which is required when other outlets hold stock. No outlet may go below zero.
 // @Tags products
 // @Accept  json
 // @Produce  json
@@ -166,7 +606,7 @@ func (h *ProductHandler) Update(w http.ResponseWriter, r *http.Request) {
 	product.ID = id
 
 	if err := h.service.Update(&product); err != nil {
-		sendError(w, err.Error(), http.StatusInternalServerError)
+		sendServiceError(w, err)
 		return
 	}
 	sendJSON(w, product)
@@ -179,6 +619,7 @@ func (h *ProductHandler) Update(w http.ResponseWriter, r *http.Request) {
 // @Produce  json
 // @Param id path int true "Product ID"
 // @Success 200 {boolean} true
+// @Failure 400 {object} map[string]string
 // @Router /products/{id} [delete]
 func (h *ProductHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	idStr := strings.TrimPrefix(r.URL.Path, "/api/v1/products/")
@@ -189,7 +630,7 @@ func (h *ProductHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.service.Delete(id); err != nil {
-		sendError(w, "Failed to delete product", http.StatusInternalServerError)
+		sendServiceError(w, err)
 		return
 	}
 	sendJSON(w, true)
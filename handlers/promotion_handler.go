@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"codeWithUmam/models"
+	"codeWithUmam/services"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// PromotionHandler bertanggung jawab menangani request HTTP terkait promosi/diskon.
+// Promosi yang aktif akan otomatis dihitung saat checkout.
+type PromotionHandler struct {
+	service services.PromotionService
+}
+
+func NewPromotionHandler(service services.PromotionService) *PromotionHandler {
+	return &PromotionHandler{service: service}
+}
+
+// HandlePromotions adalah "router" sederhana di dalam handler ini.
+// Ia menentukan fungsi mana yang dipanggil berdasarkan URL dan Method (GET/POST/dll).
+func (h *PromotionHandler) HandlePromotions(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// Jika URL persis "/api/v1/promotions" -> ini untuk GetAll atau Create.
+	if r.URL.Path == "/api/v1/promotions" {
+		switch r.Method {
+		case "GET":
+			h.GetAll(w, r)
+		case "POST":
+			h.Create(w, r)
+		default:
+			sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	// Jika URL diawali "/api/v1/promotions/" -> berarti ada ID di belakangnya.
+	if strings.HasPrefix(r.URL.Path, "/api/v1/promotions/") {
+		switch r.Method {
+		case "GET":
+			h.GetByID(w, r)
+		case "PUT":
+			h.Update(w, r)
+		case "DELETE":
+			h.Delete(w, r)
+		default:
+			sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+		return
+	}
+	sendError(w, "Not found", http.StatusNotFound)
+}
+
+// GetAll mengambil semua data promosi.
+// @Summary Get all promotions
+// @Description Get list of all promotions (active and inactive)
+// @Tags promotions
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} models.Promotion
+// @Router /promotions [get]
+func (h *PromotionHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	promotions, err := h.service.GetAll()
+	if err != nil {
+		sendError(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	sendJSON(w, promotions)
+}
+
+// Create membuat promosi baru.
+// @Summary Create a new promotion
+// @Description Create a new promotion (ITEM_PERCENTAGE, ITEM_NOMINAL, BUY_X_GET_Y, CART_PERCENTAGE, CART_NOMINAL)
+// @Tags promotions
+// @Accept  json
+// @Produce  json
+// @Param promotion body models.Promotion true "Promotion Data"
+// @Success 200 {object} models.Promotion
+// @Failure 400 {object} map[string]string
+// @Router /promotions [post]
+func (h *PromotionHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var promotion models.Promotion
+	if err := json.NewDecoder(r.Body).Decode(&promotion); err != nil {
+		sendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.Create(&promotion); err != nil {
+		sendServiceError(w, err)
+		return
+	}
+	sendJSON(w, promotion)
+}
+
+// GetByID mengambil satu promosi berdasarkan ID di URL.
+// @Summary Get promotion by ID
+// @Description Get a single promotion by its ID
+// @Tags promotions
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Promotion ID"
+// @Success 200 {object} models.Promotion
+// @Router /promotions/{id} [get]
+func (h *PromotionHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/v1/promotions/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		sendError(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	promotion, err := h.service.GetByID(id)
+	if err != nil {
+		sendError(w, "Promotion not found", http.StatusNotFound)
+		return
+	}
+	sendJSON(w, promotion)
+}
+
+// Update mengubah data promosi yang sudah ada.
+// @Summary Update a promotion
+// @Description Update an existing promotion
+// @Tags promotions
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Promotion ID"
+// @Param promotion body models.Promotion true "Promotion Data"
+// @Success 200 {object} models.Promotion
+// @Failure 400 {object} map[string]string
+// @Router /promotions/{id} [put]
+func (h *PromotionHandler) Update(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/v1/promotions/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		sendError(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	var promotion models.Promotion
+	if err := json.NewDecoder(r.Body).Decode(&promotion); err != nil {
+		sendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	// Pastikan ID di struct sama dengan ID di URL
+	promotion.ID = id
+
+	if err := h.service.Update(&promotion); err != nil {
+		sendServiceError(w, err)
+		return
+	}
+	sendJSON(w, promotion)
+}
+
+// Delete menghapus promosi berdasarkan ID.
+// @Summary Delete a promotion
+// @Description Delete a promotion by ID
+// @Tags promotions
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Promotion ID"
+// @Success 200 {boolean} true
+// @Router /promotions/{id} [delete]
+func (h *PromotionHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/v1/promotions/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		sendError(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.Delete(id); err != nil {
+		sendError(w, "Failed to delete promotion", http.StatusInternalServerError)
+		return
+	}
+	sendJSON(w, true)
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"codeWithUmam/models"
+	"codeWithUmam/services"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// PurchaseOrderHandler menangani request HTTP terkait purchase order (PO) dan penerimaan barang.
+type PurchaseOrderHandler struct {
+	service services.PurchaseService
+}
+
+func NewPurchaseOrderHandler(service services.PurchaseService) *PurchaseOrderHandler {
+	return &PurchaseOrderHandler{service: service}
+}
+
+// HandlePurchaseOrders adalah "router" untuk semua URL purchase order.
+// - GET  /api/v1/purchase-orders              -> GetAll
+// - POST /api/v1/purchase-orders              -> Create
+// - GET  /api/v1/purchase-orders/{id}         -> GetByID
+// - POST /api/v1/purchase-orders/{id}/receive -> Receive
+// - POST /api/v1/purchase-orders/{id}/cancel  -> Cancel
+func (h *PurchaseOrderHandler) HandlePurchaseOrders(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/api/v1/purchase-orders" {
+		switch r.Method {
+		case "GET":
+			h.GetAll(w, r)
+		case "POST":
+			h.Create(w, r)
+		default:
+			sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	// Contoh: "/api/v1/purchase-orders/5/receive" -> ["5", "receive"]
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/purchase-orders/"), "/"), "/")
+	id, err := strconv.Atoi(parts[0])
+	if err != nil {
+		sendError(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	switch {
+	case len(parts) == 1 && r.Method == "GET":
+		h.GetByID(w, id)
+	case len(parts) == 2 && parts[1] == "receive" && r.Method == "POST":
+		h.Receive(w, r, id)
+	case len(parts) == 2 && parts[1] == "cancel" && r.Method == "POST":
+		h.Cancel(w, id)
+	case len(parts) <= 2:
+		sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+	default:
+		sendError(w, "Not found", http.StatusNotFound)
+	}
+}
+
+// GetAll mengambil daftar purchase order.
+// @Summary List purchase orders
+// @Description Get purchase orders, optionally filtered by supplier and status
+// @Tags purchase-orders
+// @Produce  json
+// @Param supplier_id query int false "Supplier ID"
+// @Param status query string false "OPEN, PARTIALLY_RECEIVED, RECEIVED or CANCELLED"
+// @Success 200 {array} models.PurchaseOrder
+// @Router /purchase-orders [get]
+func (h *PurchaseOrderHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	supplierID := 0
+	if raw := r.URL.Query().Get("supplier_id"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil {
+			sendError(w, "Invalid supplier_id", http.StatusBadRequest)
+			return
+		}
+		supplierID = id
+	}
+
+	orders, err := h.service.GetAll(supplierID, strings.ToUpper(r.URL.Query().Get("status")))
+	if err != nil {
+		sendServiceError(w, err)
+		return
+	}
+	sendJSON(w, orders)
+}
+
+// Create membuat purchase order baru.
+// @Summary Create a purchase order
+// @Description Create a purchase order with line items (quantity and unit cost per product)
+// @Tags purchase-orders
+// @Accept  json
+// @Produce  json
+// @Param request body models.CreatePurchaseOrderRequest true "Purchase Order"
+// @Success 200 {object} models.PurchaseOrder
+// @Failure 400 {object} map[string]string
+// @Router /purchase-orders [post]
+func (h *PurchaseOrderHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.CreatePurchaseOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	order, err := h.service.Create(req)
+	if err != nil {
+		sendServiceError(w, err)
+		return
+	}
+	sendJSON(w, order)
+}
+
+// GetByID mengambil satu purchase order lengkap dengan item dan riwayat penerimaannya.
+// @Summary Get purchase order by ID
+// @Description Get a purchase order with items and goods receipts
+// @Tags purchase-orders
+// @Produce  json
+// @Param id path int true "Purchase Order ID"
+// @Success 200 {object} models.PurchaseOrder
+// @Failure 404 {object} map[string]string
+// @Router /purchase-orders/{id} [get]
+func (h *PurchaseOrderHandler) GetByID(w http.ResponseWriter, id int) {
+	order, err := h.service.GetByID(id)
+	if err != nil {
+		sendServiceError(w, err)
+		return
+	}
+	sendJSON(w, order)
+}
+
+// Receive mencatat kedatangan barang (boleh sebagian). Stok produk bertambah.
+// @Summary Receive goods
+// @Description Record a (partial) goods receipt for a purchase order; stock is incremented in the same database transaction
+// @Tags purchase-orders
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Purchase Order ID"
+// @Param request body models.ReceiveGoodsRequest true "Received items"
+// @Success 200 {object} models.PurchaseOrder
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /purchase-orders/{id}/receive [post]
+func (h *PurchaseOrderHandler) Receive(w http.ResponseWriter, r *http.Request, id int) {
+	var req models.ReceiveGoodsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	order, err := h.service.Receive(id, req)
+	if err != nil {
+		sendServiceError(w, err)
+		return
+	}
+	sendJSON(w, order)
+}
+
+// Cancel membatalkan sisa pesanan yang belum datang.
+// @Summary Cancel a purchase order
+// @Description Cancel the outstanding quantities of a purchase order; received goods stay in stock
+// @Tags purchase-orders
+// @Produce  json
+// @Param id path int true "Purchase Order ID"
+// @Success 200 {object} models.PurchaseOrder
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /purchase-orders/{id}/cancel [post]
+func (h *PurchaseOrderHandler) Cancel(w http.ResponseWriter, id int) {
+	order, err := h.service.Cancel(id)
+	if err != nil {
+		sendServiceError(w, err)
+		return
+	}
+	sendJSON(w, order)
+}
@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"bytes"
+	"codeWithUmam/models"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// receiptColumns adalah jumlah karakter per baris (font A) untuk tiap lebar kertas thermal.
+var receiptColumns = map[int]int{
+	models.ReceiptPaper58: 32,
+	models.ReceiptPaper80: 48,
+}
+
+// receiptLine adalah satu baris struk. Layout disusun sekali lalu dicetak ke teks, ESC/POS, atau PDF.
+type receiptLine struct {
+	Text   string
+	Center bool
+	Bold   bool
+}
+
+// buildReceipt menyusun baris-baris struk transaksi dengan lebar columns karakter.
+func buildReceipt(t *models.Transaction, cfg models.ReceiptConfig, columns int) []receiptLine {
+	var lines []receiptLine
+	center := func(text string, bold bool) {
+		for _, l := range wrapText(text, columns) {
+			lines = append(lines, receiptLine{Text: l, Center: true, Bold: bold})
+		}
+	}
+	row := func(left, right string, bold bool) {
+		lines = append(lines, receiptLine{Text: receiptRow(left, right, columns), Bold: bold})
+	}
+	separator := func() {
+		lines = append(lines, receiptLine{Text: strings.Repeat("-", columns)})
+	}
+
+	// Identitas toko
+	if cfg.StoreName != "" {
+		center(cfg.StoreName, true)
+	}
+	for _, h := range cfg.Header {
+		center(h, false)
+	}
+	separator()
+
+	row("No", "#"+strconv.Itoa(t.ID), false)
+	row("Tanggal", t.CreatedAt.Format("02-01-2006 15:04"), false)
+	if t.Cashier != "" {
+		row("Kasir", t.Cashier, false)
+	}
+	separator()
+
+	// Barang
+	for _, d := range t.Details {
+		for _, l := range wrapText(d.ProductName, columns) {
+			lines = append(lines, receiptLine{Text: l})
+		}
+		quantity := strconv.Itoa(d.Quantity)
+		if d.UnitFactor > 1 {
+			quantity += " " + d.Unit // Satuan selain satuan dasar dicetak, misal "2 pak x 14.000"
+		}
+		row("  "+quantity+" x "+formatAmount(d.UnitPrice), formatAmount(d.UnitPrice*d.Quantity), false)
+		if d.DiscountAmount > 0 {
+			row("  Diskon", "-"+formatAmount(d.DiscountAmount), false)
+		}
+		if d.RefundedQuantity > 0 {
+			row("  Dikembalikan", strconv.Itoa(d.RefundedQuantity)+" pcs", false)
+		}
+	}
+	separator()
+
+	// Total
+	included := ""
+	if t.TaxInclusive {
+		included = " (termasuk)"
+	}
+	row("Subtotal", formatAmount(t.GrossAmount), false)
+	if t.DiscountAmount > 0 {
+		row("Diskon", "-"+formatAmount(t.DiscountAmount), false)
+	}
+	if t.ServiceChargeAmount > 0 {
+		row("Service charge"+included, formatAmount(t.ServiceChargeAmount), false)
+	}
+	if t.TaxAmount > 0 {
+		row("Pajak"+included, formatAmount(t.TaxAmount), false)
+	}
+	row("TOTAL", formatAmount(t.TotalAmount), true)
+
+	// Pembayaran
+	if len(t.Payments) > 0 {
+		for _, p := range t.Payments {
+			row(p.Method, formatAmount(p.TenderedAmount), false)
+		}
+	} else if t.PaymentMethod != "" {
+		row(t.PaymentMethod, formatAmount(t.PaidAmount), false)
+	}
+	if t.Change > 0 {
+		row("Kembali", formatAmount(t.Change), false)
+	}
+	if t.RefundedAmount > 0 && t.Status != models.TransactionStatusVoided {
+		row("Refund", "-"+formatAmount(t.RefundedAmount), false)
+	}
+
+	// Status yang membuat struk ini bukan bukti bayar normal
+	switch t.Status {
+	case models.TransactionStatusVoided:
+		separator()
+		center("*** DIBATALKAN ***", true)
+		if t.VoidReason != "" {
+			center(t.VoidReason, false)
+		}
+	case models.TransactionStatusPendingPayment:
+		separator()
+		center("*** BELUM LUNAS ***", true)
+	case models.TransactionStatusExpired:
+		separator()
+		center("*** PEMBAYARAN KEDALUWARSA ***", true)
+	}
+
+	if len(cfg.Footer) > 0 {
+		separator()
+		for _, f := range cfg.Footer {
+			center(f, false)
+		}
+	}
+	return lines
+}
+
+// receiptRow menulis dua kolom: left rata kiri, right rata kanan. Jika tidak muat, left dipotong.
+func receiptRow(left, right string, columns int) string {
+	space := columns - utf8.RuneCountInString(right) - 1
+	if space < 0 {
+		return right
+	}
+	leftRunes := []rune(left)
+	if len(leftRunes) > space {
+		leftRunes = leftRunes[:space]
+	}
+	return string(leftRunes) + strings.Repeat(" ", columns-len(leftRunes)-utf8.RuneCountInString(right)) + right
+}
+
+// wrapText memecah teks per kata supaya setiap baris paling panjang columns karakter.
+func wrapText(text string, columns int) []string {
+	var lines []string
+	current := ""
+	for _, word := range strings.Fields(text) {
+		for utf8.RuneCountInString(word) > columns {
+			if current != "" {
+				lines = append(lines, current)
+				current = ""
+			}
+			runes := []rune(word)
+			lines = append(lines, string(runes[:columns]))
+			word = string(runes[columns:])
+		}
+		switch {
+		case current == "":
+			current = word
+		case utf8.RuneCountInString(current)+1+utf8.RuneCountInString(word) <= columns:
+			current += " " + word
+		default:
+			lines = append(lines, current)
+			current = word
+		}
+	}
+	if current != "" || len(lines) == 0 {
+		lines = append(lines, current)
+	}
+	return lines
+}
+
+// centerText menengahkan teks dalam lebar columns dengan spasi di kiri.
+func centerText(text string, columns int) string {
+	pad := (columns - utf8.RuneCountInString(text)) / 2
+	if pad <= 0 {
+		return text
+	}
+	return strings.Repeat(" ", pad) + text
+}
+
+// writeReceiptText menulis struk sebagai teks polos.
+func writeReceiptText(w io.Writer, lines []receiptLine, columns int) error {
+	var b strings.Builder
+	for _, l := range lines {
+		if l.Center {
+			b.WriteString(centerText(l.Text, columns))
+		} else {
+			b.WriteString(l.Text)
+		}
+		b.WriteByte('\n')
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Perintah ESC/POS yang dipakai (didukung hampir semua printer thermal).
+var (
+	escposInit        = []byte{0x1b, '@'}        // ESC @: reset printer
+	escposAlignLeft   = []byte{0x1b, 'a', 0}     // ESC a 0
+	escposAlignCenter = []byte{0x1b, 'a', 1}     // ESC a 1
+	escposBoldOn      = []byte{0x1b, 'E', 1}     // ESC E 1
+	escposBoldOff     = []byte{0x1b, 'E', 0}     // ESC E 0
+	escposFeed        = []byte{0x1b, 'd', 4}     // ESC d 4: maju 4 baris supaya struk melewati pisau
+	escposCut         = []byte{0x1d, 'V', 66, 0} // GS V 66 0: potong kertas (partial cut)
+)
+
+// writeReceiptESCPOS menulis struk sebagai byte ESC/POS yang bisa langsung dikirim ke printer thermal.
+// Perataan tengah & huruf tebal dikerjakan printer; karakter non-ASCII diganti '?' karena code page printer berbeda-beda.
+func writeReceiptESCPOS(w io.Writer, lines []receiptLine) error {
+	var b bytes.Buffer
+	b.Write(escposInit)
+	for _, l := range lines {
+		if l.Center {
+			b.Write(escposAlignCenter)
+		} else {
+			b.Write(escposAlignLeft)
+		}
+		if l.Bold {
+			b.Write(escposBoldOn)
+		}
+		for _, r := range l.Text {
+			if r < 0x20 || r > 0x7e {
+				r = '?'
+			}
+			b.WriteByte(byte(r))
+		}
+		b.WriteByte('\n')
+		if l.Bold {
+			b.Write(escposBoldOff)
+		}
+	}
+	b.Write(escposAlignLeft)
+	b.Write(escposFeed)
+	b.Write(escposCut)
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// writeReceiptPDF menulis struk sebagai PDF selebar kertas thermal dengan font Courier (lebar karakter tetap),
+// sehingga tampilannya sama dengan hasil cetak printer. Tinggi halaman mengikuti panjang struk.
+func writeReceiptPDF(w io.Writer, lines []receiptLine, paperWidth, columns int) error {
+	const margin = 8.0
+	width := float64(paperWidth) * pdfMMToPt
+	size := (width - 2*margin) / (float64(columns) * 0.6)
+	lineHeight := size * 1.3
+
+	doc := newPDFDocument(width, 2*margin+lineHeight*float64(len(lines)+1))
+	doc.AddPage()
+	for i, l := range lines {
+		text := l.Text
+		if l.Center {
+			text = centerText(text, columns)
+		}
+		font := pdfFontMono
+		if l.Bold {
+			// Courier tebal tidak disediakan, jadi baris tebal ditulis dua kali sedikit bergeser
+			doc.Text(margin+0.3, margin+lineHeight*float64(i+1), font, size, text)
+		}
+		doc.Text(margin, margin+lineHeight*float64(i+1), font, size, text)
+	}
+
+	_, err := doc.WriteTo(w)
+	return err
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"bytes"
+	"codeWithUmam/models"
+	"codeWithUmam/repositories"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func sampleReceiptTransaction() *models.Transaction {
+	return &models.Transaction{
+		ID:             42,
+		CreatedAt:      time.Date(2026, 3, 9, 14, 5, 0, 0, time.UTC),
+		Cashier:        "budi",
+		GrossAmount:    35000,
+		DiscountAmount: 3000,
+		TaxAmount:      3520,
+		TotalAmount:    35520,
+		PaidAmount:     35520,
+		Change:         14480,
+		Status:         models.TransactionStatusCompleted,
+		Details: []models.TransactionDetail{
+			{ProductName: "Kopi Susu Gula Aren Ukuran Jumbo Dengan Extra Shot", Quantity: 2, UnitPrice: 15000, DiscountAmount: 3000},
+			{ProductName: "Roti Bakar", Quantity: 1, UnitPrice: 5000},
+		},
+		Payments: []models.Payment{{Method: "CASH", Amount: 35520, TenderedAmount: 50000}},
+	}
+}
+
+func TestBuildReceipt_Text(t *testing.T) {
+	cfg := models.ReceiptConfig{StoreName: "Toko Umam", Header: []string{"Jl. Merdeka 1"}, Footer: []string{"Terima kasih"}}
+	for paper, columns := range receiptColumns {
+		var buf bytes.Buffer
+		if err := writeReceiptText(&buf, buildReceipt(sampleReceiptTransaction(), cfg, columns), columns); err != nil {
+			t.Fatalf("writeReceiptText failed: %v", err)
+		}
+		text := buf.String()
+		for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+			if utf8.RuneCountInString(line) > columns {
+				t.Errorf("%dmm: line wider than %d columns: %q", paper, columns, line)
+			}
+		}
+		for _, want := range []string{"Toko Umam", "Jl. Merdeka 1", "#42", "09-03-2026 14:05", "30.000", "-3.000", "35.520", "50.000", "14.480", "Terima kasih"} {
+			if !strings.Contains(text, want) {
+				t.Errorf("%dmm: receipt missing %q:\n%s", paper, want, text)
+			}
+		}
+		if !strings.Contains(text, receiptRow("TOTAL", "35.520", columns)) {
+			t.Errorf("%dmm: expected TOTAL aligned to the right edge:\n%s", paper, text)
+		}
+	}
+
+	// Satuan selain satuan dasar ikut dicetak
+	packed := sampleReceiptTransaction()
+	packed.Details[1].Unit, packed.Details[1].UnitFactor = "pak", 5
+	packed.Details[0].Unit, packed.Details[0].UnitFactor = "pcs", 1
+	var packedText bytes.Buffer
+	writeReceiptText(&packedText, buildReceipt(packed, cfg, 32), 32)
+	if !strings.Contains(packedText.String(), "  1 pak x 5.000") || strings.Contains(packedText.String(), "2 pcs") {
+		t.Errorf("expected only the non-base unit to be printed:\n%s", packedText.String())
+	}
+
+	voided := sampleReceiptTransaction()
+	voided.Status, voided.VoidReason = models.TransactionStatusVoided, "salah input"
+	var buf bytes.Buffer
+	writeReceiptText(&buf, buildReceipt(voided, models.ReceiptConfig{}, 32), 32)
+	if !strings.Contains(buf.String(), "*** DIBATALKAN ***") {
+		t.Errorf("expected voided receipt to be marked:\n%s", buf.String())
+	}
+}
+
+func TestWriteReceiptESCPOS(t *testing.T) {
+	lines := buildReceipt(sampleReceiptTransaction(), models.ReceiptConfig{StoreName: "Toko Umam"}, 32)
+	var buf bytes.Buffer
+	if err := writeReceiptESCPOS(&buf, lines); err != nil {
+		t.Fatalf("writeReceiptESCPOS failed: %v", err)
+	}
+	out := buf.Bytes()
+	if !bytes.HasPrefix(out, escposInit) || !bytes.HasSuffix(out, escposCut) {
+		t.Errorf("expected init and cut commands, got %q", out)
+	}
+	// Nama toko dicetak tengah & tebal
+	storeLine := append(append(append([]byte{}, escposAlignCenter...), escposBoldOn...), []byte("Toko Umam\n")...)
+	if !bytes.Contains(out, storeLine) {
+		t.Errorf("expected centered bold store name, got %q", out)
+	}
+}
+
+func TestWriteReceiptPDF(t *testing.T) {
+	lines := buildReceipt(sampleReceiptTransaction(), models.ReceiptConfig{}, 48)
+	var buf bytes.Buffer
+	if err := writeReceiptPDF(&buf, lines, models.ReceiptPaper80, 48); err != nil {
+		t.Fatalf("writeReceiptPDF failed: %v", err)
+	}
+	pdf := buf.String()
+	if !strings.HasPrefix(pdf, "%PDF-1.4") || !strings.Contains(pdf, "/MediaBox [0 0 226.77") || !strings.Contains(pdf, "/F3") {
+		t.Errorf("unexpected receipt pdf: %.300s", pdf)
+	}
+}
+
+func TestTransactionHandler_HandleReceipt(t *testing.T) {
+	mockService := &MockTransactionService{
+		GetReceiptFunc: func(id int) (*models.Transaction, error) {
+			if id != 42 {
+				return nil, repositories.ErrNotFound
+			}
+			return sampleReceiptTransaction(), nil
+		},
+	}
+	handler := NewTransactionHandler(mockService, models.ReceiptConfig{StoreName: "Toko Umam", PaperWidth: models.ReceiptPaper80})
+
+	tests := []struct {
+		url         string
+		status      int
+		contentType string
+	}{
+		{"/api/transactions/42/receipt", http.StatusOK, "text/plain; charset=utf-8"},
+		{"/api/transactions/42/receipt?format=escpos&width=58", http.StatusOK, "application/octet-stream"},
+		{"/api/transactions/42/receipt?format=pdf", http.StatusOK, "application/pdf"},
+		{"/api/transactions/42/receipt?format=html", http.StatusBadRequest, ""},
+		{"/api/transactions/42/receipt?width=76", http.StatusBadRequest, ""},
+		{"/api/transactions/7/receipt", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest("GET", tt.url, nil)
+		rr := httptest.NewRecorder()
+		handler.HandleTransactionByID(rr, req)
+		if rr.Code != tt.status {
+			t.Errorf("%s: got status %d, want %d", tt.url, rr.Code, tt.status)
+		}
+		if tt.contentType != "" && rr.Header().Get("Content-Type") != tt.contentType {
+			t.Errorf("%s: got content type %q", tt.url, rr.Header().Get("Content-Type"))
+		}
+	}
+
+	// Default lebar kertas dari konfigurasi (80mm = 48 kolom)
+	req, _ := http.NewRequest("GET", "/api/transactions/42/receipt", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleTransactionByID(rr, req)
+	if first := strings.SplitN(rr.Body.String(), "\n", 2)[0]; first != centerText("Toko Umam", 48) {
+		t.Errorf("expected store name centered in 48 columns, got %q", first)
+	}
+}
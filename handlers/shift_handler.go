@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"codeWithUmam/models"
+	"codeWithUmam/services"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ShiftHandler menangani request HTTP untuk shift kasir dan Z-report.
+type ShiftHandler struct {
+	service services.ShiftService
+}
+
+func NewShiftHandler(service services.ShiftService) *ShiftHandler {
+	return &ShiftHandler{service: service}
+}
+
+// HandleShifts adalah "router" untuk semua URL shift.
+// - GET  /api/v1/shifts            -> GetAll
+// - POST /api/v1/shifts            -> Open
+// - GET  /api/v1/shifts/{id}       -> GetByID (X-report / Z-report)
+// - POST /api/v1/shifts/{id}/close -> Close
+func (h *ShiftHandler) HandleShifts(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/api/v1/shifts" {
+		switch r.Method {
+		case "GET":
+			h.GetAll(w, r)
+		case "POST":
+			h.Open(w, r)
+		default:
+			sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	// Contoh: "/api/v1/shifts/3/close" -> ["3", "close"]
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/shifts/"), "/"), "/")
+	id, err := strconv.Atoi(parts[0])
+	if err != nil {
+		sendError(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	switch {
+	case len(parts) == 1 && r.Method == "GET":
+		h.GetByID(w, id)
+	case len(parts) == 2 && parts[1] == "close" && r.Method == "POST":
+		h.Close(w, r, id)
+	case len(parts) <= 2:
+		sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+	default:
+		sendError(w, "Not found", http.StatusNotFound)
+	}
+}
+
+// GetAll mengambil daftar shift.
+// @Summary List shifts
+// @Description Get cashier shifts, optionally filtered by status and cashier
+// @Tags shifts
+// @Produce  json
+// @Param status  query string false "OPEN or CLOSED"
+// @Param cashier query string false "Cashier name"
+// @Success 200 {array} models.Shift
+// @Failure 400 {object} map[string]string
+// @Router /shifts [get]
+func (h *ShiftHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	shifts, err := h.service.GetAll(r.URL.Query().Get("status"), r.URL.Query().Get("cashier"))
+	if err != nil {
+		sendServiceError(w, err)
+		return
+	}
+	sendJSON(w, shifts)
+}
+
+// Open membuka shift kasir dengan modal awal.
+// @Summary Open a shift
+// @Description Open a cash drawer shift for a cashier with an opening float; checkouts with this cashier are recorded to the shift
+// @Tags shifts
+// @Accept  json
+// @Produce  json
+// @Param request body models.OpenShiftRequest true "Cashier and opening float"
+// @Success 200 {object} models.Shift
+// @Failure 400 {object} map[string]string
+// @Router /shifts [post]
+func (h *ShiftHandler) Open(w http.ResponseWriter, r *http.Request) {
+	var req models.OpenShiftRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	shift, err := h.service.Open(req)
+	if err != nil {
+		sendServiceError(w, err)
+		return
+	}
+	sendJSON(w, shift)
+}
+
+// GetByID mengambil shift beserta laporannya (X-report jika masih buka, Z-report jika sudah tutup).
+// @Summary Get shift
+// @Description Get a shift with its running X-report, or its frozen Z-report once closed
+// @Tags shifts
+// @Produce  json
+// @Param id path int true "Shift ID"
+// @Success 200 {object} models.Shift
+// @Failure 404 {object} map[string]string
+// @Router /shifts/{id} [get]
+func (h *ShiftHandler) GetByID(w http.ResponseWriter, id int) {
+	shift, err := h.service.GetByID(id)
+	if err != nil {
+		sendServiceError(w, err)
+		return
+	}
+	sendJSON(w, shift)
+}
+
+// Close menutup shift: uang hasil hitung dibandingkan dengan uang yang seharusnya ada, lalu Z-report dibekukan.
+// @Summary Close a shift
+// @Description Close a shift with counted cash; produces an immutable Z-report with expected vs counted per tender, variance and void count
+// @Tags shifts
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Shift ID"
+// @Param request body models.CloseShiftRequest true "Counted cash"
+// @Success 200 {object} models.Shift
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /shifts/{id}/close [post]
+func (h *ShiftHandler) Close(w http.ResponseWriter, r *http.Request, id int) {
+	var req models.CloseShiftRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	shift, err := h.service.Close(id, req)
+	if err != nil {
+		sendServiceError(w, err)
+		return
+	}
+	sendJSON(w, shift)
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"codeWithUmam/services"
+)
+
+// StockHandler menangani request HTTP terkait ledger stok.
+type StockHandler struct {
+	service services.StockService
+}
+
+func NewStockHandler(service services.StockService) *StockHandler {
+	return &StockHandler{service: service}
+}
+
+// HandleMovements mengambil riwayat pergerakan stok satu produk.
+// Endpoint: GET /api/v1/stock-movements?product_id=1
+// @Summary      List stock movements
+// @Description  Get the stock ledger of a product with running balance
+// @Tags         stock
+// @Produce      json
+// @Param        product_id query int true "Product ID"
+// @Success      200  {array}   models.StockMovement
+// @Failure      400  {object}  map[string]string
+// @Router       /stock-movements [get]
+func (h *StockHandler) HandleMovements(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	productID, err := strconv.Atoi(r.URL.Query().Get("product_id"))
+	if err != nil {
+		sendError(w, "Invalid product_id", http.StatusBadRequest)
+		return
+	}
+
+	movements, err := h.service.GetMovements(productID)
+	if err != nil {
+		sendServiceError(w, err)
+		return
+	}
+	sendJSON(w, movements)
+}
+
+// HandleConsistency menghitung ulang stok dari ledger dan melaporkan produk yang tidak cocok.
+// Endpoint: GET /api/v1/stock-movements/consistency
+// @Summary      Check stock consistency
+// @Description  Recompute stock from the ledger and list products whose stock column does not match
+// @Tags         stock
+// @Produce      json
+// @Success      200  {object}  models.StockConsistencyReport
+// @Router       /stock-movements/consistency [get]
+func (h *StockHandler) HandleConsistency(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	report, err := h.service.CheckConsistency()
+	if err != nil {
+		sendServiceError(w, err)
+		return
+	}
+	sendJSON(w, report)
+}
+
+// HandleBatches mengambil batch (lot) produk ber-batch yang masih bersisa, urut tanggal kedaluwarsa.
+// Endpoint: GET /api/v1/stock-batches?product_id=1
+// @Summary      List stock batches
+// @Description  Get the remaining batches of a batch-tracked product in first-expiry-first-out order, flagging expired ones
+// @Tags         stock
+// @Produce      json
+// @Param        product_id query int true "Product ID"
+// @Success      200  {array}   models.StockBatch
+// @Failure      400  {object}  map[string]string
+// @Router       /stock-batches [get]
+func (h *StockHandler) HandleBatches(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	productID, err := strconv.Atoi(r.URL.Query().Get("product_id"))
+	if err != nil {
+		sendError(w, "Invalid product_id", http.StatusBadRequest)
+		return
+	}
+
+	batches, err := h.service.GetBatches(productID)
+	if err != nil {
+		sendServiceError(w, err)
+		return
+	}
+	sendJSON(w, batches)
+}
+
+// HandleExpiringReport menangani request laporan stok yang akan kedaluwarsa.
+// Endpoint: GET /api/reports/expiring
+// Params: ?days=N (Optional, default 30). Batch yang sudah kedaluwarsa ikut ditampilkan (days_left negatif).
+// @Summary      Get Expiring Stock Report
+// @Description  Get remaining batches that expire within N days (including already expired ones), grouped per category
+// @Tags         stock
+// @Produce      json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param        days   query int    false "Days ahead (default 30, max 365)"
+// @Param        format query string false "json (default), csv or xlsx"
+// @Success      200  {object}  models.ExpiringStockReport
+// @Failure      400  {object}  map[string]string
+// @Router       /reports/expiring [get]
+func (h *StockHandler) HandleExpiringReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	format, err := exportFormat(r)
+	if err != nil {
+		sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	days := 0
+	if raw := r.URL.Query().Get("days"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			sendError(w, "days harus berupa angka lebih dari 0", http.StatusBadRequest)
+			return
+		}
+		days = n
+	}
+
+	report, err := h.service.GetExpiringReport(days)
+	if err != nil {
+		sendServiceError(w, err)
+		return
+	}
+
+	if format != formatJSON {
+		var rows [][]interface{}
+		for _, c := range report.Categories {
+			for _, b := range c.Batches {
+				rows = append(rows, []interface{}{c.CategoryName, b.ProductID, b.ProductName, b.BatchNumber, b.ExpiryDate, b.DaysLeft, b.Quantity, b.Value})
+			}
+		}
+		writeTable(w, format, "stok-kedaluwarsa_"+report.Today+"_"+report.Until,
+			[]string{"category", "product_id", "product", "batch_number", "expiry_date", "days_left", "quantity", "value"}, rows)
+		return
+	}
+
+	sendJSON(w, report)
+}
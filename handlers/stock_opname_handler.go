@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"codeWithUmam/models"
+	"codeWithUmam/services"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// StockOpnameHandler menangani request HTTP untuk stok opname (hitung fisik bulanan).
+type StockOpnameHandler struct {
+	service services.StockOpnameService
+}
+
+func NewStockOpnameHandler(service services.StockOpnameService) *StockOpnameHandler {
+	return &StockOpnameHandler{service: service}
+}
+
+// HandleStockOpnames adalah "router" untuk semua URL stok opname.
+// - GET  /api/v1/stock-opnames              -> GetAll
+// - POST /api/v1/stock-opnames              -> Open
+// - GET  /api/v1/stock-opnames/{id}         -> GetByID
+// - POST /api/v1/stock-opnames/{id}/counts  -> SubmitCounts
+// - POST /api/v1/stock-opnames/{id}/approve -> Approve
+// - POST /api/v1/stock-opnames/{id}/cancel  -> Cancel
+func (h *StockOpnameHandler) HandleStockOpnames(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/api/v1/stock-opnames" {
+		switch r.Method {
+		case "GET":
+			h.GetAll(w, r)
+		case "POST":
+			h.Open(w, r)
+		default:
+			sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	// Contoh: "/api/v1/stock-opnames/3/counts" -> ["3", "counts"]
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/stock-opnames/"), "/"), "/")
+	id, err := strconv.Atoi(parts[0])
+	if err != nil {
+		sendError(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	switch {
+	case len(parts) == 1 && r.Method == "GET":
+		h.GetByID(w, id)
+	case len(parts) == 2 && parts[1] == "counts" && r.Method == "POST":
+		h.SubmitCounts(w, r, id)
+	case len(parts) == 2 && parts[1] == "approve" && r.Method == "POST":
+		h.Approve(w, r, id)
+	case len(parts) == 2 && parts[1] == "cancel" && r.Method == "POST":
+		h.Cancel(w, id)
+	case len(parts) <= 2:
+		sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+	default:
+		sendError(w, "Not found", http.StatusNotFound)
+	}
+}
+
+// GetAll mengambil semua sesi stok opname.
+// @Summary List stock opname sessions
+// @Description Get all stock count sessions with their variance summary
+// @Tags stock-opnames
+// @Produce  json
+// @Success 200 {array} models.StockOpname
+// @Router /stock-opnames [get]
+func (h *StockOpnameHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	opnames, err := h.service.GetAll()
+	if err != nil {
+		sendServiceError(w, err)
+		return
+	}
+	sendJSON(w, opnames)
+}
+
+// Open membuka sesi stok opname dan men-snapshot stok produk.
+// @Summary Open a stock opname session
+// @Description Snapshot current stock of all products (or one category) and start counting; checkout keeps working during the count
+// @Tags stock-opnames
+// @Accept  json
+// @Produce  json
+// @Param request body models.OpenStockOpnameRequest true "Session info"
+// @Success 200 {object} models.StockOpname
+// @Failure 400 {object} map[string]string
+// @Router /stock-opnames [post]
+func (h *StockOpnameHandler) Open(w http.ResponseWriter, r *http.Request) {
+	var req models.OpenStockOpnameRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	opname, err := h.service.Open(req)
+	if err != nil {
+		sendServiceError(w, err)
+		return
+	}
+	sendJSON(w, opname)
+}
+
+// GetByID mengambil satu sesi lengkap dengan selisih per produk dan per kategori.
+// @Summary Get stock opname session
+// @Description Get a stock count session with counts and variances per product and category
+// @Tags stock-opnames
+// @Produce  json
+// @Param id path int true "Stock Opname ID"
+// @Success 200 {object} models.StockOpname
+// @Failure 404 {object} map[string]string
+// @Router /stock-opnames/{id} [get]
+func (h *StockOpnameHandler) GetByID(w http.ResponseWriter, id int) {
+	opname, err := h.service.GetByID(id)
+	if err != nil {
+		sendServiceError(w, err)
+		return
+	}
+	sendJSON(w, opname)
+}
+
+// SubmitCounts menyimpan hasil hitungan seorang kasir.
+// @Summary Submit counted quantities
+// @Description Submit a cashier's counted quantities; counts from different cashiers for the same product are summed
+// @Tags stock-opnames
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Stock Opname ID"
+// @Param request body models.SubmitStockCountRequest true "Counted quantities"
+// @Success 200 {object} models.StockOpname
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /stock-opnames/{id}/counts [post]
+func (h *StockOpnameHandler) SubmitCounts(w http.ResponseWriter, r *http.Request, id int) {
+	var req models.SubmitStockCountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	opname, err := h.service.SubmitCounts(id, req)
+	if err != nil {
+		sendServiceError(w, err)
+		return
+	}
+	sendJSON(w, opname)
+}
+
+// Approve memposting selisih ke stok sebagai pergerakan ADJUSTMENT.
+// @Summary Approve stock opname
+// @Description Post variances of counted products as stock adjustment movements
+// @Tags stock-opnames
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Stock Opname ID"
+// @Param request body models.ApproveStockOpnameRequest true "Approver"
+// @Success 200 {object} models.StockOpname
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /stock-opnames/{id}/approve [post]
+func (h *StockOpnameHandler) Approve(w http.ResponseWriter, r *http.Request, id int) {
+	var req models.ApproveStockOpnameRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	opname, err := h.service.Approve(id, req)
+	if err != nil {
+		sendServiceError(w, err)
+		return
+	}
+	sendJSON(w, opname)
+}
+
+// Cancel membatalkan sesi tanpa mengubah stok.
+// @Summary Cancel stock opname
+// @Description Cancel an open stock count session without touching stock
+// @Tags stock-opnames
+// @Produce  json
+// @Param id path int true "Stock Opname ID"
+// @Success 200 {object} models.StockOpname
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /stock-opnames/{id}/cancel [post]
+func (h *StockOpnameHandler) Cancel(w http.ResponseWriter, id int) {
+	opname, err := h.service.Cancel(id)
+	if err != nil {
+		sendServiceError(w, err)
+		return
+	}
+	sendJSON(w, opname)
+}
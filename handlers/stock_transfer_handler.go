@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"codeWithUmam/models"
+	"codeWithUmam/services"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// StockTransferHandler menangani request HTTP terkait transfer stok antar outlet.
+type StockTransferHandler struct {
+	service services.StockTransferService
+}
+
+func NewStockTransferHandler(service services.StockTransferService) *StockTransferHandler {
+	return &StockTransferHandler{service: service}
+}
+
+// HandleStockTransfers adalah "router" untuk semua URL transfer stok.
+// - GET  /api/v1/stock-transfers              -> GetAll
+// - POST /api/v1/stock-transfers              -> Create
+// - GET  /api/v1/stock-transfers/{id}         -> GetByID
+// - POST /api/v1/stock-transfers/{id}/receive -> Receive
+// - POST /api/v1/stock-transfers/{id}/cancel  -> Cancel
+func (h *StockTransferHandler) HandleStockTransfers(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/api/v1/stock-transfers" {
+		switch r.Method {
+		case "GET":
+			h.GetAll(w, r)
+		case "POST":
+			h.Create(w, r)
+		default:
+			sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	// Contoh: "/api/v1/stock-transfers/5/receive" -> ["5", "receive"]
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/stock-transfers/"), "/"), "/")
+	id, err := strconv.Atoi(parts[0])
+	if err != nil {
+		sendError(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	switch {
+	case len(parts) == 1 && r.Method == "GET":
+		h.GetByID(w, id)
+	case len(parts) == 2 && parts[1] == "receive" && r.Method == "POST":
+		h.Receive(w, r, id)
+	case len(parts) == 2 && parts[1] == "cancel" && r.Method == "POST":
+		h.Cancel(w, id)
+	case len(parts) <= 2:
+		sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+	default:
+		sendError(w, "Not found", http.StatusNotFound)
+	}
+}
+
+// GetAll mengambil daftar transfer stok.
+// @Summary List stock transfers
+// @Description Get stock transfers, optionally filtered by outlet (as source or destination) and status
+// @Tags stock-transfers
+// @Produce  json
+// @Param outlet_id query int false "Outlet ID"
+// @Param status query string false "IN_TRANSIT, RECEIVED or CANCELLED"
+// @Success 200 {array} models.StockTransfer
+// @Failure 400 {object} map[string]string
+// @Router /stock-transfers [get]
+func (h *StockTransferHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	outletID, err := queryOutletID(r)
+	if err != nil {
+		sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	transfers, err := h.service.GetAll(outletID, strings.ToUpper(r.URL.Query().Get("status")))
+	if err != nil {
+		sendServiceError(w, err)
+		return
+	}
+	sendJSON(w, transfers)
+}
+
+// Create mengirim stok dari satu outlet ke outlet lain. Stok outlet asal langsung berkurang.
+// @Summary Create a stock transfer
+// @Description Send stock from one outlet to another; stock leaves the source outlet immediately and stays in transit until received
+// @Tags stock-transfers
+// @Accept  json
+// @Produce  json
+// @Param request body models.CreateStockTransferRequest true "Stock Transfer"
+// @Success 200 {object} models.StockTransfer
+// @Failure 400 {object} map[string]string
+// @Router /stock-transfers [post]
+func (h *StockTransferHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateStockTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	transfer, err := h.service.Create(req)
+	if err != nil {
+		sendServiceError(w, err)
+		return
+	}
+	sendJSON(w, transfer)
+}
+
+// GetByID mengambil satu transfer lengkap dengan item-itemnya.
+// @Summary Get stock transfer by ID
+// @Description Get a stock transfer with its items
+// @Tags stock-transfers
+// @Produce  json
+// @Param id path int true "Stock Transfer ID"
+// @Success 200 {object} models.StockTransfer
+// @Failure 404 {object} map[string]string
+// @Router /stock-transfers/{id} [get]
+func (h *StockTransferHandler) GetByID(w http.ResponseWriter, id int) {
+	transfer, err := h.service.GetByID(id)
+	if err != nil {
+		sendServiceError(w, err)
+		return
+	}
+	sendJSON(w, transfer)
+}
+
+// Receive mencatat transfer sudah sampai. Stok outlet tujuan bertambah.
+// @Summary Receive a stock transfer
+// @Description Mark an in-transit transfer as received; stock is added to the destination outlet
+// @Tags stock-transfers
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Stock Transfer ID"
+// @Param request body models.ReceiveStockTransferRequest true "Receiver"
+// @Success 200 {object} models.StockTransfer
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /stock-transfers/{id}/receive [post]
+func (h *StockTransferHandler) Receive(w http.ResponseWriter, r *http.Request, id int) {
+	var req models.ReceiveStockTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	transfer, err := h.service.Receive(id, req)
+	if err != nil {
+		sendServiceError(w, err)
+		return
+	}
+	sendJSON(w, transfer)
+}
+
+// Cancel membatalkan transfer yang masih di perjalanan. Stok kembali ke outlet asal.
+// @Summary Cancel a stock transfer
+// @Description Cancel an in-transit transfer; stock returns to the source outlet
+// @Tags stock-transfers
+// @Produce  json
+// @Param id path int true "Stock Transfer ID"
+// @Success 200 {object} models.StockTransfer
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /stock-transfers/{id}/cancel [post]
+func (h *StockTransferHandler) Cancel(w http.ResponseWriter, id int) {
+	transfer, err := h.service.Cancel(id)
+	if err != nil {
+		sendServiceError(w, err)
+		return
+	}
+	sendJSON(w, transfer)
+}
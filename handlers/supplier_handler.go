@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"codeWithUmam/models"
+	"codeWithUmam/services"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// SupplierHandler bertanggung jawab menangani request HTTP terkait supplier (pemasok barang).
+type SupplierHandler struct {
+	service services.SupplierService
+}
+
+func NewSupplierHandler(service services.SupplierService) *SupplierHandler {
+	return &SupplierHandler{service: service}
+}
+
+// HandleSuppliers adalah "router" sederhana di dalam handler ini.
+// Ia menentukan fungsi mana yang dipanggil berdasarkan URL dan Method (GET/POST/dll).
+func (h *SupplierHandler) HandleSuppliers(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// Jika URL persis "/api/v1/suppliers" -> ini untuk GetAll atau Create.
+	if r.URL.Path == "/api/v1/suppliers" {
+		switch r.Method {
+		case "GET":
+			h.GetAll(w, r)
+		case "POST":
+			h.Create(w, r)
+		default:
+			sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	// Jika URL diawali "/api/v1/suppliers/" -> berarti ada ID di belakangnya.
+	if strings.HasPrefix(r.URL.Path, "/api/v1/suppliers/") {
+		switch r.Method {
+		case "GET":
+			h.GetByID(w, r)
+		case "PUT":
+			h.Update(w, r)
+		case "DELETE":
+			h.Delete(w, r)
+		default:
+			sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+		return
+	}
+	sendError(w, "Not found", http.StatusNotFound)
+}
+
+// GetAll mengambil semua data supplier.
+// @Summary Get all suppliers
+// @Description Get list of all suppliers
+// @Tags suppliers
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} models.Supplier
+// @Router /suppliers [get]
+func (h *SupplierHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	suppliers, err := h.service.GetAll()
+	if err != nil {
+		sendError(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	sendJSON(w, suppliers)
+}
+
+// Create membuat supplier baru.
+// @Summary Create a new supplier
+// @Description Create a new supplier
+// @Tags suppliers
+// @Accept  json
+// @Produce  json
+// @Param supplier body models.Supplier true "Supplier Data"
+// @Success 200 {object} models.Supplier
+// @Failure 400 {object} map[string]string
+// @Router /suppliers [post]
+func (h *SupplierHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var supplier models.Supplier
+	if err := json.NewDecoder(r.Body).Decode(&supplier); err != nil {
+		sendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.Create(&supplier); err != nil {
+		sendServiceError(w, err)
+		return
+	}
+	sendJSON(w, supplier)
+}
+
+// GetByID mengambil satu supplier berdasarkan ID di URL.
+// @Summary Get supplier by ID
+// @Description Get a single supplier by its ID
+// @Tags suppliers
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Supplier ID"
+// @Success 200 {object} models.Supplier
+// @Failure 404 {object} map[string]string
+// @Router /suppliers/{id} [get]
+func (h *SupplierHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/v1/suppliers/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		sendError(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	supplier, err := h.service.GetByID(id)
+	if err != nil {
+		sendServiceError(w, err)
+		return
+	}
+	sendJSON(w, supplier)
+}
+
+// Update mengubah data supplier yang sudah ada.
+// @Summary Update a supplier
+// @Description Update an existing supplier
+// @Tags suppliers
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Supplier ID"
+// @Param supplier body models.Supplier true "Supplier Data"
+// @Success 200 {object} models.Supplier
+// @Failure 400 {object} map[string]string
+// @Router /suppliers/{id} [put]
+func (h *SupplierHandler) Update(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/v1/suppliers/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		sendError(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	var supplier models.Supplier
+	if err := json.NewDecoder(r.Body).Decode(&supplier); err != nil {
+		sendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	// Pastikan ID di struct sama dengan ID di URL
+	supplier.ID = id
+
+	if err := h.service.Update(&supplier); err != nil {
+		sendServiceError(w, err)
+		return
+	}
+	sendJSON(w, supplier)
+}
+
+// Delete menghapus supplier berdasarkan ID.
+// @Summary Delete a supplier
+// @Description Delete a supplier by ID (suppliers with purchase orders cannot be deleted)
+// @Tags suppliers
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Supplier ID"
+// @Success 200 {boolean} true
+// @Failure 400 {object} map[string]string
+// @Router /suppliers/{id} [delete]
+func (h *SupplierHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/v1/suppliers/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		sendError(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.Delete(id); err != nil {
+		sendServiceError(w, err)
+		return
+	}
+	sendJSON(w, true)
+}
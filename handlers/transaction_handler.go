@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
+	"log"
 	"net/http"
 	"strconv"
 	"strings"
@@ -16,24 +18,33 @@ type TransactionHandler struct {
 	// Kita bergantung pada Interface, bukan struct konkret.
 	// Ini membuat code "Loosely Coupled" dan mudah di-test (Mocking).
 	service services.TransactionService
+
+	// receipt berisi identitas toko (header/footer) untuk struk yang dicetak.
+	receipt models.ReceiptConfig
 }
 
 // NewTransactionHandler adalah Constructor.
-func NewTransactionHandler(service services.TransactionService) *TransactionHandler {
-	return &TransactionHandler{service: service}
+func NewTransactionHandler(service services.TransactionService, receipt models.ReceiptConfig) *TransactionHandler {
+	if _, ok := receiptColumns[receipt.PaperWidth]; !ok {
+		receipt.PaperWidth = models.ReceiptPaper58
+	}
+	return &TransactionHandler{service: service, receipt: receipt}
 }
 
 // HandleCheckout menangani request pembelian barang.
 // Endpoint: POST /api/checkout
 // Body JSON: { "items": [ { "product_id": 1, "quantity": 2 } ] }
+// Split payment: { "items": [...], "payments": [ { "method": "QRIS", "amount": 50000 }, { "method": "CASH", "amount": 30000 } ] }
 // @Summary      Checkout Transaction
 // @Description  Create a new transaction with items and payment info
 // @Tags         transactions
 // @Accept       json
 // @Produce      json
 // @Param        request body models.CheckoutRequest true "Checkout Request"
+// @Param        Idempotency-Key header string false "Unique key per checkout attempt; retries with the same key return the original transaction"
 // @Success      200  {object}  models.Transaction
 // @Failure      400  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
 // @Failure      500  {object}  map[string]string
 // @Router       /checkout [post]
 func (h *TransactionHandler) HandleCheckout(w http.ResponseWriter, r *http.Request) {
@@ -50,11 +61,15 @@ func (h *TransactionHandler) HandleCheckout(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	// Header Idempotency-Key (opsional): tablet kasir mengirim key yang sama saat me-retry request
+	// supaya checkout yang sama tidak tercatat dua kali.
+	req.IdempotencyKey = strings.TrimSpace(r.Header.Get("Idempotency-Key"))
+
 	// Panggil Service untuk proses checkout
 	transaction, err := h.service.Checkout(req)
 	if err != nil {
-		// Jika ada error (misal stok habis), kirim response 500
-		sendError(w, err.Error(), http.StatusInternalServerError)
+		// Jika ada error (misal stok habis), kirim response error sesuai jenisnya
+		sendServiceError(w, err)
 		return
 	}
 
@@ -65,11 +80,14 @@ func (h *TransactionHandler) HandleCheckout(w http.ResponseWriter, r *http.Reque
 // HandleDailyReport menangani request laporan harian.
 // Endpoint: GET /api/report/hari-ini
 // Digunakan oleh Owner/Manajer untuk melihat omset hari ini.
+// Params: ?outlet_id=N (Optional, default semua outlet)
 // @Summary      Get Daily Report
 // @Description  Get sales summary for today
 // @Tags         transactions
 // @Produce      json
+// @Param        outlet_id query int false "Outlet ID (default all outlets)"
 // @Success      200  {object}  models.SalesSummary
+// @Failure      400  {object}  map[string]string
 // @Router       /report/hari-ini [get]
 func (h *TransactionHandler) HandleDailyReport(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -77,7 +95,13 @@ func (h *TransactionHandler) HandleDailyReport(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	summary, err := h.service.GetDailyReport()
+	outletID, err := queryOutletID(r)
+	if err != nil {
+		sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	summary, err := h.service.GetDailyReport(outletID)
 	if err != nil {
 		sendError(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -86,16 +110,275 @@ func (h *TransactionHandler) HandleDailyReport(w http.ResponseWriter, r *http.Re
 	sendJSON(w, summary)
 }
 
+// HandleProfitReport menangani request laporan laba kotor.
+// Endpoint: GET /api/report/laba
+// Params: ?from=YYYY-MM-DD&to=YYYY-MM-DD (Optional, default hari ini)&outlet_id=N (Optional, default semua outlet)
+// Pendapatan, HPP dan margin dirinci per produk, per kategori, dan per hari.
+// @Summary      Get Profit Report
+// @Description  Get revenue, cost of goods sold and gross margin per product, category and day
+// @Tags         transactions
+// @Produce      json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param        from query string false "From Date (YYYY-MM-DD)"
+// @Param        to   query string false "To Date (YYYY-MM-DD)"
+// @Param        outlet_id query int false "Outlet ID (default all outlets)"
+// @Param        format query string false "json (default), csv or xlsx"
+// @Success      200  {object}  models.ProfitReport
+// @Failure      400  {object}  map[string]string
+// @Router       /report/laba [get]
+func (h *TransactionHandler) HandleProfitReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	format, err := exportFormat(r)
+	if err != nil {
+		sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	outletID, err := queryOutletID(r)
+	if err != nil {
+		sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.service.GetProfitReport(r.URL.Query().Get("from"), r.URL.Query().Get("to"), outletID)
+	if err != nil {
+		sendServiceError(w, err)
+		return
+	}
+
+	if format != formatJSON {
+		// Tiga rincian digabung dalam satu tabel, dibedakan kolom "section"
+		var rows [][]interface{}
+		for _, section := range []struct {
+			name  string
+			lines []models.ProfitLine
+		}{{"product", report.ByProduct}, {"category", report.ByCategory}, {"day", report.ByDay}} {
+			for _, l := range section.lines {
+				rows = append(rows, []interface{}{section.name, l.ID, l.Name, l.Quantity, l.Revenue, l.COGS, l.GrossProfit, l.MarginPercent})
+			}
+		}
+		writeTable(w, format, "laporan-laba_"+report.From+"_"+report.To,
+			[]string{"section", "id", "name", "quantity", "revenue", "cogs", "gross_profit", "margin_percent"}, rows)
+		return
+	}
+
+	sendJSON(w, report)
+}
+
+// HandleBundleReport menangani request laporan paket/resep.
+// Endpoint: GET /api/reports/bundles
+// Params: ?from=YYYY-MM-DD&to=YYYY-MM-DD (Optional, default hari ini)&outlet_id=N (Optional, default semua outlet)
+// Berisi penjualan per paket dan jumlah komponen yang terpakai karenanya (bersih setelah refund).
+// @Summary      Get Bundle Report
+// @Description  Get bundle/recipe sales and the component stock they consumed
+// @Tags         transactions
+// @Produce      json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param        from query string false "From Date (YYYY-MM-DD)"
+// @Param        to   query string false "To Date (YYYY-MM-DD)"
+// @Param        outlet_id query int false "Outlet ID (default all outlets)"
+// @Param        format query string false "json (default), csv or xlsx"
+// @Success      200  {object}  models.BundleReport
+// @Failure      400  {object}  map[string]string
+// @Router       /reports/bundles [get]
+func (h *TransactionHandler) HandleBundleReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	format, err := exportFormat(r)
+	if err != nil {
+		sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	outletID, err := queryOutletID(r)
+	if err != nil {
+		sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.service.GetBundleReport(r.URL.Query().Get("from"), r.URL.Query().Get("to"), outletID)
+	if err != nil {
+		sendServiceError(w, err)
+		return
+	}
+
+	if format != formatJSON {
+		// Penjualan paket & pemakaian komponen digabung dalam satu tabel, dibedakan kolom "section"
+		var rows [][]interface{}
+		for _, l := range report.Bundles {
+			rows = append(rows, []interface{}{"bundle", l.ID, l.Name, l.Quantity, l.Revenue, l.COGS})
+		}
+		for _, c := range report.Components {
+			rows = append(rows, []interface{}{"component", c.ComponentID, c.Name, c.Quantity, 0, c.COGS})
+		}
+		writeTable(w, format, "laporan-paket_"+report.From+"_"+report.To,
+			[]string{"section", "id", "name", "quantity", "revenue", "cogs"}, rows)
+		return
+	}
+
+	sendJSON(w, report)
+}
+
+// HandleReorderReport menangani request laporan saran pesan ulang.
+// Endpoint: GET /api/reports/reorder
+// Params: ?days=N (periode rata-rata penjualan, default 30)&cover_days=N (stok untuk berapa hari ke depan, default 14)
+//
+//	&outlet_id=N (penjualan & stok satu outlet, default gabungan semua outlet)
+//
+// Berisi produk yang stoknya di bawah minimum atau akan habis sebelum cover_days, dengan jumlah yang disarankan untuk dipesan.
+// @Summary      Get Reorder Report
+// @Description  Suggest reorder quantities from average daily sales velocity, minimum stock and reorder quantity
+// @Tags         transactions
+// @Produce      json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param        days       query int    false "Sales history in days (default 30, max 365)"
+// @Param        cover_days query int    false "Days of stock to plan for (default 14, max 365)"
+// @Param        outlet_id  query int    false "Outlet ID (default all outlets)"
+// @Param        format     query string false "json (default), csv or xlsx"
+// @Success      200  {object}  models.ReorderReport
+// @Failure      400  {object}  map[string]string
+// @Router       /reports/reorder [get]
+func (h *TransactionHandler) HandleReorderReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	format, err := exportFormat(r)
+	if err != nil {
+		sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	q := r.URL.Query()
+	days, err := queryIntInRange(q.Get("days"), 0, 1, 365)
+	if err != nil {
+		sendError(w, "days "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	coverDays, err := queryIntInRange(q.Get("cover_days"), 0, 1, 365)
+	if err != nil {
+		sendError(w, "cover_days "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	outletID, err := queryOutletID(r)
+	if err != nil {
+		sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.service.GetReorderReport(days, coverDays, outletID)
+	if err != nil {
+		sendServiceError(w, err)
+		return
+	}
+
+	if format != formatJSON {
+		rows := make([][]interface{}, 0, len(report.Items))
+		for _, item := range report.Items {
+			var daysOfCover interface{}
+			if item.DaysOfCover != nil {
+				daysOfCover = *item.DaysOfCover
+			}
+			rows = append(rows, []interface{}{item.ProductID, item.SKU, item.Name, item.CategoryName, item.Unit, item.Stock,
+				item.MinStock, item.ReorderQty, item.Sold, item.DailySales, daysOfCover, item.SuggestedQty})
+		}
+		writeTable(w, format, "saran-pesan-ulang_"+report.To,
+			[]string{"product_id", "sku", "name", "category", "unit", "stock", "min_stock", "reorder_qty", "sold", "daily_sales", "days_of_cover", "suggested_qty"}, rows)
+		return
+	}
+
+	sendJSON(w, report)
+}
+
+// HandleSalesReport menangani request laporan penjualan periode.
+// Endpoint: GET /api/reports/sales
+// Params: ?from=YYYY-MM-DD&to=YYYY-MM-DD (default hari ini)
+//
+//	&group_by=day|week|month|hour|category|payment_method|product (default day)
+//	&top=N (jumlah produk terlaris, default 10)
+//	&outlet_id=N (default semua outlet)
+//
+// @Summary      Get Sales Report
+// @Description  Get sales time series or breakdown, top-N best sellers, average basket size and items per transaction
+// @Tags         transactions
+// @Produce      json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param        from     query string false "From Date (YYYY-MM-DD)"
+// @Param        to       query string false "To Date (YYYY-MM-DD)"
+// @Param        group_by query string false "day, week, month, hour, category, payment_method or product"
+// @Param        top      query int    false "Number of best sellers (default 10)"
+// @Param        outlet_id query int   false "Outlet ID (default all outlets)"
+// @Param        format   query string false "json (default), csv or xlsx (rows only)"
+// @Success      200  {object}  models.SalesReport
+// @Failure      400  {object}  map[string]string
+// @Router       /reports/sales [get]
+func (h *TransactionHandler) HandleSalesReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	format, err := exportFormat(r)
+	if err != nil {
+		sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	q := r.URL.Query()
+	top := 0
+	if raw := q.Get("top"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			sendError(w, "top harus berupa angka lebih dari 0", http.StatusBadRequest)
+			return
+		}
+		top = n
+	}
+
+	outletID, err := queryOutletID(r)
+	if err != nil {
+		sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.service.GetSalesReport(q.Get("from"), q.Get("to"), q.Get("group_by"), top, outletID)
+	if err != nil {
+		sendServiceError(w, err)
+		return
+	}
+
+	if format != formatJSON {
+		rows := make([][]interface{}, 0, len(report.Rows))
+		for _, row := range report.Rows {
+			rows = append(rows, []interface{}{row.Key, row.ID, row.Transactions, row.Items, row.Revenue})
+		}
+		writeTable(w, format, "laporan-penjualan_"+report.GroupBy+"_"+report.From+"_"+report.To,
+			[]string{report.GroupBy, "id", "transactions", "items", "revenue"}, rows)
+		return
+	}
+
+	sendJSON(w, report)
+}
+
 // HandleHistory menangani request daftar transaksi.
 // Endpoint: GET /api/transactions
-// Params: ?start_date=YYYY-MM-DD&end_date=YYYY-MM-DD (Optional)
+// Params: ?start_date=YYYY-MM-DD&end_date=YYYY-MM-DD&outlet_id=N (Optional)
+// Dengan ?format=csv|xlsx (atau header Accept), satu baris per transaksi dikirim langsung dari cursor database.
 // @Summary      Get Transaction History
-// @Description  Get list of transactions with optional date filter
+// @Description  Get list of transactions with optional date filter, or export them as CSV/XLSX
 // @Tags         transactions
-// @Produce      json
+// @Produce      json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
 // @Param        start_date query string false "Start Date (YYYY-MM-DD)"
 // @Param        end_date   query string false "End Date (YYYY-MM-DD)"
+// @Param        outlet_id  query int    false "Outlet ID (default all outlets)"
+// @Param        format     query string false "json (default), csv or xlsx"
 // @Success      200  {array}   models.Transaction
+// @Failure      400  {object}  map[string]string
 // @Router       /transactions [get]
 func (h *TransactionHandler) HandleHistory(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -106,15 +389,151 @@ func (h *TransactionHandler) HandleHistory(w http.ResponseWriter, r *http.Reques
 	start := r.URL.Query().Get("start_date")
 	end := r.URL.Query().Get("end_date")
 
-	transactions, err := h.service.GetHistory(start, end)
+	format, err := exportFormat(r)
 	if err != nil {
-		sendError(w, err.Error(), http.StatusInternalServerError)
+		sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	outletID, err := queryOutletID(r)
+	if err != nil {
+		sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if format != formatJSON {
+		table := newStreamTable(w, format, "transaksi",
+			"id", "created_at", "cashier", "shift_id", "status", "payment_method", "gross_amount", "discount_amount",
+			"subtotal_amount", "service_charge_amount", "tax_amount", "total_amount", "paid_amount", "change", "refunded_amount", "net_amount", "outlet_id")
+		table.Finish(h.service.ExportHistory(start, end, outletID, func(t models.Transaction) error {
+			return table.Row(t.ID, t.CreatedAt, t.Cashier, t.ShiftID, t.Status, t.PaymentMethod, t.GrossAmount, t.DiscountAmount,
+				t.SubtotalAmount, t.ServiceChargeAmount, t.TaxAmount, t.TotalAmount, t.PaidAmount, t.Change, t.RefundedAmount, t.NetAmount, t.OutletID)
+		}))
+		return
+	}
+
+	transactions, err := h.service.GetHistory(start, end, outletID)
+	if err != nil {
+		// Format tanggal yang salah -> 400
+		sendServiceError(w, err)
 		return
 	}
 
 	sendJSON(w, transactions)
 }
 
+// queryOutletID membaca parameter ?outlet_id= untuk laporan & riwayat. Kosong = 0 (semua outlet).
+func queryOutletID(r *http.Request) (int, error) {
+	raw := r.URL.Query().Get("outlet_id")
+	if raw == "" {
+		return 0, nil
+	}
+	id, err := strconv.Atoi(raw)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("outlet_id harus berupa angka lebih dari 0")
+	}
+	return id, nil
+}
+
+// HandleTransactionByID adalah "router" untuk semua URL di bawah /api/transactions/{id}.
+// - GET  /api/transactions/{id}        -> HandleDetail
+// - POST /api/transactions/{id}/void   -> HandleVoid
+// - POST /api/transactions/{id}/refund -> HandleRefund
+// - GET  /api/transactions/{id}/receipt -> HandleReceipt
+func (h *TransactionHandler) HandleTransactionByID(w http.ResponseWriter, r *http.Request) {
+	// Contoh: "/api/transactions/123/void" -> ["123", "void"]
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/transactions/"), "/"), "/")
+
+	switch {
+	case len(parts) == 1:
+		h.HandleDetail(w, r)
+	case len(parts) == 2 && parts[1] == "void":
+		h.HandleVoid(w, r)
+	case len(parts) == 2 && parts[1] == "refund":
+		h.HandleRefund(w, r)
+	case len(parts) == 2 && parts[1] == "receipt":
+		h.HandleReceipt(w, r)
+	default:
+		sendError(w, "Not found", http.StatusNotFound)
+	}
+}
+
+// transactionIDFromPath mengambil ID transaksi dari URL /api/transactions/{id}[/aksi].
+func transactionIDFromPath(path string) (int, error) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(path, "/api/transactions/"), "/"), "/")
+	return strconv.Atoi(parts[0])
+}
+
+// HandleReceipt mencetak struk transaksi.
+// Endpoint: GET /api/transactions/{id}/receipt?format=text|escpos|pdf&width=58|80
+// @Summary      Print Receipt
+// @Description  Render a transaction receipt with the configured store header/footer as plain text, raw ESC/POS bytes for thermal printers, or PDF
+// @Tags         transactions
+// @Produce      plain,application/octet-stream,application/pdf
+// @Param        id     path  int    true  "Transaction ID"
+// @Param        format query string false "text (default), escpos or pdf"
+// @Param        width  query int    false "Paper width in mm: 58 or 80 (default from RECEIPT_PAPER_WIDTH)"
+// @Success      200  {file}    file
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /transactions/{id}/receipt [get]
+func (h *TransactionHandler) HandleReceipt(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := transactionIDFromPath(r.URL.Path)
+	if err != nil {
+		sendError(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	q := r.URL.Query()
+	format := strings.ToLower(q.Get("format"))
+	if format == "" {
+		format = models.ReceiptFormatText
+	}
+	switch format {
+	case models.ReceiptFormatText, models.ReceiptFormatESCPOS, models.ReceiptFormatPDF:
+	default:
+		sendError(w, "format harus salah satu dari text, escpos, pdf", http.StatusBadRequest)
+		return
+	}
+	paperWidth := h.receipt.PaperWidth
+	if raw := q.Get("width"); raw != "" {
+		paperWidth, err = strconv.Atoi(raw)
+		if _, ok := receiptColumns[paperWidth]; err != nil || !ok {
+			sendError(w, "width harus 58 atau 80", http.StatusBadRequest)
+			return
+		}
+	}
+
+	transaction, err := h.service.GetReceipt(id)
+	if err != nil {
+		sendServiceError(w, err)
+		return
+	}
+
+	columns := receiptColumns[paperWidth]
+	lines := buildReceipt(transaction, h.receipt, columns)
+	filename := "struk-" + strconv.Itoa(transaction.ID)
+	switch format {
+	case models.ReceiptFormatESCPOS:
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`.bin"`)
+		err = writeReceiptESCPOS(w, lines)
+	case models.ReceiptFormatPDF:
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", `inline; filename="`+filename+`.pdf"`)
+		err = writeReceiptPDF(w, lines, paperWidth, columns)
+	default:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		err = writeReceiptText(w, lines, columns)
+	}
+	if err != nil {
+		log.Println("Gagal menulis struk:", err)
+	}
+}
+
 // HandleDetail menangani request detail satu transaksi.
 // Endpoint: GET /api/transactions/{id}
 // @Summary      Get Transaction Detail
@@ -163,3 +582,85 @@ func (h *TransactionHandler) HandleDetail(w http.ResponseWriter, r *http.Request
 
 	sendJSON(w, transaction)
 }
+
+// HandleVoid membatalkan seluruh transaksi dan mengembalikan stok semua barangnya.
+// Endpoint: POST /api/transactions/{id}/void
+// Body JSON: { "reason": "salah input", "voided_by": "supervisor-1" }
+// @Summary      Void Transaction
+// @Description  Cancel a whole transaction, restore stock and exclude it from revenue
+// @Tags         transactions
+// @Accept       json
+// @Produce      json
+// @Param        id       path  int                 true  "Transaction ID"
+// @Param        request  body  models.VoidRequest  true  "Void Request"
+// @Success      200  {object}  models.Transaction
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /transactions/{id}/void [post]
+func (h *TransactionHandler) HandleVoid(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := transactionIDFromPath(r.URL.Path)
+	if err != nil {
+		sendError(w, "Invalid Transaction ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.VoidRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	transaction, err := h.service.Void(id, req)
+	if err != nil {
+		sendServiceError(w, err)
+		return
+	}
+
+	sendJSON(w, transaction)
+}
+
+// HandleRefund mengembalikan sebagian barang dari sebuah transaksi.
+// Endpoint: POST /api/transactions/{id}/refund
+// Body JSON: { "items": [ { "detail_id": 1, "quantity": 1 } ], "reason": "barang rusak", "refunded_by": "kasir-1" }
+// @Summary      Refund Transaction Items
+// @Description  Refund some items of a transaction per detail line and restore their stock
+// @Tags         transactions
+// @Accept       json
+// @Produce      json
+// @Param        id       path  int                   true  "Transaction ID"
+// @Param        request  body  models.RefundRequest  true  "Refund Request"
+// @Success      200  {object}  models.Transaction
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /transactions/{id}/refund [post]
+func (h *TransactionHandler) HandleRefund(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := transactionIDFromPath(r.URL.Path)
+	if err != nil {
+		sendError(w, "Invalid Transaction ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.RefundRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	transaction, err := h.service.Refund(id, req)
+	if err != nil {
+		sendServiceError(w, err)
+		return
+	}
+
+	sendJSON(w, transaction)
+}
@@ -3,10 +3,13 @@ package handlers
 import (
 	"bytes"
 	"codeWithUmam/models"
+	"codeWithUmam/repositories"
+	"codeWithUmam/services"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -15,10 +18,18 @@ import (
 // Gunanya: Agar kita bisa test Handler TANPA harus konek ke Database beneran.
 // Kita bisa "mengatur" agar mock ini me-return sukses atau error sesuai keinginan kita.
 type MockTransactionService struct {
-	CheckoutFunc       func(req models.CheckoutRequest) (*models.Transaction, error)
-	GetDailyReportFunc func() (*models.SalesSummary, error)
-	GetHistoryFunc     func(start, end string) ([]models.Transaction, error)
-	GetDetailFunc      func(id int) (*models.Transaction, error)
+	CheckoutFunc         func(req models.CheckoutRequest) (*models.Transaction, error)
+	GetDailyReportFunc   func(outletID int) (*models.SalesSummary, error)
+	GetProfitReportFunc  func(from, to string, outletID int) (*models.ProfitReport, error)
+	GetBundleReportFunc  func(from, to string, outletID int) (*models.BundleReport, error)
+	GetReorderReportFunc func(days, coverDays, outletID int) (*models.ReorderReport, error)
+	GetSalesReportFunc   func(from, to, groupBy string, top, outletID int) (*models.SalesReport, error)
+	GetHistoryFunc       func(start, end string, outletID int) ([]models.Transaction, error)
+	ExportHistoryFunc    func(start, end string, outletID int, fn func(models.Transaction) error) error
+	GetDetailFunc        func(id int) (*models.Transaction, error)
+	GetReceiptFunc       func(id int) (*models.Transaction, error)
+	VoidFunc             func(id int, req models.VoidRequest) (*models.Transaction, error)
+	RefundFunc           func(id int, req models.RefundRequest) (*models.Transaction, error)
 }
 
 func (m *MockTransactionService) Checkout(req models.CheckoutRequest) (*models.Transaction, error) {
@@ -30,20 +41,62 @@ func (m *MockTransactionService) Checkout(req models.CheckoutRequest) (*models.T
 	return nil, errors.New("not implemented")
 }
 
-func (m *MockTransactionService) GetDailyReport() (*models.SalesSummary, error) {
+func (m *MockTransactionService) GetDailyReport(outletID int) (*models.SalesSummary, error) {
 	if m.GetDailyReportFunc != nil {
-		return m.GetDailyReportFunc()
+		return m.GetDailyReportFunc(outletID)
 	}
 	return nil, nil
 }
 
-func (m *MockTransactionService) GetHistory(start, end string) ([]models.Transaction, error) {
+func (m *MockTransactionService) GetProfitReport(from, to string, outletID int) (*models.ProfitReport, error) {
+	if m.GetProfitReportFunc != nil {
+		return m.GetProfitReportFunc(from, to, outletID)
+	}
+	return nil, nil
+}
+
+func (m *MockTransactionService) GetBundleReport(from, to string, outletID int) (*models.BundleReport, error) {
+	if m.GetBundleReportFunc != nil {
+		return m.GetBundleReportFunc(from, to, outletID)
+	}
+	return nil, nil
+}
+
+func (m *MockTransactionService) GetReorderReport(days, coverDays, outletID int) (*models.ReorderReport, error) {
+	if m.GetReorderReportFunc != nil {
+		return m.GetReorderReportFunc(days, coverDays, outletID)
+	}
+	return nil, nil
+}
+
+func (m *MockTransactionService) GetSalesReport(from, to, groupBy string, top, outletID int) (*models.SalesReport, error) {
+	if m.GetSalesReportFunc != nil {
+		return m.GetSalesReportFunc(from, to, groupBy, top, outletID)
+	}
+	return nil, nil
+}
+
+func (m *MockTransactionService) GetHistory(start, end string, outletID int) ([]models.Transaction, error) {
 	if m.GetHistoryFunc != nil {
-		return m.GetHistoryFunc(start, end)
+		return m.GetHistoryFunc(start, end, outletID)
 	}
 	return nil, nil
 }
 
+func (m *MockTransactionService) ExportHistory(start, end string, outletID int, fn func(models.Transaction) error) error {
+	if m.ExportHistoryFunc != nil {
+		return m.ExportHistoryFunc(start, end, outletID, fn)
+	}
+	return nil
+}
+
+func (m *MockTransactionService) GetReceipt(id int) (*models.Transaction, error) {
+	if m.GetReceiptFunc != nil {
+		return m.GetReceiptFunc(id)
+	}
+	return nil, repositories.ErrNotFound
+}
+
 func (m *MockTransactionService) GetDetail(id int) (*models.Transaction, error) {
 	if m.GetDetailFunc != nil {
 		return m.GetDetailFunc(id)
@@ -51,6 +104,20 @@ func (m *MockTransactionService) GetDetail(id int) (*models.Transaction, error)
 	return nil, nil
 }
 
+func (m *MockTransactionService) Void(id int, req models.VoidRequest) (*models.Transaction, error) {
+	if m.VoidFunc != nil {
+		return m.VoidFunc(id, req)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockTransactionService) Refund(id int, req models.RefundRequest) (*models.Transaction, error) {
+	if m.RefundFunc != nil {
+		return m.RefundFunc(id, req)
+	}
+	return nil, errors.New("not implemented")
+}
+
 func TestTransactionHandler_HandleCheckout_Success(t *testing.T) {
 	// 1. Setup Mock
 	// Kita pura-pura service akan sukses memproses checkout
@@ -67,7 +134,7 @@ func TestTransactionHandler_HandleCheckout_Success(t *testing.T) {
 			}, nil
 		},
 	}
-	handler := NewTransactionHandler(mockService)
+	handler := NewTransactionHandler(mockService, models.ReceiptConfig{})
 
 	// 2. Create Request (Simulasi Panggilan HTTP)
 	// Kita buat body JSON request palsu
@@ -111,7 +178,7 @@ func TestTransactionHandler_HandleCheckout_Success(t *testing.T) {
 
 func TestTransactionHandler_HandleDailyReport_Success(t *testing.T) {
 	mockService := &MockTransactionService{
-		GetDailyReportFunc: func() (*models.SalesSummary, error) {
+		GetDailyReportFunc: func(outletID int) (*models.SalesSummary, error) {
 			return &models.SalesSummary{
 				TotalRevenue:   100000,
 				TotalTransaksi: 5,
@@ -119,7 +186,7 @@ func TestTransactionHandler_HandleDailyReport_Success(t *testing.T) {
 			}, nil
 		},
 	}
-	handler := NewTransactionHandler(mockService)
+	handler := NewTransactionHandler(mockService, models.ReceiptConfig{})
 
 	req, _ := http.NewRequest("GET", "/api/report/hari-ini", nil)
 	rr := httptest.NewRecorder()
@@ -133,13 +200,13 @@ func TestTransactionHandler_HandleDailyReport_Success(t *testing.T) {
 
 func TestTransactionHandler_HandleHistory_Success(t *testing.T) {
 	mockService := &MockTransactionService{
-		GetHistoryFunc: func(start, end string) ([]models.Transaction, error) {
+		GetHistoryFunc: func(start, end string, outletID int) ([]models.Transaction, error) {
 			return []models.Transaction{
 				{ID: 1, TotalAmount: 50000},
 			}, nil
 		},
 	}
-	handler := NewTransactionHandler(mockService)
+	handler := NewTransactionHandler(mockService, models.ReceiptConfig{})
 
 	req, _ := http.NewRequest("GET", "/api/transactions", nil)
 	rr := httptest.NewRecorder()
@@ -151,13 +218,57 @@ func TestTransactionHandler_HandleHistory_Success(t *testing.T) {
 	}
 }
 
+func TestTransactionHandler_HandleHistory_ExportCSV(t *testing.T) {
+	mockService := &MockTransactionService{
+		ExportHistoryFunc: func(start, end string, outletID int, fn func(models.Transaction) error) error {
+			if start != "2026-01-01" {
+				return repositories.NewValidationError("unexpected start %s", start)
+			}
+			for _, tx := range []models.Transaction{{ID: 1, Cashier: "budi", TotalAmount: 50000}, {ID: 2, TotalAmount: 7000}} {
+				if err := fn(tx); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	handler := NewTransactionHandler(mockService, models.ReceiptConfig{})
+
+	req, _ := http.NewRequest("GET", "/api/transactions?start_date=2026-01-01&format=csv", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleHistory(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+	}
+	lines := strings.Split(strings.TrimSpace(rr.Body.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "id,created_at,cashier") || !strings.HasPrefix(lines[1], "1,") {
+		t.Errorf("unexpected csv body: %q", rr.Body.String())
+	}
+
+	// Error sebelum baris pertama tetap dikirim sebagai error JSON
+	req, _ = http.NewRequest("GET", "/api/transactions?start_date=bad&format=csv", nil)
+	rr = httptest.NewRecorder()
+	handler.HandleHistory(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusBadRequest)
+	}
+
+	req, _ = http.NewRequest("GET", "/api/transactions?format=pdf", nil)
+	rr = httptest.NewRecorder()
+	handler.HandleHistory(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusBadRequest)
+	}
+}
+
 func TestTransactionHandler_HandleDetail_Success(t *testing.T) {
 	mockService := &MockTransactionService{
 		GetDetailFunc: func(id int) (*models.Transaction, error) {
 			return &models.Transaction{ID: 1, TotalAmount: 50000}, nil
 		},
 	}
-	handler := NewTransactionHandler(mockService)
+	handler := NewTransactionHandler(mockService, models.ReceiptConfig{})
 
 	req, _ := http.NewRequest("GET", "/api/transactions/1", nil)
 	rr := httptest.NewRecorder()
@@ -168,3 +279,118 @@ func TestTransactionHandler_HandleDetail_Success(t *testing.T) {
 		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
 	}
 }
+
+func TestTransactionHandler_HandleVoid_Success(t *testing.T) {
+	var gotID int
+	mockService := &MockTransactionService{
+		VoidFunc: func(id int, req models.VoidRequest) (*models.Transaction, error) {
+			gotID = id
+			return &models.Transaction{ID: id, TotalAmount: 50000, RefundedAmount: 50000, Status: models.TransactionStatusVoided}, nil
+		},
+	}
+	handler := NewTransactionHandler(mockService, models.ReceiptConfig{})
+
+	body, _ := json.Marshal(models.VoidRequest{Reason: "salah input", VoidedBy: "supervisor"})
+	req, _ := http.NewRequest("POST", "/api/transactions/7/void", bytes.NewBuffer(body))
+	rr := httptest.NewRecorder()
+
+	// Lewat router agar routing /{id}/void ikut teruji
+	handler.HandleTransactionByID(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	if gotID != 7 {
+		t.Errorf("expected transaction id 7, got %d", gotID)
+	}
+}
+
+func TestTransactionHandler_HandleRefund_ErrorMapping(t *testing.T) {
+	// Error aturan bisnis harus jadi 400, data tidak ada harus jadi 404
+	cases := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"validation", repositories.NewValidationError("quantity refund melebihi sisa barang"), http.StatusBadRequest},
+		{"not found", repositories.ErrNotFound, http.StatusNotFound},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockService := &MockTransactionService{
+				RefundFunc: func(id int, req models.RefundRequest) (*models.Transaction, error) {
+					return nil, tc.err
+				},
+			}
+			handler := NewTransactionHandler(mockService, models.ReceiptConfig{})
+
+			body, _ := json.Marshal(models.RefundRequest{Items: []models.RefundItem{{DetailID: 1, Quantity: 5}}, Reason: "rusak"})
+			req, _ := http.NewRequest("POST", "/api/transactions/1/refund", bytes.NewBuffer(body))
+			rr := httptest.NewRecorder()
+
+			handler.HandleTransactionByID(rr, req)
+
+			if status := rr.Code; status != tc.want {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tc.want)
+			}
+		})
+	}
+}
+
+func TestTransactionHandler_HandleCheckout_IdempotencyKey(t *testing.T) {
+	var gotKey string
+	mockService := &MockTransactionService{
+		CheckoutFunc: func(req models.CheckoutRequest) (*models.Transaction, error) {
+			gotKey = req.IdempotencyKey
+			return nil, services.ErrIdempotencyKeyReused
+		},
+	}
+	handler := NewTransactionHandler(mockService, models.ReceiptConfig{})
+
+	body, _ := json.Marshal(models.CheckoutRequest{Items: []models.CheckoutItem{{ProductID: 1, Quantity: 1}}, PaidAmount: 5000})
+	req, _ := http.NewRequest("POST", "/api/checkout", bytes.NewBuffer(body))
+	req.Header.Set("Idempotency-Key", "abc-123")
+	rr := httptest.NewRecorder()
+
+	handler.HandleCheckout(rr, req)
+
+	if gotKey != "abc-123" {
+		t.Errorf("expected idempotency key abc-123 passed to service, got %q", gotKey)
+	}
+	if status := rr.Code; status != http.StatusConflict {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusConflict)
+	}
+}
+
+func TestTransactionHandler_HandleSalesReport(t *testing.T) {
+	var gotGroupBy string
+	var gotTop int
+	mockService := &MockTransactionService{
+		GetSalesReportFunc: func(from, to, groupBy string, top, outletID int) (*models.SalesReport, error) {
+			gotGroupBy, gotTop = groupBy, top
+			return &models.SalesReport{From: from, To: to, GroupBy: groupBy}, nil
+		},
+	}
+	handler := NewTransactionHandler(mockService, models.ReceiptConfig{})
+
+	req, _ := http.NewRequest("GET", "/api/reports/sales?from=2026-01-01&to=2026-01-31&group_by=week&top=5", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleSalesReport(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+	}
+	if gotGroupBy != "week" || gotTop != 5 {
+		t.Errorf("expected group_by week and top 5, got %s and %d", gotGroupBy, gotTop)
+	}
+
+	// top yang bukan angka ditolak sebelum sampai ke Service
+	req, _ = http.NewRequest("GET", "/api/reports/sales?top=abc", nil)
+	rr = httptest.NewRecorder()
+	handler.HandleSalesReport(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusBadRequest)
+	}
+}
@@ -1,7 +1,10 @@
 package handlers
 
 import (
+	"codeWithUmam/repositories"
+	"codeWithUmam/services"
 	"encoding/json"
+	"errors"
 	"net/http"
 )
 
@@ -25,3 +28,25 @@ func sendError(w http.ResponseWriter, message string, code int) {
 	// Kirim pesan error dalam format JSON.
 	json.NewEncoder(w).Encode(map[string]string{"error": message})
 }
+
+// sendServiceError memetakan error dari Service/Repository ke HTTP status yang sesuai.
+// - repositories.ErrNotFound      -> 404
+// - *repositories.ValidationError -> 400 (request melanggar aturan bisnis)
+// - services.ErrIdempotencyKeyReused -> 409
+// - services.ErrInvalidSignature  -> 401 (webhook payment gateway palsu)
+// - selain itu                    -> 500 (error teknis)
+func sendServiceError(w http.ResponseWriter, err error) {
+	var validationErr *repositories.ValidationError
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		sendError(w, err.Error(), http.StatusNotFound)
+	case errors.As(err, &validationErr):
+		sendError(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, services.ErrIdempotencyKeyReused):
+		sendError(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, services.ErrInvalidSignature):
+		sendError(w, err.Error(), http.StatusUnauthorized)
+	default:
+		sendError(w, err.Error(), http.StatusInternalServerError)
+	}
+}
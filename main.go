@@ -5,10 +5,14 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
+	"time"
+	_ "time/tzdata" // Data zona waktu ikut di-embed, supaya STORE_TIMEZONE tetap jalan di server tanpa /usr/share/zoneinfo
 
 	"codeWithUmam/database"
 	"codeWithUmam/handlers"
+	"codeWithUmam/models"
 	"codeWithUmam/repositories"
 	"codeWithUmam/services"
 
@@ -23,6 +27,30 @@ import (
 type Config struct {
 	Port   string `mapstructure:"PORT"`    // Port dimana server akan berjalan
 	DBConn string `mapstructure:"DB_CONN"` // String koneksi database (untuk SQLite path filenya)
+
+	// Pajak & Service Charge
+	TaxRate           float64 `mapstructure:"TAX_RATE"`            // Tarif pajak default dalam persen, misal 11
+	TaxInclusive      bool    `mapstructure:"TAX_INCLUSIVE"`       // true jika harga produk sudah termasuk pajak
+	TaxCategoryRates  string  `mapstructure:"TAX_CATEGORY_RATES"`  // Tarif khusus per kategori, format "categoryID:persen", misal "3:0,5:10"
+	ServiceChargeRate float64 `mapstructure:"SERVICE_CHARGE_RATE"` // Service charge dalam persen, misal 5
+
+	// Payment Gateway
+	QRISProvider         string `mapstructure:"QRIS_PROVIDER"`          // "simulator" untuk QRIS simulator lokal; kosong = QRIS langsung dianggap lunas
+	QRISSimulatorSecret  string `mapstructure:"QRIS_SIMULATOR_SECRET"`  // Secret untuk menandatangani webhook simulator
+	PaymentExpiryMinutes int    `mapstructure:"PAYMENT_EXPIRY_MINUTES"` // Batas waktu pelanggan membayar tagihan QRIS (default 15)
+
+	// Harga Pokok
+	CostingMethod string `mapstructure:"COSTING_METHOD"` // Metode HPP: "AVERAGE" (default) atau "FIFO"
+
+	// Hari Bisnis
+	StoreTimezone     string `mapstructure:"STORE_TIMEZONE"`      // Zona waktu toko, misal Asia/Jakarta (default), Asia/Makassar, Asia/Jayapura
+	BusinessDayCutoff string `mapstructure:"BUSINESS_DAY_CUTOFF"` // Jam mulai hari bisnis format HH:MM, misal "04:00" untuk toko yang buka sampai subuh (default 00:00)
+
+	// Struk
+	ReceiptStoreName  string `mapstructure:"RECEIPT_STORE_NAME"`  // Nama toko di atas struk
+	ReceiptHeader     string `mapstructure:"RECEIPT_HEADER"`      // Baris di bawah nama toko, dipisah "|", misal "Jl. Merdeka 1|Telp 0812xxxx"
+	ReceiptFooter     string `mapstructure:"RECEIPT_FOOTER"`      // Baris penutup, dipisah "|", misal "Terima kasih|Barang tidak dapat ditukar"
+	ReceiptPaperWidth int    `mapstructure:"RECEIPT_PAPER_WIDTH"` // Lebar kertas printer thermal: 58 (default) atau 80 mm
 }
 
 // @title CodeWithUmam API
@@ -58,8 +86,66 @@ func main() {
 
 	// Masukkan nilai config ke struct agar mudah diakses
 	config := Config{
-		Port:   viper.GetString("PORT"),
-		DBConn: viper.GetString("DB_CONN"),
+		Port:              viper.GetString("PORT"),
+		DBConn:            viper.GetString("DB_CONN"),
+		TaxRate:           viper.GetFloat64("TAX_RATE"),
+		TaxInclusive:      viper.GetBool("TAX_INCLUSIVE"),
+		TaxCategoryRates:  viper.GetString("TAX_CATEGORY_RATES"),
+		ServiceChargeRate: viper.GetFloat64("SERVICE_CHARGE_RATE"),
+
+		QRISProvider:         viper.GetString("QRIS_PROVIDER"),
+		QRISSimulatorSecret:  viper.GetString("QRIS_SIMULATOR_SECRET"),
+		PaymentExpiryMinutes: viper.GetInt("PAYMENT_EXPIRY_MINUTES"),
+
+		CostingMethod: strings.ToUpper(viper.GetString("COSTING_METHOD")),
+
+		StoreTimezone:     viper.GetString("STORE_TIMEZONE"),
+		BusinessDayCutoff: viper.GetString("BUSINESS_DAY_CUTOFF"),
+
+		ReceiptStoreName:  viper.GetString("RECEIPT_STORE_NAME"),
+		ReceiptHeader:     viper.GetString("RECEIPT_HEADER"),
+		ReceiptFooter:     viper.GetString("RECEIPT_FOOTER"),
+		ReceiptPaperWidth: viper.GetInt("RECEIPT_PAPER_WIDTH"),
+	}
+	if config.PaymentExpiryMinutes <= 0 {
+		config.PaymentExpiryMinutes = 15
+	}
+	switch config.CostingMethod {
+	case "":
+		config.CostingMethod = models.CostingMethodAverage
+	case models.CostingMethodAverage, models.CostingMethodFIFO:
+	default:
+		log.Fatal("COSTING_METHOD tidak dikenal:", config.CostingMethod)
+	}
+
+	categoryRates, err := parseCategoryRates(config.TaxCategoryRates)
+	if err != nil {
+		log.Fatal("Format TAX_CATEGORY_RATES salah:", err)
+	}
+	taxConfig := models.TaxConfig{
+		Inclusive:         config.TaxInclusive,
+		DefaultRate:       config.TaxRate,
+		CategoryRates:     categoryRates,
+		ServiceChargeRate: config.ServiceChargeRate,
+	}
+
+	businessDay, err := parseBusinessDay(config.StoreTimezone, config.BusinessDayCutoff)
+	if err != nil {
+		log.Fatal("Konfigurasi hari bisnis salah:", err)
+	}
+
+	switch config.ReceiptPaperWidth {
+	case 0:
+		config.ReceiptPaperWidth = models.ReceiptPaper58
+	case models.ReceiptPaper58, models.ReceiptPaper80:
+	default:
+		log.Fatal("RECEIPT_PAPER_WIDTH harus 58 atau 80:", config.ReceiptPaperWidth)
+	}
+	receiptConfig := models.ReceiptConfig{
+		StoreName:  config.ReceiptStoreName,
+		Header:     splitReceiptLines(config.ReceiptHeader),
+		Footer:     splitReceiptLines(config.ReceiptFooter),
+		PaperWidth: config.ReceiptPaperWidth,
 	}
 
 	// ==========================================
@@ -89,10 +175,74 @@ func main() {
 	productService := services.NewProductService(productRepo)
 	productHandler := handlers.NewProductHandler(productService)
 
+	// Setup Stock Ledger
+	stockRepo := repositories.NewStockMovementRepository(db)
+	stockService := services.NewStockService(stockRepo, businessDay)
+	stockHandler := handlers.NewStockHandler(stockService)
+
+	// Setup Supplier & Pembelian
+	supplierRepo := repositories.NewSupplierRepository(db)
+	supplierService := services.NewSupplierService(supplierRepo)
+	supplierHandler := handlers.NewSupplierHandler(supplierService)
+
+	purchaseOrderRepo := repositories.NewPurchaseOrderRepository(db)
+	purchaseService := services.NewPurchaseService(purchaseOrderRepo)
+	purchaseOrderHandler := handlers.NewPurchaseOrderHandler(purchaseService)
+
+	// Setup Outlet & Transfer Stok
+	outletRepo := repositories.NewOutletRepository(db)
+	outletService := services.NewOutletService(outletRepo)
+	outletHandler := handlers.NewOutletHandler(outletService)
+
+	stockTransferRepo := repositories.NewStockTransferRepository(db, config.CostingMethod)
+	stockTransferService := services.NewStockTransferService(stockTransferRepo)
+	stockTransferHandler := handlers.NewStockTransferHandler(stockTransferService)
+
+	// Setup Stok Opname
+	stockOpnameRepo := repositories.NewStockOpnameRepository(db)
+	stockOpnameService := services.NewStockOpnameService(stockOpnameRepo)
+	stockOpnameHandler := handlers.NewStockOpnameHandler(stockOpnameService)
+
+	// Setup Shift Kasir
+	shiftRepo := repositories.NewShiftRepository(db)
+	shiftService := services.NewShiftService(shiftRepo)
+	shiftHandler := handlers.NewShiftHandler(shiftService)
+
+	// Setup Promotion (Diskon)
+	promotionRepo := repositories.NewPromotionRepository(db)
+	promotionService := services.NewPromotionService(promotionRepo)
+	promotionHandler := handlers.NewPromotionHandler(promotionService)
+
+	// Setup Payment Gateway
+	// Metode yang punya provider diproses async (PENDING -> PAID/EXPIRED lewat webhook).
+	var paymentProviders []services.PaymentProvider
+	var qrisSimulator *services.QRISSimulator
+	switch config.QRISProvider {
+	case "":
+		// Tanpa gateway: QRIS dikonfirmasi manual oleh kasir seperti metode non-tunai lainnya
+	case "simulator":
+		qrisSimulator = services.NewQRISSimulator(config.QRISSimulatorSecret, time.Duration(config.PaymentExpiryMinutes)*time.Minute)
+		paymentProviders = append(paymentProviders, qrisSimulator)
+	default:
+		log.Fatal("QRIS_PROVIDER tidak dikenal:", config.QRISProvider)
+	}
+
 	// Setup Transaction (Bootcamp Session 3)
-	transactionRepo := repositories.NewTransactionRepository(db)
-	transactionService := services.NewTransactionService(transactionRepo)
-	transactionHandler := handlers.NewTransactionHandler(transactionService)
+	transactionRepo := repositories.NewTransactionRepository(db, taxConfig, config.CostingMethod, businessDay)
+	transactionService := services.NewTransactionService(transactionRepo, paymentProviders...)
+	transactionHandler := handlers.NewTransactionHandler(transactionService, receiptConfig)
+
+	paymentService := services.NewPaymentService(transactionRepo, paymentProviders...)
+	paymentHandler := handlers.NewPaymentHandler(paymentService, qrisSimulator)
+
+	// Tagihan yang lewat batas waktu dibatalkan berkala (gateway tidak selalu mengirim callback EXPIRED)
+	go func() {
+		for range time.Tick(time.Minute) {
+			if _, err := paymentService.ExpireOverduePayments(time.Now()); err != nil {
+				log.Println("Gagal memproses pembayaran kedaluwarsa:", err)
+			}
+		}
+	}()
 
 	// ==========================================
 	// 4. Setup Routes
@@ -108,13 +258,53 @@ func main() {
 	http.HandleFunc("/api/v1/products", productHandler.HandleProducts)
 	http.HandleFunc("/api/v1/products/", productHandler.HandleProducts)
 
+	// Routes untuk Ledger Stok
+	http.HandleFunc("/api/v1/stock-movements", stockHandler.HandleMovements)
+	http.HandleFunc("/api/v1/stock-movements/consistency", stockHandler.HandleConsistency)
+	http.HandleFunc("/api/v1/stock-batches", stockHandler.HandleBatches)
+	http.HandleFunc("/api/reports/expiring", stockHandler.HandleExpiringReport)
+
+	// Routes untuk Outlet & Transfer Stok
+	http.HandleFunc("/api/v1/outlets", outletHandler.HandleOutlets)
+	http.HandleFunc("/api/v1/outlets/", outletHandler.HandleOutlets) // Detail, Update, Stock
+	http.HandleFunc("/api/v1/stock-transfers", stockTransferHandler.HandleStockTransfers)
+	http.HandleFunc("/api/v1/stock-transfers/", stockTransferHandler.HandleStockTransfers) // Detail, Receive, Cancel
+
+	// Routes untuk Stok Opname
+	http.HandleFunc("/api/v1/stock-opnames", stockOpnameHandler.HandleStockOpnames)
+	http.HandleFunc("/api/v1/stock-opnames/", stockOpnameHandler.HandleStockOpnames) // Detail, Counts, Approve, Cancel
+
+	// Routes untuk Supplier & Purchase Order
+	http.HandleFunc("/api/v1/suppliers", supplierHandler.HandleSuppliers)
+	http.HandleFunc("/api/v1/suppliers/", supplierHandler.HandleSuppliers)
+	http.HandleFunc("/api/v1/purchase-orders", purchaseOrderHandler.HandlePurchaseOrders)
+	http.HandleFunc("/api/v1/purchase-orders/", purchaseOrderHandler.HandlePurchaseOrders) // Detail, Receive, Cancel
+
+	// Routes untuk Promotions
+	http.HandleFunc("/api/v1/promotions", promotionHandler.HandlePromotions)
+	http.HandleFunc("/api/v1/promotions/", promotionHandler.HandlePromotions)
+
 	// Routes untuk Transactions (Bootcamp Session 3)
 	http.HandleFunc("/api/checkout", transactionHandler.HandleCheckout)
 	http.HandleFunc("/api/report/hari-ini", transactionHandler.HandleDailyReport)
+	http.HandleFunc("/api/report/laba", transactionHandler.HandleProfitReport)
+	http.HandleFunc("/api/reports/sales", transactionHandler.HandleSalesReport)
+	http.HandleFunc("/api/reports/bundles", transactionHandler.HandleBundleReport)
+	http.HandleFunc("/api/reports/reorder", transactionHandler.HandleReorderReport)
+
+	// Routes untuk Shift Kasir & Z-report
+	http.HandleFunc("/api/v1/shifts", shiftHandler.HandleShifts)
+	http.HandleFunc("/api/v1/shifts/", shiftHandler.HandleShifts) // Detail, Close
 
 	// Sprint 01: Transaction History
-	http.HandleFunc("/api/transactions", transactionHandler.HandleHistory) // List
-	http.HandleFunc("/api/transactions/", transactionHandler.HandleDetail) // Detail (match suffix)
+	http.HandleFunc("/api/transactions", transactionHandler.HandleHistory)          // List
+	http.HandleFunc("/api/transactions/", transactionHandler.HandleTransactionByID) // Detail, Void, Refund, Receipt (match suffix)
+
+	// Routes untuk Payment Gateway (webhook)
+	http.HandleFunc("/api/payments/callback/", paymentHandler.HandleCallback)
+	if qrisSimulator != nil {
+		http.HandleFunc("/api/payments/simulator/pay", paymentHandler.HandleSimulatorPay)
+	}
 
 	// Health Check - Endpoint sederhana untuk mengecek aplikasi hidup atau mati
 	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -138,3 +328,61 @@ func main() {
 	// Jika terjadi error fatal (misal port sudah terpakai), aplikasi akan berhenti.
 	log.Fatal(http.ListenAndServe(addr, nil))
 }
+
+// parseCategoryRates mengubah string "3:0,5:10" menjadi map category ID -> tarif pajak (persen).
+func parseCategoryRates(raw string) (map[int]float64, error) {
+	rates := make(map[int]float64)
+	if strings.TrimSpace(raw) == "" {
+		return rates, nil
+	}
+
+	for _, pair := range strings.Split(raw, ",") {
+		parts := strings.SplitN(strings.TrimSpace(pair), ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("%q harus berformat categoryID:persen", pair)
+		}
+		categoryID, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+		if err != nil {
+			return nil, fmt.Errorf("category ID %q tidak valid", parts[0])
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("tarif %q tidak valid", parts[1])
+		}
+		rates[categoryID] = rate
+	}
+	return rates, nil
+}
+
+// splitReceiptLines memecah "baris 1|baris 2" menjadi beberapa baris struk (baris kosong dibuang).
+func splitReceiptLines(raw string) []string {
+	var lines []string
+	for _, line := range strings.Split(raw, "|") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// parseBusinessDay membaca zona waktu toko (default Asia/Jakarta) dan jam mulai hari bisnis "HH:MM" (default 00:00).
+func parseBusinessDay(timezone, cutoff string) (models.BusinessDay, error) {
+	if strings.TrimSpace(timezone) == "" {
+		timezone = "Asia/Jakarta"
+	}
+	location, err := time.LoadLocation(strings.TrimSpace(timezone))
+	if err != nil {
+		return models.BusinessDay{}, fmt.Errorf("STORE_TIMEZONE %q tidak dikenal: %v", timezone, err)
+	}
+
+	businessDay := models.BusinessDay{Location: location}
+	if strings.TrimSpace(cutoff) == "" {
+		return businessDay, nil
+	}
+	at, err := time.Parse("15:04", strings.TrimSpace(cutoff))
+	if err != nil {
+		return models.BusinessDay{}, fmt.Errorf("BUSINESS_DAY_CUTOFF %q harus berformat HH:MM", cutoff)
+	}
+	businessDay.Cutoff = time.Duration(at.Hour())*time.Hour + time.Duration(at.Minute())*time.Minute
+	return businessDay, nil
+}
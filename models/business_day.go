@@ -0,0 +1,70 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// dateLayout adalah format tanggal di query parameter dan laporan (YYYY-MM-DD).
+const dateLayout = "2006-01-02"
+
+// sqliteTimestampLayout adalah format CURRENT_TIMESTAMP SQLite (selalu UTC), dipakai untuk membandingkan created_at.
+const sqliteTimestampLayout = "2006-01-02 15:04:05"
+
+// BusinessDay adalah aturan "hari bisnis" toko: zona waktu toko dan jam tutup buku harian.
+// Semua waktu di database disimpan dalam UTC, jadi tanggal di laporan & filter riwayat harus dikonversi dulu.
+//
+// Contoh: toko di WIB (UTC+7) dengan Cutoff 04:00. Penjualan Senin pukul 02:30 WIB
+// masih masuk hari bisnis Minggu, karena hari bisnis Senin baru dimulai pukul 04:00 WIB.
+type BusinessDay struct {
+	// Location adalah zona waktu toko, misal Asia/Jakarta (WIB), Asia/Makassar (WITA), Asia/Jayapura (WIT).
+	// nil berarti UTC.
+	Location *time.Location
+
+	// Cutoff adalah jam mulainya hari bisnis (0 = tengah malam). Misal 4*time.Hour untuk toko yang buka sampai subuh.
+	Cutoff time.Duration
+}
+
+func (b BusinessDay) location() *time.Location {
+	if b.Location == nil {
+		return time.UTC
+	}
+	return b.Location
+}
+
+// Local mengubah waktu t ke zona waktu toko (misal untuk ditampilkan di export).
+func (b BusinessDay) Local(t time.Time) time.Time {
+	return t.In(b.location())
+}
+
+// Date mengembalikan tanggal hari bisnis (YYYY-MM-DD) untuk waktu t.
+func (b BusinessDay) Date(t time.Time) string {
+	return t.In(b.location()).Add(-b.Cutoff).Format(dateLayout)
+}
+
+// Bounds mengembalikan rentang waktu UTC [start, end) untuk hari bisnis from..to (YYYY-MM-DD, inklusif),
+// dalam format timestamp SQLite sehingga bisa langsung dibandingkan dengan kolom created_at.
+func (b BusinessDay) Bounds(from, to string) (start, end string, err error) {
+	fromDate, err := time.ParseInLocation(dateLayout, from, b.location())
+	if err != nil {
+		return "", "", fmt.Errorf("tanggal %q harus berformat YYYY-MM-DD", from)
+	}
+	toDate, err := time.ParseInLocation(dateLayout, to, b.location())
+	if err != nil {
+		return "", "", fmt.Errorf("tanggal %q harus berformat YYYY-MM-DD", to)
+	}
+	// AddDate (bukan Add 24 jam) supaya tetap benar di zona waktu yang punya daylight saving
+	startAt := fromDate.Add(b.Cutoff)
+	endAt := toDate.AddDate(0, 0, 1).Add(b.Cutoff)
+	return startAt.UTC().Format(sqliteTimestampLayout), endAt.UTC().Format(sqliteTimestampLayout), nil
+}
+
+// SQLModifiers mengembalikan modifier fungsi tanggal SQLite untuk mengubah created_at (UTC) menjadi jam lokal toko,
+// dan jam lokal yang digeser Cutoff (supaya date() menghasilkan tanggal hari bisnis).
+// Offset diambil dari waktu sekarang: aman untuk zona waktu Indonesia yang tidak punya daylight saving.
+func (b BusinessDay) SQLModifiers() (local, business string) {
+	_, offset := time.Now().In(b.location()).Zone()
+	local = fmt.Sprintf("'%+d seconds'", offset)
+	business = fmt.Sprintf("'%+d seconds'", offset-int(b.Cutoff/time.Second))
+	return local, business
+}
@@ -0,0 +1,55 @@
+package models
+
+// Metode perhitungan HPP (Harga Pokok Penjualan / COGS).
+const (
+	// CostingMethodAverage: HPP = rata-rata tertimbang harga beli semua stok yang ada (moving average).
+	CostingMethodAverage = "AVERAGE"
+	// CostingMethodFIFO: HPP diambil dari lapisan harga beli paling lama yang masih tersisa (First In First Out).
+	CostingMethodFIFO = "FIFO"
+)
+
+// ProfitReport adalah laporan laba kotor untuk rentang tanggal tertentu.
+// Revenue adalah penjualan bersih (DPP: setelah diskon, tanpa pajak & service charge, dikurangi refund),
+// karena pajak dan service charge bukan pendapatan toko.
+type ProfitReport struct {
+	From          string       `json:"from"`
+	To            string       `json:"to"`
+	OutletID      int          `json:"outlet_id,omitempty"` // 0 = semua outlet
+	CostingMethod string       `json:"costing_method"`
+	Revenue       int          `json:"revenue"`
+	COGS          int          `json:"cogs"`
+	GrossProfit   int          `json:"gross_profit"`
+	MarginPercent float64      `json:"margin_percent"` // GrossProfit / Revenue x 100
+	ByProduct     []ProfitLine `json:"by_product"`
+	ByCategory    []ProfitLine `json:"by_category"`
+	ByDay         []ProfitLine `json:"by_day"`
+}
+
+// ProfitLine adalah satu baris laporan laba (per produk, per kategori, atau per hari).
+type ProfitLine struct {
+	ID            int     `json:"id,omitempty"` // ID produk/kategori (kosong untuk baris per hari)
+	Name          string  `json:"name"`         // Nama produk/kategori, atau tanggal YYYY-MM-DD
+	Quantity      int     `json:"quantity"`
+	Revenue       int     `json:"revenue"`
+	COGS          int     `json:"cogs"`
+	GrossProfit   int     `json:"gross_profit"`
+	MarginPercent float64 `json:"margin_percent"`
+}
+
+// BundleReport adalah laporan penjualan paket/resep untuk rentang tanggal tertentu:
+// berapa paket yang terjual, dan berapa banyak komponen yang terpakai karenanya.
+type BundleReport struct {
+	From       string               `json:"from"`
+	To         string               `json:"to"`
+	OutletID   int                  `json:"outlet_id,omitempty"` // 0 = semua outlet
+	Bundles    []ProfitLine         `json:"bundles"`
+	Components []ComponentUsageLine `json:"components"`
+}
+
+// ComponentUsageLine adalah pemakaian satu komponen oleh penjualan paket/resep (bersih setelah refund).
+type ComponentUsageLine struct {
+	ComponentID int    `json:"component_id"`
+	Name        string `json:"name"`
+	Quantity    int    `json:"quantity"` // Dalam satuan dasar komponen
+	COGS        int    `json:"cogs"`
+}
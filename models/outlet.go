@@ -0,0 +1,86 @@
+package models
+
+import "time"
+
+// DefaultOutletID adalah outlet utama yang dibuat otomatis saat migrasi.
+// Stok lama, transaksi lama, dan request yang tidak menyebutkan outlet_id masuk ke outlet ini.
+const DefaultOutletID = 1
+
+// Outlet merepresentasikan satu toko/cabang. Stok disimpan per outlet di tabel outlet_stock.
+type Outlet struct {
+	ID      int    `json:"id"`
+	Code    string `json:"code"` // Kode singkat yang unik, misal "JKT1"
+	Name    string `json:"name"`
+	Address string `json:"address,omitempty"`
+	// Active: outlet nonaktif tidak bisa dipakai checkout atau menjadi tujuan transfer stok.
+	// Outlet tidak pernah dihapus supaya riwayat transaksi & stoknya tetap utuh.
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// OutletStockItem adalah stok satu produk (atau varian) di satu outlet, dalam satuan dasar.
+type OutletStockItem struct {
+	ProductID   int    `json:"product_id"`
+	ProductName string `json:"product_name"`
+	SKU         string `json:"sku,omitempty"`
+	Unit        string `json:"unit"`
+	Stock       int    `json:"stock"`
+	// InTransit adalah jumlah yang sedang dikirim ke outlet ini (transfer yang belum diterima).
+	InTransit int `json:"in_transit"`
+}
+
+// Status dokumen transfer stok.
+const (
+	StockTransferStatusInTransit = "IN_TRANSIT" // Sudah keluar dari outlet asal, belum diterima outlet tujuan
+	StockTransferStatusReceived  = "RECEIVED"
+	StockTransferStatusCancelled = "CANCELLED" // Dibatalkan selagi di perjalanan, stok kembali ke outlet asal
+)
+
+// StockTransfer adalah dokumen pemindahan stok antar outlet.
+// Saat dibuat, stok langsung keluar dari outlet asal (status IN_TRANSIT); selama di perjalanan barangnya
+// tidak bisa dijual di outlet mana pun. Saat diterima, stok masuk ke outlet tujuan.
+type StockTransfer struct {
+	ID             int        `json:"id"`
+	FromOutletID   int        `json:"from_outlet_id"`
+	FromOutletName string     `json:"from_outlet_name,omitempty"`
+	ToOutletID     int        `json:"to_outlet_id"`
+	ToOutletName   string     `json:"to_outlet_name,omitempty"`
+	Status         string     `json:"status"`
+	Note           string     `json:"note,omitempty"`
+	SentBy         string     `json:"sent_by,omitempty"`
+	ReceivedBy     string     `json:"received_by,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	ReceivedAt     *time.Time `json:"received_at,omitempty"`
+	CancelledAt    *time.Time `json:"cancelled_at,omitempty"`
+
+	Items []StockTransferItem `json:"items"`
+}
+
+// StockTransferItem adalah satu baris barang yang dipindahkan, dalam satuan dasar produk.
+type StockTransferItem struct {
+	ID          int    `json:"id"`
+	ProductID   int    `json:"product_id"`
+	ProductName string `json:"product_name"` // Snapshot nama saat transfer dibuat
+	Quantity    int    `json:"quantity"`
+	UnitCost    int    `json:"unit_cost"` // Harga pokok per unit saat keluar dari outlet asal
+}
+
+// CreateStockTransferRequest adalah body untuk mengirim stok dari satu outlet ke outlet lain.
+type CreateStockTransferRequest struct {
+	FromOutletID int                        `json:"from_outlet_id"`
+	ToOutletID   int                        `json:"to_outlet_id"`
+	Note         string                     `json:"note"`
+	SentBy       string                     `json:"sent_by"`
+	Items        []StockTransferItemRequest `json:"items"`
+}
+
+// StockTransferItemRequest adalah satu produk yang dikirim.
+type StockTransferItemRequest struct {
+	ProductID int `json:"product_id"`
+	Quantity  int `json:"quantity"` // Dalam satuan dasar produk
+}
+
+// ReceiveStockTransferRequest adalah body untuk mencatat transfer yang sudah sampai di outlet tujuan.
+type ReceiveStockTransferRequest struct {
+	ReceivedBy string `json:"received_by"`
+}
@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+// PaymentMethodCash adalah metode pembayaran tunai.
+// Hanya pembayaran tunai yang boleh menghasilkan kembalian.
+const PaymentMethodCash = "CASH"
+
+// PaymentMethodSplit dipakai di header transaksi jika pelanggan membayar dengan lebih dari satu metode.
+const PaymentMethodSplit = "SPLIT"
+
+// Status satu tender pembayaran.
+// Tunai (dan metode tanpa payment gateway) langsung PAID saat checkout.
+// Metode yang diproses payment gateway (misal QRIS) mulai dari PENDING sampai gateway mengirim callback.
+const (
+	PaymentStatusPending   = "PENDING"   // Menunggu pelanggan membayar (misal scan QR)
+	PaymentStatusPaid      = "PAID"      // Pembayaran sudah dikonfirmasi
+	PaymentStatusExpired   = "EXPIRED"   // Batas waktu bayar habis
+	PaymentStatusCancelled = "CANCELLED" // Dibatalkan karena transaksinya di-void/kedaluwarsa
+)
+
+// Payment merepresentasikan satu pembayaran (tender) dalam sebuah transaksi.
+// Satu transaksi bisa dibayar dengan beberapa tender, misal sebagian tunai dan sebagian QRIS.
+// Struct ini mencerminkan tabel `payments` di database.
+type Payment struct {
+	ID            int    `json:"id"`
+	TransactionID int    `json:"transaction_id"`
+	Method        string `json:"method"` // "CASH", "QRIS", "DEBIT", dst
+
+	// Amount adalah nilai yang dipakai untuk membayar tagihan (untuk tunai: sudah dikurangi kembalian).
+	Amount int `json:"amount"`
+
+	// TenderedAmount adalah uang yang diserahkan pelanggan. Untuk non-tunai selalu sama dengan Amount.
+	TenderedAmount int `json:"tendered_amount"`
+
+	Reference string `json:"reference,omitempty"` // Nomor referensi EDC/QRIS (opsional)
+
+	// Field di bawah ini hanya terisi untuk pembayaran lewat payment gateway.
+	Status            string     `json:"status"`
+	ProviderReference string     `json:"provider_reference,omitempty"` // ID tagihan di payment gateway
+	QRString          string     `json:"qr_string,omitempty"`          // Isi QR yang ditampilkan ke pelanggan
+	ExpiresAt         *time.Time `json:"expires_at,omitempty"`
+	PaidAt            *time.Time `json:"paid_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PaymentCharge adalah tagihan yang dibuat payment gateway untuk satu Payment yang masih PENDING.
+type PaymentCharge struct {
+	ProviderReference string
+	QRString          string
+	ExpiresAt         time.Time
+}
+
+// PaymentCallback adalah isi webhook dari payment gateway yang sudah diverifikasi.
+type PaymentCallback struct {
+	ProviderReference string `json:"reference"`
+	Status            string `json:"status"` // PAID atau EXPIRED
+}
+
+// PaymentInput adalah satu tender yang dikirim client saat checkout.
+type PaymentInput struct {
+	Method    string `json:"method"`
+	Amount    int    `json:"amount"`
+	Reference string `json:"reference,omitempty"`
+}
+
+// TenderSummary adalah rekap penerimaan uang per metode pembayaran (untuk report).
+type TenderSummary struct {
+	Method         string `json:"method"`
+	Amount         int    `json:"amount"`
+	TotalTransaksi int    `json:"total_transaksi"`
+}
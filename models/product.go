@@ -1,5 +1,8 @@
 package models
 
+// DefaultProductUnit adalah satuan dasar produk yang tidak menyebutkan satuannya.
+const DefaultProductUnit = "pcs"
+
 // Product merepresentasikan data produk di dalam sistem.
 type Product struct {
 	// ID unik produk.
@@ -9,12 +12,46 @@ type Product struct {
 	// Nama produk.
 	Name string `json:"name"`
 
+	// SKU (kode barang) opsional. Jika diisi harus unik; dipakai sebagai kunci saat import CSV.
+	SKU string `json:"sku,omitempty"`
+
+	// Barcode EAN-8/EAN-13/UPC-A (satu produk boleh punya beberapa, misal kemasan lama & baru).
+	// UPC-A disimpan sebagai EAN-13 berawalan 0. Saat update, nil = barcode tidak diubah, [] = hapus semua.
+	Barcodes []string `json:"barcodes,omitempty"`
+
 	// Harga produk dalam integer (Rupiah tidak punya desimal penting).
 	Price int `json:"price"`
 
-	// Jumlah stok tersedia.
+	// Jumlah stok tersedia, dalam satuan dasar (Unit). Ini total semua outlet; stok per outlet ada di tabel outlet_stock.
 	Stock int `json:"stock"`
 
+	// OutletID adalah outlet tempat selisih stok dicatat saat produk dibuat/diubah (0 = outlet utama).
+	// Wajib diisi untuk mengubah stok produk yang stoknya juga ada di outlet lain.
+	OutletID int `json:"outlet_id,omitempty"`
+
+	// Unit adalah satuan dasar produk (default "pcs"). Harga & stok produk selalu dalam satuan ini.
+	// Saat update, kosong = satuan dasar tidak diubah.
+	Unit string `json:"unit,omitempty"`
+
+	// Units adalah satuan jual/beli lain (misal "pak" isi 5, "karton" isi 40).
+	// Saat update, nil = satuan tidak diubah, [] = hapus semua.
+	Units []ProductUnit `json:"units,omitempty"`
+
+	// TrackBatches: stok dicatat per batch beserta tanggal kedaluwarsanya (makanan, obat).
+	// Checkout mengambil batch yang paling cepat kedaluwarsa (FEFO) dan menolak batch yang sudah kedaluwarsa.
+	// Saat update, nil = tidak diubah (mematikannya menghapus semua batch produk ini).
+	TrackBatches *bool `json:"track_batches,omitempty"`
+
+	// MinStock adalah batas stok minimum: stok <= MinStock masuk daftar stok menipis (0 = tidak dipantau).
+	// ReorderQty adalah jumlah pesan ulang yang biasa dipakai (misal 1 karton), dalam satuan dasar.
+	// Produk yang punya varian dipantau per varian, jadi batas di induknya diabaikan.
+	// Saat update, nil = tidak diubah.
+	MinStock   *int `json:"min_stock,omitempty"`
+	ReorderQty *int `json:"reorder_qty,omitempty"`
+
+	// Harga pokok (HPP) rata-rata per unit. Dihitung ulang otomatis setiap ada penerimaan barang.
+	CostPrice int `json:"cost_price"`
+
 	// Foreign Key: ID dari kategori produk ini.
 	CategoryID int `json:"category_id"`
 
@@ -22,4 +59,53 @@ type Product struct {
 	// Pointer (*) berarti field ini bisa bernilai nil (kosong) jika tidak ada datanya.
 	// `omitempty`: Field ini tidak akan muncul di JSON jika nil.
 	Category *Category `json:"category,omitempty"`
+
+	// ParentID & VariantName hanya terisi jika baris ini adalah varian (misal "Es Teh - L" dengan VariantName "L").
+	ParentID    int    `json:"parent_id,omitempty"`
+	VariantName string `json:"variant_name,omitempty"`
+
+	// Variants berisi varian produk (ukuran, rasa). Produk yang punya varian dijual & distok per varian,
+	// jadi stok produk induknya selalu 0.
+	Variants []ProductVariant `json:"variants,omitempty"`
+
+	// Components berisi isi paket (bundle) atau bahan resep. Produk yang punya komponen tidak punya stok sendiri:
+	// setiap penjualan mengurangi stok komponennya. Saat update, nil = tidak diubah, [] = bukan paket lagi.
+	Components []ProductComponent `json:"components,omitempty"`
+}
+
+// IsBatchTracked mengembalikan true jika stok produk dicatat per batch.
+func (p Product) IsBatchTracked() bool {
+	return p.TrackBatches != nil && *p.TrackBatches
+}
+
+// ProductComponent adalah satu isi paket atau bahan resep, misal 18 gram biji kopi untuk 1 gelas kopi.
+type ProductComponent struct {
+	ComponentID   int    `json:"component_id"`
+	ComponentName string `json:"component_name,omitempty"`
+	Quantity      int    `json:"quantity"` // Dalam satuan dasar komponen, per 1 satuan dasar paket
+}
+
+// ProductUnit adalah satuan alternatif produk beserta faktor konversinya ke satuan dasar.
+type ProductUnit struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`   // Misal "pak" atau "karton"
+	Factor int    `json:"factor"` // Jumlah satuan dasar per 1 satuan ini, misal 40 pcs per karton
+	Price  int    `json:"price"`  // Harga jual per satuan ini; 0 = Factor x harga satuan dasar
+}
+
+// ProductVariant adalah satu varian produk (misal ukuran S/M/L atau rasa), dengan SKU, barcode, harga & stok sendiri.
+// ID varian dipakai sebagai variant_id saat checkout, dan sebagai product_id di ledger stok, PO, dan stok opname.
+type ProductVariant struct {
+	ID        int      `json:"id"`
+	Name      string   `json:"name"` // Nama varian saja, misal "L" atau "Pedas"
+	SKU       string   `json:"sku,omitempty"`
+	Barcodes  []string `json:"barcodes,omitempty"` // Saat update, nil = tidak diubah, [] = hapus semua
+	Price     int      `json:"price"`
+	Stock     int      `json:"stock"`
+	CostPrice int      `json:"cost_price"`
+	OutletID  int      `json:"outlet_id,omitempty"` // Outlet tempat selisih stok dicatat (lihat Product.OutletID)
+
+	// Batas stok minimum & jumlah pesan ulang varian ini (lihat Product.MinStock; saat update, nil = tidak diubah).
+	MinStock   *int `json:"min_stock,omitempty"`
+	ReorderQty *int `json:"reorder_qty,omitempty"`
 }
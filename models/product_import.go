@@ -0,0 +1,47 @@
+package models
+
+// Aksi yang dilakukan import untuk satu baris CSV.
+const (
+	ProductImportCreate = "CREATE" // SKU belum ada (atau kosong) -> produk baru
+	ProductImportUpdate = "UPDATE" // SKU sudah ada -> produk lama ditimpa
+)
+
+// ProductImportRow adalah satu baris CSV import produk yang sudah di-parse & lolos validasi format.
+type ProductImportRow struct {
+	Line         int    // Nomor baris di file CSV (header = baris 1)
+	Name         string // Nama produk
+	Price        int    // Harga jual
+	Stock        int    // Stok akhir setelah import (selisihnya dicatat di ledger stok)
+	CategoryID   int    // Diisi jika kolom kategori berupa angka (ID kategori yang sudah ada)
+	CategoryName string // Diisi jika kolom kategori berupa nama (dibuat otomatis jika belum ada)
+	SKU          string // Kunci upsert; kosong = selalu produk baru
+}
+
+// ProductImportError adalah alasan satu baris CSV ditolak.
+type ProductImportError struct {
+	Line    int    `json:"line"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// ProductImportRowResult adalah hasil import satu baris yang valid.
+type ProductImportRowResult struct {
+	Line      int    `json:"line"`
+	Action    string `json:"action"`               // CREATE / UPDATE
+	ProductID int    `json:"product_id,omitempty"` // Kosong untuk produk baru saat dry run
+	SKU       string `json:"sku,omitempty"`
+	Name      string `json:"name"`
+}
+
+// ProductImportResult adalah ringkasan import CSV.
+// Import berjalan dalam satu Database Transaction: jika ada satu baris saja yang error, tidak ada yang disimpan.
+type ProductImportResult struct {
+	DryRun            bool                     `json:"dry_run"`
+	Applied           bool                     `json:"applied"` // true jika perubahan benar-benar disimpan
+	TotalRows         int                      `json:"total_rows"`
+	Created           int                      `json:"created"`
+	Updated           int                      `json:"updated"`
+	CategoriesCreated int                      `json:"categories_created"`
+	Rows              []ProductImportRowResult `json:"rows"`
+	Errors            []ProductImportError     `json:"errors"`
+}
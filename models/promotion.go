@@ -0,0 +1,78 @@
+package models
+
+import "time"
+
+// Jenis promosi yang didukung.
+const (
+	// Diskon persen per item, misal 10% untuk produk/kategori tertentu.
+	PromotionTypeItemPercentage = "ITEM_PERCENTAGE"
+	// Potongan nominal per satuan dasar item, misal Rp 1.000 per pcs (1 karton isi 40 = Rp 40.000).
+	PromotionTypeItemNominal = "ITEM_NOMINAL"
+	// Beli X gratis Y untuk produk yang sama, misal beli 2 gratis 1.
+	PromotionTypeBuyXGetY = "BUY_X_GET_Y"
+	// Diskon persen seluruh keranjang jika belanja minimal MinSpend.
+	PromotionTypeCartPercentage = "CART_PERCENTAGE"
+	// Potongan nominal seluruh keranjang jika belanja minimal MinSpend.
+	PromotionTypeCartNominal = "CART_NOMINAL"
+)
+
+// Promotion merepresentasikan aturan promosi/diskon.
+// Struct ini mencerminkan tabel `promotions` di database.
+type Promotion struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"` // Salah satu dari konstanta PromotionType...
+
+	// Value adalah besar diskon: persen (1-100) untuk tipe *_PERCENTAGE, atau rupiah untuk tipe *_NOMINAL.
+	// Tidak dipakai untuk BUY_X_GET_Y.
+	Value int `json:"value"`
+
+	// Target promosi item. Jika keduanya 0, promosi berlaku untuk semua produk.
+	// Isi CategoryID untuk diskon satu kategori penuh.
+	ProductID  int `json:"product_id,omitempty"`
+	CategoryID int `json:"category_id,omitempty"`
+
+	// Khusus BUY_X_GET_Y: beli BuyQty, gratis GetQty (dalam satuan dasar produk).
+	BuyQty int `json:"buy_qty,omitempty"`
+	GetQty int `json:"get_qty,omitempty"`
+
+	// Khusus promosi keranjang (CART_*): minimal belanja setelah diskon item.
+	MinSpend int `json:"min_spend,omitempty"`
+
+	// Masa berlaku promosi (opsional). nil berarti tidak dibatasi.
+	StartAt *time.Time `json:"start_at,omitempty"`
+	EndAt   *time.Time `json:"end_at,omitempty"`
+
+	Active bool `json:"active"`
+}
+
+// IsCartLevel mengembalikan true jika promosi berlaku untuk seluruh keranjang, bukan per item.
+func (p Promotion) IsCartLevel() bool {
+	return p.Type == PromotionTypeCartPercentage || p.Type == PromotionTypeCartNominal
+}
+
+// IsValidAt mengecek apakah promosi aktif dan berada di dalam masa berlakunya pada waktu t.
+func (p Promotion) IsValidAt(t time.Time) bool {
+	if !p.Active {
+		return false
+	}
+	if p.StartAt != nil && t.Before(*p.StartAt) {
+		return false
+	}
+	if p.EndAt != nil && t.After(*p.EndAt) {
+		return false
+	}
+	return true
+}
+
+// AppliedPromotion mencatat promosi yang dipakai di sebuah transaksi beserta nilai potongannya.
+// TransactionDetailID bernilai 0 untuk promosi keranjang (berlaku ke seluruh transaksi).
+type AppliedPromotion struct {
+	ID                  int    `json:"id"`
+	TransactionID       int    `json:"transaction_id"`
+	TransactionDetailID int    `json:"transaction_detail_id,omitempty"`
+	PromotionID         int    `json:"promotion_id"`
+	PromotionName       string `json:"promotion_name"`
+	PromotionType       string `json:"promotion_type"`
+	Amount              int    `json:"amount"`
+}
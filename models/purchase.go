@@ -0,0 +1,104 @@
+package models
+
+import "time"
+
+// Status purchase order (PO).
+const (
+	PurchaseOrderStatusOpen              = "OPEN"               // Sudah dipesan, belum ada barang datang
+	PurchaseOrderStatusPartiallyReceived = "PARTIALLY_RECEIVED" // Sebagian barang sudah diterima
+	PurchaseOrderStatusReceived          = "RECEIVED"           // Semua barang sudah diterima
+	PurchaseOrderStatusCancelled         = "CANCELLED"          // Dibatalkan; sisa barang yang belum datang tidak ditunggu lagi
+)
+
+// PurchaseOrder merepresentasikan pesanan pembelian barang ke supplier.
+// Struct ini mencerminkan tabel `purchase_orders` di database.
+type PurchaseOrder struct {
+	ID           int    `json:"id"`
+	SupplierID   int    `json:"supplier_id"`
+	SupplierName string `json:"supplier_name,omitempty"`
+	OutletID     int    `json:"outlet_id"` // Outlet tujuan barang diterima
+	Status       string `json:"status"`
+	Note         string `json:"note,omitempty"`
+
+	// TotalCost adalah nilai pesanan (quantity x unit_cost dari PO),
+	// ReceivedCost adalah nilai barang yang benar-benar diterima (pakai harga beli saat penerimaan).
+	TotalCost    int `json:"total_cost"`
+	ReceivedCost int `json:"received_cost"`
+
+	CreatedAt time.Time           `json:"created_at"`
+	Items     []PurchaseOrderItem `json:"items"`
+	Receipts  []GoodsReceipt      `json:"receipts,omitempty"` // Riwayat penerimaan barang
+}
+
+// PurchaseOrderItem adalah satu baris barang di purchase order.
+type PurchaseOrderItem struct {
+	ID               int    `json:"id"`
+	PurchaseOrderID  int    `json:"purchase_order_id"`
+	ProductID        int    `json:"product_id"`
+	ProductName      string `json:"product_name,omitempty"` // Snapshot nama produk saat PO dibuat
+	Quantity         int    `json:"quantity"`
+	ReceivedQuantity int    `json:"received_quantity"`
+	UnitCost         int    `json:"unit_cost"` // Harga beli per unit yang disepakati di PO
+
+	// Unit adalah satuan beli (misal "karton"); Quantity, ReceivedQuantity & UnitCost dalam satuan ini.
+	// Stok bertambah Quantity x UnitFactor satuan dasar saat barang diterima.
+	Unit       string `json:"unit,omitempty"`
+	UnitFactor int    `json:"unit_factor"`
+}
+
+// GoodsReceipt adalah satu kali kedatangan barang untuk sebuah PO (satu PO bisa diterima beberapa kali).
+type GoodsReceipt struct {
+	ID              int                `json:"id"`
+	PurchaseOrderID int                `json:"purchase_order_id"`
+	ReceivedBy      string             `json:"received_by,omitempty"`
+	Note            string             `json:"note,omitempty"`
+	TotalCost       int                `json:"total_cost"`
+	CreatedAt       time.Time          `json:"created_at"`
+	Items           []GoodsReceiptItem `json:"items"`
+}
+
+// GoodsReceiptItem adalah jumlah barang yang diterima untuk satu baris PO, beserta harga beli aktualnya.
+type GoodsReceiptItem struct {
+	ID                  int    `json:"id"`
+	GoodsReceiptID      int    `json:"goods_receipt_id"`
+	PurchaseOrderItemID int    `json:"purchase_order_item_id"`
+	ProductID           int    `json:"product_id"`
+	Quantity            int    `json:"quantity"`
+	UnitCost            int    `json:"unit_cost"`
+	BatchNumber         string `json:"batch_number,omitempty"`
+	ExpiryDate          string `json:"expiry_date,omitempty"`
+}
+
+// CreatePurchaseOrderRequest adalah body untuk membuat PO baru.
+type CreatePurchaseOrderRequest struct {
+	SupplierID int                        `json:"supplier_id"`
+	OutletID   int                        `json:"outlet_id,omitempty"` // Opsional: default outlet utama
+	Note       string                     `json:"note"`
+	Items      []PurchaseOrderItemRequest `json:"items"`
+}
+
+// PurchaseOrderItemRequest adalah satu baris barang yang dipesan.
+type PurchaseOrderItemRequest struct {
+	ProductID int    `json:"product_id"`
+	Quantity  int    `json:"quantity"`
+	UnitCost  int    `json:"unit_cost"`
+	Unit      string `json:"unit,omitempty"` // Satuan beli (misal "karton"); kosong = satuan dasar produk
+}
+
+// ReceiveGoodsRequest adalah body untuk mencatat kedatangan barang.
+type ReceiveGoodsRequest struct {
+	ReceivedBy string                    `json:"received_by"`
+	Note       string                    `json:"note"`
+	Items      []ReceiveGoodsItemRequest `json:"items"`
+}
+
+// ReceiveGoodsItemRequest adalah jumlah barang yang datang untuk satu baris PO.
+// UnitCost opsional: jika 0, dipakai harga beli dari PO.
+// BatchNumber & ExpiryDate (YYYY-MM-DD) mencatat lot barang yang datang; ExpiryDate wajib untuk produk ber-batch.
+type ReceiveGoodsItemRequest struct {
+	PurchaseOrderItemID int    `json:"purchase_order_item_id"`
+	Quantity            int    `json:"quantity"`
+	UnitCost            int    `json:"unit_cost,omitempty"`
+	BatchNumber         string `json:"batch_number,omitempty"`
+	ExpiryDate          string `json:"expiry_date,omitempty"`
+}
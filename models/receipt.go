@@ -0,0 +1,22 @@
+package models
+
+// Format struk yang bisa dicetak.
+const (
+	ReceiptFormatText   = "text"   // Teks polos rata kolom (untuk preview / printer yang menerima teks)
+	ReceiptFormatESCPOS = "escpos" // Byte perintah ESC/POS, langsung dikirim ke printer thermal
+	ReceiptFormatPDF    = "pdf"    // PDF selebar kertas thermal (untuk dikirim ke pelanggan / arsip)
+)
+
+// Lebar kertas printer thermal (mm) yang didukung.
+const (
+	ReceiptPaper58 = 58
+	ReceiptPaper80 = 80
+)
+
+// ReceiptConfig berisi identitas toko yang dicetak di setiap struk.
+type ReceiptConfig struct {
+	StoreName  string   // Nama toko, dicetak tebal paling atas
+	Header     []string // Baris di bawah nama toko, misal alamat, telepon, NPWP
+	Footer     []string // Baris penutup, misal "Terima kasih" atau kebijakan retur
+	PaperWidth int      // Lebar kertas default (58 atau 80 mm)
+}
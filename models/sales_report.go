@@ -0,0 +1,52 @@
+package models
+
+// Pengelompokan laporan penjualan periode (?group_by=).
+const (
+	SalesGroupByHour          = "hour"  // Jam dalam sehari (00:00-23:00), untuk melihat jam ramai
+	SalesGroupByDay           = "day"   // YYYY-MM-DD
+	SalesGroupByWeek          = "week"  // Tanggal Senin awal minggu (YYYY-MM-DD)
+	SalesGroupByMonth         = "month" // YYYY-MM
+	SalesGroupByCategory      = "category"
+	SalesGroupByPaymentMethod = "payment_method"
+	SalesGroupByProduct       = "product"
+)
+
+// SalesReport adalah response endpoint laporan penjualan untuk rentang tanggal tertentu.
+// Semua nilai uang sudah dikurangi refund dan termasuk pajak & service charge (sama seperti TotalRevenue di SalesSummary).
+type SalesReport struct {
+	From              string `json:"from"`
+	To                string `json:"to"`
+	OutletID          int    `json:"outlet_id,omitempty"` // 0 = semua outlet
+	GroupBy           string `json:"group_by"`
+	TotalRevenue      int    `json:"total_revenue"`
+	TotalTransactions int    `json:"total_transactions"` // Transaksi yang di-void tidak dihitung
+	TotalItems        int    `json:"total_items"`        // Jumlah barang terjual (setelah refund)
+
+	// AverageBasket = TotalRevenue / TotalTransactions (rata-rata belanja per transaksi).
+	AverageBasket float64 `json:"average_basket"`
+	// ItemsPerTransaction = TotalItems / TotalTransactions.
+	ItemsPerTransaction float64 `json:"items_per_transaction"`
+
+	// Rows adalah rincian sesuai GroupBy: deret waktu untuk hour/day/week/month, atau breakdown untuk yang lain.
+	Rows []SalesReportRow `json:"rows"`
+
+	// TopProducts adalah N produk terlaris (berdasarkan qty) di rentang tanggal ini.
+	TopProducts []TopProduct `json:"top_products"`
+}
+
+// SalesReportRow adalah satu baris rincian laporan penjualan.
+type SalesReportRow struct {
+	Key          string `json:"key"`          // Periode (misal "2026-01-31"), nama kategori/produk, atau metode pembayaran
+	ID           int    `json:"id,omitempty"` // ID kategori/produk (hanya untuk group_by category/product)
+	Transactions int    `json:"transactions"` // Jumlah transaksi yang mengandung baris ini
+	Items        int    `json:"items"`        // Jumlah barang (0 untuk payment_method)
+	Revenue      int    `json:"revenue"`
+}
+
+// TopProduct adalah satu produk di daftar produk terlaris.
+type TopProduct struct {
+	ProductID int    `json:"product_id"`
+	Name      string `json:"name"`
+	Quantity  int    `json:"quantity"`
+	Revenue   int    `json:"revenue"`
+}
@@ -0,0 +1,85 @@
+package models
+
+import "time"
+
+// Status shift kasir.
+const (
+	ShiftStatusOpen   = "OPEN"   // Laci kas sedang dipakai, checkout kasir ini masuk ke shift ini
+	ShiftStatusClosed = "CLOSED" // Sudah tutup; Z-report dibekukan dan tidak bisa diubah lagi
+)
+
+// Shift adalah satu sesi kerja kasir dengan laci kasnya, dari buka (modal awal) sampai tutup (hitung uang).
+// Satu kasir hanya boleh punya satu shift OPEN.
+type Shift struct {
+	ID           int        `json:"id"`
+	Cashier      string     `json:"cashier"`
+	Status       string     `json:"status"`
+	OpeningFloat int        `json:"opening_float"` // Modal awal (uang kembalian) di laci saat shift dibuka
+	Note         string     `json:"note,omitempty"`
+	OpenedAt     time.Time  `json:"opened_at"`
+	ClosedAt     *time.Time `json:"closed_at,omitempty"`
+
+	// Report adalah X-report berjalan selama shift OPEN, dan Z-report yang sudah dibekukan setelah CLOSED.
+	Report *ShiftReport `json:"report,omitempty"`
+}
+
+// ShiftReport adalah rekap laci kas satu shift.
+// Setelah shift ditutup, laporan disimpan apa adanya (Z-report): refund/void transaksi shift ini
+// yang terjadi setelah tutup shift tidak mengubah laporan yang sudah dicetak.
+type ShiftReport struct {
+	ShiftID      int        `json:"shift_id"`
+	Cashier      string     `json:"cashier"`
+	OpenedAt     time.Time  `json:"opened_at"`
+	ClosedAt     *time.Time `json:"closed_at,omitempty"`
+	Final        bool       `json:"final"` // true = Z-report (shift sudah tutup)
+	OpeningFloat int        `json:"opening_float"`
+
+	TotalTransactions int `json:"total_transactions"` // Tanpa transaksi yang di-void
+	TotalSales        int `json:"total_sales"`        // Omset bersih (setelah refund)
+	RefundCount       int `json:"refund_count"`       // Transaksi yang sebagian/seluruh barangnya di-refund
+	RefundAmount      int `json:"refund_amount"`      // Dianggap dibayar tunai dari laci
+	VoidCount         int `json:"void_count"`
+	VoidAmount        int `json:"void_amount"`
+
+	// Tenders adalah uang yang seharusnya ada per metode pembayaran, dibandingkan dengan hasil hitung saat tutup.
+	Tenders []ShiftTender `json:"tenders"`
+
+	// Ringkasan laci kas (tender CASH): modal awal + penjualan tunai - refund.
+	ExpectedCash int  `json:"expected_cash"`
+	CountedCash  *int `json:"counted_cash,omitempty"`
+	CashVariance *int `json:"cash_variance,omitempty"` // Counted - Expected (+ lebih, - kurang)
+}
+
+// ShiftTender adalah rekap satu metode pembayaran di shift.
+type ShiftTender struct {
+	Method       string `json:"method"`
+	Transactions int    `json:"transactions"`
+	Sales        int    `json:"sales"`    // Uang masuk dari penjualan dengan metode ini (tunai sudah dikurangi kembalian)
+	Expected     int    `json:"expected"` // Untuk CASH: ditambah modal awal dan dikurangi refund
+	Counted      *int   `json:"counted,omitempty"`
+	Variance     *int   `json:"variance,omitempty"`
+}
+
+// OpenShiftRequest adalah body request untuk membuka shift.
+type OpenShiftRequest struct {
+	Cashier      string `json:"cashier"`
+	OpeningFloat int    `json:"opening_float"`
+	Note         string `json:"note,omitempty"`
+}
+
+// CloseShiftRequest adalah body request untuk menutup shift.
+type CloseShiftRequest struct {
+	// CountedCash adalah uang tunai hasil hitung di laci (termasuk modal awal).
+	CountedCash int `json:"counted_cash"`
+
+	// Counted adalah hasil hitung tender non-tunai (opsional), misal total slip EDC per metode.
+	Counted []TenderCount `json:"counted,omitempty"`
+
+	Note string `json:"note,omitempty"`
+}
+
+// TenderCount adalah hasil hitung satu metode pembayaran.
+type TenderCount struct {
+	Method string `json:"method"`
+	Amount int    `json:"amount"`
+}
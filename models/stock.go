@@ -0,0 +1,160 @@
+package models
+
+import "time"
+
+// Jenis pergerakan stok.
+const (
+	StockMovementSale       = "SALE"       // Barang keluar karena dijual (checkout)
+	StockMovementRefund     = "REFUND"     // Barang kembali karena refund/void/pembayaran kedaluwarsa
+	StockMovementAdjustment = "ADJUSTMENT" // Koreksi manual (edit produk, stok opname, saldo awal)
+	StockMovementReceiving  = "RECEIVING"  // Barang masuk dari supplier
+	StockMovementTransfer   = "TRANSFER"   // Pindah stok antar lokasi
+)
+
+// Jenis dokumen sumber pergerakan stok (StockMovement.ReferenceType).
+const (
+	StockReferenceTransaction  = "transaction"
+	StockReferenceProduct      = "product" // Edit stok langsung lewat endpoint produk
+	StockReferenceGoodsReceipt = "goods_receipt"
+	StockReferenceStockOpname  = "stock_opname"
+	StockReferenceTransfer     = "stock_transfer"
+)
+
+// StockMovement adalah satu baris buku besar (ledger) stok.
+// Setiap perubahan stok WAJIB punya satu baris di sini, supaya angka stok bisa dijelaskan asal-usulnya.
+type StockMovement struct {
+	ID        int    `json:"id"`
+	ProductID int    `json:"product_id"`
+	Type      string `json:"type"`
+	// OutletID adalah outlet yang stoknya berubah (0 saat mencatat = outlet utama).
+	OutletID int `json:"outlet_id"`
+	// Quantity bertanda: positif = stok masuk, negatif = stok keluar.
+	Quantity int `json:"quantity"`
+	// UnitCost adalah harga pokok per unit: harga beli untuk barang masuk, harga rata-rata untuk barang keluar.
+	UnitCost int `json:"unit_cost"`
+	// Balance adalah saldo berjalan (running balance) setelah pergerakan ini, dihitung dari ledger.
+	Balance int `json:"balance"`
+	// ReferenceType & ReferenceID menunjuk dokumen sumbernya, misal "transaction" #12.
+	ReferenceType string    `json:"reference_type,omitempty"`
+	ReferenceID   int       `json:"reference_id,omitempty"`
+	Note          string    `json:"note,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+
+	// Batch yang berubah (hanya produk ber-batch). Untuk barang masuk, BatchNumber & ExpiryDate membuat batch baru,
+	// atau BatchID menambah batch yang sudah ada.
+	BatchID     int    `json:"batch_id,omitempty"`
+	BatchNumber string `json:"batch_number,omitempty"`
+	ExpiryDate  string `json:"expiry_date,omitempty"`
+
+	// SellableOn (YYYY-MM-DD, hanya untuk barang keluar): batch yang kedaluwarsa sebelum tanggal ini tidak boleh diambil.
+	// Kosong = semua batch boleh diambil, yang paling cepat kedaluwarsa lebih dulu (misal pemusnahan barang kedaluwarsa).
+	SellableOn string `json:"-"`
+}
+
+// StockBatch adalah satu batch (lot) stok produk dengan tanggal kedaluwarsanya.
+type StockBatch struct {
+	ID                int       `json:"id"`
+	ProductID         int       `json:"product_id"`
+	OutletID          int       `json:"outlet_id"`
+	BatchNumber       string    `json:"batch_number,omitempty"`
+	ExpiryDate        string    `json:"expiry_date,omitempty"` // Kosong = tanpa tanggal kedaluwarsa
+	Quantity          int       `json:"quantity"`              // Jumlah masuk awal
+	RemainingQuantity int       `json:"remaining_quantity"`
+	Expired           bool      `json:"expired"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// ExpiringStockReport adalah daftar batch yang kedaluwarsa dalam Days hari ke depan (termasuk yang sudah lewat),
+// dikelompokkan per kategori.
+type ExpiringStockReport struct {
+	Today      string                  `json:"today"`
+	Until      string                  `json:"until"`
+	Days       int                     `json:"days"`
+	Quantity   int                     `json:"quantity"`
+	Value      int                     `json:"value"` // Nilai persediaan (harga pokok rata-rata saat ini)
+	Categories []ExpiringStockCategory `json:"categories"`
+}
+
+// ExpiringStockCategory adalah batch-batch yang akan kedaluwarsa dalam satu kategori.
+type ExpiringStockCategory struct {
+	CategoryID   int             `json:"category_id"`
+	CategoryName string          `json:"category_name"`
+	Quantity     int             `json:"quantity"`
+	Value        int             `json:"value"`
+	Batches      []ExpiringBatch `json:"batches"`
+}
+
+// ExpiringBatch adalah sisa stok satu batch yang akan (atau sudah) kedaluwarsa.
+type ExpiringBatch struct {
+	BatchID     int    `json:"batch_id"`
+	OutletID    int    `json:"outlet_id"`
+	ProductID   int    `json:"product_id"`
+	ProductName string `json:"product_name"`
+	BatchNumber string `json:"batch_number,omitempty"`
+	ExpiryDate  string `json:"expiry_date"`
+	DaysLeft    int    `json:"days_left"` // Negatif = sudah kedaluwarsa sekian hari
+	Quantity    int    `json:"quantity"`
+	Value       int    `json:"value"`
+}
+
+// StockDiscrepancy adalah produk yang nilai products.stock-nya berbeda dengan hasil hitung ulang dari ledger,
+// dengan total sisa batch, atau dengan total stok per outlet.
+type StockDiscrepancy struct {
+	ProductID   int    `json:"product_id"`
+	ProductName string `json:"product_name"`
+	Stock       int    `json:"stock"`        // Nilai di kolom products.stock
+	LedgerStock int    `json:"ledger_stock"` // SUM(quantity) dari stock_movements
+	Difference  int    `json:"difference"`   // Stock - LedgerStock
+	// BatchStock adalah total sisa batch, hanya diisi jika produk ber-batch dan totalnya berbeda dengan Stock.
+	BatchStock *int `json:"batch_stock,omitempty"`
+	// OutletStock adalah total stok semua outlet, hanya diisi jika berbeda dengan Stock.
+	OutletStock *int `json:"outlet_stock,omitempty"`
+}
+
+// StockConsistencyReport adalah hasil pengecekan konsistensi stok vs ledger.
+type StockConsistencyReport struct {
+	CheckedProducts int                `json:"checked_products"`
+	Consistent      bool               `json:"consistent"`
+	Discrepancies   []StockDiscrepancy `json:"discrepancies"`
+}
+
+// LowStockItem adalah produk (atau varian) yang stoknya sudah mencapai batas minimum.
+type LowStockItem struct {
+	ProductID    int    `json:"product_id"`
+	Name         string `json:"name"`
+	SKU          string `json:"sku,omitempty"`
+	CategoryName string `json:"category_name,omitempty"`
+	Unit         string `json:"unit"`
+	Stock        int    `json:"stock"`
+	MinStock     int    `json:"min_stock"`
+	ReorderQty   int    `json:"reorder_qty"`
+	Shortage     int    `json:"shortage"` // MinStock - Stock (0 jika tepat di batas)
+}
+
+// ReorderReport adalah saran pemesanan ulang berdasarkan rata-rata penjualan harian selama Days hari terakhir,
+// supaya stok cukup untuk CoverDays hari ke depan di atas stok minimum.
+type ReorderReport struct {
+	From      string              `json:"from"`
+	To        string              `json:"to"`
+	OutletID  int                 `json:"outlet_id,omitempty"` // 0 = semua outlet
+	Days      int                 `json:"days"`
+	CoverDays int                 `json:"cover_days"`
+	Items     []ReorderSuggestion `json:"items"`
+}
+
+// ReorderSuggestion adalah saran pesan ulang satu produk (atau varian), semua jumlah dalam satuan dasar.
+type ReorderSuggestion struct {
+	ProductID    int     `json:"product_id"`
+	Name         string  `json:"name"`
+	SKU          string  `json:"sku,omitempty"`
+	CategoryName string  `json:"category_name,omitempty"`
+	Unit         string  `json:"unit"`
+	Stock        int     `json:"stock"`
+	MinStock     int     `json:"min_stock"`
+	ReorderQty   int     `json:"reorder_qty"`
+	Sold         int     `json:"sold"`        // Terjual bersih (setelah refund) selama periode, termasuk sebagai isi paket
+	DailySales   float64 `json:"daily_sales"` // Sold / Days
+	// DaysOfCover adalah perkiraan berapa hari stok sekarang akan habis; null jika tidak ada penjualan.
+	DaysOfCover  *float64 `json:"days_of_cover"`
+	SuggestedQty int      `json:"suggested_qty"`
+}
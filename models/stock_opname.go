@@ -0,0 +1,98 @@
+package models
+
+import "time"
+
+// Status sesi stok opname (hitung fisik).
+const (
+	StockOpnameStatusOpen      = "OPEN"      // Sedang dihitung, kasir boleh mengirim hasil hitungan
+	StockOpnameStatusApproved  = "APPROVED"  // Selisih sudah diposting ke stok sebagai ADJUSTMENT
+	StockOpnameStatusCancelled = "CANCELLED" // Dibatalkan, stok tidak diubah
+)
+
+// StockOpname adalah satu sesi hitung fisik stok.
+// Saat dibuka, stok semua produk (atau satu kategori) di-snapshot. Checkout tetap berjalan selama penghitungan,
+// jadi selisih dihitung terhadap stok sistem saat barang itu dihitung (ExpectedStock), bukan terhadap snapshot.
+type StockOpname struct {
+	ID         int        `json:"id"`
+	Status     string     `json:"status"`
+	OutletID   int        `json:"outlet_id"`             // Outlet yang stoknya dihitung
+	CategoryID int        `json:"category_id,omitempty"` // 0 = semua produk
+	Note       string     `json:"note,omitempty"`
+	OpenedBy   string     `json:"opened_by,omitempty"`
+	ApprovedBy string     `json:"approved_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ApprovedAt *time.Time `json:"approved_at,omitempty"`
+
+	// Ringkasan selisih (hanya produk yang sudah dihitung)
+	TotalProducts   int `json:"total_products"`
+	CountedProducts int `json:"counted_products"`
+	TotalVariance   int `json:"total_variance"`       // Jumlah unit selisih (+ lebih, - kurang)
+	VarianceValue   int `json:"total_variance_value"` // Nilai rupiah selisih (variance x harga)
+
+	Items      []StockOpnameItem             `json:"items,omitempty"`
+	Categories []StockOpnameCategoryVariance `json:"categories,omitempty"`
+}
+
+// StockOpnameItem adalah satu produk di sesi stok opname.
+type StockOpnameItem struct {
+	ID            int    `json:"id"`
+	StockOpnameID int    `json:"stock_opname_id"`
+	ProductID     int    `json:"product_id"`
+	ProductName   string `json:"product_name"`
+	CategoryID    int    `json:"category_id,omitempty"`
+	CategoryName  string `json:"category_name,omitempty"`
+	UnitPrice     int    `json:"unit_price"` // Snapshot harga untuk menilai selisih
+
+	SnapshotStock int `json:"snapshot_stock"` // products.stock saat sesi dibuka
+	ExpectedStock int `json:"expected_stock"` // products.stock saat hitungan terakhir dikirim
+
+	// CountedQuantity adalah total hitungan semua kasir untuk produk ini, nil jika belum dihitung.
+	CountedQuantity *int               `json:"counted_quantity"`
+	Variance        int                `json:"variance"`       // CountedQuantity - ExpectedStock
+	VarianceValue   int                `json:"variance_value"` // Variance x UnitPrice
+	Counts          []StockOpnameCount `json:"counts,omitempty"`
+}
+
+// StockOpnameCount adalah hasil hitungan satu kasir untuk satu produk.
+// Produk yang sama bisa dihitung beberapa kasir (misal ada di dua rak); hasilnya dijumlahkan.
+type StockOpnameCount struct {
+	ProductID int       `json:"product_id"`
+	Quantity  int       `json:"quantity"`
+	CountedBy string    `json:"counted_by"`
+	CountedAt time.Time `json:"counted_at"`
+}
+
+// StockOpnameCategoryVariance adalah ringkasan selisih per kategori.
+type StockOpnameCategoryVariance struct {
+	CategoryID      int    `json:"category_id"`
+	CategoryName    string `json:"category_name"`
+	CountedProducts int    `json:"counted_products"`
+	Variance        int    `json:"variance"`
+	VarianceValue   int    `json:"variance_value"`
+}
+
+// OpenStockOpnameRequest adalah body untuk membuka sesi stok opname.
+type OpenStockOpnameRequest struct {
+	OutletID   int    `json:"outlet_id,omitempty"`   // Opsional: default outlet utama
+	CategoryID int    `json:"category_id,omitempty"` // Opsional: hanya hitung satu kategori
+	Note       string `json:"note"`
+	OpenedBy   string `json:"opened_by"`
+}
+
+// SubmitStockCountRequest adalah hasil hitungan satu kasir.
+// Mengirim ulang produk yang sama oleh kasir yang sama akan menimpa hitungan sebelumnya.
+type SubmitStockCountRequest struct {
+	CountedBy string           `json:"counted_by"`
+	Items     []StockCountItem `json:"items"`
+}
+
+// StockCountItem adalah jumlah fisik satu produk.
+type StockCountItem struct {
+	ProductID int `json:"product_id"`
+	Quantity  int `json:"quantity"`
+}
+
+// ApproveStockOpnameRequest adalah body untuk menyetujui hasil stok opname.
+type ApproveStockOpnameRequest struct {
+	ApprovedBy string `json:"approved_by"`
+}
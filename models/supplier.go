@@ -0,0 +1,11 @@
+package models
+
+// Supplier merepresentasikan pemasok barang.
+// Struct ini mencerminkan tabel `suppliers` di database.
+type Supplier struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Phone   string `json:"phone,omitempty"`
+	Email   string `json:"email,omitempty"`
+	Address string `json:"address,omitempty"`
+}
@@ -0,0 +1,27 @@
+package models
+
+// TaxConfig adalah pengaturan pajak (PPN/PB1) dan service charge toko.
+// Nilainya dibaca dari konfigurasi aplikasi (.env) saat startup, lalu dipakai setiap checkout.
+type TaxConfig struct {
+	// Inclusive: true jika harga produk SUDAH termasuk pajak & service charge (pajak "dipisahkan" dari harga).
+	// false jika pajak & service charge DITAMBAHKAN di atas harga produk.
+	Inclusive bool
+
+	// DefaultRate adalah tarif pajak dalam persen (misal 11 untuk PPN 11%).
+	DefaultRate float64
+
+	// CategoryRates adalah tarif pajak khusus per kategori (key: category ID, value: persen).
+	// Kategori yang tidak ada di map ini memakai DefaultRate. Isi 0 untuk kategori bebas pajak.
+	CategoryRates map[int]float64
+
+	// ServiceChargeRate adalah service charge dalam persen (misal 5 untuk 5%).
+	ServiceChargeRate float64
+}
+
+// RateFor mengembalikan tarif pajak (persen) untuk kategori tertentu.
+func (c TaxConfig) RateFor(categoryID int) float64 {
+	if rate, ok := c.CategoryRates[categoryID]; ok {
+		return rate
+	}
+	return c.DefaultRate
+}
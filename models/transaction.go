@@ -2,16 +2,51 @@ package models
 
 import "time"
 
+// Status transaksi.
+// Transaksi yang sudah di-void atau di-refund tetap disimpan (tidak dihapus) agar jejak auditnya ada,
+// tapi laporan harus mengurangi nilainya dari omset.
+// Transaksi PENDING_PAYMENT dan EXPIRED belum/tidak pernah dibayar, jadi tidak dihitung sebagai penjualan.
+const (
+	TransactionStatusCompleted         = "COMPLETED"          // Transaksi normal
+	TransactionStatusPartiallyRefunded = "PARTIALLY_REFUNDED" // Sebagian barang sudah dikembalikan
+	TransactionStatusRefunded          = "REFUNDED"           // Semua barang sudah dikembalikan lewat refund
+	TransactionStatusVoided            = "VOIDED"             // Transaksi dibatalkan seluruhnya
+	TransactionStatusPendingPayment    = "PENDING_PAYMENT"    // Menunggu konfirmasi pembayaran non-tunai dari payment gateway
+	TransactionStatusExpired           = "EXPIRED"            // Pembayaran tidak selesai sebelum batas waktu, stok sudah dikembalikan
+)
+
 // Transaction merepresentasikan header transaksi belanja.
 // Struct ini mencerminkan tabel `transactions` di database.
 type Transaction struct {
-	ID            int                 `json:"id"`
-	TotalAmount   int                 `json:"total_amount"`
-	PaidAmount    int                 `json:"paid_amount"`
-	Change        int                 `json:"change"`
-	PaymentMethod string              `json:"payment_method"`
-	CreatedAt     time.Time           `json:"created_at"`
-	Details       []TransactionDetail `json:"details"` // Relasi: Satu transaksi punya banyak detail (One-to-Many)
+	ID             int `json:"id"`
+	GrossAmount    int `json:"gross_amount"`    // Total harga sebelum diskon
+	DiscountAmount int `json:"discount_amount"` // Total potongan dari semua promosi
+
+	// Rincian pajak (untuk laporan pajak bulanan):
+	// TotalAmount = SubtotalAmount + ServiceChargeAmount + TaxAmount
+	SubtotalAmount      int  `json:"subtotal_amount"` // DPP: penjualan setelah diskon, tanpa pajak & service charge
+	ServiceChargeAmount int  `json:"service_charge_amount"`
+	TaxAmount           int  `json:"tax_amount"`
+	TaxInclusive        bool `json:"tax_inclusive"` // Mode harga saat transaksi terjadi (harga sudah termasuk pajak atau belum)
+
+	TotalAmount    int                 `json:"total_amount"` // Yang harus dibayar pelanggan
+	PaidAmount     int                 `json:"paid_amount"`
+	Change         int                 `json:"change"`
+	PaymentMethod  string              `json:"payment_method"`
+	Status         string              `json:"status"`
+	RefundedAmount int                 `json:"refunded_amount"`
+	NetAmount      int                 `json:"net_amount"` // TotalAmount - RefundedAmount (omset bersih transaksi ini)
+	VoidedAt       *time.Time          `json:"voided_at,omitempty"`
+	VoidReason     string              `json:"void_reason,omitempty"`
+	VoidedBy       string              `json:"voided_by,omitempty"`
+	ShiftID        int                 `json:"shift_id,omitempty"` // Shift kasir saat transaksi terjadi (0 = tanpa shift)
+	Cashier        string              `json:"cashier,omitempty"`
+	OutletID       int                 `json:"outlet_id"` // Outlet tempat transaksi terjadi
+	CreatedAt      time.Time           `json:"created_at"`
+	Details        []TransactionDetail `json:"details"`              // Relasi: Satu transaksi punya banyak detail (One-to-Many)
+	Refunds        []Refund            `json:"refunds,omitempty"`    // Riwayat refund/void untuk transaksi ini
+	Promotions     []AppliedPromotion  `json:"promotions,omitempty"` // Promosi yang dipakai di transaksi ini
+	Payments       []Payment           `json:"payments,omitempty"`   // Rincian pembayaran per metode (split payment)
 }
 
 // TransactionDetail merepresentasikan detail item dalam satu transaksi.
@@ -21,21 +56,101 @@ type TransactionDetail struct {
 	TransactionID int    `json:"transaction_id"`
 	ProductID     int    `json:"product_id"`
 	ProductName   string `json:"product_name,omitempty"` // omitempty: Field ini tidak akan muncul di JSON jika string-nya kosong ""
+	CategoryID    int    `json:"category_id,omitempty"`
+	CategoryName  string `json:"category_name,omitempty"`
+	UnitPrice     int    `json:"unit_price"` // Harga satuan saat transaksi terjadi (snapshot, tidak ikut berubah jika harga produk diubah)
 	Quantity      int    `json:"quantity"`
-	Subtotal      int    `json:"subtotal"` // Harga satuan * Quantity saat transaksi terjadi
+
+	// ParentID adalah produk induk jika ProductID adalah varian (0 jika bukan), supaya promosi produk induk ikut berlaku.
+	// Hanya diisi saat checkout, tidak disimpan.
+	ParentID int `json:"-"`
+
+	// Unit adalah satuan jual baris ini (UnitPrice & Quantity dalam satuan ini).
+	// UnitFactor = jumlah satuan dasar per Unit, jadi stok yang keluar = Quantity x UnitFactor.
+	Unit       string `json:"unit,omitempty"`
+	UnitFactor int    `json:"unit_factor"`
+
+	// Potongan untuk baris ini: diskon item + bagian dari diskon keranjang.
+	DiscountAmount int `json:"discount_amount"`
+
+	// Subtotal adalah DPP baris ini: harga satuan * Quantity - DiscountAmount, tanpa pajak & service charge.
+	// Yang dibayar pelanggan untuk baris ini = Subtotal + ServiceChargeAmount + TaxAmount.
+	Subtotal            int     `json:"subtotal"`
+	ServiceChargeAmount int     `json:"service_charge_amount"`
+	TaxRate             float64 `json:"tax_rate"` // Tarif pajak (persen) yang dipakai saat transaksi
+	TaxAmount           int     `json:"tax_amount"`
+
+	// COGS adalah total harga pokok (HPP) barang di baris ini saat checkout, sesuai metode HPP yang berlaku.
+	COGS int `json:"cogs"`
+
+	// Jumlah barang di baris ini yang sudah dikembalikan (refund/void).
+	RefundedQuantity int `json:"refunded_quantity"`
+}
+
+// Refund mencatat pengembalian barang dari satu baris TransactionDetail.
+// Void seluruh transaksi juga dicatat sebagai Refund untuk setiap baris yang masih tersisa.
+type Refund struct {
+	ID                  int       `json:"id"`
+	TransactionID       int       `json:"transaction_id"`
+	TransactionDetailID int       `json:"transaction_detail_id"`
+	ProductID           int       `json:"product_id"`
+	Quantity            int       `json:"quantity"`
+	Amount              int       `json:"amount"` // Uang yang dikembalikan ke pelanggan
+	Reason              string    `json:"reason"`
+	RefundedBy          string    `json:"refunded_by"`
+	CreatedAt           time.Time `json:"created_at"`
+}
+
+// VoidRequest adalah input untuk membatalkan seluruh transaksi.
+type VoidRequest struct {
+	Reason   string `json:"reason"`    // Wajib diisi, misal: "salah input"
+	VoidedBy string `json:"voided_by"` // Nama/ID kasir atau supervisor yang melakukan void
+}
+
+// RefundItem adalah satu baris barang yang ingin dikembalikan.
+type RefundItem struct {
+	DetailID int `json:"detail_id"` // ID dari TransactionDetail
+	Quantity int `json:"quantity"`
+}
+
+// RefundRequest adalah input untuk refund sebagian barang dari sebuah transaksi.
+type RefundRequest struct {
+	Items      []RefundItem `json:"items"`
+	Reason     string       `json:"reason"`
+	RefundedBy string       `json:"refunded_by"`
 }
 
 // CheckoutItem adalah input dari User/Frontend untuk request checkout.
 // Kita pisahkan struct ini karena User hanya perlu kirim ProductID dan Qty, sisanya (Harga, Nama) kita ambil dari DB.
 type CheckoutItem struct {
-	ProductID int `json:"product_id"`
-	Quantity  int `json:"quantity"`
+	ProductID int    `json:"product_id,omitempty"`
+	VariantID int    `json:"variant_id,omitempty"` // Wajib untuk produk yang punya varian (product_id boleh diisi ID induknya)
+	Barcode   string `json:"barcode,omitempty"`    // Hasil scan (barcode atau SKU), dipakai jika product_id kosong
+	Quantity  int    `json:"quantity"`
+	Unit      string `json:"unit,omitempty"` // Satuan jual (misal "pak"); kosong = satuan dasar produk
 }
 
 type CheckoutRequest struct {
-	Items         []CheckoutItem `json:"items"`
-	PaidAmount    int            `json:"paid_amount"`
-	PaymentMethod string         `json:"payment_method"` // "CASH", "QRIS"
+	Items []CheckoutItem `json:"items"`
+
+	// Cara bayar lama (satu metode saja). Tetap didukung jika Payments kosong.
+	PaidAmount    int    `json:"paid_amount"`
+	PaymentMethod string `json:"payment_method"` // "CASH", "QRIS"
+
+	// Payments dipakai untuk split payment, misal sebagian CASH dan sebagian QRIS.
+	// Jika diisi, PaidAmount dan PaymentMethod diabaikan.
+	Payments []PaymentInput `json:"payments,omitempty"`
+
+	// Cashier adalah kasir yang melayani. Jika diisi, kasir ini wajib punya shift OPEN
+	// dan transaksinya dicatat ke shift tersebut (masuk Z-report).
+	Cashier string `json:"cashier,omitempty"`
+
+	// OutletID adalah outlet tempat transaksi terjadi; stok outlet ini yang dicek & dikurangi (0 = outlet utama).
+	OutletID int `json:"outlet_id,omitempty"`
+
+	// IdempotencyKey diisi handler dari header `Idempotency-Key` (bukan dari body JSON, makanya `json:"-"`).
+	// Request dengan key yang sama tidak akan membuat transaksi baru, tapi mengembalikan transaksi yang pertama.
+	IdempotencyKey string `json:"-"`
 }
 
 // ProductSales merepresentasikan data penjualan produk (untuk report).
@@ -48,7 +163,15 @@ type ProductSales struct {
 // SalesSummary adalah response untuk endpoint report harian.
 // Menggabungkan total omset, jumlah transaksi, dan produk best seller dalam satu response JSON.
 type SalesSummary struct {
-	TotalRevenue   int          `json:"total_revenue"`
-	TotalTransaksi int          `json:"total_transaksi"`
-	ProdukTerlaris ProductSales `json:"produk_terlaris"`
+	TotalRevenue       int          `json:"total_revenue"`        // Uang yang diterima (setelah diskon & refund, termasuk pajak & service charge)
+	GrossRevenue       int          `json:"gross_revenue"`        // Omset kotor (harga normal sebelum diskon)
+	TotalDiscount      int          `json:"total_discount"`       // Total potongan promosi
+	NetSales           int          `json:"net_sales"`            // DPP: penjualan setelah diskon tanpa pajak & service charge
+	TotalServiceCharge int          `json:"total_service_charge"` // Total service charge
+	TotalTax           int          `json:"total_tax"`            // Total pajak yang dipungut
+	TotalTransaksi     int          `json:"total_transaksi"`
+	ProdukTerlaris     ProductSales `json:"produk_terlaris"`
+
+	// Rincian uang masuk per metode pembayaran (CASH, QRIS, dst).
+	PaymentBreakdown []TenderSummary `json:"payment_breakdown"`
 }
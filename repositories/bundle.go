@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"codeWithUmam/models"
+	"database/sql"
+)
+
+// bundleComponent adalah satu komponen paket saat checkout. Quantity awalnya per 1 satuan dasar paket,
+// lalu dikalikan jumlah satuan dasar paket yang dibeli sehingga menjadi total yang keluar untuk satu baris.
+// Sellable adalah stok yang boleh dijual (tanpa batch kedaluwarsa), Stock adalah seluruh stoknya; keduanya di outlet checkout.
+type bundleComponent struct {
+	ID       int
+	Name     string
+	Stock    int
+	Sellable int
+	Quantity int
+}
+
+// loadBundleComponents mengambil komponen produk productID beserta stok terkininya di outlet outletID pada hari today
+// (kosong jika bukan paket).
+func loadBundleComponents(tx *sql.Tx, productID, outletID int, today string) ([]bundleComponent, error) {
+	rows, err := tx.Query(`
+		SELECT p.id, p.name, `+outletStockColumn+`, `+sellableStockColumn+`, pc.quantity
+		FROM product_components pc
+		JOIN products p ON p.id = pc.component_id
+		WHERE pc.product_id = ?
+		ORDER BY pc.id`, outletID, outletID, today, outletID, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var components []bundleComponent
+	for rows.Next() {
+		var c bundleComponent
+		if err := rows.Scan(&c.ID, &c.Name, &c.Stock, &c.Sellable, &c.Quantity); err != nil {
+			return nil, err
+		}
+		components = append(components, c)
+	}
+	return components, rows.Err()
+}
+
+// returnDetailStock mengembalikan stok untuk quantity (satuan jual) dari satu baris transaksi berisi lineQuantity,
+// dengan harga pokok yang sama seperti saat keluar. Baris paket mengembalikan stok komponennya secara proporsional;
+// baris biasa mengembalikan stok produknya (produk yang sudah dihapus dilewati). Stok kembali ke outlet tempat transaksinya terjadi.
+func returnDetailStock(tx *sql.Tx, detailID, productID, quantity, lineQuantity, unitFactor, cogs, transactionID int, note string) error {
+	var outletID int
+	if err := tx.QueryRow("SELECT outlet_id FROM transactions WHERE id = ?", transactionID).Scan(&outletID); err != nil {
+		return err
+	}
+
+	rows, err := tx.Query("SELECT component_id, quantity, cogs FROM transaction_detail_components WHERE transaction_detail_id = ? ORDER BY id", detailID)
+	if err != nil {
+		return err
+	}
+	var movements []models.StockMovement
+	for rows.Next() {
+		var componentID, componentQty, componentCOGS int
+		if err := rows.Scan(&componentID, &componentQty, &componentCOGS); err != nil {
+			rows.Close()
+			return err
+		}
+		// componentQty selalu kelipatan lineQuantity, jadi pembagiannya pas
+		movements = append(movements, models.StockMovement{
+			ProductID: componentID,
+			Quantity:  componentQty * quantity / lineQuantity,
+			UnitCost:  componentCOGS / componentQty,
+		})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if len(movements) == 0 {
+		movements = append(movements, models.StockMovement{
+			ProductID: productID,
+			Quantity:  quantity * unitFactor,
+			UnitCost:  cogs / (lineQuantity * unitFactor),
+		})
+	}
+
+	for _, m := range movements {
+		m.Type = models.StockMovementRefund
+		m.OutletID = outletID
+		m.ReferenceType = models.StockReferenceTransaction
+		m.ReferenceID = transactionID
+		m.Note = note
+		if _, err := recordStockMovement(tx, m); err != nil && err != ErrNotFound {
+			return err
+		}
+	}
+	return nil
+}
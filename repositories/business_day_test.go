@@ -0,0 +1,148 @@
+package repositories
+
+import (
+	"codeWithUmam/models"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+// setTransactionTime mengubah created_at transaksi (disimpan dalam UTC, seperti CURRENT_TIMESTAMP).
+func setTransactionTime(t *testing.T, repo *TransactionRepository, id int, utc string) {
+	t.Helper()
+	if _, err := repo.db.Exec("UPDATE transactions SET created_at = ? WHERE id = ?", utc, id); err != nil {
+		t.Fatalf("failed to set created_at: %v", err)
+	}
+}
+
+func TestTransactionRepository_BusinessDayBoundaries(t *testing.T) {
+	zones := []struct {
+		name, timezone       string
+		lastSecond, midnight string // 23:59:59 dan 00:00:00 waktu lokal, dalam UTC
+	}{
+		{"WIB", "Asia/Jakarta", "2026-03-09 16:59:59", "2026-03-09 17:00:00"},
+		{"WITA", "Asia/Makassar", "2026-03-09 15:59:59", "2026-03-09 16:00:00"},
+		{"WIT", "Asia/Jayapura", "2026-03-09 14:59:59", "2026-03-09 15:00:00"},
+	}
+	for _, z := range zones {
+		t.Run(z.name, func(t *testing.T) {
+			location, err := time.LoadLocation(z.timezone)
+			if err != nil {
+				t.Fatalf("failed to load %s: %v", z.timezone, err)
+			}
+			db := setupTransactionTestDB(t)
+			repo := NewTransactionRepository(db, models.TaxConfig{}, models.CostingMethodAverage, models.BusinessDay{Location: location})
+			productID := seedProduct(t, db, "Es Teh", 5000, 10)
+
+			var ids []int
+			for _, createdAt := range []string{z.lastSecond, z.midnight} {
+				trx, err := repo.CreateTransaction(models.CheckoutRequest{Items: []models.CheckoutItem{{ProductID: productID, Quantity: 1}}, PaidAmount: 5000, PaymentMethod: "CASH"}, CheckoutOptions{})
+				if err != nil {
+					t.Fatalf("CreateTransaction failed: %v", err)
+				}
+				setTransactionTime(t, repo, trx.ID, createdAt)
+				ids = append(ids, trx.ID)
+			}
+
+			// Penjualan 23:59:59 lokal masih tanggal 9, penjualan 00:00:00 lokal sudah tanggal 10 (padahal di UTC keduanya tanggal 9)
+			for date, wantID := range map[string]int{"2026-03-09": ids[0], "2026-03-10": ids[1]} {
+				history, err := repo.FindAll(date, date, 0)
+				if err != nil {
+					t.Fatalf("FindAll failed: %v", err)
+				}
+				if len(history) != 1 || history[0].ID != wantID {
+					t.Errorf("history %s: expected only transaction %d, got %+v", date, wantID, history)
+				}
+			}
+
+			report, err := repo.GetSalesReport("2026-03-09", "2026-03-10", models.SalesGroupByDay, 10, 0)
+			if err != nil {
+				t.Fatalf("GetSalesReport failed: %v", err)
+			}
+			assertRows(t, z.name, report.Rows, []models.SalesReportRow{
+				{Key: "2026-03-09", Transactions: 1, Items: 1, Revenue: 5000},
+				{Key: "2026-03-10", Transactions: 1, Items: 1, Revenue: 5000},
+			})
+
+			profit, err := repo.GetProfitReport("2026-03-10", "2026-03-10", 0)
+			if err != nil {
+				t.Fatalf("GetProfitReport failed: %v", err)
+			}
+			if profit.Revenue != 5000 || len(profit.ByDay) != 1 || profit.ByDay[0].Name != "2026-03-10" {
+				t.Errorf("expected only the after-midnight sale on 2026-03-10, got %+v", profit)
+			}
+		})
+	}
+}
+
+func TestTransactionRepository_BusinessDayCutoff(t *testing.T) {
+	location, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatalf("failed to load Asia/Jakarta: %v", err)
+	}
+	db := setupTransactionTestDB(t)
+	repo := NewTransactionRepository(db, models.TaxConfig{}, models.CostingMethodAverage, models.BusinessDay{Location: location, Cutoff: 4 * time.Hour})
+	productID := seedProduct(t, db, "Nasi Goreng", 20000, 10)
+
+	// 03:30 WIB tanggal 10 masih hari bisnis tanggal 9; 04:00 WIB sudah hari bisnis tanggal 10
+	for _, createdAt := range []string{"2026-03-09 20:30:00", "2026-03-09 21:00:00"} {
+		trx, err := repo.CreateTransaction(models.CheckoutRequest{Items: []models.CheckoutItem{{ProductID: productID, Quantity: 1}}, PaidAmount: 20000, PaymentMethod: "CASH"}, CheckoutOptions{})
+		if err != nil {
+			t.Fatalf("CreateTransaction failed: %v", err)
+		}
+		setTransactionTime(t, repo, trx.ID, createdAt)
+	}
+
+	report, err := repo.GetSalesReport("2026-03-09", "2026-03-10", models.SalesGroupByDay, 10, 0)
+	if err != nil {
+		t.Fatalf("GetSalesReport failed: %v", err)
+	}
+	assertRows(t, "cutoff day", report.Rows, []models.SalesReportRow{
+		{Key: "2026-03-09", Transactions: 1, Items: 1, Revenue: 20000},
+		{Key: "2026-03-10", Transactions: 1, Items: 1, Revenue: 20000},
+	})
+
+	// Jam tetap jam dinding toko, tidak digeser jam tutup buku
+	report, err = repo.GetSalesReport("2026-03-09", "2026-03-10", models.SalesGroupByHour, 10, 0)
+	if err != nil {
+		t.Fatalf("GetSalesReport failed: %v", err)
+	}
+	assertRows(t, "cutoff hour", report.Rows, []models.SalesReportRow{
+		{Key: "03:00", Transactions: 1, Items: 1, Revenue: 20000},
+		{Key: "04:00", Transactions: 1, Items: 1, Revenue: 20000},
+	})
+
+	history, err := repo.FindAll("2026-03-09", "2026-03-09", 0)
+	if err != nil {
+		t.Fatalf("FindAll failed: %v", err)
+	}
+	if len(history) != 1 {
+		t.Errorf("expected 1 transaction on business day 2026-03-09, got %d", len(history))
+	}
+
+	// Cursor export memakai filter yang sama dengan FindAll, dengan jam dalam zona waktu toko
+	var exported []models.Transaction
+	err = repo.ForEachTransaction("2026-03-09", "2026-03-10", 0, func(t models.Transaction) error {
+		exported = append(exported, t)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ForEachTransaction failed: %v", err)
+	}
+	if len(exported) != 2 {
+		t.Fatalf("expected 2 exported transactions, got %d", len(exported))
+	}
+	if got := exported[0].CreatedAt.Format("15:04"); got != "03:30" {
+		t.Errorf("expected first export row at 03:30 store time, got %s", got)
+	}
+
+	if got := repo.BusinessDay().Date(time.Date(2026, 3, 9, 20, 59, 59, 0, time.UTC)); got != "2026-03-09" {
+		t.Errorf("expected 03:59:59 WIB to belong to 2026-03-09, got %s", got)
+	}
+
+	if _, err := repo.FindAll("09-03-2026", "2026-03-10", 0); err == nil {
+		t.Error("expected invalid date to be rejected")
+	} else if _, ok := err.(*ValidationError); !ok {
+		t.Errorf("expected ValidationError, got %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"codeWithUmam/models"
+	"database/sql"
+	"math"
+)
+
+// stockCost adalah nilai persediaan (rupiah) yang masuk/keluar pada satu pergerakan stok.
+// Kedua metode selalu dihitung supaya lapisan FIFO dan harga rata-rata tetap akurat
+// walaupun metode HPP yang dipakai toko diganti di tengah jalan.
+type stockCost struct {
+	Average int
+	FIFO    int
+}
+
+// forMethod mengambil nilai sesuai metode HPP yang dipakai (default AVERAGE).
+func (c stockCost) forMethod(method string) int {
+	if method == models.CostingMethodFIFO {
+		return c.FIFO
+	}
+	return c.Average
+}
+
+// applyStockCost memperbarui harga pokok produk untuk pergerakan sebanyak quantity unit, di dalam Database Transaction tx.
+// Dipanggil oleh recordStockMovement SEBELUM products.stock diubah.
+//   - Barang masuk (quantity > 0): harga rata-rata dihitung ulang dan satu lapisan FIFO baru ditambahkan.
+//     unitCost <= 0 berarti harga belinya tidak diketahui (misal koreksi stok), jadi dipakai harga rata-rata saat ini.
+//   - Barang keluar (quantity < 0): lapisan FIFO paling lama dikonsumsi; harga rata-rata tidak berubah.
+//
+// Mengembalikan nilai persediaan yang berpindah dan harga satuan yang dicatat di ledger.
+func applyStockCost(tx *sql.Tx, productID, quantity, unitCost int) (stockCost, int, error) {
+	var stock, averageCost int
+	err := tx.QueryRow("SELECT stock, cost_price FROM products WHERE id = ?", productID).Scan(&stock, &averageCost)
+	if err == sql.ErrNoRows {
+		return stockCost{}, 0, ErrNotFound
+	}
+	if err != nil {
+		return stockCost{}, 0, err
+	}
+
+	if quantity < 0 {
+		out := -quantity
+		fifo, err := consumeCostLayers(tx, productID, out, averageCost)
+		if err != nil {
+			return stockCost{}, 0, err
+		}
+		return stockCost{Average: out * averageCost, FIFO: fifo}, averageCost, nil
+	}
+
+	if unitCost <= 0 {
+		unitCost = averageCost
+	}
+	newAverage := movingAverageCost(stock, averageCost, quantity, unitCost)
+	if _, err := tx.Exec("UPDATE products SET cost_price = ? WHERE id = ?", newAverage, productID); err != nil {
+		return stockCost{}, 0, err
+	}
+	_, err = tx.Exec("INSERT INTO cost_layers (product_id, unit_cost, quantity, remaining_quantity) VALUES (?, ?, ?, ?)",
+		productID, unitCost, quantity, quantity)
+	if err != nil {
+		return stockCost{}, 0, err
+	}
+	return stockCost{Average: quantity * unitCost, FIFO: quantity * unitCost}, unitCost, nil
+}
+
+// movingAverageCost menghitung harga rata-rata tertimbang setelah quantity unit masuk dengan harga unitCost.
+// Stok minus dianggap 0, dan harga rata-rata 0 (belum diketahui) tidak ikut menurunkan rata-rata.
+// Fungsi ini murni (tidak menyentuh database) agar mudah di-test.
+func movingAverageCost(stock, averageCost, quantity, unitCost int) int {
+	if stock <= 0 || averageCost <= 0 {
+		return unitCost
+	}
+	total := float64(stock*averageCost + quantity*unitCost)
+	return int(math.Round(total / float64(stock+quantity)))
+}
+
+// consumeCostLayers mengurangi lapisan FIFO produk sebanyak quantity unit, mulai dari yang paling lama.
+// Lapisan dengan harga 0 (harga belum diketahui) dan kekurangan lapisan (stok lama sebelum fitur HPP)
+// dinilai dengan fallbackCost (harga rata-rata saat ini).
+func consumeCostLayers(tx *sql.Tx, productID, quantity, fallbackCost int) (int, error) {
+	rows, err := tx.Query("SELECT id, unit_cost, remaining_quantity FROM cost_layers WHERE product_id = ? AND remaining_quantity > 0 ORDER BY id", productID)
+	if err != nil {
+		return 0, err
+	}
+	type layer struct{ id, unitCost, remaining int }
+	var layers []layer
+	for rows.Next() {
+		var l layer
+		if err := rows.Scan(&l.id, &l.unitCost, &l.remaining); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		layers = append(layers, l)
+	}
+	rows.Close()
+
+	total, left := 0, quantity
+	for _, l := range layers {
+		if left == 0 {
+			break
+		}
+		take := l.remaining
+		if take > left {
+			take = left
+		}
+		cost := l.unitCost
+		if cost <= 0 {
+			cost = fallbackCost
+		}
+		total += take * cost
+		left -= take
+		if _, err := tx.Exec("UPDATE cost_layers SET remaining_quantity = remaining_quantity - ? WHERE id = ?", take, l.id); err != nil {
+			return 0, err
+		}
+	}
+	total += left * fallbackCost
+	return total, nil
+}
@@ -0,0 +1,141 @@
+package repositories
+
+import (
+	"codeWithUmam/models"
+	"database/sql"
+	"math"
+	"testing"
+	"time"
+)
+
+// receiveStock menambah stok lewat ledger dengan harga beli tertentu (seperti penerimaan barang dari PO).
+func receiveStock(t *testing.T, db *sql.DB, productID, quantity, unitCost int) {
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("failed to begin tx: %v", err)
+	}
+	defer tx.Rollback()
+	_, err = recordStockMovement(tx, models.StockMovement{ProductID: productID, Type: models.StockMovementReceiving, Quantity: quantity, UnitCost: unitCost})
+	if err != nil {
+		t.Fatalf("recordStockMovement failed: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+}
+
+func TestMovingAverageCost(t *testing.T) {
+	cases := []struct {
+		name                                         string
+		stock, averageCost, quantity, unitCost, want int
+	}{
+		{"rata-rata tertimbang", 10, 1000, 10, 2000, 1500},
+		{"stok kosong memakai harga baru", 0, 1000, 5, 1200, 1200},
+		{"stok minus dianggap kosong", -3, 1000, 5, 1200, 1200},
+		{"harga lama belum diketahui", 10, 0, 5, 1200, 1200},
+		{"dibulatkan", 2, 1000, 1, 1001, 1000},
+	}
+	for _, c := range cases {
+		if got := movingAverageCost(c.stock, c.averageCost, c.quantity, c.unitCost); got != c.want {
+			t.Errorf("%s: expected %d, got %d", c.name, c.want, got)
+		}
+	}
+}
+
+func TestTransactionRepository_COGSPerCostingMethod(t *testing.T) {
+	for method, wantCOGS := range map[string]int{
+		models.CostingMethodAverage: 12 * 1500,        // 20 unit rata-rata 1500
+		models.CostingMethodFIFO:    10*1000 + 2*2000, // 10 unit lama @1000 lalu 2 unit baru @2000
+	} {
+		t.Run(method, func(t *testing.T) {
+			db := setupTransactionTestDB(t)
+			p := &models.Product{Name: "Kopi Bubuk", Price: 3000, Stock: 10, CostPrice: 1000}
+			if err := NewProductRepository(db).Create(p); err != nil {
+				t.Fatalf("failed to create product: %v", err)
+			}
+			receiveStock(t, db, p.ID, 10, 2000)
+
+			repo := NewTransactionRepository(db, models.TaxConfig{}, method, models.BusinessDay{})
+			trx, err := repo.CreateTransaction(models.CheckoutRequest{
+				Items:         []models.CheckoutItem{{ProductID: p.ID, Quantity: 12}},
+				PaidAmount:    36000,
+				PaymentMethod: "CASH",
+			}, CheckoutOptions{})
+			if err != nil {
+				t.Fatalf("CreateTransaction failed: %v", err)
+			}
+			if trx.Details[0].COGS != wantCOGS {
+				t.Errorf("expected cogs %d, got %d", wantCOGS, trx.Details[0].COGS)
+			}
+
+			saved, err := repo.FindByID(trx.ID)
+			if err != nil {
+				t.Fatalf("FindByID failed: %v", err)
+			}
+			if saved.Details[0].COGS != wantCOGS {
+				t.Errorf("expected stored cogs %d, got %d", wantCOGS, saved.Details[0].COGS)
+			}
+
+			// Refund 2 unit: laporan laba hanya menghitung 10 unit yang tidak dikembalikan
+			if _, err := repo.RefundTransaction(trx.ID, []models.RefundItem{{DetailID: trx.Details[0].ID, Quantity: 2}}, "rusak", "kasir"); err != nil {
+				t.Fatalf("RefundTransaction failed: %v", err)
+			}
+			today := time.Now().UTC().Format("2006-01-02")
+			report, err := repo.GetProfitReport(today, today, 0)
+			if err != nil {
+				t.Fatalf("GetProfitReport failed: %v", err)
+			}
+			wantNetCOGS := int(math.Round(float64(wantCOGS) * 10 / 12))
+			if report.Revenue != 30000 || report.COGS != wantNetCOGS || report.GrossProfit != 30000-wantNetCOGS {
+				t.Errorf("unexpected totals: %+v", report)
+			}
+			if report.CostingMethod != method || len(report.ByProduct) != 1 || len(report.ByCategory) != 1 || len(report.ByDay) != 1 {
+				t.Fatalf("unexpected breakdown: %+v", report)
+			}
+			if line := report.ByProduct[0]; line.Name != "Kopi Bubuk" || line.Quantity != 10 || line.COGS != wantNetCOGS {
+				t.Errorf("unexpected product line: %+v", line)
+			}
+			if report.ByDay[0].Name != today {
+				t.Errorf("expected day %s, got %s", today, report.ByDay[0].Name)
+			}
+		})
+	}
+}
+
+func TestCostLayers_FIFOConsumptionAndRefund(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	p := &models.Product{Name: "Beras 5kg", Price: 80000, Stock: 2, CostPrice: 60000}
+	if err := NewProductRepository(db).Create(p); err != nil {
+		t.Fatalf("failed to create product: %v", err)
+	}
+	receiveStock(t, db, p.ID, 3, 65000)
+
+	repo := NewTransactionRepository(db, models.TaxConfig{}, models.CostingMethodFIFO, models.BusinessDay{})
+	trx, err := repo.CreateTransaction(models.CheckoutRequest{
+		Items:         []models.CheckoutItem{{ProductID: p.ID, Quantity: 2}},
+		PaidAmount:    160000,
+		PaymentMethod: "CASH",
+	}, CheckoutOptions{})
+	if err != nil {
+		t.Fatalf("CreateTransaction failed: %v", err)
+	}
+	if trx.Details[0].COGS != 120000 {
+		t.Fatalf("expected first sale to consume the oldest layer (120000), got %d", trx.Details[0].COGS)
+	}
+
+	// Barang yang dikembalikan masuk lagi sebagai lapisan baru dengan harga pokok saat terjual
+	if _, err := repo.RefundTransaction(trx.ID, []models.RefundItem{{DetailID: trx.Details[0].ID, Quantity: 1}}, "batal", "kasir"); err != nil {
+		t.Fatalf("RefundTransaction failed: %v", err)
+	}
+	trx, err = repo.CreateTransaction(models.CheckoutRequest{
+		Items:         []models.CheckoutItem{{ProductID: p.ID, Quantity: 4}},
+		PaidAmount:    320000,
+		PaymentMethod: "CASH",
+	}, CheckoutOptions{})
+	if err != nil {
+		t.Fatalf("CreateTransaction failed: %v", err)
+	}
+	if want := 3*65000 + 60000; trx.Details[0].COGS != want {
+		t.Errorf("expected cogs %d, got %d", want, trx.Details[0].COGS)
+	}
+}
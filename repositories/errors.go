@@ -0,0 +1,38 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// ErrNotFound dikembalikan ketika data yang diminta tidak ada di database.
+// Handler akan menerjemahkannya menjadi HTTP 404.
+var ErrNotFound = errors.New("data tidak ditemukan")
+
+// ErrDuplicateIdempotencyKey dikembalikan CreateTransaction jika Idempotency-Key sudah dipakai transaksi lain.
+// Biasanya terjadi saat dua request retry yang sama masuk hampir bersamaan.
+var ErrDuplicateIdempotencyKey = errors.New("idempotency key sudah dipakai")
+
+// ValidationError menandakan request ditolak karena aturan bisnis
+// (misal: transaksi sudah di-void, jumlah refund melebihi jumlah beli),
+// bukan karena error teknis database. Handler akan menerjemahkannya menjadi HTTP 400.
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// NewValidationError membuat ValidationError dengan format seperti fmt.Sprintf.
+func NewValidationError(format string, args ...interface{}) error {
+	return &ValidationError{Message: fmt.Sprintf(format, args...)}
+}
+
+// isUniqueViolation mengecek apakah error dari SQLite disebabkan oleh pelanggaran UNIQUE constraint/index.
+func isUniqueViolation(err error) bool {
+	var sqliteErr sqlite3.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique
+}
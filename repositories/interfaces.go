@@ -12,8 +12,79 @@ type CategoryRepository interface {
 
 type ProductRepository interface {
 	GetAll(name string) ([]models.Product, error)
+	ForEach(fn func(models.Product) error) error
 	Create(product *models.Product) error
 	GetByID(id int) (*models.Product, error)
+	GetByCode(code string) (*models.Product, error)
+	GetSelection(categoryID int, ids []int) ([]models.Product, error)
+	GetLowStock(outletID int) ([]models.LowStockItem, error)
 	Update(product *models.Product) error
+	Import(rows []models.ProductImportRow, dryRun bool) (*models.ProductImportResult, error)
 	Delete(id int) error
+	CreateVariant(parentID int, variant *models.ProductVariant) error
+	UpdateVariant(parentID int, variant *models.ProductVariant) error
+	DeleteVariant(parentID, variantID int) error
+}
+
+type PromotionRepository interface {
+	GetAll() ([]models.Promotion, error)
+	Create(promotion *models.Promotion) error
+	GetByID(id int) (*models.Promotion, error)
+	Update(promotion *models.Promotion) error
+	Delete(id int) error
+}
+
+type StockMovementRepository interface {
+	GetByProduct(productID int) ([]models.StockMovement, error)
+	CheckConsistency() (*models.StockConsistencyReport, error)
+	GetBatches(productID int, today string) ([]models.StockBatch, error)
+	GetExpiringBatches(today, until string) ([]models.ExpiringStockCategory, error)
+}
+
+type SupplierRepository interface {
+	GetAll() ([]models.Supplier, error)
+	Create(supplier *models.Supplier) error
+	GetByID(id int) (*models.Supplier, error)
+	Update(supplier *models.Supplier) error
+	Delete(id int) error
+}
+
+type PurchaseOrderRepository interface {
+	GetAll(supplierID int, status string) ([]models.PurchaseOrder, error)
+	Create(req models.CreatePurchaseOrderRequest) (*models.PurchaseOrder, error)
+	GetByID(id int) (*models.PurchaseOrder, error)
+	Receive(orderID int, req models.ReceiveGoodsRequest) (*models.PurchaseOrder, error)
+	Cancel(orderID int) (*models.PurchaseOrder, error)
+}
+
+type ShiftRepository interface {
+	Open(req models.OpenShiftRequest) (*models.Shift, error)
+	GetAll(status, cashier string) ([]models.Shift, error)
+	GetByID(id int) (*models.Shift, error)
+	Close(id int, req models.CloseShiftRequest) (*models.Shift, error)
+}
+
+type StockOpnameRepository interface {
+	Open(req models.OpenStockOpnameRequest) (*models.StockOpname, error)
+	GetAll() ([]models.StockOpname, error)
+	GetByID(id int) (*models.StockOpname, error)
+	SubmitCounts(id int, req models.SubmitStockCountRequest) (*models.StockOpname, error)
+	Approve(id int, approvedBy string) (*models.StockOpname, error)
+	Cancel(id int) (*models.StockOpname, error)
+}
+
+type OutletRepository interface {
+	GetAll() ([]models.Outlet, error)
+	Create(outlet *models.Outlet) error
+	GetByID(id int) (*models.Outlet, error)
+	Update(outlet *models.Outlet) error
+	GetStock(outletID int) ([]models.OutletStockItem, error)
+}
+
+type StockTransferRepository interface {
+	GetAll(outletID int, status string) ([]models.StockTransfer, error)
+	GetByID(id int) (*models.StockTransfer, error)
+	Create(req models.CreateStockTransferRequest) (*models.StockTransfer, error)
+	Receive(id int, receivedBy string) (*models.StockTransfer, error)
+	Cancel(id int) (*models.StockTransfer, error)
 }
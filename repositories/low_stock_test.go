@@ -0,0 +1,179 @@
+package repositories
+
+import (
+	"codeWithUmam/models"
+	"testing"
+)
+
+// intPtr dipakai untuk field angka opsional (nil = tidak diubah) seperti Product.MinStock.
+func intPtr(n int) *int {
+	return &n
+}
+
+func TestProductRepository_GetLowStock(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	repo := NewProductRepository(db)
+
+	products := []*models.Product{
+		{Name: "Gula", Price: 15000, Stock: 10, MinStock: intPtr(5)},
+		{Name: "Kopi", Price: 30000, Stock: 3, MinStock: intPtr(5), ReorderQty: intPtr(24)},
+		{Name: "Garam", Price: 5000, Stock: 0},
+		{Name: "Es Teh", Price: 5000, MinStock: intPtr(50), Variants: []models.ProductVariant{
+			{Name: "M", Price: 5000, Stock: 8, MinStock: intPtr(10)},
+			{Name: "L", Price: 7000, Stock: 30, MinStock: intPtr(10)},
+		}},
+	}
+	for _, p := range products {
+		if err := repo.Create(p); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	// Gula di atas batas, Garam tidak dipantau, induk Es Teh tidak punya stok sendiri
+	items, err := repo.GetLowStock(0)
+	if err != nil {
+		t.Fatalf("GetLowStock failed: %v", err)
+	}
+	if len(items) != 2 || items[0].Name != "Kopi" || items[1].Name != "Es Teh - M" {
+		t.Fatalf("expected Kopi then Es Teh - M, got %+v", items)
+	}
+	if items[0].Shortage != 2 || items[0].ReorderQty != 24 {
+		t.Errorf("unexpected Kopi line: %+v", items[0])
+	}
+}
+
+func TestProductRepository_UpdateKeepsStockThreshold(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	repo := NewProductRepository(db)
+
+	kopi := &models.Product{Name: "Kopi", Price: 30000, Stock: 3, MinStock: intPtr(5), ReorderQty: intPtr(10)}
+	teh := &models.Product{Name: "Es Teh", Price: 5000, Variants: []models.ProductVariant{{Name: "M", Price: 5000, Stock: 2, MinStock: intPtr(4)}}}
+	for _, p := range []*models.Product{kopi, teh} {
+		if err := repo.Create(p); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	// Client lama tidak mengirim min_stock & reorder_qty: batasnya tetap
+	if err := repo.Update(&models.Product{ID: kopi.ID, Name: "Kopi Bubuk", Price: 32000, Stock: 3}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	variant := teh.Variants[0]
+	if err := repo.UpdateVariant(teh.ID, &models.ProductVariant{ID: variant.ID, Name: "M", Price: 6000, Stock: 2}); err != nil {
+		t.Fatalf("UpdateVariant failed: %v", err)
+	}
+	items, err := repo.GetLowStock(0)
+	if err != nil {
+		t.Fatalf("GetLowStock failed: %v", err)
+	}
+	if len(items) != 2 || items[0].ProductID != variant.ID || items[0].MinStock != 4 ||
+		items[1].ProductID != kopi.ID || items[1].MinStock != 5 || items[1].ReorderQty != 10 {
+		t.Fatalf("expected thresholds to be kept, got %+v", items)
+	}
+
+	// 0 yang dikirim eksplisit berarti berhenti dipantau
+	if err := repo.Update(&models.Product{ID: kopi.ID, Name: "Kopi Bubuk", Price: 32000, Stock: 3, MinStock: intPtr(0)}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	items, err = repo.GetLowStock(0)
+	if err != nil {
+		t.Fatalf("GetLowStock failed: %v", err)
+	}
+	if len(items) != 1 || items[0].ProductID != variant.ID {
+		t.Errorf("expected only the variant after clearing Kopi's minimum, got %+v", items)
+	}
+}
+
+func TestProductRepository_GetLowStockPerOutlet(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	repo := NewProductRepository(db)
+	cabang := &models.Outlet{Code: "CBG", Name: "Cabang"}
+	if err := NewOutletRepository(db).Create(cabang); err != nil {
+		t.Fatalf("Create outlet failed: %v", err)
+	}
+
+	// Total 20 di atas batas 5, tapi outlet utama tinggal 2
+	kopi := &models.Product{Name: "Kopi", Price: 30000, Stock: 18, MinStock: intPtr(5), OutletID: cabang.ID}
+	if err := repo.Create(kopi); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	kopi.Stock, kopi.OutletID = 20, models.DefaultOutletID
+	if err := repo.Update(kopi); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	items, err := repo.GetLowStock(0)
+	if err != nil {
+		t.Fatalf("GetLowStock failed: %v", err)
+	}
+	if len(items) != 0 {
+		t.Errorf("expected no low stock across all outlets, got %+v", items)
+	}
+
+	items, err = repo.GetLowStock(models.DefaultOutletID)
+	if err != nil {
+		t.Fatalf("GetLowStock failed: %v", err)
+	}
+	if len(items) != 1 || items[0].ProductID != kopi.ID || items[0].Stock != 2 || items[0].Shortage != 3 {
+		t.Errorf("expected Kopi with stock 2 in the main outlet, got %+v", items)
+	}
+
+	items, err = repo.GetLowStock(cabang.ID)
+	if err != nil {
+		t.Fatalf("GetLowStock failed: %v", err)
+	}
+	if len(items) != 0 {
+		t.Errorf("expected no low stock in the branch, got %+v", items)
+	}
+}
+
+func TestTransactionRepository_GetReorderReport(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	repo := NewTransactionRepository(db, models.TaxConfig{}, models.CostingMethodAverage, models.BusinessDay{})
+	products := NewProductRepository(db)
+
+	beras := &models.Product{Name: "Beras", Price: 12000, Stock: 20}
+	kopi := &models.Product{Name: "Kopi", Price: 30000, Stock: 3, MinStock: intPtr(5), ReorderQty: intPtr(24)}
+	gula := &models.Product{Name: "Gula", Price: 15000, Stock: 100, MinStock: intPtr(5)}
+	for _, p := range []*models.Product{beras, kopi, gula} {
+		if err := products.Create(p); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+	nasi := &models.Product{Name: "Nasi Uduk", Price: 10000, Components: []models.ProductComponent{{ComponentID: beras.ID, Quantity: 2}}}
+	if err := products.Create(nasi); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	// Beras terjual 4 langsung + 3 paket x 2 = 10, sisa 10; 1 gula terjual lalu di-refund
+	trx, err := repo.CreateTransaction(models.CheckoutRequest{
+		Items:      []models.CheckoutItem{{ProductID: beras.ID, Quantity: 4}, {ProductID: nasi.ID, Quantity: 3}, {ProductID: gula.ID, Quantity: 1}},
+		PaidAmount: 200000, PaymentMethod: "CASH",
+	}, CheckoutOptions{})
+	if err != nil {
+		t.Fatalf("CreateTransaction failed: %v", err)
+	}
+	if _, err := repo.RefundTransaction(trx.ID, []models.RefundItem{{DetailID: trx.Details[2].ID, Quantity: 1}}, "batal", "admin"); err != nil {
+		t.Fatalf("RefundTransaction failed: %v", err)
+	}
+
+	date := trx.CreatedAt.Format("2006-01-02")
+	report, err := repo.GetReorderReport(date, date, 7, 0)
+	if err != nil {
+		t.Fatalf("GetReorderReport failed: %v", err)
+	}
+	if report.Days != 1 || len(report.Items) != 2 {
+		t.Fatalf("expected beras & kopi over 1 day, got %+v", report)
+	}
+
+	// Beras: 10/hari, cukup 1 hari; butuh 7 x 10 - 10 = 60
+	b := report.Items[0]
+	if b.ProductID != beras.ID || b.Sold != 10 || b.DaysOfCover == nil || *b.DaysOfCover != 1 || b.SuggestedQty != 60 {
+		t.Errorf("unexpected beras line: %+v", b)
+	}
+	// Kopi tidak laku tapi di bawah minimum: butuh 2, dibulatkan ke jumlah pesan ulang 24
+	k := report.Items[1]
+	if k.ProductID != kopi.ID || k.DaysOfCover != nil || k.SuggestedQty != 24 {
+		t.Errorf("unexpected kopi line: %+v", k)
+	}
+}
@@ -0,0 +1,164 @@
+package repositories
+
+import (
+	"codeWithUmam/models"
+	"database/sql"
+)
+
+// OutletRepositoryImpl bertugas melakukan komunikasi langsung ke Database untuk tabel outlets & outlet_stock.
+// Struct ini mengimplementasikan interface OutletRepository dari package repositories.
+// Stok per outlet hanya dibaca di sini; perubahannya selalu lewat recordStockMovement.
+type OutletRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewOutletRepository(db *sql.DB) *OutletRepositoryImpl {
+	return &OutletRepositoryImpl{db: db}
+}
+
+// GetAll mengambil semua outlet (termasuk yang nonaktif), urut ID.
+func (r *OutletRepositoryImpl) GetAll() ([]models.Outlet, error) {
+	rows, err := r.db.Query("SELECT id, code, name, COALESCE(address, ''), active, created_at FROM outlets ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	outlets := []models.Outlet{}
+	for rows.Next() {
+		var o models.Outlet
+		if err := rows.Scan(&o.ID, &o.Code, &o.Name, &o.Address, &o.Active, &o.CreatedAt); err != nil {
+			return nil, err
+		}
+		outlets = append(outlets, o)
+	}
+	return outlets, rows.Err()
+}
+
+// Create menyimpan outlet baru (langsung aktif). Kode yang sudah dipakai -> ValidationError.
+func (r *OutletRepositoryImpl) Create(outlet *models.Outlet) error {
+	result, err := r.db.Exec("INSERT INTO outlets (code, name, address, active) VALUES (?, ?, ?, 1)",
+		outlet.Code, outlet.Name, nullIfEmpty(outlet.Address))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return NewValidationError("kode outlet %s sudah dipakai", outlet.Code)
+		}
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	created, err := r.GetByID(int(id))
+	if err != nil {
+		return err
+	}
+	*outlet = *created
+	return nil
+}
+
+// GetByID mengambil satu outlet berdasarkan ID.
+func (r *OutletRepositoryImpl) GetByID(id int) (*models.Outlet, error) {
+	var o models.Outlet
+	err := r.db.QueryRow("SELECT id, code, name, COALESCE(address, ''), active, created_at FROM outlets WHERE id = ?", id).
+		Scan(&o.ID, &o.Code, &o.Name, &o.Address, &o.Active, &o.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+// Update mengubah kode, nama, alamat & status aktif outlet.
+// Outlet utama tidak bisa dinonaktifkan, begitu juga outlet yang masih punya transfer stok di perjalanan.
+func (r *OutletRepositoryImpl) Update(outlet *models.Outlet) error {
+	if !outlet.Active {
+		if outlet.ID == models.DefaultOutletID {
+			return NewValidationError("outlet utama tidak bisa dinonaktifkan")
+		}
+		var inTransit int
+		err := r.db.QueryRow("SELECT COUNT(*) FROM stock_transfers WHERE (from_outlet_id = ? OR to_outlet_id = ?) AND status = ?",
+			outlet.ID, outlet.ID, models.StockTransferStatusInTransit).Scan(&inTransit)
+		if err != nil {
+			return err
+		}
+		if inTransit > 0 {
+			return NewValidationError("outlet %d masih punya %d transfer stok di perjalanan", outlet.ID, inTransit)
+		}
+	}
+
+	result, err := r.db.Exec("UPDATE outlets SET code = ?, name = ?, address = ?, active = ? WHERE id = ?",
+		outlet.Code, outlet.Name, nullIfEmpty(outlet.Address), outlet.Active, outlet.ID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return NewValidationError("kode outlet %s sudah dipakai", outlet.Code)
+		}
+		return err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return ErrNotFound
+	}
+
+	updated, err := r.GetByID(outlet.ID)
+	if err != nil {
+		return err
+	}
+	*outlet = *updated
+	return nil
+}
+
+// GetStock mengambil stok setiap produk (dan varian) di outlet outletID, urut nama,
+// beserta jumlah yang sedang dikirim ke outlet ini. Produk yang stok & kiriman masuknya 0 tidak ditampilkan.
+func (r *OutletRepositoryImpl) GetStock(outletID int) ([]models.OutletStockItem, error) {
+	if _, err := r.GetByID(outletID); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(`
+		SELECT p.id, p.name, COALESCE(p.sku, ''), p.unit, COALESCE(os.stock, 0),
+			COALESCE((SELECT SUM(ti.quantity) FROM stock_transfer_items ti
+				JOIN stock_transfers st ON st.id = ti.stock_transfer_id
+				WHERE ti.product_id = p.id AND st.to_outlet_id = ? AND st.status = ?), 0) AS in_transit
+		FROM products p
+		LEFT JOIN outlet_stock os ON os.product_id = p.id AND os.outlet_id = ?
+		WHERE `+stockKeepingFilter+`
+		GROUP BY p.id
+		HAVING COALESCE(os.stock, 0) != 0 OR in_transit != 0
+		ORDER BY p.name, p.id`, outletID, models.StockTransferStatusInTransit, outletID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []models.OutletStockItem{}
+	for rows.Next() {
+		var item models.OutletStockItem
+		if err := rows.Scan(&item.ProductID, &item.ProductName, &item.SKU, &item.Unit, &item.Stock, &item.InTransit); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// requireActiveOutlet memastikan outlet outletID ada dan aktif, di dalam Database Transaction tx.
+func requireActiveOutlet(tx *sql.Tx, outletID int) error {
+	var name string
+	var active bool
+	err := tx.QueryRow("SELECT name, active FROM outlets WHERE id = ?", outletID).Scan(&name, &active)
+	if err == sql.ErrNoRows {
+		return NewValidationError("outlet %d tidak ditemukan", outletID)
+	}
+	if err != nil {
+		return err
+	}
+	if !active {
+		return NewValidationError("outlet %s sudah tidak aktif", name)
+	}
+	return nil
+}
@@ -0,0 +1,303 @@
+package repositories
+
+import (
+	"codeWithUmam/models"
+	"database/sql"
+	"testing"
+	"time"
+)
+
+// outletStock membaca stok produk di satu outlet.
+func outletStock(t *testing.T, db *sql.DB, outletID, productID int) int {
+	var stock int
+	err := db.QueryRow("SELECT COALESCE((SELECT stock FROM outlet_stock WHERE outlet_id = ? AND product_id = ?), 0)", outletID, productID).Scan(&stock)
+	if err != nil {
+		t.Fatalf("failed to read outlet stock: %v", err)
+	}
+	return stock
+}
+
+// assertConsistent memastikan products.stock, ledger, batch & stok per outlet saling cocok.
+func assertConsistent(t *testing.T, db *sql.DB) {
+	t.Helper()
+	report, err := NewStockMovementRepository(db).CheckConsistency()
+	if err != nil {
+		t.Fatalf("CheckConsistency failed: %v", err)
+	}
+	if !report.Consistent {
+		t.Errorf("expected consistent stock, got %+v", report.Discrepancies)
+	}
+}
+
+func TestOutletRepository_CheckoutAndTransfer(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	repo := NewTransactionRepository(db, models.TaxConfig{}, models.CostingMethodAverage, models.BusinessDay{})
+	outlets := NewOutletRepository(db)
+	transfers := NewStockTransferRepository(db, models.CostingMethodAverage)
+
+	cabang := &models.Outlet{Code: "CBG", Name: "Cabang"}
+	if err := outlets.Create(cabang); err != nil {
+		t.Fatalf("Create outlet failed: %v", err)
+	}
+	if err := outlets.Create(&models.Outlet{Code: "CBG", Name: "Duplikat"}); err == nil {
+		t.Error("expected error for duplicate outlet code")
+	}
+	kopi := seedProduct(t, db, "Kopi", 5000, 10) // Stok awal masuk ke outlet utama
+	if _, err := db.Exec("UPDATE products SET cost_price = 3000 WHERE id = ?", kopi); err != nil {
+		t.Fatalf("failed to set cost price: %v", err)
+	}
+
+	// Cabang belum punya stok
+	if _, err := repo.CreateTransaction(models.CheckoutRequest{OutletID: cabang.ID, Items: []models.CheckoutItem{{ProductID: kopi, Quantity: 1}}, PaidAmount: 5000, PaymentMethod: "CASH"}, CheckoutOptions{}); err == nil {
+		t.Error("expected error for checkout without outlet stock")
+	}
+
+	// Kirim 4 ke cabang: stok utama langsung berkurang, barang di perjalanan tidak bisa dijual di mana pun
+	transfer, err := transfers.Create(models.CreateStockTransferRequest{
+		FromOutletID: models.DefaultOutletID, ToOutletID: cabang.ID, SentBy: "gudang",
+		Items: []models.StockTransferItemRequest{{ProductID: kopi, Quantity: 4}},
+	})
+	if err != nil {
+		t.Fatalf("Create transfer failed: %v", err)
+	}
+	if transfer.Status != models.StockTransferStatusInTransit || transfer.Items[0].UnitCost != 3000 {
+		t.Errorf("expected IN_TRANSIT with unit cost 3000, got %s / %d", transfer.Status, transfer.Items[0].UnitCost)
+	}
+	if main, total := outletStock(t, db, models.DefaultOutletID, kopi), productStock(t, db, kopi); main != 6 || total != 6 {
+		t.Errorf("expected main outlet 6 and total 6 while in transit, got %d / %d", main, total)
+	}
+	stock, err := outlets.GetStock(cabang.ID)
+	if err != nil {
+		t.Fatalf("GetStock failed: %v", err)
+	}
+	if len(stock) != 1 || stock[0].Stock != 0 || stock[0].InTransit != 4 {
+		t.Errorf("expected 4 in transit to the branch, got %+v", stock)
+	}
+	if _, err := transfers.Create(models.CreateStockTransferRequest{
+		FromOutletID: models.DefaultOutletID, ToOutletID: cabang.ID,
+		Items: []models.StockTransferItemRequest{{ProductID: kopi, Quantity: 7}},
+	}); err == nil {
+		t.Error("expected error when transferring more than the source outlet holds")
+	}
+
+	received, err := transfers.Receive(transfer.ID, "kepala cabang")
+	if err != nil {
+		t.Fatalf("Receive failed: %v", err)
+	}
+	if received.Status != models.StockTransferStatusReceived || received.ReceivedAt == nil {
+		t.Errorf("expected RECEIVED with received_at, got %s / %v", received.Status, received.ReceivedAt)
+	}
+	if _, err := transfers.Cancel(transfer.ID); err == nil {
+		t.Error("expected error when cancelling a received transfer")
+	}
+	if branch, total := outletStock(t, db, cabang.ID, kopi), productStock(t, db, kopi); branch != 4 || total != 10 {
+		t.Errorf("expected branch 4 and total 10 after receiving, got %d / %d", branch, total)
+	}
+
+	// Checkout di cabang hanya memotong stok cabang; refund kembali ke cabang
+	trx, err := repo.CreateTransaction(models.CheckoutRequest{OutletID: cabang.ID, Items: []models.CheckoutItem{{ProductID: kopi, Quantity: 3}}, PaidAmount: 15000, PaymentMethod: "CASH"}, CheckoutOptions{})
+	if err != nil {
+		t.Fatalf("CreateTransaction failed: %v", err)
+	}
+	if trx.OutletID != cabang.ID {
+		t.Errorf("expected transaction at outlet %d, got %d", cabang.ID, trx.OutletID)
+	}
+	if _, err := repo.CreateTransaction(models.CheckoutRequest{OutletID: cabang.ID, Items: []models.CheckoutItem{{ProductID: kopi, Quantity: 2}}, PaidAmount: 10000, PaymentMethod: "CASH"}, CheckoutOptions{}); err == nil {
+		t.Error("expected error when the branch has only 1 left")
+	}
+	detail, err := repo.FindByID(trx.ID)
+	if err != nil {
+		t.Fatalf("FindByID failed: %v", err)
+	}
+	if _, err := repo.RefundTransaction(trx.ID, []models.RefundItem{{DetailID: detail.Details[0].ID, Quantity: 1}}, "tumpah", "kasir"); err != nil {
+		t.Fatalf("RefundTransaction failed: %v", err)
+	}
+	if main, branch := outletStock(t, db, models.DefaultOutletID, kopi), outletStock(t, db, cabang.ID, kopi); main != 6 || branch != 2 {
+		t.Errorf("expected main 6 and branch 2, got %d / %d", main, branch)
+	}
+
+	// Riwayat & laporan per outlet
+	if history, err := repo.FindAll("", "", cabang.ID); err != nil || len(history) != 1 {
+		t.Errorf("expected 1 transaction at the branch, got %d (%v)", len(history), err)
+	}
+	if history, err := repo.FindAll("", "", models.DefaultOutletID); err != nil || len(history) != 0 {
+		t.Errorf("expected no transactions at the main outlet, got %d (%v)", len(history), err)
+	}
+	summary, err := repo.GetDailySalesSummary(models.DefaultOutletID)
+	if err != nil {
+		t.Fatalf("GetDailySalesSummary failed: %v", err)
+	}
+	if summary.TotalRevenue != 0 {
+		t.Errorf("expected no revenue at the main outlet, got %d", summary.TotalRevenue)
+	}
+	if summary, err = repo.GetDailySalesSummary(0); err != nil || summary.TotalRevenue != 10000 {
+		t.Errorf("expected 10000 revenue over all outlets, got %d (%v)", summary.TotalRevenue, err)
+	}
+	from, to := time.Now().AddDate(0, 0, -1).Format("2006-01-02"), time.Now().AddDate(0, 0, 1).Format("2006-01-02")
+	for outletID, want := range map[int]int{cabang.ID: 10000, models.DefaultOutletID: 0} {
+		if sales, err := repo.GetSalesReport(from, to, models.SalesGroupByDay, 10, outletID); err != nil || sales.TotalRevenue != want {
+			t.Errorf("expected sales revenue %d at outlet %d, got %+v (%v)", want, outletID, sales, err)
+		}
+		if profit, err := repo.GetProfitReport(from, to, outletID); err != nil || profit.Revenue != want {
+			t.Errorf("expected profit revenue %d at outlet %d, got %+v (%v)", want, outletID, profit, err)
+		}
+	}
+	reorder, err := repo.GetReorderReport(from, to, 7, cabang.ID)
+	if err != nil {
+		t.Fatalf("GetReorderReport failed: %v", err)
+	}
+	if len(reorder.Items) != 1 || reorder.Items[0].Stock != 2 || reorder.Items[0].Sold != 2 {
+		t.Errorf("expected branch stock 2 and 2 sold in the reorder report, got %+v", reorder.Items)
+	}
+
+	// Outlet nonaktif tidak bisa dipakai checkout; outlet utama tidak bisa dinonaktifkan
+	cabang.Active = false
+	if err := outlets.Update(cabang); err != nil {
+		t.Fatalf("Update outlet failed: %v", err)
+	}
+	if _, err := repo.CreateTransaction(models.CheckoutRequest{OutletID: cabang.ID, Items: []models.CheckoutItem{{ProductID: kopi, Quantity: 1}}, PaidAmount: 5000, PaymentMethod: "CASH"}, CheckoutOptions{}); err == nil {
+		t.Error("expected error when checking out at an inactive outlet")
+	}
+	if err := outlets.Update(&models.Outlet{ID: models.DefaultOutletID, Code: "UTAMA", Name: "Outlet Utama"}); err == nil {
+		t.Error("expected error when deactivating the main outlet")
+	}
+
+	assertConsistent(t, db)
+}
+
+func TestStockTransferRepository_CancelReturnsBatches(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	outlets := NewOutletRepository(db)
+	transfers := NewStockTransferRepository(db, models.CostingMethodFIFO)
+	purchases := NewPurchaseOrderRepository(db)
+
+	cabang := &models.Outlet{Code: "CBG", Name: "Cabang"}
+	if err := outlets.Create(cabang); err != nil {
+		t.Fatalf("Create outlet failed: %v", err)
+	}
+	supplier := &models.Supplier{Name: "Distributor Susu"}
+	if err := NewSupplierRepository(db).Create(supplier); err != nil {
+		t.Fatalf("failed to create supplier: %v", err)
+	}
+	susu := &models.Product{Name: "Susu UHT", Price: 6000, TrackBatches: boolPtr(true)}
+	if err := NewProductRepository(db).Create(susu); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	order, err := purchases.Create(models.CreatePurchaseOrderRequest{
+		SupplierID: supplier.ID,
+		Items:      []models.PurchaseOrderItemRequest{{ProductID: susu.ID, Quantity: 8, UnitCost: 4000}},
+	})
+	if err != nil {
+		t.Fatalf("Create PO failed: %v", err)
+	}
+	if _, err := purchases.Receive(order.ID, models.ReceiveGoodsRequest{
+		Items: []models.ReceiveGoodsItemRequest{
+			{PurchaseOrderItemID: order.Items[0].ID, Quantity: 3, BatchNumber: "CEPAT", ExpiryDate: "2099-01-05"},
+			{PurchaseOrderItemID: order.Items[0].ID, Quantity: 5, BatchNumber: "LAMA", ExpiryDate: "2099-01-10"},
+		},
+	}); err != nil {
+		t.Fatalf("Receive PO failed: %v", err)
+	}
+
+	batchesAt := func(outletID int) map[string]int {
+		batches, err := NewStockMovementRepository(db).GetBatches(susu.ID, "2026-01-01")
+		if err != nil {
+			t.Fatalf("GetBatches failed: %v", err)
+		}
+		remaining := make(map[string]int)
+		for _, b := range batches {
+			if b.OutletID == outletID {
+				remaining[b.BatchNumber] += b.RemainingQuantity
+			}
+		}
+		return remaining
+	}
+
+	// 4 unit keluar FEFO: 3 dari CEPAT, 1 dari LAMA. Dibatalkan -> kembali ke batch asalnya
+	transfer, err := transfers.Create(models.CreateStockTransferRequest{
+		FromOutletID: models.DefaultOutletID, ToOutletID: cabang.ID,
+		Items: []models.StockTransferItemRequest{{ProductID: susu.ID, Quantity: 4}},
+	})
+	if err != nil {
+		t.Fatalf("Create transfer failed: %v", err)
+	}
+	if got := batchesAt(models.DefaultOutletID); got["CEPAT"] != 0 || got["LAMA"] != 4 {
+		t.Errorf("expected CEPAT 0 / LAMA 4 while in transit, got %v", got)
+	}
+	if _, err := transfers.Cancel(transfer.ID); err != nil {
+		t.Fatalf("Cancel failed: %v", err)
+	}
+	if got := batchesAt(models.DefaultOutletID); got["CEPAT"] != 3 || got["LAMA"] != 5 {
+		t.Errorf("expected batches restored after cancel, got %v", got)
+	}
+	assertConsistent(t, db)
+
+	// Dikirim ulang dan diterima: nomor lot & tanggal kedaluwarsa ikut pindah ke cabang
+	transfer, err = transfers.Create(models.CreateStockTransferRequest{
+		FromOutletID: models.DefaultOutletID, ToOutletID: cabang.ID,
+		Items: []models.StockTransferItemRequest{{ProductID: susu.ID, Quantity: 4}},
+	})
+	if err != nil {
+		t.Fatalf("Create transfer failed: %v", err)
+	}
+	if _, err := transfers.Receive(transfer.ID, ""); err != nil {
+		t.Fatalf("Receive failed: %v", err)
+	}
+	if got := batchesAt(cabang.ID); got["CEPAT"] != 3 || got["LAMA"] != 1 {
+		t.Errorf("expected CEPAT 3 / LAMA 1 at the branch, got %v", got)
+	}
+	if outletStock(t, db, cabang.ID, susu.ID) != 4 || productStock(t, db, susu.ID) != 8 {
+		t.Errorf("expected branch 4 / total 8, got %d / %d", outletStock(t, db, cabang.ID, susu.ID), productStock(t, db, susu.ID))
+	}
+	assertConsistent(t, db)
+}
+
+func TestProductRepository_UpdateStockPerOutlet(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	products := NewProductRepository(db)
+	outlets := NewOutletRepository(db)
+
+	cabangA := &models.Outlet{Code: "CBA", Name: "Cabang A"}
+	cabangB := &models.Outlet{Code: "CBB", Name: "Cabang B"}
+	for _, o := range []*models.Outlet{cabangA, cabangB} {
+		if err := outlets.Create(o); err != nil {
+			t.Fatalf("Create outlet failed: %v", err)
+		}
+	}
+
+	// Stok awal langsung masuk ke cabang A, lalu ditambah 4 di cabang B
+	kopi := &models.Product{Name: "Kopi", Price: 5000, Stock: 6, OutletID: cabangA.ID}
+	if err := products.Create(kopi); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	kopi.Stock, kopi.OutletID = 10, cabangB.ID
+	if err := products.Update(kopi); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if a, b, main := outletStock(t, db, cabangA.ID, kopi.ID), outletStock(t, db, cabangB.ID, kopi.ID), outletStock(t, db, models.DefaultOutletID, kopi.ID); a != 6 || b != 4 || main != 0 {
+		t.Fatalf("expected 6/4/0 in branch A/branch B/main, got %d/%d/%d", a, b, main)
+	}
+
+	// Tanpa outlet_id tidak jelas outlet mana yang berkurang
+	kopi.Stock, kopi.OutletID = 8, 0
+	if err := products.Update(kopi); err == nil {
+		t.Error("expected error when changing stock held by several outlets without outlet_id")
+	}
+	// Outlet utama tidak punya stok, jadi tidak bisa dikurangi
+	kopi.OutletID = models.DefaultOutletID
+	if err := products.Update(kopi); err == nil {
+		t.Error("expected error when an outlet's stock would go below zero")
+	}
+	if main := outletStock(t, db, models.DefaultOutletID, kopi.ID); main != 0 {
+		t.Errorf("expected main outlet stock to stay 0, got %d", main)
+	}
+
+	kopi.OutletID = cabangB.ID
+	if err := products.Update(kopi); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if b, total := outletStock(t, db, cabangB.ID, kopi.ID), productStock(t, db, kopi.ID); b != 2 || total != 8 {
+		t.Errorf("expected branch B 2 and total 8, got %d / %d", b, total)
+	}
+	assertConsistent(t, db)
+}
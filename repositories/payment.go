@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"codeWithUmam/models"
+	"strings"
+)
+
+// allocatePayments membagi tagihan total ke setiap tender dan menghitung kembalian.
+//
+// Aturan:
+//   - Tender non-tunai (QRIS, DEBIT, dst) dibayarkan persis sebesar nominalnya dan TIDAK PERNAH menghasilkan kembalian,
+//     jadi total non-tunai tidak boleh melebihi tagihan.
+//   - Sisa tagihan harus ditutup oleh tender tunai. Kelebihan uang tunai menjadi kembalian.
+//
+// Fungsi ini murni (tidak menyentuh database) agar mudah di-test.
+func allocatePayments(total int, inputs []models.PaymentInput) ([]models.Payment, int, error) {
+	if len(inputs) == 0 {
+		return nil, 0, NewValidationError("pembayaran tidak boleh kosong")
+	}
+
+	payments := make([]models.Payment, 0, len(inputs))
+	nonCash, cash := 0, 0
+	for _, in := range inputs {
+		method := strings.ToUpper(strings.TrimSpace(in.Method))
+		if method == "" {
+			method = models.PaymentMethodCash
+		}
+		if in.Amount <= 0 {
+			return nil, 0, NewValidationError("nominal pembayaran %s harus lebih dari 0", method)
+		}
+
+		if method == models.PaymentMethodCash {
+			cash += in.Amount
+		} else {
+			nonCash += in.Amount
+		}
+		payments = append(payments, models.Payment{
+			Method:         method,
+			Amount:         in.Amount,
+			TenderedAmount: in.Amount,
+			Reference:      in.Reference,
+		})
+	}
+
+	if nonCash > total {
+		return nil, 0, NewValidationError("pembayaran non-tunai (%d) melebihi total belanja (%d), non-tunai tidak boleh ada kembalian", nonCash, total)
+	}
+
+	remaining := total - nonCash // Bagian tagihan yang harus dibayar tunai
+	if cash < remaining {
+		return nil, 0, NewValidationError("uang pembayaran kurang (Total: %d, Paid: %d)", total, cash+nonCash)
+	}
+	change := cash - remaining
+
+	// Tender tunai hanya "terpakai" sebesar sisa tagihan; sisanya kembalian.
+	for i := range payments {
+		if payments[i].Method != models.PaymentMethodCash {
+			continue
+		}
+		applied := payments[i].TenderedAmount
+		if applied > remaining {
+			applied = remaining
+		}
+		payments[i].Amount = applied
+		remaining -= applied
+	}
+
+	return payments, change, nil
+}
+
+// headerPaymentMethod menentukan isi kolom transactions.payment_method:
+// nama metodenya jika hanya satu metode, atau "SPLIT" jika lebih dari satu.
+func headerPaymentMethod(payments []models.Payment) string {
+	method := ""
+	for _, p := range payments {
+		if method != "" && p.Method != method {
+			return models.PaymentMethodSplit
+		}
+		method = p.Method
+	}
+	return method
+}
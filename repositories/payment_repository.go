@@ -0,0 +1,209 @@
+package repositories
+
+import (
+	"codeWithUmam/models"
+	"database/sql"
+	"time"
+)
+
+// Siklus hidup tender pembayaran lewat payment gateway: PENDING -> PAID atau PENDING -> EXPIRED.
+// Method-method ini ada di TransactionRepository karena setiap perubahan status tender
+// juga mengubah status transaksi (dan stok) di Database Transaction yang sama.
+
+// AttachPaymentCharge menyimpan data tagihan dari payment gateway (referensi, isi QR, batas waktu) ke tender PENDING.
+func (repo *TransactionRepository) AttachPaymentCharge(paymentID int, charge models.PaymentCharge) error {
+	_, err := repo.db.Exec(`
+		UPDATE payments SET provider_reference = ?, qr_string = ?, expires_at = ?
+		WHERE id = ? AND status = ?`,
+		charge.ProviderReference, charge.QRString, charge.ExpiresAt.UTC(), paymentID, models.PaymentStatusPending)
+	return err
+}
+
+// MarkPaymentPaid menandai tender dengan referensi gateway tersebut sebagai PAID.
+// Jika semua tender transaksi sudah PAID, transaksi berubah dari PENDING_PAYMENT menjadi COMPLETED.
+// Callback yang sama boleh datang berkali-kali (gateway biasanya me-retry webhook), hasilnya tetap sama.
+func (repo *TransactionRepository) MarkPaymentPaid(providerReference string) (*models.Transaction, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	paymentID, transactionID, status, err := findPaymentByReference(tx, providerReference)
+	if err != nil {
+		return nil, err
+	}
+	if status == models.PaymentStatusPaid {
+		return repo.FindByID(transactionID)
+	}
+	if status != models.PaymentStatusPending {
+		return nil, NewValidationError("pembayaran %s sudah %s, tidak bisa dibayar", providerReference, status)
+	}
+
+	_, err = tx.Exec("UPDATE payments SET status = ?, paid_at = ? WHERE id = ?", models.PaymentStatusPaid, time.Now().UTC(), paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Transaksi selesai jika tidak ada lagi tender yang menunggu
+	var pending int
+	err = tx.QueryRow("SELECT COUNT(id) FROM payments WHERE transaction_id = ? AND status = ?", transactionID, models.PaymentStatusPending).Scan(&pending)
+	if err != nil {
+		return nil, err
+	}
+	if pending == 0 {
+		_, err = tx.Exec("UPDATE transactions SET status = ? WHERE id = ? AND status = ?",
+			models.TransactionStatusCompleted, transactionID, models.TransactionStatusPendingPayment)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return repo.FindByID(transactionID)
+}
+
+// ExpirePayment menandai tender dengan referensi gateway tersebut sebagai EXPIRED
+// lalu membatalkan transaksinya (lihat ExpireTransaction).
+func (repo *TransactionRepository) ExpirePayment(providerReference string) (*models.Transaction, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	paymentID, transactionID, status, err := findPaymentByReference(tx, providerReference)
+	if err != nil {
+		return nil, err
+	}
+	if status == models.PaymentStatusPaid {
+		return nil, NewValidationError("pembayaran %s sudah dibayar, tidak bisa kedaluwarsa", providerReference)
+	}
+	if status != models.PaymentStatusPending {
+		// Sudah EXPIRED/CANCELLED sebelumnya: tidak ada yang perlu diubah
+		return repo.FindByID(transactionID)
+	}
+
+	_, err = tx.Exec("UPDATE payments SET status = ? WHERE id = ?", models.PaymentStatusExpired, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := expireTransaction(tx, transactionID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return repo.FindByID(transactionID)
+}
+
+// ExpireTransaction membatalkan transaksi PENDING_PAYMENT, misalnya karena tagihan gagal dibuat di payment gateway.
+func (repo *TransactionRepository) ExpireTransaction(id int) (*models.Transaction, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := expireTransaction(tx, id); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return repo.FindByID(id)
+}
+
+// FindOverduePayments mengambil referensi gateway dari tender PENDING yang batas waktunya sudah lewat.
+// Perbandingan waktu dilakukan di Go karena format DATETIME di SQLite tidak selalu bisa dibandingkan sebagai string.
+func (repo *TransactionRepository) FindOverduePayments(now time.Time) ([]string, error) {
+	rows, err := repo.db.Query(`
+		SELECT provider_reference, expires_at FROM payments
+		WHERE status = ? AND provider_reference IS NOT NULL AND expires_at IS NOT NULL`, models.PaymentStatusPending)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	references := []string{}
+	for rows.Next() {
+		var reference string
+		var expiresAt time.Time
+		if err := rows.Scan(&reference, &expiresAt); err != nil {
+			return nil, err
+		}
+		if expiresAt.Before(now) {
+			references = append(references, reference)
+		}
+	}
+	return references, rows.Err()
+}
+
+// findPaymentByReference mencari tender berdasarkan referensi payment gateway.
+func findPaymentByReference(tx *sql.Tx, providerReference string) (paymentID, transactionID int, status string, err error) {
+	err = tx.QueryRow("SELECT id, transaction_id, status FROM payments WHERE provider_reference = ?", providerReference).
+		Scan(&paymentID, &transactionID, &status)
+	if err == sql.ErrNoRows {
+		return 0, 0, "", ErrNotFound
+	}
+	return paymentID, transactionID, status, err
+}
+
+// expireTransaction mengubah transaksi PENDING_PAYMENT menjadi EXPIRED di dalam Database Transaction tx:
+// stok yang dipesan dikembalikan dan tender lain yang masih PENDING ikut dibatalkan.
+func expireTransaction(tx *sql.Tx, id int) error {
+	var status string
+	err := tx.QueryRow("SELECT status FROM transactions WHERE id = ?", id).Scan(&status)
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if status != models.TransactionStatusPendingPayment {
+		return NewValidationError("transaksi %d berstatus %s, hanya transaksi %s yang bisa kedaluwarsa",
+			id, status, models.TransactionStatusPendingPayment)
+	}
+
+	// 1. Kembalikan stok yang sudah dikurangi saat checkout
+	// Baris dikumpulkan dulu: cursor harus ditutup sebelum tx dipakai untuk query lain
+	type line struct{ detailID, productID, remaining, quantity, unitFactor, cogs int }
+	rows, err := tx.Query(`
+		SELECT id, product_id, quantity - refunded_quantity, quantity, unit_factor, cogs
+		FROM transaction_details WHERE transaction_id = ? AND quantity > refunded_quantity`, id)
+	if err != nil {
+		return err
+	}
+	lines := make([]line, 0)
+	for rows.Next() {
+		var l line
+		if err := rows.Scan(&l.detailID, &l.productID, &l.remaining, &l.quantity, &l.unitFactor, &l.cogs); err != nil {
+			rows.Close()
+			return err
+		}
+		lines = append(lines, l)
+	}
+	rows.Close()
+
+	// Barang (atau komponen paket) kembali dengan harga pokok yang sama seperti saat keluar
+	for _, l := range lines {
+		if err := returnDetailStock(tx, l.detailID, l.productID, l.remaining, l.quantity, l.unitFactor, l.cogs, id, "Pembayaran kedaluwarsa"); err != nil {
+			return err
+		}
+	}
+
+	// 2. Batalkan tender lain yang masih menunggu
+	_, err = tx.Exec("UPDATE payments SET status = ? WHERE transaction_id = ? AND status = ?",
+		models.PaymentStatusCancelled, id, models.PaymentStatusPending)
+	if err != nil {
+		return err
+	}
+
+	// 3. Tandai transaksi EXPIRED
+	_, err = tx.Exec("UPDATE transactions SET status = ? WHERE id = ?", models.TransactionStatusExpired, id)
+	return err
+}
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"codeWithUmam/models"
+	"errors"
+	"testing"
+)
+
+func TestAllocatePayments(t *testing.T) {
+	// Total 75.000: QRIS 50.000 + tunai 30.000 -> kembalian 5.000, tunai terpakai 25.000
+	payments, change, err := allocatePayments(75000, []models.PaymentInput{
+		{Method: "qris", Amount: 50000, Reference: "QR-1"},
+		{Method: "CASH", Amount: 30000},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if change != 5000 {
+		t.Errorf("expected change 5000, got %d", change)
+	}
+	if payments[0].Method != "QRIS" || payments[0].Amount != 50000 {
+		t.Errorf("unexpected QRIS payment: %+v", payments[0])
+	}
+	if payments[1].Amount != 25000 || payments[1].TenderedAmount != 30000 {
+		t.Errorf("expected cash applied 25000 / tendered 30000, got %+v", payments[1])
+	}
+	if method := headerPaymentMethod(payments); method != models.PaymentMethodSplit {
+		t.Errorf("expected SPLIT header method, got %s", method)
+	}
+}
+
+func TestAllocatePayments_Rejected(t *testing.T) {
+	cases := []struct {
+		name   string
+		inputs []models.PaymentInput
+	}{
+		{"non-cash overpay", []models.PaymentInput{{Method: "QRIS", Amount: 80000}}},
+		{"not enough", []models.PaymentInput{{Method: "QRIS", Amount: 50000}, {Method: "CASH", Amount: 10000}}},
+		{"zero amount", []models.PaymentInput{{Method: "CASH", Amount: 0}}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := allocatePayments(75000, tc.inputs)
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Errorf("expected ValidationError, got %v", err)
+			}
+		})
+	}
+}
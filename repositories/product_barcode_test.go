@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"codeWithUmam/models"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestProductRepository_Barcodes(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	repo := NewProductRepository(db)
+	if _, err := db.Exec("INSERT INTO categories (name, description) VALUES ('Makanan', '')"); err != nil {
+		t.Fatalf("failed to seed category: %v", err)
+	}
+
+	product := &models.Product{Name: "Mie Goreng", SKU: "MIE-01", Price: 3500, Stock: 10, CategoryID: 1, Barcodes: []string{"8992388101012", "96385074"}}
+	if err := repo.Create(product); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	for _, code := range []string{"8992388101012", "96385074", "MIE-01"} {
+		found, err := repo.GetByCode(code)
+		if err != nil || found.ID != product.ID {
+			t.Errorf("GetByCode(%s) = %+v, %v; want product %d", code, found, err, product.ID)
+		}
+	}
+	if _, err := repo.GetByCode("0000000000000"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for unknown code, got %v", err)
+	}
+
+	// Barcode satu produk tidak boleh dipakai produk lain
+	other := &models.Product{Name: "Mie Kuah", Price: 3500, Barcodes: []string{"96385074"}}
+	if _, ok := repo.Create(other).(*ValidationError); !ok {
+		t.Error("expected ValidationError for barcode already used by another product")
+	}
+
+	// Update tanpa field barcodes tidak menghapus barcode; [] menghapus semua
+	if err := repo.Update(&models.Product{ID: product.ID, Name: "Mie Goreng", SKU: "MIE-01", Price: 4000, Stock: 10, CategoryID: 1}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	got, err := repo.GetByID(product.ID)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if len(got.Barcodes) != 2 {
+		t.Errorf("expected barcodes to be kept, got %v", got.Barcodes)
+	}
+	if err := repo.Update(&models.Product{ID: product.ID, Name: "Mie Goreng", SKU: "MIE-01", Price: 4000, Stock: 10, CategoryID: 1, Barcodes: []string{}}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	got, _ = repo.GetByID(product.ID)
+	if len(got.Barcodes) != 0 {
+		t.Errorf("expected barcodes to be cleared, got %v", got.Barcodes)
+	}
+
+	// Barcode produk yang dihapus bisa dipakai lagi
+	if err := repo.Create(other); err != nil {
+		t.Fatalf("Create with freed barcode failed: %v", err)
+	}
+	if err := repo.Delete(other.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := repo.GetByCode("96385074"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected barcode of deleted product to be gone, got %v", err)
+	}
+}
+
+func TestProductRepository_GetSelection(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	repo := NewProductRepository(db)
+	db.Exec("INSERT INTO categories (name, description) VALUES ('Minuman', ''), ('Snack', '')")
+
+	products := []*models.Product{
+		{Name: "Teh", Price: 3000, CategoryID: 1, Barcodes: []string{"96385074"}},
+		{Name: "Kopi", Price: 5000, CategoryID: 1},
+		{Name: "Keripik", Price: 8000, CategoryID: 2},
+	}
+	for _, p := range products {
+		if err := repo.Create(p); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	got, err := repo.GetSelection(1, []int{products[2].ID})
+	if err != nil {
+		t.Fatalf("GetSelection failed: %v", err)
+	}
+	var names []string
+	for _, p := range got {
+		names = append(names, p.Name)
+	}
+	// Urut nama kategori, lalu nama produk
+	if strings.Join(names, ",") != "Kopi,Teh,Keripik" || len(got[1].Barcodes) != 1 {
+		t.Errorf("unexpected selection %v", got)
+	}
+
+	if got, _ := repo.GetSelection(0, nil); len(got) != 0 {
+		t.Errorf("expected empty selection, got %v", got)
+	}
+}
@@ -0,0 +1,132 @@
+package repositories
+
+import (
+	"codeWithUmam/models"
+	"testing"
+)
+
+func TestProductRepository_BundleRules(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	repo := NewProductRepository(db)
+	kopi := &models.Product{Name: "Biji Kopi", Price: 200, Stock: 1000, Unit: "gram"}
+	if err := repo.Create(kopi); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	latte := &models.Product{Name: "Latte", Price: 25000, Components: []models.ProductComponent{{ComponentID: kopi.ID, Quantity: 18}}}
+	if err := repo.Create(latte); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	products, err := repo.GetAll("latte")
+	if err != nil || len(products) != 1 || len(products[0].Components) != 1 || products[0].Components[0].ComponentName != "Biji Kopi" {
+		t.Fatalf("expected Latte with its component, got %+v (%v)", products, err)
+	}
+
+	invalid := []*models.Product{
+		{Name: "Paket Berstok", Price: 1, Stock: 5, Components: []models.ProductComponent{{ComponentID: kopi.ID, Quantity: 1}}},
+		{Name: "Paket Bersarang", Price: 1, Components: []models.ProductComponent{{ComponentID: latte.ID, Quantity: 1}}},
+		{Name: "Paket Hantu", Price: 1, Components: []models.ProductComponent{{ComponentID: 999, Quantity: 1}}},
+	}
+	for _, p := range invalid {
+		if _, ok := repo.Create(p).(*ValidationError); !ok {
+			t.Errorf("expected ValidationError for %s", p.Name)
+		}
+	}
+
+	// Komponen yang masih dipakai paket tidak bisa dijadikan paket atau dihapus
+	kopi.Components = []models.ProductComponent{{ComponentID: latte.ID, Quantity: 1}}
+	if _, ok := repo.Update(kopi).(*ValidationError); !ok {
+		t.Error("expected ValidationError when turning a component into a bundle")
+	}
+	if _, ok := repo.Delete(kopi.ID).(*ValidationError); !ok {
+		t.Error("expected ValidationError when deleting a component in use")
+	}
+
+	// Menghapus paket ikut menghapus definisi komponennya, setelah itu komponen bebas dihapus
+	if err := repo.Delete(latte.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := repo.Delete(kopi.ID); err != nil {
+		t.Errorf("expected component to be deletable once the bundle is gone, got %v", err)
+	}
+}
+
+func TestTransactionRepository_CheckoutBundle(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	repo := NewTransactionRepository(db, models.TaxConfig{}, models.CostingMethodAverage, models.BusinessDay{})
+	products := NewProductRepository(db)
+
+	burger := &models.Product{Name: "Burger", Price: 20000, Stock: 10, CostPrice: 8000}
+	cola := &models.Product{Name: "Cola", Price: 8000, Stock: 10, CostPrice: 3000}
+	for _, p := range []*models.Product{burger, cola} {
+		if err := products.Create(p); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+	combo := &models.Product{Name: "Paket Hemat", Price: 25000, Components: []models.ProductComponent{
+		{ComponentID: burger.ID, Quantity: 1},
+		{ComponentID: cola.ID, Quantity: 2},
+	}}
+	if err := products.Create(combo); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	// 3 paket + 1 cola satuan: cola keluar 3x2 + 1 = 7
+	trx, err := repo.CreateTransaction(models.CheckoutRequest{
+		Items:      []models.CheckoutItem{{ProductID: combo.ID, Quantity: 3}, {ProductID: cola.ID, Quantity: 1}},
+		PaidAmount: 100000, PaymentMethod: "CASH",
+	}, CheckoutOptions{})
+	if err != nil {
+		t.Fatalf("CreateTransaction failed: %v", err)
+	}
+	if burgerStock, colaStock := productStock(t, db, burger.ID), productStock(t, db, cola.ID); burgerStock != 7 || colaStock != 3 {
+		t.Errorf("expected burger 7 and cola 3 left, got %d and %d", burgerStock, colaStock)
+	}
+	if stock := productStock(t, db, combo.ID); stock != 0 {
+		t.Errorf("expected bundle to have no stock of its own, got %d", stock)
+	}
+	if cogs := trx.Details[0].COGS; cogs != 3*(8000+2*3000) {
+		t.Errorf("expected bundle COGS to be the sum of its components, got %d", cogs)
+	}
+
+	// Cola tersisa 3: 2 paket butuh 4 -> ditolak, stok tidak berubah
+	if _, err := repo.CreateTransaction(models.CheckoutRequest{
+		Items:      []models.CheckoutItem{{ProductID: combo.ID, Quantity: 2}},
+		PaidAmount: 100000, PaymentMethod: "CASH",
+	}, CheckoutOptions{}); err == nil {
+		t.Error("expected error when a component runs out")
+	}
+	if stock := productStock(t, db, burger.ID); stock != 7 {
+		t.Errorf("expected burger stock to be untouched after failed checkout, got %d", stock)
+	}
+
+	// Refund 1 paket mengembalikan 1 burger & 2 cola
+	if _, err := repo.RefundTransaction(trx.ID, []models.RefundItem{{DetailID: trx.Details[0].ID, Quantity: 1}}, "salah pesan", "admin"); err != nil {
+		t.Fatalf("RefundTransaction failed: %v", err)
+	}
+	if burgerStock, colaStock := productStock(t, db, burger.ID), productStock(t, db, cola.ID); burgerStock != 8 || colaStock != 5 {
+		t.Errorf("expected burger 8 and cola 5 after refund, got %d and %d", burgerStock, colaStock)
+	}
+	if report, _ := NewStockMovementRepository(db).CheckConsistency(); !report.Consistent {
+		t.Errorf("expected ledger to be consistent, got %+v", report.Discrepancies)
+	}
+
+	// Laporan: 2 paket bersih, memakai 2 burger & 4 cola (cola satuan tidak dihitung sebagai isi paket)
+	date := trx.CreatedAt.Format("2006-01-02")
+	report, err := repo.GetBundleReport(date, date, 0)
+	if err != nil {
+		t.Fatalf("GetBundleReport failed: %v", err)
+	}
+	if len(report.Bundles) != 1 || report.Bundles[0].Quantity != 2 || report.Bundles[0].Revenue != 50000 || report.Bundles[0].COGS != 28000 {
+		t.Errorf("unexpected bundle lines: %+v", report.Bundles)
+	}
+	want := map[int]int{burger.ID: 2, cola.ID: 4}
+	if len(report.Components) != 2 {
+		t.Fatalf("expected 2 component lines, got %+v", report.Components)
+	}
+	for _, c := range report.Components {
+		if c.Quantity != want[c.ComponentID] {
+			t.Errorf("expected %d of %s consumed, got %d", want[c.ComponentID], c.Name, c.Quantity)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package repositories
+
+import (
+	"codeWithUmam/models"
+	"testing"
+)
+
+func TestProductRepository_Import(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	repo := NewProductRepository(db)
+	if _, err := db.Exec("INSERT INTO categories (name, description) VALUES ('Minuman', '')"); err != nil {
+		t.Fatalf("failed to seed category: %v", err)
+	}
+	existing := &models.Product{Name: "Teh Botol", SKU: "TEH-001", Price: 4000, Stock: 5, CategoryID: 1}
+	if err := repo.Create(existing); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	rows := []models.ProductImportRow{
+		{Line: 2, Name: "Teh Botol 350ml", SKU: "TEH-001", Price: 4500, Stock: 24, CategoryName: "minuman"},
+		{Line: 3, Name: "Kopi Sachet", SKU: "KOP-001", Price: 2000, Stock: 100, CategoryName: "Kopi"},
+		{Line: 4, Name: "Kopi Susu", Price: 3000, Stock: 50, CategoryName: "KOPI"},
+	}
+
+	// Dry run: hasil disimulasikan, database tidak berubah
+	result, err := repo.Import(rows, true)
+	if err != nil {
+		t.Fatalf("Import dry run failed: %v", err)
+	}
+	if result.Applied || result.Created != 2 || result.Updated != 1 || result.CategoriesCreated != 1 || len(result.Errors) != 0 {
+		t.Errorf("unexpected dry run result: %+v", result)
+	}
+	var count int
+	db.QueryRow("SELECT COUNT(*) FROM products").Scan(&count)
+	if count != 1 || productStock(t, db, existing.ID) != 5 {
+		t.Errorf("dry run must not change the database, got %d products and stock %d", count, productStock(t, db, existing.ID))
+	}
+
+	// Satu baris salah (kategori ID tidak ada) membatalkan seluruh import
+	bad := append(append([]models.ProductImportRow{}, rows...), models.ProductImportRow{Line: 5, Name: "Roti", Price: 5000, CategoryID: 99})
+	result, err = repo.Import(bad, false)
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if result.Applied || len(result.Errors) != 1 || result.Errors[0].Line != 5 {
+		t.Errorf("expected one error on line 5 and nothing applied, got %+v", result)
+	}
+	db.QueryRow("SELECT COUNT(*) FROM products").Scan(&count)
+	if count != 1 {
+		t.Errorf("failed import must be rolled back, got %d products", count)
+	}
+
+	// Import sungguhan: SKU lama di-update (stok lewat ledger), kategori baru dibuat sekali
+	result, err = repo.Import(rows, false)
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if !result.Applied || result.Created != 2 || result.Updated != 1 || result.CategoriesCreated != 1 {
+		t.Errorf("unexpected import result: %+v", result)
+	}
+
+	updated, err := repo.GetByID(existing.ID)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if updated.Name != "Teh Botol 350ml" || updated.Price != 4500 || updated.Stock != 24 || updated.CategoryID != 1 {
+		t.Errorf("expected existing SKU to be updated, got %+v", updated)
+	}
+	var ledger int
+	db.QueryRow("SELECT COALESCE(SUM(quantity), 0) FROM stock_movements WHERE product_id = ?", existing.ID).Scan(&ledger)
+	if ledger != 24 {
+		t.Errorf("expected ledger to follow imported stock, got %d", ledger)
+	}
+
+	var kopiCategories, kopiProducts int
+	db.QueryRow("SELECT COUNT(*) FROM categories WHERE LOWER(name) = 'kopi'").Scan(&kopiCategories)
+	db.QueryRow("SELECT COUNT(*) FROM products p JOIN categories c ON c.id = p.category_id WHERE c.name = 'Kopi'").Scan(&kopiProducts)
+	if kopiCategories != 1 || kopiProducts != 2 {
+		t.Errorf("expected 1 Kopi category with 2 products, got %d categories and %d products", kopiCategories, kopiProducts)
+	}
+}
+
+func TestProductRepository_DuplicateSKU(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	repo := NewProductRepository(db)
+
+	if err := repo.Create(&models.Product{Name: "A", SKU: "SKU-1", Price: 1000}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	// Produk tanpa SKU boleh lebih dari satu
+	for i := 0; i < 2; i++ {
+		if err := repo.Create(&models.Product{Name: "Tanpa SKU", Price: 1000}); err != nil {
+			t.Fatalf("Create without SKU failed: %v", err)
+		}
+	}
+	err := repo.Create(&models.Product{Name: "B", SKU: "SKU-1", Price: 1000})
+	if _, ok := err.(*ValidationError); !ok {
+		t.Errorf("expected ValidationError for duplicate SKU, got %v", err)
+	}
+}
+
+func TestProductRepository_ImportKeepsUnit(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	repo := NewProductRepository(db)
+	if _, err := db.Exec("INSERT INTO categories (name, description) VALUES ('Sembako', '')"); err != nil {
+		t.Fatalf("failed to seed category: %v", err)
+	}
+	beras := &models.Product{Name: "Beras", SKU: "BRS-001", Price: 14000, Stock: 50, Unit: "kg", CategoryID: 1,
+		Units: []models.ProductUnit{{Name: "karung", Factor: 25}}}
+	if err := repo.Create(beras); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	// CSV tidak punya kolom satuan: satuan dasar produk lama tidak boleh kembali ke "pcs"
+	rows := []models.ProductImportRow{{Line: 2, Name: "Beras Pandan", SKU: "BRS-001", Price: 15000, Stock: 50, CategoryID: 1}}
+	result, err := repo.Import(rows, false)
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if !result.Applied || result.Updated != 1 {
+		t.Fatalf("unexpected import result: %+v", result)
+	}
+
+	updated, err := repo.GetByID(beras.ID)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if updated.Unit != "kg" || updated.Price != 15000 {
+		t.Errorf("expected unit kg to be kept, got unit %q and price %d", updated.Unit, updated.Price)
+	}
+	if len(updated.Units) != 1 || updated.Units[0].Factor != 25 {
+		t.Errorf("expected karung conversion to be kept, got %+v", updated.Units)
+	}
+}
+
+func TestProductRepository_ImportReportsEveryBadRow(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	repo := NewProductRepository(db)
+	if _, err := db.Exec("INSERT INTO categories (name, description) VALUES ('Minuman', '')"); err != nil {
+		t.Fatalf("failed to seed category: %v", err)
+	}
+	cabang := &models.Outlet{Code: "CBG", Name: "Cabang"}
+	if err := NewOutletRepository(db).Create(cabang); err != nil {
+		t.Fatalf("Create outlet failed: %v", err)
+	}
+	teh := &models.Product{Name: "Teh Botol", SKU: "TEH-001", Price: 4000, Stock: 5, CategoryID: 1}
+	kopi := &models.Product{Name: "Kopi Kaleng", SKU: "KOP-001", Price: 8000, Stock: 10, CategoryID: 1, OutletID: cabang.ID}
+	for _, p := range []*models.Product{teh, kopi} {
+		if err := repo.Create(p); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	// Baris 3 mengubah stok yang ada di cabang (CSV tidak punya outlet), baris lain valid
+	rows := []models.ProductImportRow{
+		{Line: 2, Name: "Teh Botol", SKU: "TEH-001", Price: 4500, Stock: 12, CategoryID: 1},
+		{Line: 3, Name: "Kopi Kaleng", SKU: "KOP-001", Price: 8000, Stock: 4, CategoryID: 1},
+		{Line: 4, Name: "Air Mineral", SKU: "AIR-001", Price: 3000, Stock: 24, CategoryID: 1},
+		{Line: 5, Name: "Roti", Price: 5000, CategoryID: 99},
+	}
+	result, err := repo.Import(rows, true)
+	if err != nil {
+		t.Fatalf("Import dry run failed: %v", err)
+	}
+	if len(result.Errors) != 2 || result.Errors[0].Line != 3 || result.Errors[1].Line != 5 {
+		t.Fatalf("expected errors on lines 3 and 5, got %+v", result.Errors)
+	}
+	if result.Updated != 1 || result.Created != 1 || len(result.Rows) != 2 || result.Rows[0].Line != 2 || result.Rows[1].Line != 4 {
+		t.Errorf("expected only lines 2 and 4 to be counted, got %+v", result)
+	}
+
+	result, err = repo.Import(rows, false)
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if result.Applied || len(result.Errors) != 2 {
+		t.Errorf("expected nothing applied with 2 errors, got %+v", result)
+	}
+	if productStock(t, db, teh.ID) != 5 || productStock(t, db, kopi.ID) != 10 {
+		t.Errorf("failed import must be rolled back, got stock %d / %d", productStock(t, db, teh.ID), productStock(t, db, kopi.ID))
+	}
+}
@@ -3,6 +3,9 @@ package repositories
 import (
 	"codeWithUmam/models"
 	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
 )
 
 // ProductRepositoryImpl bertugas melakukan komunikasi langsung ke Database.
@@ -16,17 +19,17 @@ func NewProductRepository(db *sql.DB) *ProductRepositoryImpl {
 	return &ProductRepositoryImpl{db: db}
 }
 
-// GetAll mengambil semua baris data dari tabel products.
-// Jika parameter name tidak kosong, akan dilakukan filter search by name.
+// GetAll mengambil semua produk (tanpa baris varian) beserta varian-variannya di field Variants.
+// Jika parameter name tidak kosong, akan dilakukan filter search by name (nama produk atau nama varian).
 func (r *ProductRepositoryImpl) GetAll(name string) ([]models.Product, error) {
-	query := "SELECT id, name, price, stock, category_id FROM products"
+	query := "SELECT p.id, p.name, COALESCE(p.sku, ''), " + productBarcodesColumn + ", p.price, p.stock, p.unit, p.track_batches, p.min_stock, p.reorder_qty, p.category_id, p.cost_price FROM products p WHERE p.parent_id IS NULL"
 	args := []interface{}{}
 
 	// Jika ada filter nama, tambahkan WHERE clause
 	// Kita pakai LIKE untuk pencarian partial (misal: "indom" -> "Indomie")
 	if name != "" {
-		query += " WHERE name LIKE ?"
-		args = append(args, "%"+name+"%")
+		query += " AND (p.name LIKE ? OR EXISTS (SELECT 1 FROM products v WHERE v.parent_id = p.id AND v.name LIKE ?))"
+		args = append(args, "%"+name+"%", "%"+name+"%")
 	}
 
 	// Masukkan args... (spread operator) ke dalam Query
@@ -44,55 +47,539 @@ func (r *ProductRepositoryImpl) GetAll(name string) ([]models.Product, error) {
 		var p models.Product
 		// Scan: Memindahkan data dari database ke variabel struct Go.
 		// Urutan Scan HARUS SAMA dengan urutan SELECT di atas.
-		if err := rows.Scan(&p.ID, &p.Name, &p.Price, &p.Stock, &p.CategoryID); err != nil {
+		var barcodes string
+		if err := rows.Scan(&p.ID, &p.Name, &p.SKU, &barcodes, &p.Price, &p.Stock, &p.Unit, &p.TrackBatches, &p.MinStock, &p.ReorderQty, &p.CategoryID, &p.CostPrice); err != nil {
 			return nil, err
 		}
+		p.Barcodes = splitBarcodes(barcodes)
 		// Masukkan ke slice (array dinamis)
 		products = append(products, p)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	// Varian, satuan & komponen paket diambil sekaligus dengan satu query masing-masing, lalu ditempel ke produknya
+	variants, err := queryVariants(r.db, "parent_id IS NOT NULL")
+	if err != nil {
+		return nil, err
+	}
+	units, err := queryUnits(r.db, "1 = 1")
+	if err != nil {
+		return nil, err
+	}
+	components, err := queryComponents(r.db, "1 = 1")
+	if err != nil {
+		return nil, err
+	}
+	for i := range products {
+		products[i].Variants = variants[products[i].ID]
+		products[i].Units = units[products[i].ID]
+		products[i].Components = components[products[i].ID]
+	}
 	return products, nil
 }
 
-// Create menyimpan data produk baru ke database.
+// queryUnits mengambil satuan alternatif yang memenuhi kondisi where (kolom tabel product_units), dikelompokkan per ID produk.
+func queryUnits(q queryer, where string, args ...interface{}) (map[int][]models.ProductUnit, error) {
+	rows, err := q.Query("SELECT product_id, id, name, factor, price FROM product_units WHERE "+where+" ORDER BY product_id, factor, id", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	units := make(map[int][]models.ProductUnit)
+	for rows.Next() {
+		var productID int
+		var u models.ProductUnit
+		if err := rows.Scan(&productID, &u.ID, &u.Name, &u.Factor, &u.Price); err != nil {
+			return nil, err
+		}
+		units[productID] = append(units[productID], u)
+	}
+	return units, rows.Err()
+}
+
+// replaceUnits mengganti seluruh satuan alternatif produk dengan daftar baru (nil = tidak diubah).
+func replaceUnits(tx *sql.Tx, productID int, units []models.ProductUnit) error {
+	if units == nil {
+		return nil
+	}
+	if _, err := tx.Exec("DELETE FROM product_units WHERE product_id = ?", productID); err != nil {
+		return err
+	}
+	for i := range units {
+		res, err := tx.Exec("INSERT INTO product_units (product_id, name, factor, price) VALUES (?, ?, ?, ?)",
+			productID, units[i].Name, units[i].Factor, units[i].Price)
+		if err != nil {
+			if isUniqueViolation(err) {
+				return NewValidationError("satuan %s ditulis lebih dari sekali", units[i].Name)
+			}
+			return err
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+		units[i].ID = int(id)
+	}
+	return nil
+}
+
+// queryComponents mengambil komponen paket/resep yang memenuhi kondisi where (alias tabel pc), dikelompokkan per ID produk paket.
+func queryComponents(q queryer, where string, args ...interface{}) (map[int][]models.ProductComponent, error) {
+	rows, err := q.Query(`
+		SELECT pc.product_id, pc.component_id, COALESCE(p.name, ''), pc.quantity
+		FROM product_components pc
+		LEFT JOIN products p ON p.id = pc.component_id
+		WHERE `+where+`
+		ORDER BY pc.product_id, pc.id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	components := make(map[int][]models.ProductComponent)
+	for rows.Next() {
+		var productID int
+		var c models.ProductComponent
+		if err := rows.Scan(&productID, &c.ComponentID, &c.ComponentName, &c.Quantity); err != nil {
+			return nil, err
+		}
+		components[productID] = append(components[productID], c)
+	}
+	return components, rows.Err()
+}
+
+// replaceComponents mengganti seluruh komponen produk paket dengan daftar baru (nil = tidak diubah, [] = bukan paket lagi).
+// Paket hanya satu tingkat: komponen tidak boleh paket juga, dan produk yang sudah menjadi komponen tidak bisa dijadikan paket.
+// Produk yang punya varian tidak bisa menjadi paket maupun komponen (pakai ID variannya).
+func replaceComponents(tx *sql.Tx, productID int, components []models.ProductComponent) error {
+	if components == nil {
+		return nil
+	}
+	if _, err := tx.Exec("DELETE FROM product_components WHERE product_id = ?", productID); err != nil {
+		return err
+	}
+	if len(components) == 0 {
+		return nil
+	}
+
+	var variantCount, usedAsComponent int
+	err := tx.QueryRow(`SELECT (SELECT COUNT(*) FROM products WHERE parent_id = ?), (SELECT COUNT(*) FROM product_components WHERE component_id = ?)`,
+		productID, productID).Scan(&variantCount, &usedAsComponent)
+	if err != nil {
+		return err
+	}
+	if variantCount > 0 {
+		return NewValidationError("produk %d punya varian, tidak bisa dijadikan paket", productID)
+	}
+	if usedAsComponent > 0 {
+		return NewValidationError("produk %d sudah menjadi komponen paket lain, tidak bisa dijadikan paket", productID)
+	}
+
+	for i := range components {
+		c := &components[i]
+		if c.ComponentID == productID {
+			return NewValidationError("paket tidak bisa berisi dirinya sendiri")
+		}
+		var isBundle, hasVariants int
+		err := tx.QueryRow(`
+			SELECT name,
+				(SELECT COUNT(*) FROM product_components pc WHERE pc.product_id = p.id),
+				(SELECT COUNT(*) FROM products v WHERE v.parent_id = p.id)
+			FROM products p WHERE p.id = ?`, c.ComponentID).Scan(&c.ComponentName, &isBundle, &hasVariants)
+		if err == sql.ErrNoRows {
+			return NewValidationError("komponen product id %d tidak ditemukan", c.ComponentID)
+		}
+		if err != nil {
+			return err
+		}
+		if isBundle > 0 {
+			return NewValidationError("komponen %s adalah paket juga; paket tidak bisa bersarang", c.ComponentName)
+		}
+		if hasVariants > 0 {
+			return NewValidationError("komponen %s punya varian, pakai ID variannya", c.ComponentName)
+		}
+
+		if _, err := tx.Exec("INSERT INTO product_components (product_id, component_id, quantity) VALUES (?, ?, ?)",
+			productID, c.ComponentID, c.Quantity); err != nil {
+			if isUniqueViolation(err) {
+				return NewValidationError("komponen %s ditulis lebih dari sekali", c.ComponentName)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// queryVariants mengambil baris varian yang memenuhi kondisi where (tanpa alias tabel), dikelompokkan per ID produk induk.
+func queryVariants(q queryer, where string, args ...interface{}) (map[int][]models.ProductVariant, error) {
+	rows, err := q.Query(`
+		SELECT p.parent_id, p.id, COALESCE(p.variant_name, ''), COALESCE(p.sku, ''), `+productBarcodesColumn+`, p.price, p.stock, p.cost_price, p.min_stock, p.reorder_qty
+		FROM products p
+		WHERE `+where+`
+		ORDER BY p.parent_id, p.id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	variants := make(map[int][]models.ProductVariant)
+	for rows.Next() {
+		var parentID int
+		var v models.ProductVariant
+		var barcodes string
+		if err := rows.Scan(&parentID, &v.ID, &v.Name, &v.SKU, &barcodes, &v.Price, &v.Stock, &v.CostPrice, &v.MinStock, &v.ReorderQty); err != nil {
+			return nil, err
+		}
+		v.Barcodes = splitBarcodes(barcodes)
+		variants[parentID] = append(variants[parentID], v)
+	}
+	return variants, rows.Err()
+}
+
+// GetSelection mengambil produk dalam kategori categoryID DAN/ATAU dengan ID di ids (misal untuk cetak label rak),
+// urut kategori lalu nama. categoryID 0 dan ids kosong berarti tidak ada produk yang dipilih.
+// Produk yang punya varian diwakili oleh varian-variannya (yang benar-benar dijual), bukan baris induknya.
+func (r *ProductRepositoryImpl) GetSelection(categoryID int, ids []int) ([]models.Product, error) {
+	var conditions []string
+	var args []interface{}
+	if categoryID > 0 {
+		conditions = append(conditions, "p.category_id = ?")
+		args = append(args, categoryID)
+	}
+	if len(ids) > 0 {
+		placeholders := "(?" + strings.Repeat(", ?", len(ids)-1) + ")"
+		conditions = append(conditions, "p.id IN "+placeholders, "p.parent_id IN "+placeholders)
+		for _, id := range ids {
+			args = append(args, id)
+		}
+		for _, id := range ids {
+			args = append(args, id)
+		}
+	}
+	if len(conditions) == 0 {
+		return []models.Product{}, nil
+	}
+
+	rows, err := r.db.Query(`
+		SELECT p.id, p.name, COALESCE(p.sku, ''), `+productBarcodesColumn+`, p.price, p.stock, COALESCE(p.category_id, 0), p.cost_price
+		FROM products p
+		LEFT JOIN categories c ON p.category_id = c.id
+		WHERE (`+strings.Join(conditions, " OR ")+`)
+			AND NOT EXISTS (SELECT 1 FROM products v WHERE v.parent_id = p.id)
+		ORDER BY COALESCE(c.name, ''), p.name, p.id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := []models.Product{}
+	for rows.Next() {
+		var p models.Product
+		var barcodes string
+		if err := rows.Scan(&p.ID, &p.Name, &p.SKU, &barcodes, &p.Price, &p.Stock, &p.CategoryID, &p.CostPrice); err != nil {
+			return nil, err
+		}
+		p.Barcodes = splitBarcodes(barcodes)
+		products = append(products, p)
+	}
+	return products, rows.Err()
+}
+
+// stockKeepingFilter memilih baris produk (alias tabel p) yang benar-benar menyimpan stok:
+// bukan induk yang punya varian dan bukan paket (stok keduanya ada di varian/komponen).
+const stockKeepingFilter = `NOT EXISTS (SELECT 1 FROM products v WHERE v.parent_id = p.id)
+	AND NOT EXISTS (SELECT 1 FROM product_components pc WHERE pc.product_id = p.id)`
+
+// GetLowStock mengambil produk & varian yang punya batas stok minimum dan stoknya sudah <= batas itu,
+// urut dari yang paling kurang (relatif terhadap batasnya).
+// outletID > 0 membandingkan stok di outlet itu saja; 0 = total semua outlet.
+func (r *ProductRepositoryImpl) GetLowStock(outletID int) ([]models.LowStockItem, error) {
+	stockColumn := "p.stock"
+	var args []interface{}
+	if outletID > 0 {
+		// Kolom stok dipakai di SELECT, WHERE & ORDER BY, masing-masing butuh parameter outlet
+		stockColumn = outletStockColumn
+		args = []interface{}{outletID, outletID, outletID}
+	}
+
+	rows, err := r.db.Query(`
+		SELECT p.id, p.name, COALESCE(p.sku, ''), COALESCE(c.name, ''), p.unit, `+stockColumn+`, p.min_stock, p.reorder_qty
+		FROM products p
+		LEFT JOIN categories c ON c.id = p.category_id
+		WHERE p.min_stock > 0 AND `+stockColumn+` <= p.min_stock AND `+stockKeepingFilter+`
+		ORDER BY `+stockColumn+` * 1.0 / p.min_stock, p.name, p.id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []models.LowStockItem{}
+	for rows.Next() {
+		var item models.LowStockItem
+		if err := rows.Scan(&item.ProductID, &item.Name, &item.SKU, &item.CategoryName, &item.Unit, &item.Stock, &item.MinStock, &item.ReorderQty); err != nil {
+			return nil, err
+		}
+		item.Shortage = item.MinStock - item.Stock
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// ForEach memanggil fn untuk setiap produk (beserta nama kategorinya) langsung dari cursor database, urut ID.
+// Varian ikut sebagai baris tersendiri (ParentID terisi).
+// Dipakai untuk export, supaya katalog besar tidak perlu ditampung di memory.
+func (r *ProductRepositoryImpl) ForEach(fn func(models.Product) error) error {
+	rows, err := r.db.Query(`
+		SELECT p.id, p.name, COALESCE(p.sku, ''), ` + productBarcodesColumn + `, p.price, p.stock, p.unit, COALESCE(p.category_id, 0), p.cost_price, COALESCE(p.parent_id, 0), COALESCE(p.variant_name, ''), c.id, c.name
+		FROM products p
+		LEFT JOIN categories c ON p.category_id = c.id
+		ORDER BY p.id`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p models.Product
+		var barcodes string
+		var categoryID sql.NullInt64
+		var categoryName sql.NullString
+		if err := rows.Scan(&p.ID, &p.Name, &p.SKU, &barcodes, &p.Price, &p.Stock, &p.Unit, &p.CategoryID, &p.CostPrice, &p.ParentID, &p.VariantName, &categoryID, &categoryName); err != nil {
+			return err
+		}
+		p.Barcodes = splitBarcodes(barcodes)
+		if categoryID.Valid {
+			p.Category = &models.Category{ID: int(categoryID.Int64), Name: categoryName.String}
+		}
+		if err := fn(p); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Create menyimpan data produk baru (beserta variannya, jika ada) ke database.
+// Stok awal produk dicatat di ledger stok sebagai ADJUSTMENT, dalam Database Transaction yang sama.
 func (r *ProductRepositoryImpl) Create(product *models.Product) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	id, err := insertProduct(tx, product, "Stok awal")
+	if err != nil {
+		return err
+	}
+	product.ID = id
+
+	for i := range product.Variants {
+		if err := insertVariant(tx, product, &product.Variants[i]); err != nil {
+			return err
+		}
+	}
+
+	// ID di struct product sudah diisi agar pemanggil fungsi tau ID barunya.
+	return tx.Commit()
+}
+
+// variantRow menyusun baris products untuk varian v milik produk induk parent.
+// Nama, kategori, satuan & pencatatan batch mengikuti induk; harga, stok, barcode & batas stok milik varian sendiri.
+func variantRow(parent *models.Product, v *models.ProductVariant) models.Product {
+	return models.Product{
+		ID:           v.ID,
+		Name:         parent.Name + " - " + v.Name,
+		SKU:          v.SKU,
+		Barcodes:     v.Barcodes,
+		Price:        v.Price,
+		Stock:        v.Stock,
+		OutletID:     v.OutletID,
+		CostPrice:    v.CostPrice,
+		Unit:         parent.Unit,
+		TrackBatches: parent.TrackBatches,
+		MinStock:     v.MinStock,
+		ReorderQty:   v.ReorderQty,
+		CategoryID:   parent.CategoryID,
+		ParentID:     parent.ID,
+		VariantName:  v.Name,
+	}
+}
+
+// insertVariant menyimpan varian baru di bawah parent (yang sudah punya ID) dan mengisi v.ID.
+func insertVariant(tx *sql.Tx, parent *models.Product, v *models.ProductVariant) error {
+	row := variantRow(parent, v)
+	id, err := insertProduct(tx, &row, "Stok awal varian")
+	if err != nil {
+		return err
+	}
+	v.ID = id
+	return nil
+}
+
+// insertProduct menyimpan produk baru di dalam tx dan mencatat stok awalnya di ledger (note = catatan movement).
+func insertProduct(tx *sql.Tx, product *models.Product, note string) (int, error) {
 	// Query INSERT. Tanda tanya (?) adalah placeholder untuk mencegah SQL Injection.
-	query := "INSERT INTO products (name, price, stock, category_id) VALUES (?, ?, ?, ?)"
+	// Stok diisi 0 dulu, lalu ditambah lewat ledger supaya products.stock = SUM(stock_movements).
+	// cost_price (harga pokok) menjadi harga stok awal.
+	query := "INSERT INTO products (name, sku, price, stock, unit, track_batches, min_stock, reorder_qty, category_id, cost_price, parent_id, variant_name) VALUES (?, ?, ?, 0, ?, ?, ?, ?, ?, ?, ?, ?)"
+	if len(product.Components) > 0 && product.Stock != 0 {
+		return 0, NewValidationError("paket %s tidak punya stok sendiri; stok diambil dari komponennya", product.Name)
+	}
 
 	// Exec: Menjalankan query yang mengubah data (tidak mengembalikan baris data).
-	result, err := r.db.Exec(query, product.Name, product.Price, product.Stock, product.CategoryID)
+	result, err := tx.Exec(query, product.Name, nullIfEmpty(product.SKU), product.Price, productUnit(product), product.IsBatchTracked(), intOrZero(product.MinStock), intOrZero(product.ReorderQty),
+		product.CategoryID, product.CostPrice, nullIfZero(product.ParentID), nullIfEmpty(product.VariantName))
 	if err != nil {
-		return err
+		return 0, productUniqueError(err, product)
 	}
 
 	// Ambil ID yang baru saja digenerate oleh database (AUTOINCREMENT).
 	id, err := result.LastInsertId()
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	// Update ID di struct product agar pemanggil fungsi tau ID barunya.
-	product.ID = int(id)
+	if err := replaceBarcodes(tx, int(id), product.Barcodes); err != nil {
+		return 0, err
+	}
+	if err := replaceUnits(tx, int(id), product.Units); err != nil {
+		return 0, err
+	}
+	if err := replaceComponents(tx, int(id), product.Components); err != nil {
+		return 0, err
+	}
+
+	if product.OutletID > 0 && product.Stock != 0 {
+		if err := requireActiveOutlet(tx, product.OutletID); err != nil {
+			return 0, err
+		}
+	}
+	_, err = recordStockMovement(tx, models.StockMovement{
+		ProductID:     int(id),
+		OutletID:      product.OutletID,
+		Type:          models.StockMovementAdjustment,
+		Quantity:      product.Stock,
+		ReferenceType: models.StockReferenceProduct,
+		ReferenceID:   int(id),
+		Note:          note,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int(id), nil
+}
+
+// productBarcodesColumn mengambil semua barcode produk (alias tabel p) sebagai satu string dipisah koma,
+// supaya daftar produk tidak perlu satu query tambahan per produk.
+const productBarcodesColumn = "COALESCE((SELECT GROUP_CONCAT(b.barcode, ',') FROM product_barcodes b WHERE b.product_id = p.id), '')"
+
+func splitBarcodes(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+// replaceBarcodes mengganti seluruh barcode produk dengan daftar baru (nil = tidak diubah).
+func replaceBarcodes(tx *sql.Tx, productID int, barcodes []string) error {
+	if barcodes == nil {
+		return nil
+	}
+	if _, err := tx.Exec("DELETE FROM product_barcodes WHERE product_id = ?", productID); err != nil {
+		return err
+	}
+	for _, barcode := range barcodes {
+		if _, err := tx.Exec("INSERT INTO product_barcodes (product_id, barcode) VALUES (?, ?)", productID, barcode); err != nil {
+			if isUniqueViolation(err) {
+				return NewValidationError("barcode %s sudah dipakai produk lain", barcode)
+			}
+			return err
+		}
+	}
 	return nil
 }
 
-// GetByID mengambil satu produk dan DETAIL KATEGORINYA menggunakan JOIN.
+// findProductIDByCode mencari produk dari hasil scan: barcode lebih dulu, lalu SKU.
+func findProductIDByCode(q queryer, code string) (int, error) {
+	var id int
+	err := q.QueryRow("SELECT product_id FROM product_barcodes WHERE barcode = ?", code).Scan(&id)
+	if err == sql.ErrNoRows {
+		err = q.QueryRow("SELECT id FROM products WHERE sku = ?", code).Scan(&id)
+	}
+	if err == sql.ErrNoRows {
+		return 0, ErrNotFound
+	}
+	return id, err
+}
+
+// productUnit mengembalikan satuan dasar produk; kosong berarti "pcs".
+func productUnit(product *models.Product) string {
+	if product.Unit == "" {
+		return models.DefaultProductUnit
+	}
+	return product.Unit
+}
+
+// intOrZero mengembalikan nilai field angka opsional (nil = 0).
+func intOrZero(n *int) int {
+	if n == nil {
+		return 0
+	}
+	return *n
+}
+
+// nullIfZero menyimpan ID 0 sebagai NULL (misal parent_id produk yang bukan varian).
+func nullIfZero(id int) interface{} {
+	if id == 0 {
+		return nil
+	}
+	return id
+}
+
+// productUniqueError menerjemahkan pelanggaran UNIQUE index products (SKU atau nama varian) menjadi ValidationError.
+func productUniqueError(err error, product *models.Product) error {
+	if !isUniqueViolation(err) {
+		return err
+	}
+	if strings.Contains(err.Error(), "variant_name") {
+		return NewValidationError("varian %s sudah ada", product.VariantName)
+	}
+	return NewValidationError("SKU %s sudah dipakai produk lain", product.SKU)
+}
+
+// nullIfEmpty menyimpan string kosong sebagai NULL, supaya tidak bentrok dengan UNIQUE index (NULL boleh lebih dari satu).
+func nullIfEmpty(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// GetByID mengambil satu produk dan DETAIL KATEGORINYA menggunakan JOIN, beserta variannya.
+// ID varian juga bisa dipakai; hasilnya baris varian itu sendiri (ParentID terisi).
 func (r *ProductRepositoryImpl) GetByID(id int) (*models.Product, error) {
 	// Query JOIN: Menggabungkan tabel products (p) dan categories (c).
 	// LEFT JOIN: Ambil produk meskipun kategori-nya tidak ada.
 	query := `
-		SELECT p.id, p.name, p.price, p.stock, p.category_id, c.id, c.name, c.description
+		SELECT p.id, p.name, COALESCE(p.sku, ''), ` + productBarcodesColumn + `, p.price, p.stock, p.unit, p.track_batches, p.min_stock, p.reorder_qty, p.category_id, p.cost_price,
+			COALESCE(p.parent_id, 0), COALESCE(p.variant_name, ''), c.id, c.name, c.description
 		FROM products p
 		LEFT JOIN categories c ON p.category_id = c.id
 		WHERE p.id = ?`
 
 	var p models.Product
 	var c models.Category
+	var barcodes string
 
 	// QueryRow: Untuk mengambil 1 baris data saja.
 	// Kita scan kolom produk ke struct p, dan kolom kategori ke struct c.
 	err := r.db.QueryRow(query, id).Scan(
-		&p.ID, &p.Name, &p.Price, &p.Stock, &p.CategoryID,
-		&c.ID, &c.Name, &c.Description,
+		&p.ID, &p.Name, &p.SKU, &barcodes, &p.Price, &p.Stock, &p.Unit, &p.TrackBatches, &p.MinStock, &p.ReorderQty, &p.CategoryID, &p.CostPrice,
+		&p.ParentID, &p.VariantName, &c.ID, &c.Name, &c.Description,
 	)
 	if err != nil {
 		return nil, err
@@ -100,19 +587,471 @@ func (r *ProductRepositoryImpl) GetByID(id int) (*models.Product, error) {
 
 	// Masukkan struct category ke dalam struct product (Nested Struct).
 	p.Category = &c
+	p.Barcodes = splitBarcodes(barcodes)
+
+	variants, err := queryVariants(r.db, "parent_id = ?", p.ID)
+	if err != nil {
+		return nil, err
+	}
+	p.Variants = variants[p.ID]
+
+	units, err := queryUnits(r.db, "product_id = ?", p.ID)
+	if err != nil {
+		return nil, err
+	}
+	p.Units = units[p.ID]
+
+	components, err := queryComponents(r.db, "pc.product_id = ?", p.ID)
+	if err != nil {
+		return nil, err
+	}
+	p.Components = components[p.ID]
 	return &p, nil
 }
 
+// GetByCode mencari produk dari hasil scan (barcode, lalu SKU). Mengembalikan ErrNotFound jika tidak ada.
+func (r *ProductRepositoryImpl) GetByCode(code string) (*models.Product, error) {
+	id, err := findProductIDByCode(r.db, code)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(id)
+}
+
 // Update mengubah data produk yang sudah ada.
+// Selisih stok (jika stoknya diubah) dicatat di ledger stok sebagai ADJUSTMENT, dalam Database Transaction yang sama.
+// Varian tidak bisa diubah lewat sini (pakai UpdateVariant), dan product.Variants diabaikan.
 func (r *ProductRepositoryImpl) Update(product *models.Product) error {
-	query := "UPDATE products SET name = ?, price = ?, stock = ?, category_id = ? WHERE id = ?"
-	_, err := r.db.Exec(query, product.Name, product.Price, product.Stock, product.CategoryID, product.ID)
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var parentID sql.NullInt64
+	err = tx.QueryRow("SELECT parent_id FROM products WHERE id = ?", product.ID).Scan(&parentID)
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if parentID.Valid {
+		return NewValidationError("produk %d adalah varian, ubah lewat /api/v1/products/%d/variants/%d", product.ID, parentID.Int64, product.ID)
+	}
+
+	if err := updateProduct(tx, product, "Edit stok produk"); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// updateProduct menimpa data produk di dalam tx; selisih stok dicatat di ledger sebagai ADJUSTMENT (note = catatan movement).
+// Satuan dasar yang kosong berarti tidak diubah. Jika produk punya varian, nama, satuan & kategori varian ikut diperbarui,
+// dan stoknya diabaikan (stok ada di varian).
+// Begitu juga paket: stoknya diabaikan karena stok ada di komponen.
+func updateProduct(tx *sql.Tx, product *models.Product, note string) error {
+	var currentStock, variantCount int
+	var currentUnit string
+	err := tx.QueryRow("SELECT stock, unit, (SELECT COUNT(*) FROM products v WHERE v.parent_id = p.id) FROM products p WHERE p.id = ?",
+		product.ID).Scan(&currentStock, &currentUnit, &variantCount)
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	// Satuan dasar yang tidak dikirim tetap seperti semula; stok & faktor satuan lain dihitung dari satuan itu
+	if product.Unit == "" {
+		product.Unit = currentUnit
+	}
+	for _, u := range product.Units {
+		if strings.EqualFold(u.Name, product.Unit) {
+			return NewValidationError("satuan %s sama dengan satuan dasar", u.Name)
+		}
+	}
+
+	// min_stock & reorder_qty yang tidak dikirim (NULL) tetap seperti semula
+	query := "UPDATE products SET name = ?, sku = ?, price = ?, unit = ?, min_stock = COALESCE(?, min_stock), reorder_qty = COALESCE(?, reorder_qty), category_id = ?, variant_name = ? WHERE id = ?"
+	if _, err := tx.Exec(query, product.Name, nullIfEmpty(product.SKU), product.Price, productUnit(product), product.MinStock, product.ReorderQty,
+		product.CategoryID, nullIfEmpty(product.VariantName), product.ID); err != nil {
+		return productUniqueError(err, product)
+	}
+
+	if variantCount > 0 {
+		_, err := tx.Exec("UPDATE products SET name = ? || ' - ' || variant_name, unit = ?, category_id = ? WHERE parent_id = ?",
+			product.Name, productUnit(product), product.CategoryID, product.ID)
+		if err != nil {
+			return err
+		}
+		product.Stock = currentStock
+	}
+
+	if err := replaceBarcodes(tx, product.ID, product.Barcodes); err != nil {
+		return err
+	}
+	if err := replaceUnits(tx, product.ID, product.Units); err != nil {
+		return err
+	}
+	if err := replaceComponents(tx, product.ID, product.Components); err != nil {
+		return err
+	}
+	if product.TrackBatches != nil {
+		if err := setBatchTracking(tx, product.ID, *product.TrackBatches); err != nil {
+			return err
+		}
+	}
+
+	var componentCount int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM product_components WHERE product_id = ?", product.ID).Scan(&componentCount); err != nil {
+		return err
+	}
+	if componentCount > 0 {
+		if currentStock != 0 {
+			return NewValidationError("stok %s masih %d; nolkan dulu sebelum dijadikan paket", product.Name, currentStock)
+		}
+		product.Stock = currentStock
+	}
+
+	// Harga pokok hanya diubah jika dikirim; biasanya harga pokok dihitung otomatis dari penerimaan barang.
+	if product.CostPrice > 0 {
+		if _, err := tx.Exec("UPDATE products SET cost_price = ? WHERE id = ?", product.CostPrice, product.ID); err != nil {
+			return err
+		}
+	}
+
+	// product.Stock adalah total semua outlet; selisihnya dicatat di outlet product.OutletID
+	if product.Stock != currentStock {
+		if err := requireStockOutlet(tx, product); err != nil {
+			return err
+		}
+	}
+	_, err = recordStockMovement(tx, models.StockMovement{
+		ProductID:     product.ID,
+		OutletID:      product.OutletID,
+		Type:          models.StockMovementAdjustment,
+		Quantity:      product.Stock - currentStock,
+		ReferenceType: models.StockReferenceProduct,
+		ReferenceID:   product.ID,
+		Note:          note,
+	})
 	return err
 }
 
-// Delete menghapus produk dari database.
+// requireStockOutlet memastikan outlet tempat selisih stok product dicatat jelas: outlet yang disebut harus aktif,
+// dan tanpa outlet_id stok hanya boleh diubah jika semua stoknya ada di outlet utama.
+func requireStockOutlet(tx *sql.Tx, product *models.Product) error {
+	if product.OutletID > 0 {
+		return requireActiveOutlet(tx, product.OutletID)
+	}
+	var otherOutlets int
+	err := tx.QueryRow("SELECT COUNT(*) FROM outlet_stock WHERE product_id = ? AND outlet_id != ? AND stock != 0",
+		product.ID, models.DefaultOutletID).Scan(&otherOutlets)
+	if err != nil {
+		return err
+	}
+	if otherOutlets > 0 {
+		return NewValidationError("stok %s ada di beberapa outlet; isi outlet_id untuk mengubah stoknya", product.Name)
+	}
+	return nil
+}
+
+// Delete menghapus produk (beserta barcode, satuan, komponen paket, lapisan HPP, batch & varian-variannya) dari database.
+// Produk yang masih menjadi komponen paket lain tidak bisa dihapus.
 func (r *ProductRepositoryImpl) Delete(id int) error {
-	query := "DELETE FROM products WHERE id = ?"
-	_, err := r.db.Exec(query, id)
-	return err
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var bundleName string
+	err = tx.QueryRow(`
+		SELECT p.name FROM product_components pc JOIN products p ON p.id = pc.product_id
+		WHERE pc.component_id IN (SELECT id FROM products WHERE id = ? OR parent_id = ?)
+		LIMIT 1`, id, id).Scan(&bundleName)
+	if err == nil {
+		return NewValidationError("produk %d masih menjadi komponen paket %s", id, bundleName)
+	}
+	if err != sql.ErrNoRows {
+		return err
+	}
+
+	// SQLite tidak menjalankan ON DELETE CASCADE tanpa PRAGMA foreign_keys, jadi barcode, satuan, komponen, stok outlet, lapisan HPP, batch & varian dihapus manual
+	if _, err := tx.Exec("DELETE FROM product_barcodes WHERE product_id IN (SELECT id FROM products WHERE id = ? OR parent_id = ?)", id, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM product_units WHERE product_id IN (SELECT id FROM products WHERE id = ? OR parent_id = ?)", id, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM product_components WHERE product_id = ?", id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM outlet_stock WHERE product_id IN (SELECT id FROM products WHERE id = ? OR parent_id = ?)", id, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM cost_layers WHERE product_id IN (SELECT id FROM products WHERE id = ? OR parent_id = ?)", id, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM stock_batches WHERE product_id IN (SELECT id FROM products WHERE id = ? OR parent_id = ?)", id, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM products WHERE id = ? OR parent_id = ?", id, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// loadVariantParent mengambil produk induk untuk operasi varian. Induk yang tidak ada -> ErrNotFound;
+// varian tidak boleh menjadi induk varian lain.
+func loadVariantParent(tx *sql.Tx, parentID int) (*models.Product, error) {
+	parent := models.Product{ID: parentID}
+	var grandParentID sql.NullInt64
+	err := tx.QueryRow("SELECT name, stock, unit, track_batches, COALESCE(category_id, 0), parent_id FROM products WHERE id = ?", parentID).
+		Scan(&parent.Name, &parent.Stock, &parent.Unit, &parent.TrackBatches, &parent.CategoryID, &grandParentID)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if grandParentID.Valid {
+		return nil, NewValidationError("produk %d adalah varian, tidak bisa punya varian lagi", parentID)
+	}
+	return &parent, nil
+}
+
+// checkVariantOwner memastikan variantID adalah varian milik parentID (jika bukan -> ErrNotFound).
+func checkVariantOwner(tx *sql.Tx, parentID, variantID int) error {
+	var exists int
+	err := tx.QueryRow("SELECT COUNT(*) FROM products WHERE id = ? AND parent_id = ?", variantID, parentID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if exists == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// CreateVariant menambah varian baru ke produk parentID dan mengisi v.ID.
+// Stok produk induk harus 0 dulu, karena setelah punya varian stok hanya dihitung per varian.
+func (r *ProductRepositoryImpl) CreateVariant(parentID int, v *models.ProductVariant) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	parent, err := loadVariantParent(tx, parentID)
+	if err != nil {
+		return err
+	}
+	if parent.Stock != 0 {
+		return NewValidationError("stok %s masih %d; nolkan dulu (atau pindahkan ke varian) sebelum menambah varian", parent.Name, parent.Stock)
+	}
+	var componentRefs int
+	err = tx.QueryRow("SELECT COUNT(*) FROM product_components WHERE product_id = ? OR component_id = ?", parentID, parentID).Scan(&componentRefs)
+	if err != nil {
+		return err
+	}
+	if componentRefs > 0 {
+		return NewValidationError("%s adalah paket atau komponen paket, tidak bisa punya varian", parent.Name)
+	}
+
+	if err := insertVariant(tx, parent, v); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// UpdateVariant mengubah varian v.ID milik produk parentID. Selisih stok dicatat di ledger seperti Update.
+func (r *ProductRepositoryImpl) UpdateVariant(parentID int, v *models.ProductVariant) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	parent, err := loadVariantParent(tx, parentID)
+	if err != nil {
+		return err
+	}
+	if err := checkVariantOwner(tx, parentID, v.ID); err != nil {
+		return err
+	}
+
+	row := variantRow(parent, v)
+	if err := updateProduct(tx, &row, "Edit stok varian"); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeleteVariant menghapus varian variantID (beserta barcode, stok outlet, lapisan HPP & batch-nya) dari produk parentID.
+func (r *ProductRepositoryImpl) DeleteVariant(parentID, variantID int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := checkVariantOwner(tx, parentID, variantID); err != nil {
+		return err
+	}
+	var componentRefs int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM product_components WHERE component_id = ?", variantID).Scan(&componentRefs); err != nil {
+		return err
+	}
+	if componentRefs > 0 {
+		return NewValidationError("varian %d masih menjadi komponen paket", variantID)
+	}
+	if _, err := tx.Exec("DELETE FROM product_barcodes WHERE product_id = ?", variantID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM product_units WHERE product_id = ?", variantID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM outlet_stock WHERE product_id = ?", variantID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM cost_layers WHERE product_id = ?", variantID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM stock_batches WHERE product_id = ?", variantID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM products WHERE id = ?", variantID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Import menyimpan (upsert by SKU) baris-baris import CSV dalam SATU Database Transaction.
+// Kategori dicari berdasarkan ID atau nama (tidak case-sensitive); nama yang belum ada dibuat otomatis.
+// Setiap baris yang gagal dicatat di result.Errors dan baris lain tetap diperiksa, supaya semua kesalahan terlihat sekaligus.
+// Perubahan hanya di-commit jika bukan dry run DAN tidak ada error sama sekali.
+func (r *ProductRepositoryImpl) Import(rows []models.ProductImportRow, dryRun bool) (*models.ProductImportResult, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result := &models.ProductImportResult{
+		DryRun:    dryRun,
+		TotalRows: len(rows),
+		Rows:      []models.ProductImportRowResult{},
+		Errors:    []models.ProductImportError{},
+	}
+	categories := make(map[string]int) // nama kategori (lowercase) -> ID, termasuk yang baru dibuat di import ini
+
+	for _, row := range rows {
+		categoryID, err := resolveImportCategory(tx, row, categories, result)
+		if err != nil {
+			var validationErr *ValidationError
+			if errors.As(err, &validationErr) {
+				result.Errors = append(result.Errors, models.ProductImportError{Line: row.Line, Field: "category", Message: err.Error()})
+				continue
+			}
+			return nil, err
+		}
+
+		product := models.Product{Name: row.Name, SKU: row.SKU, Price: row.Price, Stock: row.Stock, CategoryID: categoryID}
+		action := models.ProductImportCreate
+		if row.SKU != "" {
+			var parentID sql.NullInt64
+			// Satuan dasar, pencatatan batch & batas stok tidak ada di CSV, jadi ikut nilai produk yang sudah ada
+			err := tx.QueryRow("SELECT id, parent_id, unit, track_batches, min_stock, reorder_qty FROM products WHERE sku = ?", row.SKU).
+				Scan(&product.ID, &parentID, &product.Unit, &product.TrackBatches, &product.MinStock, &product.ReorderQty)
+			if err != nil && err != sql.ErrNoRows {
+				return nil, err
+			}
+			if err == nil && parentID.Valid {
+				result.Errors = append(result.Errors, models.ProductImportError{Line: row.Line, Field: "sku",
+					Message: fmt.Sprintf("SKU %s milik varian produk %d, ubah lewat endpoint varian", row.SKU, parentID.Int64)})
+				continue
+			}
+			if err == nil {
+				action = models.ProductImportUpdate
+			}
+		}
+
+		if action == models.ProductImportUpdate {
+			err = updateProduct(tx, &product, "Import produk")
+		} else {
+			product.ID, err = insertProduct(tx, &product, "Import produk (stok awal)")
+		}
+		if err != nil {
+			// Misal SKU bentrok atau stok outlet tidak cukup: catat di baris ini dan lanjut ke baris berikutnya
+			var validationErr *ValidationError
+			if errors.As(err, &validationErr) {
+				result.Errors = append(result.Errors, models.ProductImportError{Line: row.Line, Message: err.Error()})
+				continue
+			}
+			return nil, err
+		}
+		if action == models.ProductImportUpdate {
+			result.Updated++
+		} else {
+			result.Created++
+		}
+
+		rowResult := models.ProductImportRowResult{Line: row.Line, Action: action, ProductID: product.ID, SKU: row.SKU, Name: row.Name}
+		if dryRun && action == models.ProductImportCreate {
+			rowResult.ProductID = 0 // ID hanya sementara, ikut di-rollback
+		}
+		result.Rows = append(result.Rows, rowResult)
+	}
+
+	if dryRun || len(result.Errors) > 0 {
+		return result, nil // defer Rollback membatalkan semua perubahan
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	result.Applied = true
+	return result, nil
+}
+
+// resolveImportCategory mengembalikan ID kategori untuk satu baris import.
+// Kategori berupa ID harus sudah ada; kategori berupa nama dibuat jika belum ada (dihitung di result.CategoriesCreated).
+func resolveImportCategory(tx *sql.Tx, row models.ProductImportRow, categories map[string]int, result *models.ProductImportResult) (int, error) {
+	if row.CategoryName == "" {
+		var exists int
+		err := tx.QueryRow("SELECT COUNT(*) FROM categories WHERE id = ?", row.CategoryID).Scan(&exists)
+		if err != nil {
+			return 0, err
+		}
+		if exists == 0 {
+			return 0, NewValidationError("kategori ID %d tidak ditemukan", row.CategoryID)
+		}
+		return row.CategoryID, nil
+	}
+
+	key := strings.ToLower(row.CategoryName)
+	if id, ok := categories[key]; ok {
+		return id, nil
+	}
+
+	var id int
+	err := tx.QueryRow("SELECT id FROM categories WHERE LOWER(name) = ? ORDER BY id LIMIT 1", key).Scan(&id)
+	if err == sql.ErrNoRows {
+		res, err := tx.Exec("INSERT INTO categories (name, description) VALUES (?, '')", row.CategoryName)
+		if err != nil {
+			return 0, err
+		}
+		newID, err := res.LastInsertId()
+		if err != nil {
+			return 0, err
+		}
+		id = int(newID)
+		result.CategoriesCreated++
+	} else if err != nil {
+		return 0, err
+	}
+
+	categories[key] = id
+	return id, nil
 }
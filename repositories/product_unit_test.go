@@ -0,0 +1,163 @@
+package repositories
+
+import (
+	"codeWithUmam/models"
+	"testing"
+)
+
+func TestTransactionRepository_CheckoutUnits(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	repo := NewTransactionRepository(db, models.TaxConfig{}, models.CostingMethodAverage, models.BusinessDay{})
+
+	mie := &models.Product{Name: "Mie Instan", Price: 3000, Stock: 100, CostPrice: 2500, Unit: "pcs", Units: []models.ProductUnit{
+		{Name: "pak", Factor: 5, Price: 14000}, // harga grosir per pak
+		{Name: "karton", Factor: 40},           // harga 0 = 40 x harga per pcs
+	}}
+	if err := NewProductRepository(db).Create(mie); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	trx, err := repo.CreateTransaction(models.CheckoutRequest{
+		Items: []models.CheckoutItem{
+			{ProductID: mie.ID, Quantity: 2, Unit: "PAK"}, // nama satuan tidak case-sensitive
+			{ProductID: mie.ID, Quantity: 1, Unit: "karton"},
+			{ProductID: mie.ID, Quantity: 3},
+		},
+		PaidAmount: 200000, PaymentMethod: "CASH",
+	}, CheckoutOptions{})
+	if err != nil {
+		t.Fatalf("CreateTransaction failed: %v", err)
+	}
+
+	want := []struct {
+		unit          string
+		factor, price int
+	}{{"pak", 5, 14000}, {"karton", 40, 120000}, {"pcs", 1, 3000}}
+	for i, w := range want {
+		d := trx.Details[i]
+		if d.Unit != w.unit || d.UnitFactor != w.factor || d.UnitPrice != w.price {
+			t.Errorf("detail %d: expected %d x %s @ %d, got %+v", i, w.factor, w.unit, w.price, d)
+		}
+	}
+	if trx.TotalAmount != 2*14000+120000+3*3000 {
+		t.Errorf("unexpected total %d", trx.TotalAmount)
+	}
+	// 2 pak + 1 karton + 3 pcs = 53 pcs keluar dari stok
+	if stock := productStock(t, db, mie.ID); stock != 47 {
+		t.Errorf("expected stock 47 pcs, got %d", stock)
+	}
+	if trx.Details[0].COGS != 10*2500 {
+		t.Errorf("expected COGS of 10 pcs, got %d", trx.Details[0].COGS)
+	}
+
+	// Refund 1 pak mengembalikan 5 pcs
+	if _, err := repo.RefundTransaction(trx.ID, []models.RefundItem{{DetailID: trx.Details[0].ID, Quantity: 1}}, "rusak", "admin"); err != nil {
+		t.Fatalf("RefundTransaction failed: %v", err)
+	}
+	if stock := productStock(t, db, mie.ID); stock != 52 {
+		t.Errorf("expected stock 52 pcs after refund, got %d", stock)
+	}
+	if report, _ := NewStockMovementRepository(db).CheckConsistency(); !report.Consistent {
+		t.Errorf("expected ledger to be consistent, got %+v", report.Discrepancies)
+	}
+
+	// Satuan tidak terdaftar & stok (dalam pcs) tidak cukup untuk 2 karton
+	for _, item := range []models.CheckoutItem{{ProductID: mie.ID, Quantity: 1, Unit: "dus"}, {ProductID: mie.ID, Quantity: 2, Unit: "karton"}} {
+		if _, err := repo.CreateTransaction(models.CheckoutRequest{Items: []models.CheckoutItem{item}, PaidAmount: 500000, PaymentMethod: "CASH"}, CheckoutOptions{}); err == nil {
+			t.Errorf("expected error for %+v", item)
+		}
+	}
+
+	// Laporan menghitung qty dalam satuan dasar: 1 pak (5) + 1 karton (40) + 3 pcs
+	date := trx.CreatedAt.Format("2006-01-02")
+	report, err := repo.GetSalesReport(date, date, models.SalesGroupByProduct, 5, 0)
+	if err != nil {
+		t.Fatalf("GetSalesReport failed: %v", err)
+	}
+	if len(report.TopProducts) != 1 || report.TopProducts[0].Quantity != 48 {
+		t.Errorf("expected 48 pcs sold, got %+v", report.TopProducts)
+	}
+}
+
+func TestProductRepository_UpdateKeepsUnit(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	repo := NewProductRepository(db)
+	if _, err := db.Exec("INSERT INTO categories (name, description) VALUES ('Minuman', '')"); err != nil {
+		t.Fatalf("failed to seed category: %v", err)
+	}
+
+	teh := &models.Product{Name: "Teh Celup", Price: 12000, Unit: "kotak", CategoryID: 1, Units: []models.ProductUnit{{Name: "dus", Factor: 24}},
+		Variants: []models.ProductVariant{{Name: "Melati", Price: 12000, Stock: 10}}}
+	if err := repo.Create(teh); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	// Client lama tidak mengirim "unit": satuan dasar induk & varian tetap "kotak"
+	if err := repo.Update(&models.Product{ID: teh.ID, Name: "Teh Celup Premium", Price: 13000, CategoryID: 1}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	updated, err := repo.GetByID(teh.ID)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if updated.Unit != "kotak" || len(updated.Units) != 1 || updated.Units[0].Factor != 24 {
+		t.Errorf("expected unit kotak with dus of 24 to be kept, got %q %+v", updated.Unit, updated.Units)
+	}
+	variant, err := repo.GetByID(teh.Variants[0].ID)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if variant.Unit != "kotak" {
+		t.Errorf("expected variant unit kotak, got %q", variant.Unit)
+	}
+
+	// Satuan lain tidak boleh sama dengan satuan dasar yang tersimpan
+	err = repo.Update(&models.Product{ID: teh.ID, Name: "Teh Celup", Price: 13000, CategoryID: 1, Units: []models.ProductUnit{{Name: "Kotak", Factor: 2}}})
+	if _, ok := err.(*ValidationError); !ok {
+		t.Errorf("expected ValidationError for a unit named like the base unit, got %v", err)
+	}
+}
+
+func TestPurchaseOrderRepository_ReceiveInPurchaseUnit(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	repo := NewPurchaseOrderRepository(db)
+	supplier := &models.Supplier{Name: "Distributor Mie"}
+	if err := NewSupplierRepository(db).Create(supplier); err != nil {
+		t.Fatalf("failed to create supplier: %v", err)
+	}
+	mie := &models.Product{Name: "Mie Instan", Price: 3000, Units: []models.ProductUnit{{Name: "karton", Factor: 40}}}
+	if err := NewProductRepository(db).Create(mie); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	if _, err := repo.Create(models.CreatePurchaseOrderRequest{
+		SupplierID: supplier.ID,
+		Items:      []models.PurchaseOrderItemRequest{{ProductID: mie.ID, Quantity: 1, UnitCost: 1000, Unit: "lusin"}},
+	}); err == nil {
+		t.Error("expected error for unknown purchase unit")
+	}
+
+	order, err := repo.Create(models.CreatePurchaseOrderRequest{
+		SupplierID: supplier.ID,
+		Items:      []models.PurchaseOrderItemRequest{{ProductID: mie.ID, Quantity: 3, UnitCost: 100000, Unit: "karton"}},
+	})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if item := order.Items[0]; item.Unit != "karton" || item.UnitFactor != 40 || order.TotalCost != 300000 {
+		t.Fatalf("unexpected purchase order: %+v", order)
+	}
+
+	if _, err := repo.Receive(order.ID, models.ReceiveGoodsRequest{
+		Items: []models.ReceiveGoodsItemRequest{{PurchaseOrderItemID: order.Items[0].ID, Quantity: 2}},
+	}); err != nil {
+		t.Fatalf("Receive failed: %v", err)
+	}
+
+	// 2 karton = 80 pcs, harga pokok per pcs = 100000 / 40
+	var stock, cost int
+	db.QueryRow("SELECT stock, cost_price FROM products WHERE id = ?", mie.ID).Scan(&stock, &cost)
+	if stock != 80 || cost != 2500 {
+		t.Errorf("expected 80 pcs at 2500, got %d pcs at %d", stock, cost)
+	}
+}
@@ -0,0 +1,224 @@
+package repositories
+
+import (
+	"codeWithUmam/models"
+	"errors"
+	"testing"
+)
+
+func TestProductRepository_Variants(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	repo := NewProductRepository(db)
+	if _, err := db.Exec("INSERT INTO categories (name, description) VALUES ('Minuman', ''), ('Es', '')"); err != nil {
+		t.Fatalf("failed to seed category: %v", err)
+	}
+
+	teh := &models.Product{Name: "Es Teh", Price: 5000, CategoryID: 1, Variants: []models.ProductVariant{
+		{Name: "S", SKU: "TEH-S", Price: 4000, Stock: 10},
+		{Name: "L", SKU: "TEH-L", Price: 6000, Stock: 5, Barcodes: []string{"96385074"}},
+	}}
+	if err := repo.Create(teh); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	small, large := teh.Variants[0], teh.Variants[1]
+	if small.ID == 0 || large.ID == 0 {
+		t.Fatalf("expected variant IDs to be set, got %+v", teh.Variants)
+	}
+	kopi := &models.Product{Name: "Kopi", Price: 5000, Stock: 3, CategoryID: 1}
+	if err := repo.Create(kopi); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	// GetAll hanya mengembalikan produk induk, variannya bersarang
+	products, err := repo.GetAll("")
+	if err != nil {
+		t.Fatalf("GetAll failed: %v", err)
+	}
+	if len(products) != 2 || len(products[0].Variants) != 2 || len(products[1].Variants) != 0 {
+		t.Fatalf("expected 2 parents (Es Teh with 2 variants), got %+v", products)
+	}
+	if v := products[0].Variants[1]; v.Name != "L" || v.Price != 6000 || v.Stock != 5 || len(v.Barcodes) != 1 {
+		t.Errorf("unexpected variant L: %+v", v)
+	}
+	// Pencarian nama varian ikut menemukan induknya
+	if found, _ := repo.GetAll("teh - l"); len(found) != 1 || found[0].ID != teh.ID {
+		t.Errorf("expected search by variant name to return Es Teh, got %+v", found)
+	}
+
+	// Stok awal varian masuk ledger per varian
+	if got := productStock(t, db, large.ID); got != 5 {
+		t.Errorf("expected variant stock 5, got %d", got)
+	}
+	if report, _ := NewStockMovementRepository(db).CheckConsistency(); !report.Consistent {
+		t.Errorf("expected ledger to be consistent, got %+v", report.Discrepancies)
+	}
+
+	// Barcode varian menunjuk ke baris varian
+	scanned, err := repo.GetByCode("96385074")
+	if err != nil {
+		t.Fatalf("GetByCode failed: %v", err)
+	}
+	if scanned.ID != large.ID || scanned.ParentID != teh.ID || scanned.Name != "Es Teh - L" || scanned.VariantName != "L" {
+		t.Errorf("unexpected scanned variant: %+v", scanned)
+	}
+
+	// Ganti nama & kategori induk ikut mengubah varian; stok induk tetap 0
+	if err := repo.Update(&models.Product{ID: teh.ID, Name: "Teh Manis", Price: 5000, Stock: 99, CategoryID: 2}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	got, err := repo.GetByID(large.ID)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if got.Name != "Teh Manis - L" || got.CategoryID != 2 {
+		t.Errorf("expected variant to follow parent, got %+v", got)
+	}
+	if stock := productStock(t, db, teh.ID); stock != 0 {
+		t.Errorf("expected parent stock to stay 0, got %d", stock)
+	}
+
+	// Varian tidak bisa diubah lewat Update biasa, dan nama varian unik per induk
+	if _, ok := repo.Update(&models.Product{ID: large.ID, Name: "X", CategoryID: 2}).(*ValidationError); !ok {
+		t.Error("expected ValidationError when updating a variant through Update")
+	}
+	if _, ok := repo.CreateVariant(teh.ID, &models.ProductVariant{Name: "L", Price: 1}).(*ValidationError); !ok {
+		t.Error("expected ValidationError for duplicate variant name")
+	}
+
+	// Update varian: selisih stok dicatat di ledger
+	large.Stock = 8
+	large.Price = 6500
+	if err := repo.UpdateVariant(teh.ID, &large); err != nil {
+		t.Fatalf("UpdateVariant failed: %v", err)
+	}
+	movements, _ := NewStockMovementRepository(db).GetByProduct(large.ID)
+	if len(movements) != 2 {
+		t.Errorf("expected initial stock + adjustment in variant ledger, got %+v", movements)
+	}
+	if got := productStock(t, db, large.ID); got != 8 {
+		t.Errorf("expected variant stock 8, got %d", got)
+	}
+	if err := repo.UpdateVariant(kopi.ID, &large); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for variant of another product, got %v", err)
+	}
+
+	// Produk dengan stok tidak bisa langsung diberi varian, dan varian tidak bisa punya varian
+	if _, ok := repo.CreateVariant(kopi.ID, &models.ProductVariant{Name: "Besar"}).(*ValidationError); !ok {
+		t.Error("expected ValidationError when parent still has stock")
+	}
+	if _, ok := repo.CreateVariant(small.ID, &models.ProductVariant{Name: "Besar"}).(*ValidationError); !ok {
+		t.Error("expected ValidationError when adding a variant to a variant")
+	}
+	if err := repo.CreateVariant(999, &models.ProductVariant{Name: "X"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for unknown parent, got %v", err)
+	}
+
+	// Hapus varian lalu hapus induk beserta sisa variannya
+	if err := repo.DeleteVariant(teh.ID, small.ID); err != nil {
+		t.Fatalf("DeleteVariant failed: %v", err)
+	}
+	if err := repo.Delete(teh.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	var remaining int
+	db.QueryRow("SELECT COUNT(*) FROM products WHERE id = ? OR parent_id = ?", teh.ID, teh.ID).Scan(&remaining)
+	if remaining != 0 {
+		t.Errorf("expected parent and variants to be deleted, %d rows left", remaining)
+	}
+	if _, err := repo.GetByCode("96385074"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected variant barcode to be deleted, got %v", err)
+	}
+	var layers int
+	db.QueryRow("SELECT COUNT(*) FROM cost_layers WHERE product_id IN (?, ?)", small.ID, large.ID).Scan(&layers)
+	if layers != 0 {
+		t.Errorf("expected variant cost layers to be deleted, %d rows left", layers)
+	}
+}
+
+func TestTransactionRepository_CheckoutVariant(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	repo := NewTransactionRepository(db, models.TaxConfig{}, models.CostingMethodAverage, models.BusinessDay{})
+	products := NewProductRepository(db)
+
+	keripik := &models.Product{Name: "Keripik", Price: 10000, Variants: []models.ProductVariant{
+		{Name: "Original", Price: 10000, Stock: 5},
+		{Name: "Pedas", Price: 11000, Stock: 2},
+	}}
+	if err := products.Create(keripik); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	pedas := keripik.Variants[1]
+	otherID := seedProduct(t, db, "Kopi", 5000, 10)
+
+	trx, err := repo.CreateTransaction(models.CheckoutRequest{
+		Items: []models.CheckoutItem{
+			{ProductID: keripik.ID, VariantID: pedas.ID, Quantity: 1},
+			{VariantID: pedas.ID, Quantity: 1},
+		},
+		PaidAmount: 22000, PaymentMethod: "CASH",
+	}, CheckoutOptions{})
+	if err != nil {
+		t.Fatalf("CreateTransaction failed: %v", err)
+	}
+	if trx.TotalAmount != 22000 {
+		t.Errorf("expected variant price to be used, total %d", trx.TotalAmount)
+	}
+	for _, d := range trx.Details {
+		if d.ProductID != pedas.ID || d.ProductName != "Keripik - Pedas" {
+			t.Errorf("expected detail for variant Pedas, got %+v", d)
+		}
+	}
+	if stock := productStock(t, db, pedas.ID); stock != 0 {
+		t.Errorf("expected variant stock 0, got %d", stock)
+	}
+
+	invalid := []models.CheckoutItem{
+		{ProductID: keripik.ID, Quantity: 1},                      // induk tanpa varian
+		{ProductID: otherID, VariantID: pedas.ID, Quantity: 1},    // varian milik produk lain
+		{VariantID: otherID, Quantity: 1},                         // bukan varian
+		{VariantID: keripik.Variants[0].ID + 100, Quantity: 1},    // tidak ada
+		{ProductID: keripik.ID, VariantID: pedas.ID, Quantity: 1}, // stok habis
+	}
+	for _, item := range invalid {
+		_, err := repo.CreateTransaction(models.CheckoutRequest{Items: []models.CheckoutItem{item}, PaidAmount: 20000, PaymentMethod: "CASH"}, CheckoutOptions{})
+		if err == nil {
+			t.Errorf("expected error for %+v", item)
+		}
+	}
+	if stock := productStock(t, db, keripik.Variants[0].ID); stock != 5 {
+		t.Errorf("expected untouched variant stock 5, got %d", stock)
+	}
+}
+
+func TestTransactionRepository_CheckoutVariantWithParentPromotion(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	repo := NewTransactionRepository(db, models.TaxConfig{}, models.CostingMethodAverage, models.BusinessDay{})
+	products := NewProductRepository(db)
+
+	keripik := &models.Product{Name: "Keripik", Price: 10000, Variants: []models.ProductVariant{
+		{Name: "Original", Price: 10000, Stock: 5},
+		{Name: "Pedas", Price: 11000, Stock: 5},
+	}}
+	if err := products.Create(keripik); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	promo := &models.Promotion{Name: "Keripik 10%", Type: models.PromotionTypeItemPercentage, Value: 10, ProductID: keripik.ID, Active: true}
+	if err := NewPromotionRepository(db).Create(promo); err != nil {
+		t.Fatalf("failed to seed promotion: %v", err)
+	}
+
+	// Promosi dibuat untuk produk induk, yang dijual variannya
+	trx, err := repo.CreateTransaction(models.CheckoutRequest{
+		Items:      []models.CheckoutItem{{ProductID: keripik.ID, VariantID: keripik.Variants[1].ID, Quantity: 2}},
+		PaidAmount: 19800, PaymentMethod: "CASH",
+	}, CheckoutOptions{})
+	if err != nil {
+		t.Fatalf("CreateTransaction failed: %v", err)
+	}
+	if trx.DiscountAmount != 2200 || trx.TotalAmount != 19800 {
+		t.Errorf("expected discount 2200 / total 19800, got %d / %d", trx.DiscountAmount, trx.TotalAmount)
+	}
+	if len(trx.Promotions) != 1 || trx.Promotions[0].PromotionName != "Keripik 10%" {
+		t.Errorf("expected parent promotion to be applied, got %+v", trx.Promotions)
+	}
+}
@@ -0,0 +1,136 @@
+package repositories
+
+import (
+	"codeWithUmam/models"
+	"time"
+)
+
+// appliedDiscount adalah hasil perhitungan satu promosi.
+// lineIndex menunjuk index baris di slice details, atau -1 untuk promosi keranjang.
+type appliedDiscount struct {
+	lineIndex int
+	promotion models.Promotion
+	amount    int
+}
+
+// applyPromotions menghitung diskon untuk setiap baris details berdasarkan daftar promosi.
+// Sebelum dipanggil, setiap baris harus sudah berisi UnitPrice, Quantity dan Subtotal (= UnitPrice * Quantity).
+// Setelah dipanggil, DiscountAmount terisi dan Subtotal menjadi harga setelah diskon.
+//
+// Aturan:
+//  1. Promosi item (persen, nominal, beli X gratis Y) tidak ditumpuk: setiap baris hanya mendapat SATU promosi
+//     item dengan potongan terbesar.
+//  2. Setelah itu, SATU promosi keranjang dengan potongan terbesar dipilih dari yang syarat minimal belanjanya
+//     terpenuhi (dihitung dari total setelah diskon item).
+//  3. Potongan keranjang dibagi ke setiap baris secara proporsional, supaya refund per baris tetap akurat.
+//
+// Fungsi ini murni (tidak menyentuh database) agar mudah di-test.
+func applyPromotions(details []models.TransactionDetail, promotions []models.Promotion, now time.Time) []appliedDiscount {
+	var applied []appliedDiscount
+
+	// 1. Promosi item
+	for i := range details {
+		best := appliedDiscount{lineIndex: i}
+		for _, promo := range promotions {
+			if promo.IsCartLevel() || !promo.IsValidAt(now) || !promotionTargets(promo, details[i]) {
+				continue
+			}
+			if amount := itemDiscount(promo, details[i]); amount > best.amount {
+				best.promotion, best.amount = promo, amount
+			}
+		}
+		if best.amount > 0 {
+			details[i].DiscountAmount += best.amount
+			details[i].Subtotal -= best.amount
+			applied = append(applied, best)
+		}
+	}
+
+	// 2. Promosi keranjang
+	cartSubtotal := 0
+	for _, d := range details {
+		cartSubtotal += d.Subtotal
+	}
+	bestCart := appliedDiscount{lineIndex: -1}
+	for _, promo := range promotions {
+		if !promo.IsCartLevel() || !promo.IsValidAt(now) || cartSubtotal < promo.MinSpend {
+			continue
+		}
+		if amount := cartDiscount(promo, cartSubtotal); amount > bestCart.amount {
+			bestCart.promotion, bestCart.amount = promo, amount
+		}
+	}
+
+	// 3. Bagi potongan keranjang ke setiap baris (proporsional terhadap subtotal baris)
+	if bestCart.amount > 0 {
+		remaining := bestCart.amount
+		for i := range details {
+			share := bestCart.amount * details[i].Subtotal / cartSubtotal
+			if i == len(details)-1 {
+				share = remaining // Sisa pembulatan masuk ke baris terakhir
+			}
+			details[i].DiscountAmount += share
+			details[i].Subtotal -= share
+			remaining -= share
+		}
+		applied = append(applied, bestCart)
+	}
+
+	return applied
+}
+
+// promotionTargets mengecek apakah promosi item berlaku untuk baris detail ini.
+// Promosi untuk produk induk juga berlaku untuk semua variannya.
+func promotionTargets(promo models.Promotion, d models.TransactionDetail) bool {
+	if promo.ProductID != 0 && promo.ProductID != d.ProductID && (d.ParentID == 0 || promo.ProductID != d.ParentID) {
+		return false
+	}
+	if promo.CategoryID != 0 && promo.CategoryID != d.CategoryID {
+		return false
+	}
+	return true
+}
+
+// itemDiscount menghitung potongan promosi item untuk satu baris (tidak pernah melebihi subtotal baris).
+// Promosi per unit selalu dihitung dalam satuan dasar, jadi 1 karton isi 40 dihitung 40 pcs.
+func itemDiscount(promo models.Promotion, d models.TransactionDetail) int {
+	factor := d.UnitFactor
+	if factor < 1 {
+		factor = 1
+	}
+	baseQuantity := d.Quantity * factor
+
+	amount := 0
+	switch promo.Type {
+	case models.PromotionTypeItemPercentage:
+		amount = d.Subtotal * promo.Value / 100
+	case models.PromotionTypeItemNominal:
+		amount = promo.Value * baseQuantity
+	case models.PromotionTypeBuyXGetY:
+		if promo.BuyQty > 0 && promo.GetQty > 0 {
+			// Contoh beli 2 gratis 1, qty 7: 2 paket (2+1) -> gratis 2 pcs.
+			// Harga per pcs diambil dari harga satuan jual baris ini (harga karton / isi karton).
+			freeQty := baseQuantity / (promo.BuyQty + promo.GetQty) * promo.GetQty
+			amount = freeQty * d.UnitPrice / factor
+		}
+	}
+	if amount > d.Subtotal {
+		amount = d.Subtotal
+	}
+	return amount
+}
+
+// cartDiscount menghitung potongan promosi keranjang (tidak pernah melebihi total keranjang).
+func cartDiscount(promo models.Promotion, cartSubtotal int) int {
+	amount := 0
+	switch promo.Type {
+	case models.PromotionTypeCartPercentage:
+		amount = cartSubtotal * promo.Value / 100
+	case models.PromotionTypeCartNominal:
+		amount = promo.Value
+	}
+	if amount > cartSubtotal {
+		amount = cartSubtotal
+	}
+	return amount
+}
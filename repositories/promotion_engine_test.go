@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"codeWithUmam/models"
+	"testing"
+	"time"
+)
+
+func line(productID, categoryID, price, qty int) models.TransactionDetail {
+	return models.TransactionDetail{ProductID: productID, CategoryID: categoryID, UnitPrice: price, Quantity: qty, Subtotal: price * qty}
+}
+
+func TestApplyPromotions_ItemPromotions(t *testing.T) {
+	now := time.Now()
+	promotions := []models.Promotion{
+		{ID: 1, Name: "Kopi 10%", Type: models.PromotionTypeItemPercentage, Value: 10, ProductID: 1, Active: true},
+		{ID: 2, Name: "Beli 2 Gratis 1", Type: models.PromotionTypeBuyXGetY, BuyQty: 2, GetQty: 1, ProductID: 1, Active: true},
+		{ID: 3, Name: "Snack -500", Type: models.PromotionTypeItemNominal, Value: 500, CategoryID: 2, Active: true},
+	}
+	details := []models.TransactionDetail{
+		line(1, 1, 10000, 3), // 30.000: 10% = 3.000, beli 2 gratis 1 = 10.000 -> pilih 10.000
+		line(2, 2, 5000, 2),  // 10.000: -500 x 2 = 1.000
+		line(3, 3, 8000, 1),  // tidak ada promosi
+	}
+
+	applied := applyPromotions(details, promotions, now)
+
+	if len(applied) != 2 {
+		t.Fatalf("expected 2 applied promotions, got %d", len(applied))
+	}
+	if details[0].DiscountAmount != 10000 || details[0].Subtotal != 20000 {
+		t.Errorf("expected best item promo (buy 2 get 1) on line 0, got discount %d", details[0].DiscountAmount)
+	}
+	if details[1].DiscountAmount != 1000 {
+		t.Errorf("expected category nominal discount 1000, got %d", details[1].DiscountAmount)
+	}
+	if details[2].DiscountAmount != 0 {
+		t.Errorf("expected no discount on line 2, got %d", details[2].DiscountAmount)
+	}
+}
+
+func TestApplyPromotions_ParentPromotionCoversVariants(t *testing.T) {
+	promotions := []models.Promotion{
+		{ID: 1, Name: "Keripik 10%", Type: models.PromotionTypeItemPercentage, Value: 10, ProductID: 1, Active: true},
+	}
+	pedas := line(11, 1, 11000, 2) // varian Pedas milik produk 1
+	pedas.ParentID = 1
+	details := []models.TransactionDetail{
+		pedas,
+		line(12, 1, 8000, 1), // produk lain, bukan varian produk 1
+	}
+
+	applied := applyPromotions(details, promotions, time.Now())
+
+	if len(applied) != 1 || applied[0].lineIndex != 0 {
+		t.Fatalf("expected the parent promotion on the variant line only, got %+v", applied)
+	}
+	if details[0].DiscountAmount != 2200 || details[1].DiscountAmount != 0 {
+		t.Errorf("expected discount 2200 / 0, got %d / %d", details[0].DiscountAmount, details[1].DiscountAmount)
+	}
+}
+
+func TestApplyPromotions_PackedUnitCountsBaseUnits(t *testing.T) {
+	promotions := []models.Promotion{
+		{ID: 1, Name: "Mi -1000/pcs", Type: models.PromotionTypeItemNominal, Value: 1000, ProductID: 1, Active: true},
+		{ID: 2, Name: "Teh beli 2 gratis 1", Type: models.PromotionTypeBuyXGetY, BuyQty: 2, GetQty: 1, ProductID: 2, Active: true},
+	}
+	karton := line(1, 1, 120000, 1) // 1 karton isi 40 pcs
+	karton.Unit, karton.UnitFactor = "karton", 40
+	pak := line(2, 1, 24000, 1) // 1 pak isi 6 botol @4.000 -> gratis 2 botol
+	pak.Unit, pak.UnitFactor = "pak", 6
+	details := []models.TransactionDetail{karton, pak}
+
+	applyPromotions(details, promotions, time.Now())
+
+	if details[0].DiscountAmount != 40000 {
+		t.Errorf("expected Rp1000 off each of 40 pcs, got %d", details[0].DiscountAmount)
+	}
+	if details[1].DiscountAmount != 8000 {
+		t.Errorf("expected 2 free bottles worth 8000, got %d", details[1].DiscountAmount)
+	}
+}
+
+func TestApplyPromotions_CartPromotionAndValidity(t *testing.T) {
+	now := time.Now()
+	yesterday := now.Add(-24 * time.Hour)
+	promotions := []models.Promotion{
+		{ID: 1, Name: "Min 50rb diskon 10%", Type: models.PromotionTypeCartPercentage, Value: 10, MinSpend: 50000, Active: true},
+		{ID: 2, Name: "Promo kemarin", Type: models.PromotionTypeCartNominal, Value: 20000, EndAt: &yesterday, Active: true},
+		{ID: 3, Name: "Nonaktif", Type: models.PromotionTypeCartNominal, Value: 30000, Active: false},
+	}
+	details := []models.TransactionDetail{
+		line(1, 1, 10000, 3), // 30.000
+		line(2, 1, 7000, 4),  // 28.000
+	}
+
+	applied := applyPromotions(details, promotions, now)
+
+	if len(applied) != 1 || applied[0].promotion.ID != 1 || applied[0].lineIndex != -1 {
+		t.Fatalf("expected only cart promo 1 applied, got %+v", applied)
+	}
+
+	// Diskon keranjang 5.800 dibagi proporsional ke setiap baris
+	total, discount := 0, 0
+	for _, d := range details {
+		total += d.Subtotal
+		discount += d.DiscountAmount
+	}
+	if discount != 5800 || total != 52200 {
+		t.Errorf("expected discount 5800 / total 52200, got %d / %d", discount, total)
+	}
+
+	// Belanja di bawah minimal -> tidak dapat diskon keranjang
+	small := []models.TransactionDetail{line(1, 1, 10000, 1)}
+	if applied := applyPromotions(small, promotions, now); len(applied) != 0 {
+		t.Errorf("expected no promo below min spend, got %+v", applied)
+	}
+}
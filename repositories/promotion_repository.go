@@ -0,0 +1,114 @@
+package repositories
+
+import (
+	"codeWithUmam/models"
+	"database/sql"
+)
+
+// PromotionRepositoryImpl bertugas melakukan komunikasi langsung ke Database untuk tabel promotions.
+// Struct ini mengimplementasikan interface PromotionRepository dari package repositories.
+type PromotionRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewPromotionRepository(db *sql.DB) *PromotionRepositoryImpl {
+	return &PromotionRepositoryImpl{db: db}
+}
+
+// promotionColumns adalah daftar kolom yang dibaca oleh scanPromotion.
+const promotionColumns = "id, name, type, value, product_id, category_id, buy_qty, get_qty, min_spend, start_at, end_at, active"
+
+// rowScanner adalah interface kecil yang dipenuhi oleh *sql.Row dan *sql.Rows,
+// supaya satu fungsi scan bisa dipakai untuk QueryRow maupun Query.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanPromotion(row rowScanner) (*models.Promotion, error) {
+	var p models.Promotion
+	err := row.Scan(&p.ID, &p.Name, &p.Type, &p.Value, &p.ProductID, &p.CategoryID,
+		&p.BuyQty, &p.GetQty, &p.MinSpend, &p.StartAt, &p.EndAt, &p.Active)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// GetAll mengambil semua promosi (aktif maupun tidak).
+func (r *PromotionRepositoryImpl) GetAll() ([]models.Promotion, error) {
+	rows, err := r.db.Query("SELECT " + promotionColumns + " FROM promotions ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var promotions []models.Promotion
+	for rows.Next() {
+		p, err := scanPromotion(rows)
+		if err != nil {
+			return nil, err
+		}
+		promotions = append(promotions, *p)
+	}
+	return promotions, nil
+}
+
+// Create menyimpan promosi baru ke database.
+func (r *PromotionRepositoryImpl) Create(p *models.Promotion) error {
+	query := `INSERT INTO promotions (name, type, value, product_id, category_id, buy_qty, get_qty, min_spend, start_at, end_at, active)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	result, err := r.db.Exec(query, p.Name, p.Type, p.Value, p.ProductID, p.CategoryID,
+		p.BuyQty, p.GetQty, p.MinSpend, p.StartAt, p.EndAt, p.Active)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	p.ID = int(id)
+	return nil
+}
+
+// GetByID mengambil satu promosi berdasarkan ID.
+func (r *PromotionRepositoryImpl) GetByID(id int) (*models.Promotion, error) {
+	return scanPromotion(r.db.QueryRow("SELECT "+promotionColumns+" FROM promotions WHERE id = ?", id))
+}
+
+// Update mengubah data promosi yang sudah ada.
+func (r *PromotionRepositoryImpl) Update(p *models.Promotion) error {
+	query := `UPDATE promotions
+		SET name = ?, type = ?, value = ?, product_id = ?, category_id = ?, buy_qty = ?, get_qty = ?, min_spend = ?, start_at = ?, end_at = ?, active = ?
+		WHERE id = ?`
+	_, err := r.db.Exec(query, p.Name, p.Type, p.Value, p.ProductID, p.CategoryID,
+		p.BuyQty, p.GetQty, p.MinSpend, p.StartAt, p.EndAt, p.Active, p.ID)
+	return err
+}
+
+// Delete menghapus promosi dari database.
+// Riwayat promosi di transaksi lama tetap aman karena nama & nilainya sudah disalin ke transaction_promotions.
+func (r *PromotionRepositoryImpl) Delete(id int) error {
+	_, err := r.db.Exec("DELETE FROM promotions WHERE id = ?", id)
+	return err
+}
+
+// findActivePromotions mengambil promosi aktif di dalam Database Transaction checkout.
+// Pengecekan masa berlaku (start_at/end_at) dilakukan di Go oleh applyPromotions.
+func findActivePromotions(tx *sql.Tx) ([]models.Promotion, error) {
+	rows, err := tx.Query("SELECT " + promotionColumns + " FROM promotions WHERE active = 1")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var promotions []models.Promotion
+	for rows.Next() {
+		p, err := scanPromotion(rows)
+		if err != nil {
+			return nil, err
+		}
+		promotions = append(promotions, *p)
+	}
+	return promotions, rows.Err()
+}
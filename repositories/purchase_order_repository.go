@@ -0,0 +1,378 @@
+package repositories
+
+import (
+	"codeWithUmam/models"
+	"database/sql"
+	"math"
+)
+
+// PurchaseOrderRepositoryImpl mengelola purchase order (PO) dan penerimaan barang (goods receipt).
+// Struct ini mengimplementasikan interface PurchaseOrderRepository dari package repositories.
+type PurchaseOrderRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewPurchaseOrderRepository(db *sql.DB) *PurchaseOrderRepositoryImpl {
+	return &PurchaseOrderRepositoryImpl{db: db}
+}
+
+// GetAll mengambil daftar PO (tanpa item), opsional difilter supplier dan status.
+func (r *PurchaseOrderRepositoryImpl) GetAll(supplierID int, status string) ([]models.PurchaseOrder, error) {
+	query := `
+		SELECT po.id, po.supplier_id, s.name, po.outlet_id, po.status, COALESCE(po.note, ''), po.total_cost, po.received_cost, po.created_at
+		FROM purchase_orders po
+		JOIN suppliers s ON po.supplier_id = s.id
+		WHERE 1 = 1`
+	args := []interface{}{}
+	if supplierID > 0 {
+		query += " AND po.supplier_id = ?"
+		args = append(args, supplierID)
+	}
+	if status != "" {
+		query += " AND po.status = ?"
+		args = append(args, status)
+	}
+	query += " ORDER BY po.id DESC"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := []models.PurchaseOrder{}
+	for rows.Next() {
+		var po models.PurchaseOrder
+		if err := rows.Scan(&po.ID, &po.SupplierID, &po.SupplierName, &po.OutletID, &po.Status, &po.Note, &po.TotalCost, &po.ReceivedCost, &po.CreatedAt); err != nil {
+			return nil, err
+		}
+		orders = append(orders, po)
+	}
+	return orders, rows.Err()
+}
+
+// Create menyimpan PO beserta item-itemnya dalam satu Database Transaction.
+// Nama produk disalin ke item PO (snapshot), dan total nilai PO dihitung di sini.
+func (r *PurchaseOrderRepositoryImpl) Create(req models.CreatePurchaseOrderRequest) (*models.PurchaseOrder, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var supplierExists int
+	if err := tx.QueryRow("SELECT COUNT(id) FROM suppliers WHERE id = ?", req.SupplierID).Scan(&supplierExists); err != nil {
+		return nil, err
+	}
+	if supplierExists == 0 {
+		return nil, NewValidationError("supplier id %d tidak ditemukan", req.SupplierID)
+	}
+
+	outletID := req.OutletID
+	if outletID == 0 {
+		outletID = models.DefaultOutletID
+	}
+	if err := requireActiveOutlet(tx, outletID); err != nil {
+		return nil, err
+	}
+
+	res, err := tx.Exec("INSERT INTO purchase_orders (supplier_id, outlet_id, status, note) VALUES (?, ?, ?, ?)",
+		req.SupplierID, outletID, models.PurchaseOrderStatusOpen, req.Note)
+	if err != nil {
+		return nil, err
+	}
+	orderID, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	totalCost := 0
+	for _, item := range req.Items {
+		var productName string
+		var variantCount, componentCount int
+		err := tx.QueryRow(`
+			SELECT name, (SELECT COUNT(*) FROM products v WHERE v.parent_id = p.id), (SELECT COUNT(*) FROM product_components pc WHERE pc.product_id = p.id)
+			FROM products p WHERE p.id = ?`, item.ProductID).Scan(&productName, &variantCount, &componentCount)
+		if err == sql.ErrNoRows {
+			return nil, NewValidationError("product id %d tidak ditemukan", item.ProductID)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if variantCount > 0 {
+			return nil, NewValidationError("produk %s punya varian, pesan per varian", productName)
+		}
+		if componentCount > 0 {
+			return nil, NewValidationError("produk %s adalah paket, pesan komponennya", productName)
+		}
+
+		unit, err := resolveProductUnit(tx, item.ProductID, item.Unit)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = tx.Exec("INSERT INTO purchase_order_items (purchase_order_id, product_id, product_name, quantity, unit_cost, unit, unit_factor) VALUES (?, ?, ?, ?, ?, ?, ?)",
+			orderID, item.ProductID, productName, item.Quantity, item.UnitCost, unit.Name, unit.Factor)
+		if err != nil {
+			return nil, err
+		}
+		totalCost += item.Quantity * item.UnitCost
+	}
+
+	if _, err := tx.Exec("UPDATE purchase_orders SET total_cost = ? WHERE id = ?", totalCost, orderID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.GetByID(int(orderID))
+}
+
+// GetByID mengambil satu PO lengkap dengan item dan riwayat penerimaan barangnya.
+func (r *PurchaseOrderRepositoryImpl) GetByID(id int) (*models.PurchaseOrder, error) {
+	var po models.PurchaseOrder
+	err := r.db.QueryRow(`
+		SELECT po.id, po.supplier_id, s.name, po.outlet_id, po.status, COALESCE(po.note, ''), po.total_cost, po.received_cost, po.created_at
+		FROM purchase_orders po
+		JOIN suppliers s ON po.supplier_id = s.id
+		WHERE po.id = ?`, id).
+		Scan(&po.ID, &po.SupplierID, &po.SupplierName, &po.OutletID, &po.Status, &po.Note, &po.TotalCost, &po.ReceivedCost, &po.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := r.findItems(id)
+	if err != nil {
+		return nil, err
+	}
+	po.Items = items
+
+	receipts, err := r.findReceipts(id)
+	if err != nil {
+		return nil, err
+	}
+	po.Receipts = receipts
+
+	return &po, nil
+}
+
+func (r *PurchaseOrderRepositoryImpl) findItems(orderID int) ([]models.PurchaseOrderItem, error) {
+	rows, err := r.db.Query(`
+		SELECT id, purchase_order_id, product_id, product_name, quantity, received_quantity, unit_cost, COALESCE(unit, ''), unit_factor
+		FROM purchase_order_items WHERE purchase_order_id = ? ORDER BY id`, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []models.PurchaseOrderItem{}
+	for rows.Next() {
+		var item models.PurchaseOrderItem
+		if err := rows.Scan(&item.ID, &item.PurchaseOrderID, &item.ProductID, &item.ProductName,
+			&item.Quantity, &item.ReceivedQuantity, &item.UnitCost, &item.Unit, &item.UnitFactor); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// findReceipts mengambil riwayat penerimaan barang beserta itemnya.
+// Item semua penerimaan diambil dengan SATU query lalu dikelompokkan per receipt di Go.
+func (r *PurchaseOrderRepositoryImpl) findReceipts(orderID int) ([]models.GoodsReceipt, error) {
+	rows, err := r.db.Query(`
+		SELECT id, purchase_order_id, COALESCE(received_by, ''), COALESCE(note, ''), total_cost, created_at
+		FROM goods_receipts WHERE purchase_order_id = ? ORDER BY id`, orderID)
+	if err != nil {
+		return nil, err
+	}
+	var receipts []models.GoodsReceipt
+	index := make(map[int]int) // receipt ID -> posisi di slice
+	for rows.Next() {
+		var gr models.GoodsReceipt
+		if err := rows.Scan(&gr.ID, &gr.PurchaseOrderID, &gr.ReceivedBy, &gr.Note, &gr.TotalCost, &gr.CreatedAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		gr.Items = []models.GoodsReceiptItem{}
+		index[gr.ID] = len(receipts)
+		receipts = append(receipts, gr)
+	}
+	rows.Close()
+	if len(receipts) == 0 {
+		return nil, nil
+	}
+
+	itemRows, err := r.db.Query(`
+		SELECT gri.id, gri.goods_receipt_id, gri.purchase_order_item_id, gri.product_id, gri.quantity, gri.unit_cost,
+			COALESCE(gri.batch_number, ''), COALESCE(gri.expiry_date, '')
+		FROM goods_receipt_items gri
+		JOIN goods_receipts gr ON gri.goods_receipt_id = gr.id
+		WHERE gr.purchase_order_id = ?
+		ORDER BY gri.id`, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer itemRows.Close()
+
+	for itemRows.Next() {
+		var item models.GoodsReceiptItem
+		if err := itemRows.Scan(&item.ID, &item.GoodsReceiptID, &item.PurchaseOrderItemID, &item.ProductID, &item.Quantity, &item.UnitCost,
+			&item.BatchNumber, &item.ExpiryDate); err != nil {
+			return nil, err
+		}
+		i := index[item.GoodsReceiptID]
+		receipts[i].Items = append(receipts[i].Items, item)
+	}
+	return receipts, itemRows.Err()
+}
+
+// Receive mencatat kedatangan barang untuk sebuah PO.
+// Semua langkah dijalankan dalam satu Database Transaction:
+// jumlah diterima di item PO, stok produk (lewat ledger sebagai RECEIVING), harga beli, dan status PO
+// berubah bersama-sama atau tidak sama sekali.
+func (r *PurchaseOrderRepositoryImpl) Receive(orderID int, req models.ReceiveGoodsRequest) (*models.PurchaseOrder, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var status string
+	var outletID int
+	err = tx.QueryRow("SELECT status, outlet_id FROM purchase_orders WHERE id = ?", orderID).Scan(&status, &outletID)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if status != models.PurchaseOrderStatusOpen && status != models.PurchaseOrderStatusPartiallyReceived {
+		return nil, NewValidationError("purchase order %d sudah %s, tidak bisa menerima barang lagi", orderID, status)
+	}
+
+	// 1. Header penerimaan barang
+	res, err := tx.Exec("INSERT INTO goods_receipts (purchase_order_id, received_by, note) VALUES (?, ?, ?)",
+		orderID, req.ReceivedBy, req.Note)
+	if err != nil {
+		return nil, err
+	}
+	receiptID, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Setiap item: validasi sisa, catat harga beli, tambah stok
+	totalCost := 0
+	for _, item := range req.Items {
+		// Jumlah & harga diterima dalam satuan beli item PO; ledger & HPP memakai satuan dasar
+		// Produk yang sudah dihapus tidak ber-batch; recordStockMovement di bawah yang menolaknya
+		var productID, ordered, received, orderCost, unitFactor int
+		var productName string
+		var trackBatches bool
+		err := tx.QueryRow(`
+			SELECT poi.product_id, poi.product_name, poi.quantity, poi.received_quantity, poi.unit_cost, poi.unit_factor, COALESCE(p.track_batches, 0)
+			FROM purchase_order_items poi
+			LEFT JOIN products p ON p.id = poi.product_id
+			WHERE poi.id = ? AND poi.purchase_order_id = ?`, item.PurchaseOrderItemID, orderID).
+			Scan(&productID, &productName, &ordered, &received, &orderCost, &unitFactor, &trackBatches)
+		if err == sql.ErrNoRows {
+			return nil, NewValidationError("item id %d bukan bagian dari purchase order %d", item.PurchaseOrderItemID, orderID)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if item.Quantity > ordered-received {
+			return nil, NewValidationError("jumlah diterima untuk item id %d melebihi sisa pesanan (sisa: %d)", item.PurchaseOrderItemID, ordered-received)
+		}
+		if trackBatches && item.ExpiryDate == "" {
+			return nil, NewValidationError("%s dicatat per batch, expiry_date wajib diisi", productName)
+		}
+
+		unitCost := item.UnitCost
+		if unitCost == 0 {
+			unitCost = orderCost
+		}
+
+		if _, err := tx.Exec("UPDATE purchase_order_items SET received_quantity = received_quantity + ? WHERE id = ?", item.Quantity, item.PurchaseOrderItemID); err != nil {
+			return nil, err
+		}
+		_, err = tx.Exec(`
+			INSERT INTO goods_receipt_items (goods_receipt_id, purchase_order_item_id, product_id, quantity, unit_cost, batch_number, expiry_date)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			receiptID, item.PurchaseOrderItemID, productID, item.Quantity, unitCost, nullIfEmpty(item.BatchNumber), nullIfEmpty(item.ExpiryDate))
+		if err != nil {
+			return nil, err
+		}
+
+		// Harga beli penerimaan menjadi dasar harga pokok (HPP) produk; produk ber-batch mendapat batch baru.
+		// Barang masuk ke stok outlet tujuan PO.
+		_, err = recordStockMovement(tx, models.StockMovement{
+			ProductID:     productID,
+			OutletID:      outletID,
+			Type:          models.StockMovementReceiving,
+			Quantity:      item.Quantity * unitFactor,
+			UnitCost:      int(math.Round(float64(unitCost) / float64(unitFactor))),
+			ReferenceType: models.StockReferenceGoodsReceipt,
+			ReferenceID:   int(receiptID),
+			BatchNumber:   item.BatchNumber,
+			ExpiryDate:    item.ExpiryDate,
+		})
+		if err == ErrNotFound {
+			return nil, NewValidationError("product id %d sudah dihapus, barang tidak bisa diterima", productID)
+		}
+		if err != nil {
+			return nil, err
+		}
+		totalCost += item.Quantity * unitCost
+	}
+
+	if _, err := tx.Exec("UPDATE goods_receipts SET total_cost = ? WHERE id = ?", totalCost, receiptID); err != nil {
+		return nil, err
+	}
+
+	// 3. Status PO: RECEIVED jika semua item sudah lengkap
+	var remaining int
+	err = tx.QueryRow("SELECT COALESCE(SUM(quantity - received_quantity), 0) FROM purchase_order_items WHERE purchase_order_id = ?", orderID).Scan(&remaining)
+	if err != nil {
+		return nil, err
+	}
+	newStatus := models.PurchaseOrderStatusPartiallyReceived
+	if remaining == 0 {
+		newStatus = models.PurchaseOrderStatusReceived
+	}
+	_, err = tx.Exec("UPDATE purchase_orders SET status = ?, received_cost = received_cost + ? WHERE id = ?", newStatus, totalCost, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.GetByID(orderID)
+}
+
+// Cancel membatalkan PO. Barang yang sudah diterima tetap tercatat di stok;
+// hanya sisa pesanan yang tidak ditunggu lagi.
+func (r *PurchaseOrderRepositoryImpl) Cancel(orderID int) (*models.PurchaseOrder, error) {
+	var status string
+	err := r.db.QueryRow("SELECT status FROM purchase_orders WHERE id = ?", orderID).Scan(&status)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if status == models.PurchaseOrderStatusReceived || status == models.PurchaseOrderStatusCancelled {
+		return nil, NewValidationError("purchase order %d sudah %s, tidak bisa dibatalkan", orderID, status)
+	}
+
+	if _, err := r.db.Exec("UPDATE purchase_orders SET status = ? WHERE id = ?", models.PurchaseOrderStatusCancelled, orderID); err != nil {
+		return nil, err
+	}
+	return r.GetByID(orderID)
+}
@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"codeWithUmam/models"
+	"errors"
+	"testing"
+)
+
+func TestPurchaseOrderRepository_PartialReceiving(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	supplierRepo := NewSupplierRepository(db)
+	repo := NewPurchaseOrderRepository(db)
+	productID := seedProduct(t, db, "Minyak Goreng 2L", 38000, 5)
+
+	supplier := &models.Supplier{Name: "CV Sumber Rejeki"}
+	if err := supplierRepo.Create(supplier); err != nil {
+		t.Fatalf("failed to create supplier: %v", err)
+	}
+
+	order, err := repo.Create(models.CreatePurchaseOrderRequest{
+		SupplierID: supplier.ID,
+		Items:      []models.PurchaseOrderItemRequest{{ProductID: productID, Quantity: 10, UnitCost: 30000}},
+	})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if order.Status != models.PurchaseOrderStatusOpen || order.TotalCost != 300000 || order.Items[0].ProductName != "Minyak Goreng 2L" {
+		t.Fatalf("unexpected purchase order: %+v", order)
+	}
+	itemID := order.Items[0].ID
+
+	// Kedatangan pertama: 4 unit dengan harga beli aktual lebih mahal
+	order, err = repo.Receive(order.ID, models.ReceiveGoodsRequest{
+		ReceivedBy: "gudang",
+		Items:      []models.ReceiveGoodsItemRequest{{PurchaseOrderItemID: itemID, Quantity: 4, UnitCost: 31000}},
+	})
+	if err != nil {
+		t.Fatalf("Receive failed: %v", err)
+	}
+	if order.Status != models.PurchaseOrderStatusPartiallyReceived || order.Items[0].ReceivedQuantity != 4 || order.ReceivedCost != 124000 {
+		t.Errorf("unexpected partial receipt result: %+v", order)
+	}
+	if got := productStock(t, db, productID); got != 9 {
+		t.Errorf("expected stock 9, got %d", got)
+	}
+
+	// Menerima melebihi sisa pesanan ditolak dan tidak mengubah stok
+	_, err = repo.Receive(order.ID, models.ReceiveGoodsRequest{
+		Items: []models.ReceiveGoodsItemRequest{{PurchaseOrderItemID: itemID, Quantity: 7}},
+	})
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Errorf("expected ValidationError, got %v", err)
+	}
+	if got := productStock(t, db, productID); got != 9 {
+		t.Errorf("expected stock unchanged at 9, got %d", got)
+	}
+
+	// Sisa 6 unit datang dengan harga PO
+	order, err = repo.Receive(order.ID, models.ReceiveGoodsRequest{
+		Items: []models.ReceiveGoodsItemRequest{{PurchaseOrderItemID: itemID, Quantity: 6}},
+	})
+	if err != nil {
+		t.Fatalf("Receive failed: %v", err)
+	}
+	if order.Status != models.PurchaseOrderStatusReceived || order.ReceivedCost != 304000 || len(order.Receipts) != 2 {
+		t.Errorf("unexpected final receipt result: %+v", order)
+	}
+	if order.Receipts[1].Items[0].UnitCost != 30000 {
+		t.Errorf("expected PO unit cost as default, got %d", order.Receipts[1].Items[0].UnitCost)
+	}
+	if got := productStock(t, db, productID); got != 15 {
+		t.Errorf("expected stock 15, got %d", got)
+	}
+
+	// Penerimaan tercatat di ledger stok
+	report, err := NewStockMovementRepository(db).CheckConsistency()
+	if err != nil {
+		t.Fatalf("CheckConsistency failed: %v", err)
+	}
+	if !report.Consistent {
+		t.Errorf("expected stock to match ledger, got %+v", report)
+	}
+
+	// Supplier yang sudah punya PO tidak bisa dihapus
+	if err := supplierRepo.Delete(supplier.ID); !errors.As(err, &validationErr) {
+		t.Errorf("expected ValidationError deleting supplier with orders, got %v", err)
+	}
+}
@@ -0,0 +1,417 @@
+package repositories
+
+import (
+	"codeWithUmam/models"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Laporan-laporan penjualan. Method-method ini ada di TransactionRepository karena
+// sumber datanya adalah tabel transactions & transaction_details.
+
+// profitLineColumns menghitung qty (dalam satuan dasar), pendapatan (DPP) dan HPP dari barang yang tidak dikembalikan.
+// Nilai per baris dikalikan porsi barang yang tidak di-refund: (quantity - refunded_quantity) / quantity.
+const profitLineColumns = `
+	SUM((td.quantity - td.refunded_quantity) * td.unit_factor),
+	CAST(ROUND(COALESCE(SUM(td.subtotal * 1.0 * (td.quantity - td.refunded_quantity) / td.quantity), 0)) AS INTEGER),
+	CAST(ROUND(COALESCE(SUM(td.cogs * 1.0 * (td.quantity - td.refunded_quantity) / td.quantity), 0)) AS INTEGER)`
+
+// GetProfitReport menghitung laba kotor (pendapatan - HPP) untuk rentang hari bisnis from..to (format YYYY-MM-DD, inklusif),
+// dirinci per produk, per kategori, dan per hari.
+// HPP diambil dari snapshot cogs di transaction_details, jadi perubahan harga pokok setelah transaksi tidak mengubah laporan.
+// outletID 0 = gabungan semua outlet.
+func (repo *TransactionRepository) GetProfitReport(from, to string, outletID int) (*models.ProfitReport, error) {
+	report := &models.ProfitReport{From: from, To: to, OutletID: outletID, CostingMethod: repo.costingMethod}
+	start, end, err := repo.businessDayBounds(from, to)
+	if err != nil {
+		return nil, err
+	}
+	where := "t.created_at >= ? AND t.created_at < ? AND " + salesStatusFilter + outletFilter
+	_, business := repo.businessDay.SQLModifiers()
+
+	// Nama produk/kategori diambil dari snapshot, jadi produk yang sudah dihapus tetap muncul.
+	report.ByProduct, err = repo.queryProfitLines(`
+		SELECT td.product_id, MAX(td.product_name),`+profitLineColumns+`
+		FROM transaction_details td
+		JOIN transactions t ON td.transaction_id = t.id
+		WHERE `+where+`
+		GROUP BY td.product_id
+		HAVING SUM((td.quantity - td.refunded_quantity) * td.unit_factor) > 0
+		ORDER BY 4 DESC`, start, end, outletID, outletID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal hitung laba per produk: %v", err)
+	}
+
+	report.ByCategory, err = repo.queryProfitLines(`
+		SELECT COALESCE(td.category_id, 0), COALESCE(MAX(td.category_name), ''),`+profitLineColumns+`
+		FROM transaction_details td
+		JOIN transactions t ON td.transaction_id = t.id
+		WHERE `+where+`
+		GROUP BY COALESCE(td.category_id, 0)
+		HAVING SUM((td.quantity - td.refunded_quantity) * td.unit_factor) > 0
+		ORDER BY 4 DESC`, start, end, outletID, outletID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal hitung laba per kategori: %v", err)
+	}
+
+	report.ByDay, err = repo.queryProfitLines(`
+		SELECT 0, date(t.created_at, `+business+`),`+profitLineColumns+`
+		FROM transaction_details td
+		JOIN transactions t ON td.transaction_id = t.id
+		WHERE `+where+`
+		GROUP BY 2
+		HAVING SUM((td.quantity - td.refunded_quantity) * td.unit_factor) > 0
+		ORDER BY 2`, start, end, outletID, outletID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal hitung laba per hari: %v", err)
+	}
+
+	// Total dijumlahkan dari rincian per hari (setiap baris transaksi masuk tepat satu hari)
+	for _, line := range report.ByDay {
+		report.Revenue += line.Revenue
+		report.COGS += line.COGS
+	}
+	report.GrossProfit = report.Revenue - report.COGS
+	report.MarginPercent = marginPercent(report.GrossProfit, report.Revenue)
+
+	return report, nil
+}
+
+// GetBundleReport menghitung penjualan paket/resep untuk rentang hari bisnis from..to (format YYYY-MM-DD, inklusif)
+// beserta pemakaian komponennya. Semua nilai bersih setelah refund, dihitung dari snapshot saat transaksi.
+// outletID 0 = gabungan semua outlet.
+func (repo *TransactionRepository) GetBundleReport(from, to string, outletID int) (*models.BundleReport, error) {
+	report := &models.BundleReport{From: from, To: to, OutletID: outletID}
+	start, end, err := repo.businessDayBounds(from, to)
+	if err != nil {
+		return nil, err
+	}
+	where := "t.created_at >= ? AND t.created_at < ? AND " + salesStatusFilter + outletFilter
+
+	report.Bundles, err = repo.queryProfitLines(`
+		SELECT td.product_id, MAX(td.product_name),`+profitLineColumns+`
+		FROM transaction_details td
+		JOIN transactions t ON td.transaction_id = t.id
+		WHERE `+where+` AND td.id IN (SELECT transaction_detail_id FROM transaction_detail_components)
+		GROUP BY td.product_id
+		HAVING SUM((td.quantity - td.refunded_quantity) * td.unit_factor) > 0
+		ORDER BY 4 DESC`, start, end, outletID, outletID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal hitung penjualan paket: %v", err)
+	}
+
+	// Pemakaian komponen dikalikan porsi baris paket yang tidak di-refund (selalu pas karena kelipatan quantity baris)
+	rows, err := repo.db.Query(`
+		SELECT tdc.component_id, MAX(tdc.component_name),
+			SUM(tdc.quantity * (td.quantity - td.refunded_quantity) / td.quantity),
+			CAST(ROUND(COALESCE(SUM(tdc.cogs * 1.0 * (td.quantity - td.refunded_quantity) / td.quantity), 0)) AS INTEGER)
+		FROM transaction_detail_components tdc
+		JOIN transaction_details td ON td.id = tdc.transaction_detail_id
+		JOIN transactions t ON t.id = td.transaction_id
+		WHERE `+where+`
+		GROUP BY tdc.component_id
+		HAVING SUM(tdc.quantity * (td.quantity - td.refunded_quantity) / td.quantity) > 0
+		ORDER BY 3 DESC, 2`, start, end, outletID, outletID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal hitung pemakaian komponen: %v", err)
+	}
+	defer rows.Close()
+
+	report.Components = []models.ComponentUsageLine{}
+	for rows.Next() {
+		var c models.ComponentUsageLine
+		if err := rows.Scan(&c.ComponentID, &c.Name, &c.Quantity, &c.COGS); err != nil {
+			return nil, err
+		}
+		report.Components = append(report.Components, c)
+	}
+	return report, rows.Err()
+}
+
+// GetReorderReport menyusun saran pesan ulang dari rata-rata penjualan harian selama hari bisnis from..to
+// (format YYYY-MM-DD, inklusif), supaya stok cukup untuk coverDays hari ke depan di atas stok minimumnya.
+// Penjualan dihitung bersih setelah refund, dalam satuan dasar, termasuk komponen yang terpakai oleh penjualan paket.
+// Produk yang muncul: stoknya <= stok minimum, atau stoknya tidak cukup untuk coverDays hari + stok minimum.
+// outletID > 0: penjualan & stok hanya dari outlet tersebut; 0 = gabungan semua outlet (stok total).
+func (repo *TransactionRepository) GetReorderReport(from, to string, coverDays, outletID int) (*models.ReorderReport, error) {
+	fromDate, err := time.Parse("2006-01-02", from)
+	if err != nil {
+		return nil, err
+	}
+	toDate, err := time.Parse("2006-01-02", to)
+	if err != nil {
+		return nil, err
+	}
+	report := &models.ReorderReport{From: from, To: to, OutletID: outletID, Days: int(toDate.Sub(fromDate).Hours()/24) + 1, CoverDays: coverDays}
+	start, end, err := repo.businessDayBounds(from, to)
+	if err != nil {
+		return nil, err
+	}
+	where := "t.created_at >= ? AND t.created_at < ? AND " + salesStatusFilter + outletFilter
+
+	args := []interface{}{start, end, outletID, outletID, start, end, outletID, outletID}
+	stockColumn := "p.stock"
+	if outletID > 0 {
+		stockColumn = outletStockColumn
+		args = append(args, outletID)
+	}
+
+	// Baris paket tidak punya stok sendiri (tidak ikut stockKeepingFilter), penjualannya dihitung lewat komponennya
+	rows, err := repo.db.Query(`
+		WITH sold AS (
+			SELECT td.product_id, (td.quantity - td.refunded_quantity) * td.unit_factor AS quantity
+			FROM transaction_details td
+			JOIN transactions t ON t.id = td.transaction_id
+			WHERE `+where+`
+			UNION ALL
+			SELECT tdc.component_id, tdc.quantity * (td.quantity - td.refunded_quantity) / td.quantity
+			FROM transaction_detail_components tdc
+			JOIN transaction_details td ON td.id = tdc.transaction_detail_id
+			JOIN transactions t ON t.id = td.transaction_id
+			WHERE `+where+`
+		)
+		SELECT p.id, p.name, COALESCE(p.sku, ''), COALESCE(c.name, ''), p.unit, `+stockColumn+`, p.min_stock, p.reorder_qty,
+			COALESCE((SELECT SUM(s.quantity) FROM sold s WHERE s.product_id = p.id), 0)
+		FROM products p
+		LEFT JOIN categories c ON c.id = p.category_id
+		WHERE `+stockKeepingFilter+`
+		ORDER BY p.id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("gagal hitung penjualan harian: %v", err)
+	}
+	defer rows.Close()
+
+	report.Items = []models.ReorderSuggestion{}
+	for rows.Next() {
+		var item models.ReorderSuggestion
+		if err := rows.Scan(&item.ProductID, &item.Name, &item.SKU, &item.CategoryName, &item.Unit,
+			&item.Stock, &item.MinStock, &item.ReorderQty, &item.Sold); err != nil {
+			return nil, err
+		}
+		if item.Sold < 0 {
+			item.Sold = 0
+		}
+		item.DailySales = math.Round(float64(item.Sold)/float64(report.Days)*100) / 100
+
+		// Kebutuhan = penjualan selama coverDays + stok minimum - stok sekarang; minimal sebanyak jumlah pesan ulang biasanya
+		need := int(math.Ceil(float64(item.Sold)*float64(coverDays)/float64(report.Days))) + item.MinStock - item.Stock
+		low := item.MinStock > 0 && item.Stock <= item.MinStock
+		if need <= 0 && !low {
+			continue
+		}
+		item.SuggestedQty = need
+		if item.SuggestedQty < item.ReorderQty {
+			item.SuggestedQty = item.ReorderQty
+		}
+		if item.Sold > 0 {
+			cover := math.Round(float64(item.Stock)*float64(report.Days)/float64(item.Sold)*10) / 10
+			if cover < 0 {
+				cover = 0
+			}
+			item.DaysOfCover = &cover
+		}
+		report.Items = append(report.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Yang paling cepat habis lebih dulu; produk tanpa penjualan (tapi di bawah stok minimum) paling akhir
+	sort.SliceStable(report.Items, func(i, j int) bool {
+		a, b := report.Items[i].DaysOfCover, report.Items[j].DaysOfCover
+		if a == nil || b == nil {
+			return a != nil && b == nil
+		}
+		return *a < *b
+	})
+	return report, nil
+}
+
+// queryProfitLines menjalankan query laporan laba dengan kolom: id, nama, qty, pendapatan, HPP.
+func (repo *TransactionRepository) queryProfitLines(query string, args ...interface{}) ([]models.ProfitLine, error) {
+	rows, err := repo.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lines := []models.ProfitLine{}
+	for rows.Next() {
+		var l models.ProfitLine
+		if err := rows.Scan(&l.ID, &l.Name, &l.Quantity, &l.Revenue, &l.COGS); err != nil {
+			return nil, err
+		}
+		l.GrossProfit = l.Revenue - l.COGS
+		l.MarginPercent = marginPercent(l.GrossProfit, l.Revenue)
+		lines = append(lines, l)
+	}
+	return lines, rows.Err()
+}
+
+// marginPercent menghitung margin laba kotor (persen, 2 desimal). Pendapatan 0 dianggap margin 0.
+func marginPercent(grossProfit, revenue int) float64 {
+	if revenue == 0 {
+		return 0
+	}
+	return math.Round(float64(grossProfit)*10000/float64(revenue)) / 100
+}
+
+// salesPeriodKey mengembalikan ekspresi SQL untuk kunci periode laporan penjualan (deret waktu).
+// Jam memakai jam lokal toko; hari/minggu/bulan memakai hari bisnis (jam lokal digeser jam tutup buku).
+func (repo *TransactionRepository) salesPeriodKey(groupBy string) string {
+	local, business := repo.businessDay.SQLModifiers()
+	switch groupBy {
+	case models.SalesGroupByHour:
+		return "strftime('%H:00', t.created_at, " + local + ")"
+	case models.SalesGroupByWeek:
+		return "date(t.created_at, " + business + ", 'weekday 0', '-6 days')" // Senin awal minggu
+	case models.SalesGroupByMonth:
+		return "strftime('%Y-%m', t.created_at, " + business + ")"
+	default:
+		return "date(t.created_at, " + business + ")"
+	}
+}
+
+// soldLineTotal adalah yang dibayar pelanggan untuk barang yang tidak dikembalikan di satu baris transaction_details.
+const soldLineTotal = "CAST(ROUND(COALESCE(SUM((td.subtotal + td.service_charge_amount + td.tax_amount) * 1.0 * (td.quantity - td.refunded_quantity) / td.quantity), 0)) AS INTEGER)"
+
+// GetSalesReport menghitung laporan penjualan untuk rentang hari bisnis from..to (YYYY-MM-DD, inklusif),
+// dirinci sesuai groupBy (lihat models.SalesGroupBy*) beserta top produk terlaris.
+// Semua agregasi (termasuk rata-rata) dihitung di SQL. outletID 0 = gabungan semua outlet.
+func (repo *TransactionRepository) GetSalesReport(from, to, groupBy string, top, outletID int) (*models.SalesReport, error) {
+	report := &models.SalesReport{From: from, To: to, OutletID: outletID, GroupBy: groupBy}
+	start, end, err := repo.businessDayBounds(from, to)
+	if err != nil {
+		return nil, err
+	}
+	where := "t.created_at >= ? AND t.created_at < ? AND " + salesStatusFilter + outletFilter
+
+	// Query 1: Total, rata-rata belanja per transaksi dan jumlah barang per transaksi.
+	// Transaksi VOIDED punya refunded_amount = total_amount dan refunded_quantity = quantity, jadi tidak menambah omset/barang.
+	err = repo.db.QueryRow(`
+		SELECT revenue, transactions, items,
+			COALESCE(ROUND(revenue * 1.0 / NULLIF(transactions, 0), 2), 0),
+			COALESCE(ROUND(items * 1.0 / NULLIF(transactions, 0), 2), 0)
+		FROM (
+			SELECT
+				COALESCE(SUM(t.total_amount - t.refunded_amount), 0) AS revenue,
+				COUNT(CASE WHEN t.status != ? THEN 1 END) AS transactions,
+				(SELECT COALESCE(SUM((td.quantity - td.refunded_quantity) * td.unit_factor), 0)
+					FROM transaction_details td JOIN transactions t ON td.transaction_id = t.id
+					WHERE `+where+`) AS items
+			FROM transactions t
+			WHERE `+where+`
+		)`, models.TransactionStatusVoided, start, end, outletID, outletID, start, end, outletID, outletID).
+		Scan(&report.TotalRevenue, &report.TotalTransactions, &report.TotalItems, &report.AverageBasket, &report.ItemsPerTransaction)
+	if err != nil {
+		return nil, fmt.Errorf("gagal hitung total penjualan: %v", err)
+	}
+
+	// Query 2: Rincian sesuai group_by. Setiap query menghasilkan kolom: key, id, transaksi, barang, omset.
+	var query string
+	args := []interface{}{models.TransactionStatusVoided, start, end, outletID, outletID}
+	switch groupBy {
+	case models.SalesGroupByHour, models.SalesGroupByDay, models.SalesGroupByWeek, models.SalesGroupByMonth:
+		// Jumlah barang per transaksi dihitung dulu, supaya JOIN tidak menggandakan total_amount.
+		query = `
+		WITH items AS (
+			SELECT transaction_id, SUM((quantity - refunded_quantity) * unit_factor) AS qty
+			FROM transaction_details GROUP BY transaction_id
+		)
+		SELECT ` + repo.salesPeriodKey(groupBy) + `, 0,
+			COUNT(CASE WHEN t.status != ? THEN 1 END),
+			COALESCE(SUM(i.qty), 0),
+			COALESCE(SUM(t.total_amount - t.refunded_amount), 0)
+		FROM transactions t
+		LEFT JOIN items i ON i.transaction_id = t.id
+		WHERE ` + where + `
+		GROUP BY 1
+		ORDER BY 1`
+	case models.SalesGroupByCategory:
+		query = `
+		SELECT COALESCE(NULLIF(MAX(td.category_name), ''), '-'), COALESCE(td.category_id, 0),
+			COUNT(DISTINCT CASE WHEN t.status != ? THEN t.id END),
+			SUM((td.quantity - td.refunded_quantity) * td.unit_factor),
+			` + soldLineTotal + `
+		FROM transaction_details td
+		JOIN transactions t ON td.transaction_id = t.id
+		WHERE ` + where + `
+		GROUP BY COALESCE(td.category_id, 0)
+		ORDER BY 5 DESC, 1`
+	case models.SalesGroupByProduct:
+		query = `
+		SELECT COALESCE(MAX(td.product_name), ''), td.product_id,
+			COUNT(DISTINCT CASE WHEN t.status != ? THEN t.id END),
+			SUM((td.quantity - td.refunded_quantity) * td.unit_factor),
+			` + soldLineTotal + `
+		FROM transaction_details td
+		JOIN transactions t ON td.transaction_id = t.id
+		WHERE ` + where + `
+		GROUP BY td.product_id
+		ORDER BY 5 DESC, 1`
+	case models.SalesGroupByPaymentMethod:
+		// Uang masuk per metode pembayaran: hanya tender PAID dari transaksi yang tidak di-void (sama seperti laporan harian).
+		query = `
+		SELECT p.method, 0, COUNT(DISTINCT p.transaction_id), 0, SUM(p.amount)
+		FROM payments p
+		JOIN transactions t ON p.transaction_id = t.id
+		WHERE t.status != ? AND ` + where + ` AND p.status = ?
+		GROUP BY p.method
+		ORDER BY 5 DESC, 1`
+		args = append(args, models.PaymentStatusPaid)
+	default:
+		return nil, NewValidationError("group_by %q tidak dikenal", groupBy)
+	}
+
+	report.Rows, err = repo.querySalesReportRows(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("gagal hitung rincian penjualan: %v", err)
+	}
+
+	// Query 3: Top-N produk terlaris (qty terbanyak, omset sebagai penentu jika qty sama)
+	rows, err := repo.db.Query(`
+		SELECT td.product_id, COALESCE(MAX(td.product_name), ''), SUM((td.quantity - td.refunded_quantity) * td.unit_factor) AS qty, `+soldLineTotal+` AS revenue
+		FROM transaction_details td
+		JOIN transactions t ON td.transaction_id = t.id
+		WHERE `+where+`
+		GROUP BY td.product_id
+		HAVING qty > 0
+		ORDER BY qty DESC, revenue DESC
+		LIMIT ?`, start, end, outletID, outletID, top)
+	if err != nil {
+		return nil, fmt.Errorf("gagal cari produk terlaris: %v", err)
+	}
+	defer rows.Close()
+
+	report.TopProducts = []models.TopProduct{}
+	for rows.Next() {
+		var p models.TopProduct
+		if err := rows.Scan(&p.ProductID, &p.Name, &p.Quantity, &p.Revenue); err != nil {
+			return nil, err
+		}
+		report.TopProducts = append(report.TopProducts, p)
+	}
+
+	return report, rows.Err()
+}
+
+// querySalesReportRows menjalankan query rincian laporan penjualan dengan kolom: key, id, transaksi, barang, omset.
+func (repo *TransactionRepository) querySalesReportRows(query string, args ...interface{}) ([]models.SalesReportRow, error) {
+	rows, err := repo.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []models.SalesReportRow{}
+	for rows.Next() {
+		var r models.SalesReportRow
+		if err := rows.Scan(&r.Key, &r.ID, &r.Transactions, &r.Items, &r.Revenue); err != nil {
+			return nil, err
+		}
+		result = append(result, r)
+	}
+	return result, rows.Err()
+}
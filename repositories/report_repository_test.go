@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"codeWithUmam/models"
+	"testing"
+)
+
+func TestTransactionRepository_GetSalesReport(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	repo := NewTransactionRepository(db, models.TaxConfig{}, models.CostingMethodAverage, models.BusinessDay{})
+
+	category := &models.Category{Name: "Minuman"}
+	if err := NewCategoryRepository(db).Create(category); err != nil {
+		t.Fatalf("failed to seed category: %v", err)
+	}
+	kopi := &models.Product{Name: "Kopi", Price: 5000, Stock: 50, CategoryID: category.ID}
+	if err := NewProductRepository(db).Create(kopi); err != nil {
+		t.Fatalf("failed to seed product: %v", err)
+	}
+	roti := seedProduct(t, db, "Roti", 8000, 50)
+
+	checkout := func(createdAt, method string, items ...models.CheckoutItem) *models.Transaction {
+		t.Helper()
+		total := 0
+		for _, item := range items {
+			if item.ProductID == kopi.ID {
+				total += 5000 * item.Quantity
+			} else {
+				total += 8000 * item.Quantity
+			}
+		}
+		trx, err := repo.CreateTransaction(models.CheckoutRequest{Items: items, PaidAmount: total, PaymentMethod: method}, CheckoutOptions{})
+		if err != nil {
+			t.Fatalf("CreateTransaction failed: %v", err)
+		}
+		if _, err := db.Exec("UPDATE transactions SET created_at = ? WHERE id = ?", createdAt, trx.ID); err != nil {
+			t.Fatalf("failed to set created_at: %v", err)
+		}
+		return trx
+	}
+
+	// 2026-01-05 (Senin) dan 2026-01-07 ada di minggu yang sama
+	checkout("2026-01-05 10:15:00", "CASH", models.CheckoutItem{ProductID: kopi.ID, Quantity: 2})
+	checkout("2026-01-07 15:30:00", "QRIS", models.CheckoutItem{ProductID: kopi.ID, Quantity: 1}, models.CheckoutItem{ProductID: roti, Quantity: 1})
+	voided := checkout("2026-01-07 16:00:00", "CASH", models.CheckoutItem{ProductID: roti, Quantity: 2})
+	if _, err := repo.VoidTransaction(voided.ID, "salah input", "kasir"); err != nil {
+		t.Fatalf("VoidTransaction failed: %v", err)
+	}
+	checkout("2026-02-01 09:00:00", "CASH", models.CheckoutItem{ProductID: kopi.ID, Quantity: 3})
+
+	report, err := repo.GetSalesReport("2026-01-01", "2026-01-31", models.SalesGroupByDay, 1, 0)
+	if err != nil {
+		t.Fatalf("GetSalesReport failed: %v", err)
+	}
+	if report.TotalRevenue != 23000 || report.TotalTransactions != 2 || report.TotalItems != 4 ||
+		report.AverageBasket != 11500 || report.ItemsPerTransaction != 2 {
+		t.Errorf("unexpected totals: %+v", report)
+	}
+	assertRows(t, models.SalesGroupByDay, report.Rows, []models.SalesReportRow{
+		{Key: "2026-01-05", Transactions: 1, Items: 2, Revenue: 10000},
+		{Key: "2026-01-07", Transactions: 1, Items: 2, Revenue: 13000},
+	})
+	if len(report.TopProducts) != 1 || report.TopProducts[0].Name != "Kopi" || report.TopProducts[0].Quantity != 3 {
+		t.Errorf("expected Kopi (3) as the only top product, got %+v", report.TopProducts)
+	}
+
+	cases := []struct {
+		groupBy string
+		to      string
+		want    []models.SalesReportRow
+	}{
+		{models.SalesGroupByHour, "2026-01-31", []models.SalesReportRow{
+			{Key: "10:00", Transactions: 1, Items: 2, Revenue: 10000},
+			{Key: "15:00", Transactions: 1, Items: 2, Revenue: 13000},
+			{Key: "16:00", Transactions: 0, Items: 0, Revenue: 0}, // Transaksi void tetap tercatat di jamnya, tapi bernilai 0
+		}},
+		{models.SalesGroupByWeek, "2026-01-31", []models.SalesReportRow{
+			{Key: "2026-01-05", Transactions: 2, Items: 4, Revenue: 23000},
+		}},
+		{models.SalesGroupByMonth, "2026-02-28", []models.SalesReportRow{
+			{Key: "2026-01", Transactions: 2, Items: 4, Revenue: 23000},
+			{Key: "2026-02", Transactions: 1, Items: 3, Revenue: 15000},
+		}},
+		{models.SalesGroupByCategory, "2026-01-31", []models.SalesReportRow{
+			{Key: "Minuman", ID: category.ID, Transactions: 2, Items: 3, Revenue: 15000},
+			{Key: "-", Transactions: 1, Items: 1, Revenue: 8000},
+		}},
+		{models.SalesGroupByProduct, "2026-01-31", []models.SalesReportRow{
+			{Key: "Kopi", ID: kopi.ID, Transactions: 2, Items: 3, Revenue: 15000},
+			{Key: "Roti", ID: roti, Transactions: 1, Items: 1, Revenue: 8000},
+		}},
+		{models.SalesGroupByPaymentMethod, "2026-01-31", []models.SalesReportRow{
+			{Key: "QRIS", Transactions: 1, Revenue: 13000},
+			{Key: "CASH", Transactions: 1, Revenue: 10000},
+		}},
+	}
+	for _, c := range cases {
+		report, err := repo.GetSalesReport("2026-01-01", c.to, c.groupBy, 10, 0)
+		if err != nil {
+			t.Fatalf("GetSalesReport(%s) failed: %v", c.groupBy, err)
+		}
+		assertRows(t, c.groupBy, report.Rows, c.want)
+	}
+}
+
+func assertRows(t *testing.T, groupBy string, got, want []models.SalesReportRow) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%s: expected %d rows, got %+v", groupBy, len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("%s row %d: expected %+v, got %+v", groupBy, i, want[i], got[i])
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package repositories
+
+import (
+	"codeWithUmam/models"
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+// ShiftRepositoryImpl mengelola shift kasir dan Z-report laci kas.
+// Struct ini mengimplementasikan interface ShiftRepository dari package repositories.
+type ShiftRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewShiftRepository(db *sql.DB) *ShiftRepositoryImpl {
+	return &ShiftRepositoryImpl{db: db}
+}
+
+// queryer adalah method baca yang dimiliki *sql.DB maupun *sql.Tx,
+// supaya laporan shift bisa dihitung di luar maupun di dalam Database Transaction.
+type queryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+const shiftColumns = "id, cashier, status, opening_float, COALESCE(note, ''), opened_at, closed_at"
+
+func scanShift(row interface{ Scan(...interface{}) error }) (models.Shift, error) {
+	var s models.Shift
+	err := row.Scan(&s.ID, &s.Cashier, &s.Status, &s.OpeningFloat, &s.Note, &s.OpenedAt, &s.ClosedAt)
+	return s, err
+}
+
+// Open membuka shift baru. Kasir yang masih punya shift OPEN ditolak.
+func (r *ShiftRepositoryImpl) Open(req models.OpenShiftRequest) (*models.Shift, error) {
+	res, err := r.db.Exec("INSERT INTO shifts (cashier, status, opening_float, note) VALUES (?, ?, ?, ?)",
+		req.Cashier, models.ShiftStatusOpen, req.OpeningFloat, req.Note)
+	if isUniqueViolation(err) {
+		return nil, NewValidationError("kasir %s masih punya shift yang belum ditutup", req.Cashier)
+	}
+	if err != nil {
+		return nil, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(int(id))
+}
+
+// GetAll mengambil daftar shift (tanpa laporan), opsional difilter status dan kasir.
+func (r *ShiftRepositoryImpl) GetAll(status, cashier string) ([]models.Shift, error) {
+	rows, err := r.db.Query(`
+		SELECT `+shiftColumns+` FROM shifts
+		WHERE (? = '' OR status = ?) AND (? = '' OR cashier = ?)
+		ORDER BY id DESC`, status, status, cashier, cashier)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	shifts := []models.Shift{}
+	for rows.Next() {
+		s, err := scanShift(rows)
+		if err != nil {
+			return nil, err
+		}
+		shifts = append(shifts, s)
+	}
+	return shifts, rows.Err()
+}
+
+// GetByID mengambil shift beserta laporannya:
+// X-report yang dihitung ulang dari transaksi jika shift masih OPEN, atau Z-report yang tersimpan jika sudah CLOSED.
+func (r *ShiftRepositoryImpl) GetByID(id int) (*models.Shift, error) {
+	var zReport sql.NullString
+	var s models.Shift
+	err := r.db.QueryRow("SELECT "+shiftColumns+", z_report FROM shifts WHERE id = ?", id).
+		Scan(&s.ID, &s.Cashier, &s.Status, &s.OpeningFloat, &s.Note, &s.OpenedAt, &s.ClosedAt, &zReport)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if zReport.Valid {
+		var report models.ShiftReport
+		if err := json.Unmarshal([]byte(zReport.String), &report); err != nil {
+			return nil, err
+		}
+		s.Report = &report
+		return &s, nil
+	}
+
+	s.Report, err = buildShiftReport(r.db, s)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Close menutup shift: menghitung Z-report, membandingkannya dengan uang hasil hitung, lalu membekukannya.
+// Shift yang masih punya transaksi menunggu pembayaran (QRIS belum dibayar) belum boleh ditutup,
+// karena uangnya belum pasti masuk.
+func (r *ShiftRepositoryImpl) Close(id int, req models.CloseShiftRequest) (*models.Shift, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	s, err := scanShift(tx.QueryRow("SELECT "+shiftColumns+" FROM shifts WHERE id = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if s.Status != models.ShiftStatusOpen {
+		return nil, NewValidationError("shift #%d sudah %s", id, s.Status)
+	}
+
+	var pending int
+	err = tx.QueryRow("SELECT COUNT(id) FROM transactions WHERE shift_id = ? AND status = ?", id, models.TransactionStatusPendingPayment).Scan(&pending)
+	if err != nil {
+		return nil, err
+	}
+	if pending > 0 {
+		return nil, NewValidationError("masih ada %d transaksi menunggu pembayaran di shift #%d", pending, id)
+	}
+
+	closedAt := time.Now().UTC()
+	s.ClosedAt = &closedAt
+	report, err := buildShiftReport(tx, s)
+	if err != nil {
+		return nil, err
+	}
+	applyShiftCounts(report, req)
+
+	frozen, err := json.Marshal(report)
+	if err != nil {
+		return nil, err
+	}
+	note := s.Note
+	if req.Note != "" {
+		note = req.Note
+	}
+	_, err = tx.Exec("UPDATE shifts SET status = ?, closed_at = ?, counted_cash = ?, note = ?, z_report = ? WHERE id = ?",
+		models.ShiftStatusClosed, closedAt, req.CountedCash, note, string(frozen), id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.GetByID(id)
+}
+
+// buildShiftReport menghitung rekap laci kas dari transaksi shift tersebut.
+// Transaksi yang menunggu pembayaran / kedaluwarsa tidak dihitung, dan tender hanya yang sudah PAID.
+func buildShiftReport(q queryer, s models.Shift) (*models.ShiftReport, error) {
+	report := &models.ShiftReport{
+		ShiftID:      s.ID,
+		Cashier:      s.Cashier,
+		OpenedAt:     s.OpenedAt,
+		ClosedAt:     s.ClosedAt,
+		Final:        s.ClosedAt != nil,
+		OpeningFloat: s.OpeningFloat,
+	}
+
+	err := q.QueryRow(`
+		SELECT
+			COUNT(CASE WHEN t.status != ? THEN 1 END),
+			COALESCE(SUM(CASE WHEN t.status != ? THEN t.total_amount - t.refunded_amount END), 0),
+			COUNT(CASE WHEN t.status != ? AND t.refunded_amount > 0 THEN 1 END),
+			COALESCE(SUM(CASE WHEN t.status != ? THEN t.refunded_amount END), 0),
+			COUNT(CASE WHEN t.status = ? THEN 1 END),
+			COALESCE(SUM(CASE WHEN t.status = ? THEN t.total_amount END), 0)
+		FROM transactions t
+		WHERE t.shift_id = ? AND `+salesStatusFilter,
+		models.TransactionStatusVoided, models.TransactionStatusVoided, models.TransactionStatusVoided,
+		models.TransactionStatusVoided, models.TransactionStatusVoided, models.TransactionStatusVoided, s.ID).
+		Scan(&report.TotalTransactions, &report.TotalSales, &report.RefundCount, &report.RefundAmount, &report.VoidCount, &report.VoidAmount)
+	if err != nil {
+		return nil, err
+	}
+
+	// Uang masuk per tender dari tabel payments. Transaksi yang di-void tidak dihitung karena uangnya sudah dikembalikan.
+	rows, err := q.Query(`
+		SELECT p.method, COUNT(DISTINCT p.transaction_id), SUM(p.amount)
+		FROM payments p
+		JOIN transactions t ON p.transaction_id = t.id
+		WHERE t.shift_id = ? AND t.status != ? AND p.status = ?
+		GROUP BY p.method
+		ORDER BY p.method`, s.ID, models.TransactionStatusVoided, models.PaymentStatusPaid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	report.Tenders = []models.ShiftTender{}
+	hasCash := false
+	for rows.Next() {
+		var t models.ShiftTender
+		if err := rows.Scan(&t.Method, &t.Transactions, &t.Sales); err != nil {
+			return nil, err
+		}
+		t.Expected = t.Sales
+		if t.Method == models.PaymentMethodCash {
+			hasCash = true
+		}
+		report.Tenders = append(report.Tenders, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	// Laci kas selalu ada di laporan, walaupun belum ada penjualan tunai (modal awal tetap harus dihitung)
+	if !hasCash {
+		report.Tenders = append([]models.ShiftTender{{Method: models.PaymentMethodCash}}, report.Tenders...)
+	}
+
+	for i := range report.Tenders {
+		if report.Tenders[i].Method == models.PaymentMethodCash {
+			// Refund dibayar tunai dari laci
+			report.Tenders[i].Expected = report.OpeningFloat + report.Tenders[i].Sales - report.RefundAmount
+			report.ExpectedCash = report.Tenders[i].Expected
+		}
+	}
+
+	return report, nil
+}
+
+// applyShiftCounts mengisi hasil hitung dan selisih per tender. Fungsi ini murni (tidak menyentuh database).
+func applyShiftCounts(report *models.ShiftReport, req models.CloseShiftRequest) {
+	counted := map[string]int{models.PaymentMethodCash: req.CountedCash}
+	for _, c := range req.Counted {
+		if c.Method != models.PaymentMethodCash {
+			counted[c.Method] = c.Amount
+		}
+	}
+
+	for i := range report.Tenders {
+		t := &report.Tenders[i]
+		if amount, ok := counted[t.Method]; ok {
+			variance := amount - t.Expected
+			t.Counted, t.Variance = &amount, &variance
+			delete(counted, t.Method)
+		}
+	}
+	// Tender yang dihitung tapi tidak ada penjualannya tetap dicatat (selisih = seluruh hasil hitung)
+	for _, c := range req.Counted {
+		if amount, ok := counted[c.Method]; ok {
+			variance := amount
+			report.Tenders = append(report.Tenders, models.ShiftTender{Method: c.Method, Counted: &amount, Variance: &variance})
+			delete(counted, c.Method)
+		}
+	}
+
+	cashVariance := req.CountedCash - report.ExpectedCash
+	report.CountedCash = &req.CountedCash
+	report.CashVariance = &cashVariance
+}
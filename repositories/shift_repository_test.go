@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"codeWithUmam/models"
+	"testing"
+)
+
+func TestShiftRepository_ZReport(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	repo := NewShiftRepository(db)
+	trxRepo := NewTransactionRepository(db, models.TaxConfig{}, models.CostingMethodAverage, models.BusinessDay{})
+	kopi := seedProduct(t, db, "Kopi", 10000, 100)
+	roti := seedProduct(t, db, "Roti", 5000, 100)
+
+	shift, err := repo.Open(models.OpenShiftRequest{Cashier: "ani", OpeningFloat: 100000})
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if _, err := repo.Open(models.OpenShiftRequest{Cashier: "ani"}); err == nil {
+		t.Error("expected second open shift for the same cashier to be rejected")
+	}
+
+	checkout := func(req models.CheckoutRequest) *models.Transaction {
+		t.Helper()
+		trx, err := trxRepo.CreateTransaction(req, CheckoutOptions{})
+		if err != nil {
+			t.Fatalf("CreateTransaction failed: %v", err)
+		}
+		return trx
+	}
+
+	if _, err := trxRepo.CreateTransaction(models.CheckoutRequest{
+		Items: []models.CheckoutItem{{ProductID: kopi, Quantity: 1}}, PaidAmount: 10000, PaymentMethod: "CASH", Cashier: "budi",
+	}, CheckoutOptions{}); err == nil {
+		t.Error("expected checkout by a cashier without an open shift to be rejected")
+	}
+
+	// Tunai dengan kembalian: yang masuk laci hanya 20000
+	trx := checkout(models.CheckoutRequest{Items: []models.CheckoutItem{{ProductID: kopi, Quantity: 2}}, PaidAmount: 50000, PaymentMethod: "CASH", Cashier: "ani"})
+	if trx.ShiftID != shift.ID || trx.Cashier != "ani" {
+		t.Errorf("expected transaction on shift %d by ani, got shift %d by %q", shift.ID, trx.ShiftID, trx.Cashier)
+	}
+	// Split payment
+	checkout(models.CheckoutRequest{
+		Items:    []models.CheckoutItem{{ProductID: kopi, Quantity: 1}, {ProductID: roti, Quantity: 3}},
+		Payments: []models.PaymentInput{{Method: "CASH", Amount: 10000}, {Method: "QRIS", Amount: 15000}},
+		Cashier:  "ani",
+	})
+	// Void: tidak masuk tender, tapi dihitung di void count
+	voided := checkout(models.CheckoutRequest{Items: []models.CheckoutItem{{ProductID: roti, Quantity: 1}}, PaidAmount: 5000, PaymentMethod: "CASH", Cashier: "ani"})
+	if _, err := trxRepo.VoidTransaction(voided.ID, "salah input", "ani"); err != nil {
+		t.Fatalf("VoidTransaction failed: %v", err)
+	}
+	// Refund sebagian: uangnya keluar dari laci
+	refunded := checkout(models.CheckoutRequest{Items: []models.CheckoutItem{{ProductID: kopi, Quantity: 2}}, PaidAmount: 20000, PaymentMethod: "CASH", Cashier: "ani"})
+	if _, err := trxRepo.RefundTransaction(refunded.ID, []models.RefundItem{{DetailID: refunded.Details[0].ID, Quantity: 1}}, "rusak", "ani"); err != nil {
+		t.Fatalf("RefundTransaction failed: %v", err)
+	}
+	// Transaksi tanpa kasir tidak masuk shift mana pun
+	checkout(models.CheckoutRequest{Items: []models.CheckoutItem{{ProductID: roti, Quantity: 1}}, PaidAmount: 5000, PaymentMethod: "CASH"})
+
+	closed, err := repo.Close(shift.ID, models.CloseShiftRequest{
+		CountedCash: 139000,
+		Counted:     []models.TenderCount{{Method: "QRIS", Amount: 15000}},
+	})
+	if err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	report := closed.Report
+	if closed.Status != models.ShiftStatusClosed || report == nil || !report.Final {
+		t.Fatalf("expected closed shift with final Z-report, got %+v", closed)
+	}
+	if report.TotalTransactions != 3 || report.TotalSales != 55000 || report.RefundCount != 1 || report.RefundAmount != 10000 ||
+		report.VoidCount != 1 || report.VoidAmount != 5000 {
+		t.Errorf("unexpected totals: %+v", report)
+	}
+	if report.ExpectedCash != 140000 || *report.CountedCash != 139000 || *report.CashVariance != -1000 {
+		t.Errorf("expected cash 140000 counted 139000 variance -1000, got %d/%d/%d", report.ExpectedCash, *report.CountedCash, *report.CashVariance)
+	}
+	if len(report.Tenders) != 2 {
+		t.Fatalf("expected CASH and QRIS tenders, got %+v", report.Tenders)
+	}
+	cash, qris := report.Tenders[0], report.Tenders[1]
+	if cash.Method != "CASH" || cash.Sales != 50000 || cash.Transactions != 3 || cash.Expected != 140000 || *cash.Variance != -1000 {
+		t.Errorf("unexpected cash tender: %+v", cash)
+	}
+	if qris.Method != "QRIS" || qris.Expected != 15000 || *qris.Counted != 15000 || *qris.Variance != 0 {
+		t.Errorf("unexpected QRIS tender: %+v", qris)
+	}
+
+	// Z-report tidak berubah walaupun ada refund setelah shift ditutup
+	if _, err := trxRepo.RefundTransaction(refunded.ID, []models.RefundItem{{DetailID: refunded.Details[0].ID, Quantity: 1}}, "rusak", "ani"); err != nil {
+		t.Fatalf("RefundTransaction failed: %v", err)
+	}
+	again, err := repo.GetByID(shift.ID)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if again.Report.RefundAmount != 10000 || again.Report.ExpectedCash != 140000 || *again.Report.CashVariance != -1000 {
+		t.Errorf("expected frozen Z-report, got %+v", again.Report)
+	}
+
+	if _, err := repo.Close(shift.ID, models.CloseShiftRequest{CountedCash: 140000}); err == nil {
+		t.Error("expected closing a closed shift to be rejected")
+	}
+	if _, err := trxRepo.CreateTransaction(models.CheckoutRequest{
+		Items: []models.CheckoutItem{{ProductID: kopi, Quantity: 1}}, PaidAmount: 10000, PaymentMethod: "CASH", Cashier: "ani",
+	}, CheckoutOptions{}); err == nil {
+		t.Error("expected checkout after the shift closed to be rejected")
+	}
+
+	// Shift baru untuk kasir yang sama boleh dibuka lagi
+	if _, err := repo.Open(models.OpenShiftRequest{Cashier: "ani", OpeningFloat: 50000}); err != nil {
+		t.Errorf("expected new shift to open after closing, got %v", err)
+	}
+}
@@ -0,0 +1,239 @@
+package repositories
+
+import (
+	"codeWithUmam/models"
+	"database/sql"
+	"fmt"
+)
+
+// batchPortion adalah bagian satu pergerakan stok yang jatuh ke satu batch (quantity bertanda seperti ledger).
+// batchID 0 berarti bagian yang tidak punya batch, misal stok minus yang tidak tertutup batch mana pun.
+type batchPortion struct {
+	batchID  int
+	quantity int
+}
+
+// outletStockColumn adalah stok produk (alias tabel p) di outlet pada parameter ?.
+const outletStockColumn = "COALESCE((SELECT os.stock FROM outlet_stock os WHERE os.outlet_id = ? AND os.product_id = p.id), 0)"
+
+// sellableStockColumn adalah stok yang boleh dijual (alias tabel p) di satu outlet pada satu tanggal:
+// untuk produk ber-batch, sisa batch yang sudah kedaluwarsa tidak dihitung.
+// Parameternya berurutan: outlet, tanggal (YYYY-MM-DD), outlet.
+const sellableStockColumn = `CASE WHEN p.track_batches = 1
+	THEN COALESCE((SELECT SUM(b.remaining_quantity) FROM stock_batches b
+		WHERE b.product_id = p.id AND b.outlet_id = ? AND (b.expiry_date IS NULL OR b.expiry_date >= ?)), 0)
+	ELSE ` + outletStockColumn + ` END`
+
+// stockShortage menjelaskan stok yang tersisa untuk pesan "stok tidak cukup", termasuk jumlah yang tertahan karena kedaluwarsa.
+func stockShortage(sellable, stock int) string {
+	if stock > sellable {
+		return fmt.Sprintf("sisa: %d, %d lainnya sudah kedaluwarsa", sellable, stock-sellable)
+	}
+	return fmt.Sprintf("sisa: %d", sellable)
+}
+
+// applyBatchMovement memperbarui batch produk ber-batch di outlet m.OutletID untuk pergerakan m, di dalam Database Transaction tx.
+// Dipanggil oleh recordStockMovement; hasilnya dipakai untuk menulis satu baris ledger per batch.
+// Produk yang tidak ber-batch menghasilkan nil.
+//   - Barang keluar: batch yang paling cepat kedaluwarsa diambil lebih dulu (FEFO), batch tanpa tanggal paling akhir.
+//     Jika m.SellableOn diisi, batch yang sudah kedaluwarsa dilewati dan kekurangannya -> ValidationError.
+//   - Barang masuk: ke batch m.BatchID; refund transaksi kembali ke batch yang dulu dijual;
+//     selain itu (penerimaan, koreksi stok) membuat batch baru dari m.BatchNumber & m.ExpiryDate.
+func applyBatchMovement(tx *sql.Tx, m models.StockMovement) ([]batchPortion, error) {
+	var name string
+	var tracked bool
+	err := tx.QueryRow("SELECT name, track_batches FROM products WHERE id = ?", m.ProductID).Scan(&name, &tracked)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil || !tracked {
+		return nil, err
+	}
+
+	switch {
+	case m.Quantity < 0:
+		return takeFromBatches(tx, name, m)
+	case m.BatchID > 0:
+		res, err := tx.Exec("UPDATE stock_batches SET remaining_quantity = remaining_quantity + ? WHERE id = ? AND product_id = ? AND outlet_id = ?",
+			m.Quantity, m.BatchID, m.ProductID, m.OutletID)
+		if err != nil {
+			return nil, err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		if affected == 0 {
+			return nil, NewValidationError("batch %d bukan milik produk %s di outlet %d", m.BatchID, name, m.OutletID)
+		}
+		return []batchPortion{{batchID: m.BatchID, quantity: m.Quantity}}, nil
+	case m.Type == models.StockMovementRefund && m.ReferenceType == models.StockReferenceTransaction && m.BatchNumber == "" && m.ExpiryDate == "":
+		return returnToSoldBatches(tx, m)
+	}
+
+	id, err := insertBatch(tx, m.ProductID, m.OutletID, m.BatchNumber, m.ExpiryDate, m.Quantity)
+	if err != nil {
+		return nil, err
+	}
+	return []batchPortion{{batchID: id, quantity: m.Quantity}}, nil
+}
+
+// insertBatch membuat batch baru di outlet outletID berisi quantity unit (expiryDate kosong = tanpa tanggal kedaluwarsa).
+func insertBatch(tx *sql.Tx, productID, outletID int, batchNumber, expiryDate string, quantity int) (int, error) {
+	res, err := tx.Exec("INSERT INTO stock_batches (product_id, outlet_id, batch_number, expiry_date, quantity, remaining_quantity) VALUES (?, ?, ?, ?, ?, ?)",
+		productID, outletID, nullIfEmpty(batchNumber), nullIfEmpty(expiryDate), quantity, quantity)
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	return int(id), err
+}
+
+// takeFromBatches mengurangi batch produk di outlet m.OutletID sebanyak -m.Quantity unit dengan urutan FEFO.
+func takeFromBatches(tx *sql.Tx, productName string, m models.StockMovement) ([]batchPortion, error) {
+	query := "SELECT id, remaining_quantity FROM stock_batches WHERE product_id = ? AND outlet_id = ? AND remaining_quantity > 0"
+	args := []interface{}{m.ProductID, m.OutletID}
+	if m.SellableOn != "" {
+		query += " AND (expiry_date IS NULL OR expiry_date >= ?)"
+		args = append(args, m.SellableOn)
+	}
+	rows, err := tx.Query(query+" ORDER BY expiry_date IS NULL, expiry_date, id", args...)
+	if err != nil {
+		return nil, err
+	}
+	var available []batchPortion
+	for rows.Next() {
+		var b batchPortion
+		if err := rows.Scan(&b.batchID, &b.quantity); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		available = append(available, b)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var portions []batchPortion
+	left := -m.Quantity
+	for _, b := range available {
+		if left == 0 {
+			break
+		}
+		take := b.quantity
+		if take > left {
+			take = left
+		}
+		if _, err := tx.Exec("UPDATE stock_batches SET remaining_quantity = remaining_quantity - ? WHERE id = ?", take, b.batchID); err != nil {
+			return nil, err
+		}
+		portions = append(portions, batchPortion{batchID: b.batchID, quantity: -take})
+		left -= take
+	}
+
+	if left > 0 {
+		if m.SellableOn != "" {
+			return nil, NewValidationError("stok %s yang belum kedaluwarsa tidak cukup (kurang %d)", productName, left)
+		}
+		portions = append(portions, batchPortion{quantity: -left})
+	}
+	return portions, nil
+}
+
+// returnToSoldBatches mengembalikan barang refund ke batch yang dulu dijual di transaksi m.ReferenceID
+// (yang paling lama kedaluwarsanya lebih dulu). Sisanya (misal dijual sebelum produk ber-batch) menjadi batch baru tanpa tanggal.
+func returnToSoldBatches(tx *sql.Tx, m models.StockMovement) ([]batchPortion, error) {
+	rows, err := tx.Query(`
+		SELECT sm.batch_id, -SUM(sm.quantity)
+		FROM stock_movements sm
+		JOIN stock_batches b ON b.id = sm.batch_id
+		WHERE sm.product_id = ? AND sm.reference_type = ? AND sm.reference_id = ?
+		GROUP BY sm.batch_id
+		HAVING -SUM(sm.quantity) > 0
+		ORDER BY b.expiry_date IS NULL DESC, b.expiry_date DESC, sm.batch_id DESC`,
+		m.ProductID, models.StockReferenceTransaction, m.ReferenceID)
+	if err != nil {
+		return nil, err
+	}
+	var sold []batchPortion
+	for rows.Next() {
+		var b batchPortion
+		if err := rows.Scan(&b.batchID, &b.quantity); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		sold = append(sold, b)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var portions []batchPortion
+	left := m.Quantity
+	for _, b := range sold {
+		if left == 0 {
+			break
+		}
+		give := b.quantity
+		if give > left {
+			give = left
+		}
+		if _, err := tx.Exec("UPDATE stock_batches SET remaining_quantity = remaining_quantity + ? WHERE id = ?", give, b.batchID); err != nil {
+			return nil, err
+		}
+		portions = append(portions, batchPortion{batchID: b.batchID, quantity: give})
+		left -= give
+	}
+
+	if left > 0 {
+		id, err := insertBatch(tx, m.ProductID, m.OutletID, "", "", left)
+		if err != nil {
+			return nil, err
+		}
+		portions = append(portions, batchPortion{batchID: id, quantity: left})
+	}
+	return portions, nil
+}
+
+// setBatchTracking menyalakan/mematikan pencatatan batch untuk produk productID beserta varian-variannya.
+// Saat dinyalakan, stok yang sudah ada di setiap outlet menjadi satu batch tanpa tanggal kedaluwarsa; saat dimatikan, batch-nya dihapus.
+func setBatchTracking(tx *sql.Tx, productID int, enabled bool) error {
+	rows, err := tx.Query("SELECT id FROM products WHERE (id = ? OR parent_id = ?) AND track_batches != ?", productID, productID, enabled)
+	if err != nil {
+		return err
+	}
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if _, err := tx.Exec("UPDATE products SET track_batches = ? WHERE id = ?", enabled, id); err != nil {
+			return err
+		}
+		if _, err := tx.Exec("DELETE FROM stock_batches WHERE product_id = ?", id); err != nil {
+			return err
+		}
+		if !enabled {
+			continue
+		}
+		_, err := tx.Exec(`
+			INSERT INTO stock_batches (product_id, outlet_id, quantity, remaining_quantity)
+			SELECT product_id, outlet_id, stock, stock FROM outlet_stock WHERE product_id = ? AND stock > 0`, id)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
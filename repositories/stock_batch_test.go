@@ -0,0 +1,170 @@
+package repositories
+
+import (
+	"codeWithUmam/models"
+	"testing"
+	"time"
+)
+
+// batchRemaining mengembalikan sisa setiap batch produk, dengan kunci nomor batch.
+func batchRemaining(t *testing.T, repo *StockMovementRepositoryImpl, productID int) map[string]int {
+	batches, err := repo.GetBatches(productID, time.Now().UTC().Format("2006-01-02"))
+	if err != nil {
+		t.Fatalf("GetBatches failed: %v", err)
+	}
+	remaining := make(map[string]int)
+	for _, b := range batches {
+		remaining[b.BatchNumber] = b.RemainingQuantity
+	}
+	return remaining
+}
+
+// boolPtr dipakai untuk field opsional (nil = tidak diubah) seperti Product.TrackBatches.
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func TestTransactionRepository_CheckoutFEFO(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	repo := NewTransactionRepository(db, models.TaxConfig{}, models.CostingMethodAverage, models.BusinessDay{})
+	stockRepo := NewStockMovementRepository(db)
+	purchases := NewPurchaseOrderRepository(db)
+	if _, err := db.Exec("INSERT INTO categories (name, description) VALUES ('Susu', '')"); err != nil {
+		t.Fatalf("failed to seed category: %v", err)
+	}
+	supplier := &models.Supplier{Name: "Distributor Susu"}
+	if err := NewSupplierRepository(db).Create(supplier); err != nil {
+		t.Fatalf("failed to create supplier: %v", err)
+	}
+	susu := &models.Product{Name: "Susu UHT", Price: 6000, CategoryID: 1, TrackBatches: boolPtr(true)}
+	if err := NewProductRepository(db).Create(susu); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	order, err := purchases.Create(models.CreatePurchaseOrderRequest{
+		SupplierID: supplier.ID,
+		Items:      []models.PurchaseOrderItemRequest{{ProductID: susu.ID, Quantity: 13, UnitCost: 4000}},
+	})
+	if err != nil {
+		t.Fatalf("Create PO failed: %v", err)
+	}
+	itemID := order.Items[0].ID
+	if _, err := purchases.Receive(order.ID, models.ReceiveGoodsRequest{
+		Items: []models.ReceiveGoodsItemRequest{{PurchaseOrderItemID: itemID, Quantity: 1}},
+	}); err == nil {
+		t.Error("expected error when receiving a batch-tracked product without expiry_date")
+	}
+	if _, err := purchases.Receive(order.ID, models.ReceiveGoodsRequest{
+		Items: []models.ReceiveGoodsItemRequest{
+			{PurchaseOrderItemID: itemID, Quantity: 5, BatchNumber: "LAMA", ExpiryDate: "2099-01-10"},
+			{PurchaseOrderItemID: itemID, Quantity: 5, BatchNumber: "CEPAT", ExpiryDate: "2099-01-05"},
+			{PurchaseOrderItemID: itemID, Quantity: 3, BatchNumber: "BASI", ExpiryDate: "2000-01-01"},
+		},
+	}); err != nil {
+		t.Fatalf("Receive failed: %v", err)
+	}
+
+	// 6 pcs: 5 dari batch yang paling cepat kedaluwarsa, 1 dari batch berikutnya; batch basi tidak disentuh
+	trx, err := repo.CreateTransaction(models.CheckoutRequest{
+		Items:      []models.CheckoutItem{{ProductID: susu.ID, Quantity: 6}},
+		PaidAmount: 50000, PaymentMethod: "CASH",
+	}, CheckoutOptions{})
+	if err != nil {
+		t.Fatalf("CreateTransaction failed: %v", err)
+	}
+	if got := batchRemaining(t, stockRepo, susu.ID); got["CEPAT"] != 0 || got["LAMA"] != 4 || got["BASI"] != 3 {
+		t.Errorf("expected FEFO deduction, got %+v", got)
+	}
+
+	// Stok 7, tapi 3 di antaranya kedaluwarsa: 5 pcs ditolak
+	if _, err := repo.CreateTransaction(models.CheckoutRequest{
+		Items:      []models.CheckoutItem{{ProductID: susu.ID, Quantity: 5}},
+		PaidAmount: 50000, PaymentMethod: "CASH",
+	}, CheckoutOptions{}); err == nil {
+		t.Error("expected error when only expired batches are left")
+	}
+
+	// Refund kembali ke batch yang dijual, yang paling lama kedaluwarsanya lebih dulu
+	if _, err := repo.RefundTransaction(trx.ID, []models.RefundItem{{DetailID: trx.Details[0].ID, Quantity: 2}}, "salah beli", "admin"); err != nil {
+		t.Fatalf("RefundTransaction failed: %v", err)
+	}
+	if got := batchRemaining(t, stockRepo, susu.ID); got["CEPAT"] != 1 || got["LAMA"] != 5 {
+		t.Errorf("expected refund to go back to the sold batches, got %+v", got)
+	}
+
+	// Laporan: batch basi (sudah lewat) dan batch yang kedaluwarsa sampai 2099-01-06
+	categories, err := stockRepo.GetExpiringBatches(time.Now().UTC().Format("2006-01-02"), "2099-01-06")
+	if err != nil {
+		t.Fatalf("GetExpiringBatches failed: %v", err)
+	}
+	if len(categories) != 1 || categories[0].CategoryName != "Susu" || len(categories[0].Batches) != 2 || categories[0].Quantity != 4 {
+		t.Fatalf("unexpected expiring report: %+v", categories)
+	}
+	if b := categories[0].Batches[0]; b.BatchNumber != "BASI" || b.DaysLeft >= 0 || b.Value != 3*4000 {
+		t.Errorf("expected the expired batch first with negative days_left, got %+v", b)
+	}
+
+	// Koreksi stok turun (misal pemusnahan) mengambil batch basi lebih dulu
+	susu.Stock = 6
+	if err := NewProductRepository(db).Update(susu); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if got := batchRemaining(t, stockRepo, susu.ID); got["BASI"] != 0 || got["CEPAT"] != 1 || got["LAMA"] != 5 {
+		t.Errorf("expected the adjustment to remove the expired batch, got %+v", got)
+	}
+	if report, _ := stockRepo.CheckConsistency(); !report.Consistent {
+		t.Errorf("expected ledger and batches to be consistent, got %+v", report.Discrepancies)
+	}
+}
+
+func TestProductRepository_EnableBatchTracking(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	products := NewProductRepository(db)
+	roti := &models.Product{Name: "Roti", Price: 8000, Stock: 4}
+	if err := products.Create(roti); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	// Stok yang sudah ada menjadi satu batch tanpa tanggal kedaluwarsa
+	roti.TrackBatches = boolPtr(true)
+	if err := products.Update(roti); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	batches, err := NewStockMovementRepository(db).GetBatches(roti.ID, "2026-01-01")
+	if err != nil {
+		t.Fatalf("GetBatches failed: %v", err)
+	}
+	if len(batches) != 1 || batches[0].RemainingQuantity != 4 || batches[0].ExpiryDate != "" || batches[0].Expired {
+		t.Errorf("expected one opening batch of 4, got %+v", batches)
+	}
+	if report, _ := NewStockMovementRepository(db).CheckConsistency(); !report.Consistent {
+		t.Errorf("expected ledger and batches to be consistent, got %+v", report.Discrepancies)
+	}
+
+	// Update tanpa track_batches (client lama) tidak mematikan pencatatan batch
+	if err := products.Update(&models.Product{ID: roti.ID, Name: "Roti Tawar", Price: 9000, Stock: 4}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	batches, err = NewStockMovementRepository(db).GetBatches(roti.ID, "2026-01-01")
+	if err != nil {
+		t.Fatalf("GetBatches failed: %v", err)
+	}
+	if len(batches) != 1 || batches[0].RemainingQuantity != 4 {
+		t.Errorf("expected the batch to be kept, got %+v", batches)
+	}
+	var tracked bool
+	db.QueryRow("SELECT track_batches FROM products WHERE id = ?", roti.ID).Scan(&tracked)
+	if !tracked {
+		t.Error("expected batch tracking to stay on")
+	}
+
+	// Batch ikut terhapus bersama produknya
+	if err := products.Delete(roti.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	var left int
+	db.QueryRow("SELECT COUNT(*) FROM stock_batches WHERE product_id = ?", roti.ID).Scan(&left)
+	if left != 0 {
+		t.Errorf("expected batches to be deleted with the product, %d rows left", left)
+	}
+}
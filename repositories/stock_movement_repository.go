@@ -0,0 +1,218 @@
+package repositories
+
+import (
+	"codeWithUmam/models"
+	"database/sql"
+)
+
+// StockMovementRepositoryImpl membaca ledger stok (tabel stock_movements).
+// Penulisan ledger TIDAK lewat struct ini, tapi lewat recordStockMovement di dalam Database Transaction
+// milik proses yang mengubah stok (checkout, refund, edit produk, dst).
+type StockMovementRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewStockMovementRepository(db *sql.DB) *StockMovementRepositoryImpl {
+	return &StockMovementRepositoryImpl{db: db}
+}
+
+// GetByProduct mengambil semua pergerakan stok satu produk, urut dari yang paling lama,
+// lengkap dengan saldo berjalan (running balance) yang dihitung SQL window function dan batch-nya (produk ber-batch).
+func (r *StockMovementRepositoryImpl) GetByProduct(productID int) ([]models.StockMovement, error) {
+	rows, err := r.db.Query(`
+		SELECT sm.id, sm.product_id, sm.outlet_id, sm.type, sm.quantity, sm.unit_cost,
+			SUM(sm.quantity) OVER (ORDER BY sm.id ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW) AS balance,
+			COALESCE(sm.reference_type, ''), COALESCE(sm.reference_id, 0), COALESCE(sm.note, ''), sm.created_at,
+			COALESCE(sm.batch_id, 0), COALESCE(b.batch_number, ''), COALESCE(b.expiry_date, '')
+		FROM stock_movements sm
+		LEFT JOIN stock_batches b ON b.id = sm.batch_id
+		WHERE sm.product_id = ?
+		ORDER BY sm.id`, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	movements := []models.StockMovement{}
+	for rows.Next() {
+		var m models.StockMovement
+		if err := rows.Scan(&m.ID, &m.ProductID, &m.OutletID, &m.Type, &m.Quantity, &m.UnitCost, &m.Balance,
+			&m.ReferenceType, &m.ReferenceID, &m.Note, &m.CreatedAt, &m.BatchID, &m.BatchNumber, &m.ExpiryDate); err != nil {
+			return nil, err
+		}
+		movements = append(movements, m)
+	}
+	return movements, rows.Err()
+}
+
+// CheckConsistency menghitung ulang stok setiap produk dari ledger dan membandingkannya dengan products.stock.
+// Total stok semua outlet, dan untuk produk ber-batch total sisa batch, juga harus sama dengan products.stock.
+func (r *StockMovementRepositoryImpl) CheckConsistency() (*models.StockConsistencyReport, error) {
+	report := &models.StockConsistencyReport{Discrepancies: []models.StockDiscrepancy{}}
+
+	rows, err := r.db.Query(`
+		SELECT p.id, p.name, p.stock, COALESCE(SUM(sm.quantity), 0) AS ledger_stock,
+			CASE WHEN p.track_batches = 1
+				THEN COALESCE((SELECT SUM(b.remaining_quantity) FROM stock_batches b WHERE b.product_id = p.id), 0)
+				ELSE p.stock END AS batch_stock,
+			COALESCE((SELECT SUM(os.stock) FROM outlet_stock os WHERE os.product_id = p.id), 0) AS outlet_stock
+		FROM products p
+		LEFT JOIN stock_movements sm ON sm.product_id = p.id
+		GROUP BY p.id
+		ORDER BY p.id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var d models.StockDiscrepancy
+		var batchStock, outletStock int
+		if err := rows.Scan(&d.ProductID, &d.ProductName, &d.Stock, &d.LedgerStock, &batchStock, &outletStock); err != nil {
+			return nil, err
+		}
+		report.CheckedProducts++
+		if batchStock != d.Stock {
+			d.BatchStock = &batchStock
+		}
+		if outletStock != d.Stock {
+			d.OutletStock = &outletStock
+		}
+		if d.Stock != d.LedgerStock || d.BatchStock != nil || d.OutletStock != nil {
+			d.Difference = d.Stock - d.LedgerStock
+			report.Discrepancies = append(report.Discrepancies, d)
+		}
+	}
+	report.Consistent = len(report.Discrepancies) == 0
+	return report, rows.Err()
+}
+
+// GetBatches mengambil batch produk yang masih bersisa per outlet, urut FEFO (batch tanpa tanggal kedaluwarsa paling akhir).
+// Batch yang kedaluwarsa sebelum today (YYYY-MM-DD) ditandai Expired.
+func (r *StockMovementRepositoryImpl) GetBatches(productID int, today string) ([]models.StockBatch, error) {
+	rows, err := r.db.Query(`
+		SELECT id, product_id, outlet_id, COALESCE(batch_number, ''), COALESCE(expiry_date, ''), quantity, remaining_quantity, created_at
+		FROM stock_batches
+		WHERE product_id = ? AND remaining_quantity > 0
+		ORDER BY outlet_id, expiry_date IS NULL, expiry_date, id`, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	batches := []models.StockBatch{}
+	for rows.Next() {
+		var b models.StockBatch
+		if err := rows.Scan(&b.ID, &b.ProductID, &b.OutletID, &b.BatchNumber, &b.ExpiryDate, &b.Quantity, &b.RemainingQuantity, &b.CreatedAt); err != nil {
+			return nil, err
+		}
+		b.Expired = b.ExpiryDate != "" && b.ExpiryDate < today
+		batches = append(batches, b)
+	}
+	return batches, rows.Err()
+}
+
+// GetExpiringBatches mengambil sisa batch yang kedaluwarsa paling lambat until (termasuk yang sudah lewat),
+// dikelompokkan per kategori (urut nama kategori), dan di dalamnya urut tanggal kedaluwarsa.
+// Nilai persediaan = sisa x harga pokok rata-rata produk saat ini.
+func (r *StockMovementRepositoryImpl) GetExpiringBatches(today, until string) ([]models.ExpiringStockCategory, error) {
+	rows, err := r.db.Query(`
+		SELECT COALESCE(p.category_id, 0), COALESCE(c.name, ''), b.id, b.outlet_id, p.id, p.name, COALESCE(b.batch_number, ''), b.expiry_date,
+			CAST(julianday(b.expiry_date) - julianday(?) AS INTEGER), b.remaining_quantity, b.remaining_quantity * p.cost_price
+		FROM stock_batches b
+		JOIN products p ON p.id = b.product_id
+		LEFT JOIN categories c ON c.id = p.category_id
+		WHERE p.track_batches = 1 AND b.remaining_quantity > 0 AND b.expiry_date IS NOT NULL AND b.expiry_date <= ?
+		ORDER BY COALESCE(c.name, ''), COALESCE(p.category_id, 0), b.expiry_date, p.name, b.id`, today, until)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := []models.ExpiringStockCategory{}
+	for rows.Next() {
+		var categoryID int
+		var categoryName string
+		var b models.ExpiringBatch
+		if err := rows.Scan(&categoryID, &categoryName, &b.BatchID, &b.OutletID, &b.ProductID, &b.ProductName, &b.BatchNumber, &b.ExpiryDate,
+			&b.DaysLeft, &b.Quantity, &b.Value); err != nil {
+			return nil, err
+		}
+		if n := len(categories); n == 0 || categories[n-1].CategoryID != categoryID {
+			categories = append(categories, models.ExpiringStockCategory{CategoryID: categoryID, CategoryName: categoryName})
+		}
+		c := &categories[len(categories)-1]
+		c.Batches = append(c.Batches, b)
+		c.Quantity += b.Quantity
+		c.Value += b.Value
+	}
+	return categories, rows.Err()
+}
+
+// recordStockMovement mengubah stok outlet m.OutletID (0 = outlet utama) dan total products.stock sebesar m.Quantity
+// (positif = masuk, negatif = keluar) dan mencatatnya di stock_movements. Stok outlet tidak boleh menjadi minus. Semuanya dijalankan di Database Transaction tx yang sama,
+// jadi tidak mungkin stok berubah tanpa jejak di ledger (atau sebaliknya).
+// Harga pokok (rata-rata & lapisan FIFO) ikut diperbarui; m.UnitCost adalah harga beli untuk barang masuk.
+// Nilai persediaan yang berpindah dikembalikan, dipakai checkout untuk mencatat HPP.
+func recordStockMovement(tx *sql.Tx, m models.StockMovement) (stockCost, error) {
+	if m.Quantity == 0 {
+		return stockCost{}, nil
+	}
+	if m.OutletID == 0 {
+		m.OutletID = models.DefaultOutletID
+	}
+	if m.Quantity < 0 {
+		var name string
+		var outletStock int
+		err := tx.QueryRow(`
+			SELECT p.name, COALESCE(os.stock, 0)
+			FROM products p
+			LEFT JOIN outlet_stock os ON os.product_id = p.id AND os.outlet_id = ?
+			WHERE p.id = ?`, m.OutletID, m.ProductID).Scan(&name, &outletStock)
+		if err == sql.ErrNoRows {
+			return stockCost{}, ErrNotFound
+		}
+		if err != nil {
+			return stockCost{}, err
+		}
+		if outletStock+m.Quantity < 0 {
+			return stockCost{}, NewValidationError("stok %s di outlet %d tidak cukup (sisa: %d, keluar: %d)", name, m.OutletID, outletStock, -m.Quantity)
+		}
+	}
+
+	cost, unitCost, err := applyStockCost(tx, m.ProductID, m.Quantity, m.UnitCost)
+	if err != nil {
+		return stockCost{}, err
+	}
+	portions, err := applyBatchMovement(tx, m)
+	if err != nil {
+		return stockCost{}, err
+	}
+	if len(portions) == 0 {
+		portions = []batchPortion{{quantity: m.Quantity}}
+	}
+
+	if _, err := tx.Exec("UPDATE products SET stock = stock + ? WHERE id = ?", m.Quantity, m.ProductID); err != nil {
+		return stockCost{}, err
+	}
+	_, err = tx.Exec(`
+		INSERT INTO outlet_stock (outlet_id, product_id, stock) VALUES (?, ?, ?)
+		ON CONFLICT(outlet_id, product_id) DO UPDATE SET stock = stock + excluded.stock`, m.OutletID, m.ProductID, m.Quantity)
+	if err != nil {
+		return stockCost{}, err
+	}
+
+	var referenceType, referenceID interface{} // NULL jika tidak ada dokumen sumber
+	if m.ReferenceType != "" {
+		referenceType, referenceID = m.ReferenceType, m.ReferenceID
+	}
+	// Produk ber-batch: satu baris ledger per batch yang berubah
+	for _, p := range portions {
+		_, err = tx.Exec("INSERT INTO stock_movements (product_id, outlet_id, type, quantity, unit_cost, reference_type, reference_id, note, batch_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+			m.ProductID, m.OutletID, m.Type, p.quantity, unitCost, referenceType, referenceID, m.Note, nullIfZero(p.batchID))
+		if err != nil {
+			return stockCost{}, err
+		}
+	}
+	return cost, nil
+}
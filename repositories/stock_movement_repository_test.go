@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"codeWithUmam/models"
+	"testing"
+)
+
+func TestStockMovementRepository_LedgerFollowsEveryStockChange(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	trxRepo := NewTransactionRepository(db, models.TaxConfig{}, models.CostingMethodAverage, models.BusinessDay{})
+	productRepo := NewProductRepository(db)
+	stockRepo := NewStockMovementRepository(db)
+	productID := seedProduct(t, db, "Gula 1kg", 15000, 10)
+
+	// Jual 3, refund 1, lalu stok diedit manual menjadi 20
+	trx, err := trxRepo.CreateTransaction(models.CheckoutRequest{Items: []models.CheckoutItem{{ProductID: productID, Quantity: 3}}, PaidAmount: 45000, PaymentMethod: "CASH"}, CheckoutOptions{})
+	if err != nil {
+		t.Fatalf("CreateTransaction failed: %v", err)
+	}
+	if _, err := trxRepo.RefundTransaction(trx.ID, []models.RefundItem{{DetailID: trx.Details[0].ID, Quantity: 1}}, "salah ambil", "kasir"); err != nil {
+		t.Fatalf("RefundTransaction failed: %v", err)
+	}
+	if err := productRepo.Update(&models.Product{ID: productID, Name: "Gula 1kg", Price: 15000, Stock: 20}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	movements, err := stockRepo.GetByProduct(productID)
+	if err != nil {
+		t.Fatalf("GetByProduct failed: %v", err)
+	}
+	expected := []struct {
+		typ       string
+		qty, bal  int
+		reference int
+	}{
+		{models.StockMovementAdjustment, 10, 10, productID},
+		{models.StockMovementSale, -3, 7, trx.ID},
+		{models.StockMovementRefund, 1, 8, trx.ID},
+		{models.StockMovementAdjustment, 12, 20, productID},
+	}
+	if len(movements) != len(expected) {
+		t.Fatalf("expected %d movements, got %d: %+v", len(expected), len(movements), movements)
+	}
+	for i, e := range expected {
+		m := movements[i]
+		if m.Type != e.typ || m.Quantity != e.qty || m.Balance != e.bal || m.ReferenceID != e.reference {
+			t.Errorf("movement %d: expected %+v, got %+v", i, e, m)
+		}
+	}
+
+	report, err := stockRepo.CheckConsistency()
+	if err != nil {
+		t.Fatalf("CheckConsistency failed: %v", err)
+	}
+	if !report.Consistent || report.CheckedProducts != 1 {
+		t.Errorf("expected consistent stock, got %+v", report)
+	}
+
+	// Perubahan stok di luar ledger harus terdeteksi
+	if _, err := db.Exec("UPDATE products SET stock = 25 WHERE id = ?", productID); err != nil {
+		t.Fatalf("failed to tamper stock: %v", err)
+	}
+	report, err = stockRepo.CheckConsistency()
+	if err != nil {
+		t.Fatalf("CheckConsistency failed: %v", err)
+	}
+	if report.Consistent || len(report.Discrepancies) != 1 || report.Discrepancies[0].Difference != 5 {
+		t.Errorf("expected discrepancy of 5, got %+v", report)
+	}
+}
@@ -0,0 +1,350 @@
+package repositories
+
+import (
+	"codeWithUmam/models"
+	"database/sql"
+	"sort"
+	"time"
+)
+
+// StockOpnameRepositoryImpl mengelola sesi stok opname (hitung fisik).
+// Struct ini mengimplementasikan interface StockOpnameRepository dari package repositories.
+//
+// Setiap sesi menghitung stok satu outlet. Sesi stok opname TIDAK mengunci stok: checkout tetap jalan selama penghitungan.
+// Karena itu setiap kali hitungan dikirim, stok sistem outlet saat itu disimpan sebagai expected_stock,
+// dan selisih saat approval = hitungan - expected_stock, yang lalu ditambahkan ke stok terkini.
+// Penjualan yang terjadi selama penghitungan tetap terpotong dengan benar.
+type StockOpnameRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewStockOpnameRepository(db *sql.DB) *StockOpnameRepositoryImpl {
+	return &StockOpnameRepositoryImpl{db: db}
+}
+
+// Open membuka sesi baru dan men-snapshot stok produk di satu outlet (semua produk atau satu kategori).
+// Hanya boleh ada satu sesi OPEN per outlet dalam satu waktu.
+func (r *StockOpnameRepositoryImpl) Open(req models.OpenStockOpnameRequest) (*models.StockOpname, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	outletID := req.OutletID
+	if outletID == 0 {
+		outletID = models.DefaultOutletID
+	}
+	if err := requireActiveOutlet(tx, outletID); err != nil {
+		return nil, err
+	}
+
+	var openID int
+	err = tx.QueryRow("SELECT id FROM stock_opnames WHERE status = ? AND outlet_id = ?", models.StockOpnameStatusOpen, outletID).Scan(&openID)
+	if err == nil {
+		return nil, NewValidationError("stok opname #%d masih berjalan, selesaikan atau batalkan dulu", openID)
+	}
+	if err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	res, err := tx.Exec("INSERT INTO stock_opnames (status, outlet_id, category_id, note, opened_by) VALUES (?, ?, ?, ?, ?)",
+		models.StockOpnameStatusOpen, outletID, req.CategoryID, req.Note, req.OpenedBy)
+	if err != nil {
+		return nil, err
+	}
+	opnameID, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	// Snapshot langsung dengan INSERT ... SELECT supaya semua produk diambil pada titik waktu yang sama.
+	// Produk yang punya varian dihitung per varian, jadi baris induknya (stok selalu 0) dilewati; begitu juga paket (stok ada di komponen).
+	res, err = tx.Exec(`
+		INSERT INTO stock_opname_items (stock_opname_id, product_id, product_name, category_id, category_name, unit_price, snapshot_stock, expected_stock)
+		SELECT ?, p.id, p.name, COALESCE(p.category_id, 0), COALESCE(c.name, ''), p.price, COALESCE(os.stock, 0), COALESCE(os.stock, 0)
+		FROM products p
+		LEFT JOIN categories c ON p.category_id = c.id
+		LEFT JOIN outlet_stock os ON os.product_id = p.id AND os.outlet_id = ?
+		WHERE (? = 0 OR p.category_id = ?)
+			AND NOT EXISTS (SELECT 1 FROM products v WHERE v.parent_id = p.id)
+			AND NOT EXISTS (SELECT 1 FROM product_components pc WHERE pc.product_id = p.id)`, opnameID, outletID, req.CategoryID, req.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	if affected, err := res.RowsAffected(); err != nil {
+		return nil, err
+	} else if affected == 0 {
+		return nil, NewValidationError("tidak ada produk untuk dihitung")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.GetByID(int(opnameID))
+}
+
+// GetAll mengambil semua sesi stok opname beserta ringkasan selisihnya (tanpa rincian per produk).
+func (r *StockOpnameRepositoryImpl) GetAll() ([]models.StockOpname, error) {
+	rows, err := r.db.Query("SELECT id FROM stock_opnames ORDER BY id DESC")
+	if err != nil {
+		return nil, err
+	}
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+
+	opnames := []models.StockOpname{}
+	for _, id := range ids {
+		op, err := r.GetByID(id)
+		if err != nil {
+			return nil, err
+		}
+		op.Items = nil
+		opnames = append(opnames, *op)
+	}
+	return opnames, nil
+}
+
+// GetByID mengambil satu sesi lengkap dengan hitungan, selisih per produk dan per kategori.
+func (r *StockOpnameRepositoryImpl) GetByID(id int) (*models.StockOpname, error) {
+	var op models.StockOpname
+	err := r.db.QueryRow(`
+		SELECT id, status, outlet_id, category_id, COALESCE(note, ''), COALESCE(opened_by, ''), COALESCE(approved_by, ''), created_at, approved_at
+		FROM stock_opnames WHERE id = ?`, id).
+		Scan(&op.ID, &op.Status, &op.OutletID, &op.CategoryID, &op.Note, &op.OpenedBy, &op.ApprovedBy, &op.CreatedAt, &op.ApprovedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(`
+		SELECT id, stock_opname_id, product_id, product_name, category_id, category_name, unit_price, snapshot_stock, expected_stock
+		FROM stock_opname_items WHERE stock_opname_id = ? ORDER BY category_name, product_name`, id)
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[int]int) // product ID -> posisi di op.Items
+	for rows.Next() {
+		var item models.StockOpnameItem
+		if err := rows.Scan(&item.ID, &item.StockOpnameID, &item.ProductID, &item.ProductName, &item.CategoryID, &item.CategoryName,
+			&item.UnitPrice, &item.SnapshotStock, &item.ExpectedStock); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		index[item.ProductID] = len(op.Items)
+		op.Items = append(op.Items, item)
+	}
+	rows.Close()
+
+	countRows, err := r.db.Query(`
+		SELECT product_id, quantity, counted_by, counted_at
+		FROM stock_opname_counts WHERE stock_opname_id = ? ORDER BY id`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer countRows.Close()
+	for countRows.Next() {
+		var c models.StockOpnameCount
+		if err := countRows.Scan(&c.ProductID, &c.Quantity, &c.CountedBy, &c.CountedAt); err != nil {
+			return nil, err
+		}
+		if i, ok := index[c.ProductID]; ok {
+			op.Items[i].Counts = append(op.Items[i].Counts, c)
+		}
+	}
+	if err := countRows.Err(); err != nil {
+		return nil, err
+	}
+
+	summarizeStockOpname(&op)
+	return &op, nil
+}
+
+// SubmitCounts menyimpan hasil hitungan satu kasir.
+// Stok sistem outlet sesi ini disimpan sebagai expected_stock produk tersebut (lihat komentar di StockOpnameRepositoryImpl).
+func (r *StockOpnameRepositoryImpl) SubmitCounts(id int, req models.SubmitStockCountRequest) (*models.StockOpname, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := requireOpenStockOpname(tx, id); err != nil {
+		return nil, err
+	}
+	var outletID int
+	if err := tx.QueryRow("SELECT outlet_id FROM stock_opnames WHERE id = ?", id).Scan(&outletID); err != nil {
+		return nil, err
+	}
+
+	for _, item := range req.Items {
+		// Produk yang sudah dihapus tetap memakai expected_stock terakhir
+		res, err := tx.Exec(`
+			UPDATE stock_opname_items
+			SET expected_stock = COALESCE((
+				SELECT COALESCE(os.stock, 0) FROM products p
+				LEFT JOIN outlet_stock os ON os.product_id = p.id AND os.outlet_id = ?
+				WHERE p.id = ?), expected_stock)
+			WHERE stock_opname_id = ? AND product_id = ?`, outletID, item.ProductID, id, item.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		if affected, err := res.RowsAffected(); err != nil {
+			return nil, err
+		} else if affected == 0 {
+			return nil, NewValidationError("product id %d tidak termasuk di stok opname #%d", item.ProductID, id)
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO stock_opname_counts (stock_opname_id, product_id, quantity, counted_by) VALUES (?, ?, ?, ?)
+			ON CONFLICT(stock_opname_id, product_id, counted_by) DO UPDATE SET quantity = excluded.quantity, counted_at = CURRENT_TIMESTAMP`,
+			id, item.ProductID, item.Quantity, req.CountedBy)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.GetByID(id)
+}
+
+// Approve memposting selisih setiap produk yang sudah dihitung sebagai pergerakan ADJUSTMENT,
+// sehingga stok sistem sama dengan hasil hitung fisik. Produk yang belum dihitung tidak diubah.
+func (r *StockOpnameRepositoryImpl) Approve(id int, approvedBy string) (*models.StockOpname, error) {
+	op, err := r.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Status dicek ulang di dalam Database Transaction agar dua approval bersamaan tidak memposting dua kali
+	if err := requireOpenStockOpname(tx, id); err != nil {
+		return nil, err
+	}
+
+	for _, item := range op.Items {
+		if item.CountedQuantity == nil {
+			continue
+		}
+		_, err := recordStockMovement(tx, models.StockMovement{
+			ProductID:     item.ProductID,
+			OutletID:      op.OutletID,
+			Type:          models.StockMovementAdjustment,
+			Quantity:      item.Variance,
+			ReferenceType: models.StockReferenceStockOpname,
+			ReferenceID:   id,
+			Note:          "Selisih stok opname",
+		})
+		if err != nil && err != ErrNotFound { // Produk yang sudah dihapus dilewati
+			return nil, err
+		}
+	}
+
+	_, err = tx.Exec("UPDATE stock_opnames SET status = ?, approved_by = ?, approved_at = ? WHERE id = ?",
+		models.StockOpnameStatusApproved, approvedBy, time.Now().UTC(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.GetByID(id)
+}
+
+// Cancel membatalkan sesi tanpa mengubah stok.
+func (r *StockOpnameRepositoryImpl) Cancel(id int) (*models.StockOpname, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := requireOpenStockOpname(tx, id); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec("UPDATE stock_opnames SET status = ? WHERE id = ?", models.StockOpnameStatusCancelled, id); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.GetByID(id)
+}
+
+// requireOpenStockOpname memastikan sesi ada dan masih OPEN.
+func requireOpenStockOpname(tx *sql.Tx, id int) error {
+	var status string
+	err := tx.QueryRow("SELECT status FROM stock_opnames WHERE id = ?", id).Scan(&status)
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if status != models.StockOpnameStatusOpen {
+		return NewValidationError("stok opname #%d sudah %s", id, status)
+	}
+	return nil
+}
+
+// summarizeStockOpname menghitung hitungan total, selisih per produk, per kategori, dan total sesi.
+// Fungsi ini murni (tidak menyentuh database) agar mudah di-test.
+func summarizeStockOpname(op *models.StockOpname) {
+	op.TotalProducts = len(op.Items)
+	op.CountedProducts, op.TotalVariance, op.VarianceValue = 0, 0, 0
+
+	categories := make(map[int]*models.StockOpnameCategoryVariance)
+	for i := range op.Items {
+		item := &op.Items[i]
+		item.CountedQuantity, item.Variance, item.VarianceValue = nil, 0, 0
+		if len(item.Counts) == 0 {
+			continue
+		}
+
+		counted := 0
+		for _, c := range item.Counts {
+			counted += c.Quantity
+		}
+		item.CountedQuantity = &counted
+		item.Variance = counted - item.ExpectedStock
+		item.VarianceValue = item.Variance * item.UnitPrice
+
+		op.CountedProducts++
+		op.TotalVariance += item.Variance
+		op.VarianceValue += item.VarianceValue
+
+		cat, ok := categories[item.CategoryID]
+		if !ok {
+			cat = &models.StockOpnameCategoryVariance{CategoryID: item.CategoryID, CategoryName: item.CategoryName}
+			categories[item.CategoryID] = cat
+		}
+		cat.CountedProducts++
+		cat.Variance += item.Variance
+		cat.VarianceValue += item.VarianceValue
+	}
+
+	op.Categories = make([]models.StockOpnameCategoryVariance, 0, len(categories))
+	for _, cat := range categories {
+		op.Categories = append(op.Categories, *cat)
+	}
+	sort.Slice(op.Categories, func(i, j int) bool { return op.Categories[i].CategoryName < op.Categories[j].CategoryName })
+}
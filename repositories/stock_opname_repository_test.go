@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"codeWithUmam/models"
+	"errors"
+	"testing"
+)
+
+func TestStockOpnameRepository_CountWhileSelling(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	repo := NewStockOpnameRepository(db)
+	trxRepo := NewTransactionRepository(db, models.TaxConfig{}, models.CostingMethodAverage, models.BusinessDay{})
+	sabun := seedProduct(t, db, "Sabun", 4000, 20)
+	sampo := seedProduct(t, db, "Sampo", 12000, 10)
+
+	op, err := repo.Open(models.OpenStockOpnameRequest{OpenedBy: "supervisor"})
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if op.TotalProducts != 2 || op.CountedProducts != 0 {
+		t.Fatalf("unexpected snapshot: %+v", op)
+	}
+	var validationErr *ValidationError
+	if _, err := repo.Open(models.OpenStockOpnameRequest{}); !errors.As(err, &validationErr) {
+		t.Errorf("expected ValidationError opening a second session, got %v", err)
+	}
+
+	// Checkout tetap jalan selama penghitungan: 5 sabun terjual sebelum dihitung
+	if _, err := trxRepo.CreateTransaction(models.CheckoutRequest{Items: []models.CheckoutItem{{ProductID: sabun, Quantity: 5}}, PaidAmount: 20000, PaymentMethod: "CASH"}, CheckoutOptions{}); err != nil {
+		t.Fatalf("checkout during count failed: %v", err)
+	}
+
+	// Dua kasir menghitung sabun di dua rak (8 + 6 = 14, sistem 15 -> kurang 1).
+	// Kasir B mengirim ulang hitungannya; yang terakhir yang dipakai.
+	submissions := []models.SubmitStockCountRequest{
+		{CountedBy: "kasir-a", Items: []models.StockCountItem{{ProductID: sabun, Quantity: 8}, {ProductID: sampo, Quantity: 12}}},
+		{CountedBy: "kasir-b", Items: []models.StockCountItem{{ProductID: sabun, Quantity: 7}}},
+		{CountedBy: "kasir-b", Items: []models.StockCountItem{{ProductID: sabun, Quantity: 6}}},
+	}
+	for _, s := range submissions {
+		if op, err = repo.SubmitCounts(op.ID, s); err != nil {
+			t.Fatalf("SubmitCounts failed: %v", err)
+		}
+	}
+
+	variances := map[int]int{}
+	for _, item := range op.Items {
+		variances[item.ProductID] = item.Variance
+	}
+	if variances[sabun] != -1 || variances[sampo] != 2 {
+		t.Errorf("expected variances sabun -1 / sampo +2, got %+v", variances)
+	}
+	if op.TotalVariance != 1 || op.VarianceValue != -4000+24000 {
+		t.Errorf("unexpected totals: variance %d value %d", op.TotalVariance, op.VarianceValue)
+	}
+
+	// Penjualan lagi setelah dihitung, sebelum approval: selisih tetap ditambahkan ke stok terkini
+	if _, err := trxRepo.CreateTransaction(models.CheckoutRequest{Items: []models.CheckoutItem{{ProductID: sabun, Quantity: 2}}, PaidAmount: 8000, PaymentMethod: "CASH"}, CheckoutOptions{}); err != nil {
+		t.Fatalf("checkout failed: %v", err)
+	}
+
+	op, err = repo.Approve(op.ID, "manager")
+	if err != nil {
+		t.Fatalf("Approve failed: %v", err)
+	}
+	if op.Status != models.StockOpnameStatusApproved {
+		t.Errorf("expected APPROVED, got %s", op.Status)
+	}
+	if got := productStock(t, db, sabun); got != 12 {
+		t.Errorf("expected sabun stock 12 (14 counted - 2 sold after count), got %d", got)
+	}
+	if got := productStock(t, db, sampo); got != 12 {
+		t.Errorf("expected sampo stock 12, got %d", got)
+	}
+
+	if _, err := repo.Approve(op.ID, "manager"); !errors.As(err, &validationErr) {
+		t.Errorf("expected ValidationError approving twice, got %v", err)
+	}
+	report, err := NewStockMovementRepository(db).CheckConsistency()
+	if err != nil || !report.Consistent {
+		t.Errorf("expected stock to match ledger, got %+v (%v)", report, err)
+	}
+}
@@ -0,0 +1,338 @@
+package repositories
+
+import (
+	"codeWithUmam/models"
+	"database/sql"
+	"math"
+	"time"
+)
+
+// StockTransferRepositoryImpl mengelola dokumen transfer stok antar outlet.
+// Struct ini mengimplementasikan interface StockTransferRepository dari package repositories.
+//
+// Alur stok: saat dibuat, barang keluar dari outlet asal sebagai pergerakan TRANSFER negatif (status IN_TRANSIT).
+// Saat diterima, barang masuk ke outlet tujuan sebagai TRANSFER positif; saat dibatalkan, kembali ke outlet asal.
+// Selama di perjalanan barangnya tidak tercatat di stok outlet mana pun, sehingga products.stock ikut berkurang.
+type StockTransferRepositoryImpl struct {
+	db            *sql.DB
+	costingMethod string // Metode HPP untuk menilai barang yang dikirim: models.CostingMethodAverage atau models.CostingMethodFIFO
+}
+
+func NewStockTransferRepository(db *sql.DB, costingMethod string) *StockTransferRepositoryImpl {
+	return &StockTransferRepositoryImpl{db: db, costingMethod: costingMethod}
+}
+
+// stockTransferColumns adalah kolom header transfer yang dibaca oleh scanStockTransfer (alias st, outlet asal fo, outlet tujuan tt).
+const stockTransferColumns = `st.id, st.from_outlet_id, fo.name, st.to_outlet_id, tt.name, st.status, COALESCE(st.note, ''),
+	COALESCE(st.sent_by, ''), COALESCE(st.received_by, ''), st.created_at, st.received_at, st.cancelled_at`
+
+const stockTransferFrom = `stock_transfers st
+	JOIN outlets fo ON fo.id = st.from_outlet_id
+	JOIN outlets tt ON tt.id = st.to_outlet_id`
+
+// scanStockTransfer membaca satu baris hasil query `SELECT stockTransferColumns ...`.
+func scanStockTransfer(row interface{ Scan(...interface{}) error }) (models.StockTransfer, error) {
+	var t models.StockTransfer
+	err := row.Scan(&t.ID, &t.FromOutletID, &t.FromOutletName, &t.ToOutletID, &t.ToOutletName, &t.Status, &t.Note,
+		&t.SentBy, &t.ReceivedBy, &t.CreatedAt, &t.ReceivedAt, &t.CancelledAt)
+	return t, err
+}
+
+// GetAll mengambil daftar transfer (tanpa item), opsional difilter outlet (sebagai asal atau tujuan) dan status.
+func (r *StockTransferRepositoryImpl) GetAll(outletID int, status string) ([]models.StockTransfer, error) {
+	query := "SELECT " + stockTransferColumns + " FROM " + stockTransferFrom + " WHERE 1 = 1"
+	args := []interface{}{}
+	if outletID > 0 {
+		query += " AND (st.from_outlet_id = ? OR st.to_outlet_id = ?)"
+		args = append(args, outletID, outletID)
+	}
+	if status != "" {
+		query += " AND st.status = ?"
+		args = append(args, status)
+	}
+	query += " ORDER BY st.id DESC"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transfers := []models.StockTransfer{}
+	for rows.Next() {
+		t, err := scanStockTransfer(rows)
+		if err != nil {
+			return nil, err
+		}
+		transfers = append(transfers, t)
+	}
+	return transfers, rows.Err()
+}
+
+// GetByID mengambil satu transfer lengkap dengan item-itemnya.
+func (r *StockTransferRepositoryImpl) GetByID(id int) (*models.StockTransfer, error) {
+	t, err := scanStockTransfer(r.db.QueryRow("SELECT "+stockTransferColumns+" FROM "+stockTransferFrom+" WHERE st.id = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(`
+		SELECT id, product_id, product_name, quantity, unit_cost
+		FROM stock_transfer_items WHERE stock_transfer_id = ? ORDER BY id`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	t.Items = []models.StockTransferItem{}
+	for rows.Next() {
+		var item models.StockTransferItem
+		if err := rows.Scan(&item.ID, &item.ProductID, &item.ProductName, &item.Quantity, &item.UnitCost); err != nil {
+			return nil, err
+		}
+		t.Items = append(t.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// Create membuat dokumen transfer dan langsung mengeluarkan stoknya dari outlet asal, dalam satu Database Transaction.
+// Stok di outlet asal harus cukup; harga pokok saat keluar disimpan per item supaya nilainya terbawa ke outlet tujuan.
+func (r *StockTransferRepositoryImpl) Create(req models.CreateStockTransferRequest) (*models.StockTransfer, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Outlet asal boleh sudah nonaktif (misal memindahkan sisa stok outlet yang tutup); outlet tujuan harus aktif
+	var fromExists int
+	if err := tx.QueryRow("SELECT COUNT(id) FROM outlets WHERE id = ?", req.FromOutletID).Scan(&fromExists); err != nil {
+		return nil, err
+	}
+	if fromExists == 0 {
+		return nil, NewValidationError("outlet %d tidak ditemukan", req.FromOutletID)
+	}
+	if err := requireActiveOutlet(tx, req.ToOutletID); err != nil {
+		return nil, err
+	}
+
+	res, err := tx.Exec("INSERT INTO stock_transfers (from_outlet_id, to_outlet_id, status, note, sent_by) VALUES (?, ?, ?, ?, ?)",
+		req.FromOutletID, req.ToOutletID, models.StockTransferStatusInTransit, nullIfEmpty(req.Note), nullIfEmpty(req.SentBy))
+	if err != nil {
+		return nil, err
+	}
+	transferID, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range req.Items {
+		var productName string
+		var stock, variantCount, componentCount int
+		err := tx.QueryRow(`
+			SELECT p.name, `+outletStockColumn+`,
+				(SELECT COUNT(*) FROM products v WHERE v.parent_id = p.id), (SELECT COUNT(*) FROM product_components pc WHERE pc.product_id = p.id)
+			FROM products p WHERE p.id = ?`, req.FromOutletID, item.ProductID).Scan(&productName, &stock, &variantCount, &componentCount)
+		if err == sql.ErrNoRows {
+			return nil, NewValidationError("product id %d tidak ditemukan", item.ProductID)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if variantCount > 0 {
+			return nil, NewValidationError("produk %s punya varian, kirim per varian", productName)
+		}
+		if componentCount > 0 {
+			return nil, NewValidationError("produk %s adalah paket, kirim komponennya", productName)
+		}
+		if stock < item.Quantity {
+			return nil, NewValidationError("stok %s di outlet asal tidak cukup (sisa: %d)", productName, stock)
+		}
+
+		cost, err := recordStockMovement(tx, models.StockMovement{
+			ProductID:     item.ProductID,
+			OutletID:      req.FromOutletID,
+			Type:          models.StockMovementTransfer,
+			Quantity:      -item.Quantity,
+			ReferenceType: models.StockReferenceTransfer,
+			ReferenceID:   int(transferID),
+			Note:          "Transfer keluar",
+		})
+		if err != nil {
+			return nil, err
+		}
+		unitCost := int(math.Round(float64(cost.forMethod(r.costingMethod)) / float64(item.Quantity)))
+
+		_, err = tx.Exec("INSERT INTO stock_transfer_items (stock_transfer_id, product_id, product_name, quantity, unit_cost) VALUES (?, ?, ?, ?, ?)",
+			transferID, item.ProductID, productName, item.Quantity, unitCost)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.GetByID(int(transferID))
+}
+
+// Receive mencatat transfer sudah sampai: seluruh barangnya masuk ke stok outlet tujuan.
+// Barang ber-batch masuk sebagai batch baru di outlet tujuan dengan nomor lot & tanggal kedaluwarsa yang sama.
+func (r *StockTransferRepositoryImpl) Receive(id int, receivedBy string) (*models.StockTransfer, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	t, err := requireInTransitTransfer(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireActiveOutlet(tx, t.ToOutletID); err != nil {
+		return nil, err
+	}
+
+	portions, err := transferOutPortions(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range portions {
+		_, err := recordStockMovement(tx, models.StockMovement{
+			ProductID:     p.productID,
+			OutletID:      t.ToOutletID,
+			Type:          models.StockMovementTransfer,
+			Quantity:      p.quantity,
+			UnitCost:      p.unitCost,
+			ReferenceType: models.StockReferenceTransfer,
+			ReferenceID:   id,
+			Note:          "Transfer masuk",
+			BatchNumber:   p.batchNumber,
+			ExpiryDate:    p.expiryDate,
+		})
+		if err == ErrNotFound {
+			return nil, NewValidationError("product id %d sudah dihapus, transfer tidak bisa diterima", p.productID)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = tx.Exec("UPDATE stock_transfers SET status = ?, received_by = ?, received_at = ? WHERE id = ?",
+		models.StockTransferStatusReceived, nullIfEmpty(receivedBy), time.Now().UTC(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.GetByID(id)
+}
+
+// Cancel membatalkan transfer yang masih di perjalanan: barangnya kembali ke outlet asal (ke batch asalnya).
+func (r *StockTransferRepositoryImpl) Cancel(id int) (*models.StockTransfer, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	t, err := requireInTransitTransfer(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	portions, err := transferOutPortions(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range portions {
+		_, err := recordStockMovement(tx, models.StockMovement{
+			ProductID:     p.productID,
+			OutletID:      t.FromOutletID,
+			Type:          models.StockMovementTransfer,
+			Quantity:      p.quantity,
+			UnitCost:      p.unitCost,
+			ReferenceType: models.StockReferenceTransfer,
+			ReferenceID:   id,
+			Note:          "Transfer dibatalkan",
+			BatchID:       p.batchID,
+		})
+		if err != nil && err != ErrNotFound { // Produk yang sudah dihapus dilewati
+			return nil, err
+		}
+	}
+
+	_, err = tx.Exec("UPDATE stock_transfers SET status = ?, cancelled_at = ? WHERE id = ?",
+		models.StockTransferStatusCancelled, time.Now().UTC(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.GetByID(id)
+}
+
+// requireInTransitTransfer memastikan transfer ada dan masih IN_TRANSIT, lalu mengembalikan outlet asal & tujuannya.
+func requireInTransitTransfer(tx *sql.Tx, id int) (models.StockTransfer, error) {
+	t := models.StockTransfer{ID: id}
+	err := tx.QueryRow("SELECT from_outlet_id, to_outlet_id, status FROM stock_transfers WHERE id = ?", id).
+		Scan(&t.FromOutletID, &t.ToOutletID, &t.Status)
+	if err == sql.ErrNoRows {
+		return t, ErrNotFound
+	}
+	if err != nil {
+		return t, err
+	}
+	if t.Status != models.StockTransferStatusInTransit {
+		return t, NewValidationError("transfer stok #%d sudah %s", id, t.Status)
+	}
+	return t, nil
+}
+
+// transferPortion adalah sebagian barang transfer yang keluar dari satu batch di outlet asal
+// (batchID 0 = produk tidak ber-batch), beserta harga pokok per unit saat keluar.
+type transferPortion struct {
+	productID   int
+	batchID     int
+	batchNumber string
+	expiryDate  string
+	quantity    int
+	unitCost    int
+}
+
+// transferOutPortions mengambil barang yang keluar dari outlet asal untuk transfer id, dari ledger stock_movements.
+// Ledger menyimpan satu baris per batch, jadi batch asal setiap unit bisa ditelusuri kembali.
+func transferOutPortions(tx *sql.Tx, id int) ([]transferPortion, error) {
+	rows, err := tx.Query(`
+		SELECT sm.product_id, COALESCE(sm.batch_id, 0), COALESCE(b.batch_number, ''), COALESCE(b.expiry_date, ''), -SUM(sm.quantity),
+			COALESCE((SELECT MAX(ti.unit_cost) FROM stock_transfer_items ti WHERE ti.stock_transfer_id = sm.reference_id AND ti.product_id = sm.product_id), 0)
+		FROM stock_movements sm
+		LEFT JOIN stock_batches b ON b.id = sm.batch_id
+		WHERE sm.reference_type = ? AND sm.reference_id = ? AND sm.quantity < 0
+		GROUP BY sm.product_id, sm.batch_id
+		ORDER BY MIN(sm.id)`, models.StockReferenceTransfer, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var portions []transferPortion
+	for rows.Next() {
+		var p transferPortion
+		if err := rows.Scan(&p.productID, &p.batchID, &p.batchNumber, &p.expiryDate, &p.quantity, &p.unitCost); err != nil {
+			return nil, err
+		}
+		portions = append(portions, p)
+	}
+	return portions, rows.Err()
+}
@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"codeWithUmam/models"
+	"database/sql"
+)
+
+// SupplierRepositoryImpl bertugas melakukan komunikasi langsung ke Database untuk tabel suppliers.
+// Struct ini mengimplementasikan interface SupplierRepository dari package repositories.
+type SupplierRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewSupplierRepository(db *sql.DB) *SupplierRepositoryImpl {
+	return &SupplierRepositoryImpl{db: db}
+}
+
+// GetAll mengambil semua supplier, urut berdasarkan nama.
+func (r *SupplierRepositoryImpl) GetAll() ([]models.Supplier, error) {
+	rows, err := r.db.Query("SELECT id, name, COALESCE(phone, ''), COALESCE(email, ''), COALESCE(address, '') FROM suppliers ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	suppliers := []models.Supplier{}
+	for rows.Next() {
+		var s models.Supplier
+		if err := rows.Scan(&s.ID, &s.Name, &s.Phone, &s.Email, &s.Address); err != nil {
+			return nil, err
+		}
+		suppliers = append(suppliers, s)
+	}
+	return suppliers, rows.Err()
+}
+
+// Create menyimpan supplier baru ke database.
+func (r *SupplierRepositoryImpl) Create(supplier *models.Supplier) error {
+	result, err := r.db.Exec("INSERT INTO suppliers (name, phone, email, address) VALUES (?, ?, ?, ?)",
+		supplier.Name, supplier.Phone, supplier.Email, supplier.Address)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	supplier.ID = int(id)
+	return nil
+}
+
+// GetByID mengambil satu supplier berdasarkan ID.
+func (r *SupplierRepositoryImpl) GetByID(id int) (*models.Supplier, error) {
+	var s models.Supplier
+	err := r.db.QueryRow("SELECT id, name, COALESCE(phone, ''), COALESCE(email, ''), COALESCE(address, '') FROM suppliers WHERE id = ?", id).
+		Scan(&s.ID, &s.Name, &s.Phone, &s.Email, &s.Address)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Update mengubah data supplier yang sudah ada.
+func (r *SupplierRepositoryImpl) Update(supplier *models.Supplier) error {
+	result, err := r.db.Exec("UPDATE suppliers SET name = ?, phone = ?, email = ?, address = ? WHERE id = ?",
+		supplier.Name, supplier.Phone, supplier.Email, supplier.Address, supplier.ID)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Delete menghapus supplier. Supplier yang sudah punya purchase order tidak boleh dihapus
+// supaya riwayat pembelian tetap lengkap.
+func (r *SupplierRepositoryImpl) Delete(id int) error {
+	var orders int
+	if err := r.db.QueryRow("SELECT COUNT(id) FROM purchase_orders WHERE supplier_id = ?", id).Scan(&orders); err != nil {
+		return err
+	}
+	if orders > 0 {
+		return NewValidationError("supplier %d sudah punya %d purchase order, tidak bisa dihapus", id, orders)
+	}
+
+	_, err := r.db.Exec("DELETE FROM suppliers WHERE id = ?", id)
+	return err
+}
@@ -0,0 +1,44 @@
+package repositories
+
+import (
+	"codeWithUmam/models"
+	"math"
+)
+
+// applyTax menghitung service charge dan pajak untuk setiap baris details (dipanggil setelah applyPromotions).
+// Setelah dipanggil, setiap baris berisi:
+//   - Subtotal: DPP (dasar pengenaan pajak), yaitu harga setelah diskon TANPA pajak & service charge
+//   - ServiceChargeAmount dan TaxAmount
+//
+// sehingga yang dibayar pelanggan untuk baris tersebut selalu Subtotal + ServiceChargeAmount + TaxAmount.
+//
+// Mode exclusive (harga belum termasuk pajak):
+//
+//	service = subtotal * service%
+//	pajak   = (subtotal + service) * tarif%
+//
+// Mode inclusive (harga sudah termasuk pajak & service): kebalikannya, pajak dan service "dikeluarkan" dari harga.
+func applyTax(details []models.TransactionDetail, cfg models.TaxConfig) {
+	for i := range details {
+		d := &details[i]
+		rate := cfg.RateFor(d.CategoryID)
+		d.TaxRate = rate
+
+		if cfg.Inclusive {
+			paid := float64(d.Subtotal)
+			d.TaxAmount = roundRupiah(paid - paid/(1+rate/100))
+			rest := float64(d.Subtotal - d.TaxAmount)
+			d.ServiceChargeAmount = roundRupiah(rest - rest/(1+cfg.ServiceChargeRate/100))
+			d.Subtotal = d.Subtotal - d.TaxAmount - d.ServiceChargeAmount
+			continue
+		}
+
+		d.ServiceChargeAmount = roundRupiah(float64(d.Subtotal) * cfg.ServiceChargeRate / 100)
+		d.TaxAmount = roundRupiah(float64(d.Subtotal+d.ServiceChargeAmount) * rate / 100)
+	}
+}
+
+// roundRupiah membulatkan ke rupiah terdekat (0,5 ke atas).
+func roundRupiah(v float64) int {
+	return int(math.Round(v))
+}
@@ -0,0 +1,39 @@
+package repositories
+
+import (
+	"codeWithUmam/models"
+	"testing"
+)
+
+func TestApplyTax_Exclusive(t *testing.T) {
+	cfg := models.TaxConfig{DefaultRate: 11, ServiceChargeRate: 5, CategoryRates: map[int]float64{2: 0}}
+	details := []models.TransactionDetail{
+		line(1, 1, 10000, 2), // 20.000 -> service 1.000, pajak 11% x 21.000 = 2.310
+		line(2, 2, 5000, 1),  // kategori 2 bebas pajak -> service 250, pajak 0
+	}
+
+	applyTax(details, cfg)
+
+	if details[0].Subtotal != 20000 || details[0].ServiceChargeAmount != 1000 || details[0].TaxAmount != 2310 {
+		t.Errorf("unexpected line 0: %+v", details[0])
+	}
+	if details[1].ServiceChargeAmount != 250 || details[1].TaxAmount != 0 || details[1].TaxRate != 0 {
+		t.Errorf("unexpected line 1: %+v", details[1])
+	}
+}
+
+func TestApplyTax_Inclusive(t *testing.T) {
+	cfg := models.TaxConfig{Inclusive: true, DefaultRate: 11}
+	details := []models.TransactionDetail{line(1, 1, 11100, 1)}
+
+	applyTax(details, cfg)
+
+	// Harga 11.100 sudah termasuk PPN 11% -> DPP 10.000, pajak 1.100
+	d := details[0]
+	if d.Subtotal != 10000 || d.TaxAmount != 1100 || d.ServiceChargeAmount != 0 {
+		t.Errorf("expected DPP 10000 / tax 1100, got %+v", d)
+	}
+	if d.Subtotal+d.TaxAmount+d.ServiceChargeAmount != 11100 {
+		t.Errorf("inclusive split must add up to the shelf price, got %d", d.Subtotal+d.TaxAmount+d.ServiceChargeAmount)
+	}
+}
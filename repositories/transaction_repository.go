@@ -4,14 +4,68 @@ import (
 	"codeWithUmam/models"
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 )
 
 type TransactionRepository struct {
-	db *sql.DB
+	db            *sql.DB
+	taxConfig     models.TaxConfig   // Aturan pajak & service charge yang dipakai setiap checkout
+	costingMethod string             // Metode HPP: models.CostingMethodAverage atau models.CostingMethodFIFO
+	businessDay   models.BusinessDay // Zona waktu & jam tutup buku, dipakai semua laporan dan filter tanggal
 }
 
-func NewTransactionRepository(db *sql.DB) *TransactionRepository {
-	return &TransactionRepository{db: db}
+func NewTransactionRepository(db *sql.DB, taxConfig models.TaxConfig, costingMethod string, businessDay models.BusinessDay) *TransactionRepository {
+	return &TransactionRepository{db: db, taxConfig: taxConfig, costingMethod: costingMethod, businessDay: businessDay}
+}
+
+// BusinessDay mengembalikan aturan hari bisnis toko (dipakai Service untuk menentukan "hari ini").
+func (repo *TransactionRepository) BusinessDay() models.BusinessDay {
+	return repo.businessDay
+}
+
+// businessDayBounds mengubah rentang hari bisnis from..to (YYYY-MM-DD) menjadi rentang created_at [start, end) dalam UTC.
+func (repo *TransactionRepository) businessDayBounds(from, to string) (string, string, error) {
+	start, end, err := repo.businessDay.Bounds(from, to)
+	if err != nil {
+		return "", "", NewValidationError("%s", err.Error())
+	}
+	return start, end, nil
+}
+
+// CheckoutOptions berisi data tambahan checkout yang ditentukan Service, bukan dikirim client.
+type CheckoutOptions struct {
+	// RequestHash adalah sidik jari isi request, disimpan bersama Idempotency-Key.
+	RequestHash string
+	// AsyncPaymentMethods adalah metode pembayaran yang dikonfirmasi lewat payment gateway (misal QRIS).
+	// Tender dengan metode ini disimpan sebagai PENDING dan transaksinya berstatus PENDING_PAYMENT.
+	AsyncPaymentMethods map[string]bool
+}
+
+// salesStatusFilter adalah kondisi SQL untuk transaksi yang dihitung sebagai penjualan.
+// Transaksi yang belum/tidak pernah dibayar (PENDING_PAYMENT, EXPIRED) tidak masuk laporan.
+const salesStatusFilter = "t.status NOT IN ('" + models.TransactionStatusPendingPayment + "', '" + models.TransactionStatusExpired + "')"
+
+// outletFilter adalah kondisi SQL tambahan untuk membatasi laporan ke satu outlet (alias tabel transactions = t).
+// Parameternya outletID dua kali; outletID 0 = semua outlet.
+const outletFilter = " AND (? = 0 OR t.outlet_id = ?)"
+
+// resolveCheckoutVariant memastikan item.VariantID adalah varian (dan milik item.ProductID jika diisi),
+// lalu mengarahkan item ke baris varian tersebut.
+func resolveCheckoutVariant(tx *sql.Tx, item *models.CheckoutItem) error {
+	var parentID sql.NullInt64
+	err := tx.QueryRow("SELECT parent_id FROM products WHERE id = ?", item.VariantID).Scan(&parentID)
+	if err == sql.ErrNoRows || (err == nil && !parentID.Valid) {
+		return NewValidationError("varian %d tidak ditemukan", item.VariantID)
+	}
+	if err != nil {
+		return err
+	}
+	if item.ProductID != 0 && int64(item.ProductID) != parentID.Int64 {
+		return NewValidationError("varian %d bukan milik produk %d", item.VariantID, item.ProductID)
+	}
+	item.ProductID = item.VariantID
+	return nil
 }
 
 // CreateTransaction memproses pembelian barang.
@@ -21,8 +75,15 @@ func NewTransactionRepository(db *sql.DB) *TransactionRepository {
 // - Consistency: Data harus valid sebelum dan sesudah transaksi.
 // - Isolation: Transaksi ini tidak boleh terganggu transaksi lain yang berjalan bersamaan.
 // - Durability: Setelah commit, data tersimpan permanen.
-// CreateTransaction memproses pembelian barang.
-func (repo *TransactionRepository) CreateTransaction(items []models.CheckoutItem, paidAmount int, change int, paymentMethod string) (*models.Transaction, error) {
+//
+// Jika req.IdempotencyKey diisi, key tersebut (beserta opts.RequestHash, sidik jari isi request) disimpan di header transaksi.
+// Key yang sudah pernah dipakai akan ditolak oleh UNIQUE index dengan ErrDuplicateIdempotencyKey.
+//
+// Stok langsung dikurangi walaupun ada tender yang masih PENDING, supaya barangnya tidak terjual dua kali
+// selagi pelanggan scan QR. Jika pembayaran kedaluwarsa, ExpireTransaction mengembalikan stoknya.
+func (repo *TransactionRepository) CreateTransaction(req models.CheckoutRequest, opts CheckoutOptions) (*models.Transaction, error) {
+	items := req.Items
+
 	// 1. Mulai Database Transaction
 	tx, err := repo.db.Begin()
 	if err != nil {
@@ -30,58 +91,200 @@ func (repo *TransactionRepository) CreateTransaction(items []models.CheckoutItem
 	}
 	defer tx.Rollback()
 
-	totalAmount := 0
+	grossAmount := 0
 	details := make([]models.TransactionDetail, 0)
+	reserved := make(map[int]int)                  // Jumlah yang sudah diambil per produk di keranjang ini (produk yang sama bisa muncul di beberapa item)
+	lineComponents := make([][]bundleComponent, 0) // Komponen yang keluar per baris (kosong jika bukan paket)
+	today := repo.businessDay.Date(time.Now())     // Batch yang kedaluwarsa sebelum hari bisnis ini tidak boleh dijual
+
+	// Stok yang dicek & dikurangi adalah stok outlet tempat transaksi terjadi
+	outletID := req.OutletID
+	if outletID == 0 {
+		outletID = models.DefaultOutletID
+	}
+	if err := requireActiveOutlet(tx, outletID); err != nil {
+		return nil, err
+	}
 
 	// 2. Loop setiap item yang dibeli
 	for _, item := range items {
-		var productPrice, stock int
-		var productName string
+		var productPrice, stock, sellable, categoryID, parentID int
+		var productName, categoryName string
+
+		// Item hasil scan: cari produknya dari barcode/SKU
+		if item.ProductID == 0 && item.Barcode != "" {
+			item.ProductID, err = findProductIDByCode(tx, item.Barcode)
+			if err == ErrNotFound {
+				return nil, NewValidationError("barcode %s tidak terdaftar", item.Barcode)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
 
-		// Ambil data produk terbaru
-		err := tx.QueryRow("SELECT name, price, stock FROM products WHERE id = ?", item.ProductID).Scan(&productName, &productPrice, &stock)
+		// Item varian: yang dijual & dikurangi stoknya adalah baris varian itu sendiri
+		if item.VariantID != 0 {
+			if err := resolveCheckoutVariant(tx, &item); err != nil {
+				return nil, err
+			}
+		}
+
+		// Ambil data produk terbaru (beserta kategorinya untuk snapshot di detail transaksi)
+		var variantCount int
+		err := tx.QueryRow(`
+			SELECT p.name, p.price, `+outletStockColumn+`, `+sellableStockColumn+`, COALESCE(p.category_id, 0), COALESCE(c.name, ''),
+				COALESCE(p.parent_id, 0), (SELECT COUNT(*) FROM products v WHERE v.parent_id = p.id)
+			FROM products p
+			LEFT JOIN categories c ON p.category_id = c.id
+			WHERE p.id = ?`, outletID, outletID, today, outletID, item.ProductID).Scan(&productName, &productPrice, &stock, &sellable, &categoryID, &categoryName, &parentID, &variantCount)
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("product id %d not found", item.ProductID)
 		}
 		if err != nil {
 			return nil, err
 		}
-
-		// Validasi Stok
-		if stock < item.Quantity {
-			return nil, fmt.Errorf("stok tidak cukup untuk produk %s (sisa: %d)", productName, stock)
+		if variantCount > 0 {
+			return nil, NewValidationError("produk %s punya varian, pilih variant_id", productName)
 		}
 
-		subtotal := productPrice * item.Quantity
-		totalAmount += subtotal
+		// Satuan jual: harga per satuan tersebut, stok dihitung dalam satuan dasar (quantity x faktor)
+		unit, err := resolveProductUnit(tx, item.ProductID, item.Unit)
+		if err != nil {
+			return nil, err
+		}
+		unitPrice := productPrice
+		if unit.Factor > 1 {
+			unitPrice = unit.Price
+			if unitPrice == 0 {
+				unitPrice = productPrice * unit.Factor
+			}
+		}
+		baseQuantity := item.Quantity * unit.Factor
 
-		// Kurangi stok produk
-		_, err = tx.Exec("UPDATE products SET stock = stock - ? WHERE id = ?", item.Quantity, item.ProductID)
+		// Validasi Stok
+		// Stok baru benar-benar dikurangi (dan dicatat di ledger) setelah header transaksi dibuat,
+		// karena baris ledger butuh ID transaksi sebagai referensi.
+		// Paket tidak punya stok sendiri: yang dicek (dan nanti dikurangi) adalah stok setiap komponennya.
+		// Untuk produk ber-batch, hanya batch yang belum kedaluwarsa yang dihitung.
+		components, err := loadBundleComponents(tx, item.ProductID, outletID, today)
 		if err != nil {
 			return nil, err
 		}
+		for i := range components {
+			c := &components[i]
+			c.Quantity *= baseQuantity
+			if c.Sellable-reserved[c.ID] < c.Quantity {
+				return nil, fmt.Errorf("stok tidak cukup untuk komponen %s di paket %s (%s)", c.Name, productName, stockShortage(c.Sellable-reserved[c.ID], c.Stock-reserved[c.ID]))
+			}
+			reserved[c.ID] += c.Quantity
+		}
+		if len(components) == 0 {
+			if sellable-reserved[item.ProductID] < baseQuantity {
+				return nil, fmt.Errorf("stok tidak cukup untuk produk %s (%s)", productName, stockShortage(sellable-reserved[item.ProductID], stock-reserved[item.ProductID]))
+			}
+			reserved[item.ProductID] += baseQuantity
+		}
+		lineComponents = append(lineComponents, components)
+
+		subtotal := unitPrice * item.Quantity
+		grossAmount += subtotal
 
 		details = append(details, models.TransactionDetail{
-			ProductID:   item.ProductID,
-			ProductName: productName, // Optional: simpan nama produk history
-			Quantity:    item.Quantity,
-			Subtotal:    subtotal,
+			ProductID:    item.ProductID,
+			ProductName:  productName, // Snapshot: nama produk saat transaksi terjadi
+			ParentID:     parentID,
+			CategoryID:   categoryID,
+			CategoryName: categoryName,
+			UnitPrice:    unitPrice,
+			Quantity:     item.Quantity,
+			Unit:         unit.Name,
+			UnitFactor:   unit.Factor,
+			Subtotal:     subtotal,
 		})
 	}
 
-	// Validasi ulang Paid Amount
-	// Note: change argument is ignored, we calculate it here to be safe from client manipulation
-	if paidAmount < totalAmount {
-		return nil, fmt.Errorf("uang pembayaran kurang (Total: %d, Paid: %d)", totalAmount, paidAmount)
+	// 3. Hitung promosi/diskon
+	// Dievaluasi di dalam Database Transaction yang sama, supaya promosi yang dipakai konsisten dengan harga yang dibaca di atas.
+	promotions, err := findActivePromotions(tx)
+	if err != nil {
+		return nil, err
+	}
+	applied := applyPromotions(details, promotions, time.Now())
+
+	discountAmount := 0
+	for _, d := range details {
+		discountAmount += d.DiscountAmount
 	}
 
-	realChange := paidAmount - totalAmount
+	// 3b. Hitung service charge & pajak per baris (setelah diskon)
+	applyTax(details, repo.taxConfig)
 
-	// 3. Insert ke tabel transaction header
+	subtotalAmount, serviceChargeAmount, taxAmount := 0, 0, 0
+	for _, d := range details {
+		subtotalAmount += d.Subtotal
+		serviceChargeAmount += d.ServiceChargeAmount
+		taxAmount += d.TaxAmount
+	}
+	totalAmount := subtotalAmount + serviceChargeAmount + taxAmount
+
+	// Validasi Pembayaran
+	// Kembalian dihitung di sini (bukan dari client) agar aman dari manipulasi.
+	// Request lama (tanpa "payments") dianggap satu tender sebesar paid_amount.
+	paymentInputs := req.Payments
+	if len(paymentInputs) == 0 {
+		paymentInputs = []models.PaymentInput{{Method: req.PaymentMethod, Amount: req.PaidAmount}}
+	}
+	payments, realChange, err := allocatePayments(totalAmount, paymentInputs)
+	if err != nil {
+		return nil, err
+	}
+	paidAmount := 0
+	for _, p := range payments {
+		paidAmount += p.TenderedAmount
+	}
+	paymentMethod := headerPaymentMethod(payments)
+
+	status := models.TransactionStatusCompleted
+	for i := range payments {
+		payments[i].Status = models.PaymentStatusPaid
+		if opts.AsyncPaymentMethods[payments[i].Method] {
+			payments[i].Status = models.PaymentStatusPending
+			status = models.TransactionStatusPendingPayment
+		}
+	}
+
+	// Transaksi dicatat ke shift kasir yang sedang OPEN (dicek di dalam tx, supaya shift yang baru ditutup tidak kebagian transaksi)
+	var shiftID, cashier interface{}
+	if req.Cashier != "" {
+		var id int
+		err := tx.QueryRow("SELECT id FROM shifts WHERE cashier = ? AND status = ?", req.Cashier, models.ShiftStatusOpen).Scan(&id)
+		if err == sql.ErrNoRows {
+			return nil, NewValidationError("kasir %s belum membuka shift", req.Cashier)
+		}
+		if err != nil {
+			return nil, err
+		}
+		shiftID, cashier = id, req.Cashier
+	}
+
+	// 4. Insert ke tabel transaction header
 	var transactionID int64
 	// SQLite tidak support RETURNING id secara native di semua versi/driver dengan mudah, jadi pakai LastInsertId
-	res, err := tx.Exec("INSERT INTO transactions (total_amount, paid_amount, change, payment_method) VALUES (?, ?, ?, ?)", totalAmount, paidAmount, realChange, paymentMethod)
+	// Key kosong disimpan sebagai NULL agar tidak bentrok dengan UNIQUE index (NULL boleh lebih dari satu).
+	var idempotencyKey, hash interface{}
+	if req.IdempotencyKey != "" {
+		idempotencyKey, hash = req.IdempotencyKey, opts.RequestHash
+	}
+	res, err := tx.Exec(`
+		INSERT INTO transactions (gross_amount, discount_amount, subtotal_amount, service_charge_amount, tax_amount, tax_inclusive,
+			total_amount, paid_amount, change, payment_method, status, idempotency_key, request_hash, shift_id, cashier, outlet_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		grossAmount, discountAmount, subtotalAmount, serviceChargeAmount, taxAmount, repo.taxConfig.Inclusive,
+		totalAmount, paidAmount, realChange, paymentMethod, status, idempotencyKey, hash, shiftID, cashier, outletID)
 	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrDuplicateIdempotencyKey
+		}
 		return nil, err
 	}
 	transactionID, err = res.LastInsertId()
@@ -89,69 +292,180 @@ func (repo *TransactionRepository) CreateTransaction(items []models.CheckoutItem
 		return nil, err
 	}
 
-	// 4. Insert ke tabel transaction details
+	// 5. Kurangi stok (tercatat di ledger sebagai SALE) lalu insert ke tabel transaction details.
+	// Stok dikurangi lebih dulu karena HPP baris (cogs) baru diketahui setelah lapisan harga pokoknya dikonsumsi.
+	// Baris paket mengurangi stok setiap komponennya; HPP paket = jumlah HPP komponen.
 	for i := range details {
 		details[i].TransactionID = int(transactionID)
-		_, err = tx.Exec("INSERT INTO transaction_details (transaction_id, product_id, quantity, subtotal) VALUES (?, ?, ?, ?)",
-			transactionID, details[i].ProductID, details[i].Quantity, details[i].Subtotal)
+		componentCOGS := make([]int, len(lineComponents[i]))
+		if len(lineComponents[i]) > 0 {
+			for j, c := range lineComponents[i] {
+				cost, err := recordStockMovement(tx, models.StockMovement{
+					ProductID:     c.ID,
+					OutletID:      outletID,
+					Type:          models.StockMovementSale,
+					Quantity:      -c.Quantity,
+					ReferenceType: models.StockReferenceTransaction,
+					ReferenceID:   int(transactionID),
+					Note:          "Isi paket " + details[i].ProductName,
+					SellableOn:    today,
+				})
+				if err != nil {
+					return nil, err
+				}
+				componentCOGS[j] = cost.forMethod(repo.costingMethod)
+				details[i].COGS += componentCOGS[j]
+			}
+		} else {
+			cost, err := recordStockMovement(tx, models.StockMovement{
+				ProductID:     details[i].ProductID,
+				OutletID:      outletID,
+				Type:          models.StockMovementSale,
+				Quantity:      -details[i].Quantity * details[i].UnitFactor,
+				ReferenceType: models.StockReferenceTransaction,
+				ReferenceID:   int(transactionID),
+				SellableOn:    today,
+			})
+			if err != nil {
+				return nil, err
+			}
+			details[i].COGS = cost.forMethod(repo.costingMethod)
+		}
+
+		res, err := tx.Exec(`
+			INSERT INTO transaction_details (transaction_id, product_id, product_name, category_id, category_name, unit_price, quantity,
+				unit, unit_factor, discount_amount, subtotal, service_charge_amount, tax_rate, tax_amount, cogs)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			transactionID, details[i].ProductID, details[i].ProductName, details[i].CategoryID, details[i].CategoryName,
+			details[i].UnitPrice, details[i].Quantity, details[i].Unit, details[i].UnitFactor, details[i].DiscountAmount, details[i].Subtotal,
+			details[i].ServiceChargeAmount, details[i].TaxRate, details[i].TaxAmount, details[i].COGS)
+		if err != nil {
+			return nil, err
+		}
+		detailID, err := res.LastInsertId()
 		if err != nil {
 			return nil, err
 		}
+		details[i].ID = int(detailID)
+
+		for j, c := range lineComponents[i] {
+			_, err := tx.Exec(`
+				INSERT INTO transaction_detail_components (transaction_id, transaction_detail_id, component_id, component_name, quantity, cogs)
+				VALUES (?, ?, ?, ?, ?, ?)`,
+				transactionID, detailID, c.ID, c.Name, c.Quantity, componentCOGS[j])
+			if err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	// 5. Commit Transaksi (Simpan permanen)
+	// 6. Catat setiap tender pembayaran (tender PENDING belum punya paid_at)
+	for _, p := range payments {
+		var paidAt interface{}
+		if p.Status == models.PaymentStatusPaid {
+			paidAt = time.Now().UTC()
+		}
+		_, err := tx.Exec("INSERT INTO payments (transaction_id, method, amount, tendered_amount, reference, status, paid_at) VALUES (?, ?, ?, ?, ?, ?, ?)",
+			transactionID, p.Method, p.Amount, p.TenderedAmount, p.Reference, p.Status, paidAt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 7. Catat promosi yang dipakai (untuk laporan diskon)
+	for _, a := range applied {
+		var detailID interface{} // NULL untuk promosi keranjang
+		if a.lineIndex >= 0 {
+			detailID = details[a.lineIndex].ID
+		}
+		_, err := tx.Exec(`
+			INSERT INTO transaction_promotions (transaction_id, transaction_detail_id, promotion_id, promotion_name, promotion_type, amount)
+			VALUES (?, ?, ?, ?, ?, ?)`,
+			transactionID, detailID, a.promotion.ID, a.promotion.Name, a.promotion.Type, a.amount)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 8. Commit Transaksi (Simpan permanen)
 	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
 
-	return &models.Transaction{
-		ID:            int(transactionID),
-		TotalAmount:   totalAmount,
-		PaidAmount:    paidAmount,
-		Change:        realChange,
-		PaymentMethod: paymentMethod,
-		Details:       details,
-	}, nil
+	// Kembalikan data dari database (bukan dari variabel lokal) supaya bentuknya sama persis
+	// dengan GET /api/transactions/{id} dan dengan response replay idempotency.
+	return repo.FindByID(int(transactionID))
 }
 
 // GetDailySalesSummary mengambil laporan penjualan hari ini.
 // Menggunakan fungsi agregasi SQL (SUM, COUNT, MAX) dan JOIN tabel.
-func (repo *TransactionRepository) GetDailySalesSummary() (*models.SalesSummary, error) {
+// Transaksi yang di-void tidak dihitung, dan nilai refund dikurangkan dari omset.
+// Transaksi yang masih menunggu pembayaran atau sudah kedaluwarsa juga tidak dihitung.
+// "Hari ini" adalah hari bisnis toko (zona waktu toko & jam tutup buku), bukan tanggal UTC.
+// outletID 0 = gabungan semua outlet.
+func (repo *TransactionRepository) GetDailySalesSummary(outletID int) (*models.SalesSummary, error) {
 	summary := &models.SalesSummary{}
+	salesFilter := salesStatusFilter + outletFilter
+
+	today := repo.businessDay.Date(time.Now())
+	start, end, err := repo.businessDayBounds(today, today)
+	if err != nil {
+		return nil, err
+	}
 
-	// Query 1: Total Revenue hari ini
+	// Query 1: Total Revenue (omset bersih) hari ini
 	// COALESCE digunakan agar jika hasilnya NULL (tidak ada penjualan), diganti jadi 0.
-	err := repo.db.QueryRow("SELECT COALESCE(SUM(total_amount), 0) FROM transactions WHERE date(created_at) = date('now')").Scan(&summary.TotalRevenue)
+	// Transaksi VOIDED punya refunded_amount = total_amount, jadi otomatis bernilai 0.
+	err = repo.db.QueryRow("SELECT COALESCE(SUM(t.total_amount - t.refunded_amount), 0) FROM transactions t WHERE t.created_at >= ? AND t.created_at < ? AND "+salesFilter, start, end, outletID, outletID).Scan(&summary.TotalRevenue)
 	if err != nil {
 		return nil, fmt.Errorf("gagal hitung revenue: %v", err)
 	}
 
+	// Query 1b: Rincian omset dari barang yang tidak dikembalikan:
+	// omset kotor (harga normal), diskon, DPP, service charge dan pajak.
+	// Nilai per baris dikalikan porsi barang yang tidak di-refund: (quantity - refunded_quantity) / quantity.
+	err = repo.db.QueryRow(`
+		SELECT
+			COALESCE(SUM(td.unit_price * (td.quantity - td.refunded_quantity)), 0),
+			CAST(ROUND(COALESCE(SUM(td.discount_amount * 1.0 * (td.quantity - td.refunded_quantity) / td.quantity), 0)) AS INTEGER),
+			CAST(ROUND(COALESCE(SUM(td.subtotal * 1.0 * (td.quantity - td.refunded_quantity) / td.quantity), 0)) AS INTEGER),
+			CAST(ROUND(COALESCE(SUM(td.service_charge_amount * 1.0 * (td.quantity - td.refunded_quantity) / td.quantity), 0)) AS INTEGER),
+			CAST(ROUND(COALESCE(SUM(td.tax_amount * 1.0 * (td.quantity - td.refunded_quantity) / td.quantity), 0)) AS INTEGER)
+		FROM transaction_details td
+		JOIN transactions t ON td.transaction_id = t.id
+		WHERE t.created_at >= ? AND t.created_at < ? AND `+salesFilter, start, end, outletID, outletID).
+		Scan(&summary.GrossRevenue, &summary.TotalDiscount, &summary.NetSales, &summary.TotalServiceCharge, &summary.TotalTax)
+	if err != nil {
+		return nil, fmt.Errorf("gagal hitung rincian omset: %v", err)
+	}
+
 	// Query 2: Total Transaksi hari ini
-	// Menghitung berapa baris transaksi yang terjadi hari ini.
-	err = repo.db.QueryRow("SELECT COUNT(id) FROM transactions WHERE date(created_at) = date('now')").Scan(&summary.TotalTransaksi)
+	// Menghitung berapa baris transaksi yang terjadi hari ini (transaksi yang di-void tidak dihitung).
+	err = repo.db.QueryRow("SELECT COUNT(t.id) FROM transactions t WHERE t.created_at >= ? AND t.created_at < ? AND t.status != ? AND "+salesFilter, start, end, models.TransactionStatusVoided, outletID, outletID).Scan(&summary.TotalTransaksi)
 	if err != nil {
 		return nil, fmt.Errorf("gagal hitung transaksi: %v", err)
 	}
 
 	// Query 3: Produk Terlaris hari ini
 	// Ini query agak kompleks (Intermediate SQL):
-	// 1. JOIN 3 tabel: transaction_details -> transactions -> products
+	// 1. JOIN 2 tabel: transaction_details -> transactions
+	//    (nama produk diambil dari snapshot di transaction_details, jadi produk yang sudah dihapus tetap terhitung)
 	// 2. Filter hanya transaksi hari ini
-	// 3. GROUP BY nama produk (kelompokkan penjualan per produk)
-	// 4. SUM quantity (hitung total qty terjual per produk)
+	// 3. GROUP BY produk (kelompokkan penjualan per produk)
+	// 4. SUM quantity dikurangi yang sudah di-refund (hitung total qty terjual bersih per produk)
 	// 5. ORDER BY qty DESC (urutkan dari yang paling banyak terjual)
 	// 6. LIMIT 1 (ambil juara 1 nya saja)
 	queryBestSeller := `
-		SELECT p.name, SUM(td.quantity) as qty
+		SELECT MAX(td.product_name), SUM((td.quantity - td.refunded_quantity) * td.unit_factor) as qty
 		FROM transaction_details td
 		JOIN transactions t ON td.transaction_id = t.id
-		JOIN products p ON td.product_id = p.id
-		WHERE date(t.created_at) = date('now')
-		GROUP BY p.name
+		WHERE t.created_at >= ? AND t.created_at < ? AND ` + salesFilter + `
+		GROUP BY td.product_id
+		HAVING qty > 0
 		ORDER BY qty DESC
 		LIMIT 1
 	`
-	err = repo.db.QueryRow(queryBestSeller).Scan(&summary.ProdukTerlaris.Name, &summary.ProdukTerlaris.QtyTerjual)
+	err = repo.db.QueryRow(queryBestSeller, start, end, outletID, outletID).Scan(&summary.ProdukTerlaris.Name, &summary.ProdukTerlaris.QtyTerjual)
 
 	if err == sql.ErrNoRows {
 		// Belum ada penjualan hari ini, set default strip (-) dan 0
@@ -160,23 +474,46 @@ func (repo *TransactionRepository) GetDailySalesSummary() (*models.SalesSummary,
 		return nil, fmt.Errorf("gagal cari produk terlaris: %v", err)
 	}
 
+	// Query 4: Rincian uang masuk per metode pembayaran (tanpa transaksi yang di-void)
+	// payments.amount untuk tunai sudah dikurangi kembalian, jadi SUM-nya adalah uang yang benar-benar masuk.
+	// Hanya tender yang sudah PAID yang dihitung.
+	rows, err := repo.db.Query(`
+		SELECT p.method, SUM(p.amount), COUNT(DISTINCT p.transaction_id)
+		FROM payments p
+		JOIN transactions t ON p.transaction_id = t.id
+		WHERE t.created_at >= ? AND t.created_at < ? AND t.status != ? AND p.status = ?`+outletFilter+`
+		GROUP BY p.method
+		ORDER BY SUM(p.amount) DESC`, start, end, models.TransactionStatusVoided, models.PaymentStatusPaid, outletID, outletID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal hitung rincian pembayaran: %v", err)
+	}
+	defer rows.Close()
+
+	summary.PaymentBreakdown = []models.TenderSummary{}
+	for rows.Next() {
+		var ts models.TenderSummary
+		if err := rows.Scan(&ts.Method, &ts.Amount, &ts.TotalTransaksi); err != nil {
+			return nil, err
+		}
+		summary.PaymentBreakdown = append(summary.PaymentBreakdown, ts)
+	}
+
 	return summary, nil
 }
 
-// FindAll mengambil semua data transaksi, opsional dengan filter tanggal.
-// filter start/end format: YYYY-MM-DD
-func (repo *TransactionRepository) FindAll(start, end string) ([]models.Transaction, error) {
-	query := "SELECT id, total_amount, created_at FROM transactions"
-	args := []interface{}{}
-
-	if start != "" && end != "" {
-		// Filter by date range (inclusive)
-		// SQLite date function: date(created_at)
-		query += " WHERE date(created_at) BETWEEN ? AND ?"
-		args = append(args, start, end)
+// FindAll mengambil semua data transaksi, opsional dengan filter tanggal dan outlet.
+// filter start/end format: YYYY-MM-DD, dalam hari bisnis toko (zona waktu toko & jam tutup buku).
+// outletID 0 = semua outlet.
+// Detail item setiap transaksi ikut diambil dengan SATU query tambahan (bukan satu query per transaksi).
+func (repo *TransactionRepository) FindAll(start, end string, outletID int) ([]models.Transaction, error) {
+	query := `SELECT id, gross_amount, discount_amount, subtotal_amount, service_charge_amount, tax_amount, tax_inclusive,
+		total_amount, status, refunded_amount, COALESCE(shift_id, 0), COALESCE(cashier, ''), outlet_id, created_at FROM transactions`
+	where, args, err := repo.historyFilter(start, end, outletID)
+	if err != nil {
+		return nil, err
 	}
 
-	query += " ORDER BY created_at DESC"
+	query += where + " ORDER BY created_at DESC"
 
 	rows, err := repo.db.Query(query, args...)
 	if err != nil {
@@ -185,31 +522,146 @@ func (repo *TransactionRepository) FindAll(start, end string) ([]models.Transact
 	defer rows.Close()
 
 	var transactions []models.Transaction
+	index := make(map[int]int) // transaction id -> posisi di slice
 	for rows.Next() {
 		var t models.Transaction
-		if err := rows.Scan(&t.ID, &t.TotalAmount, &t.CreatedAt); err != nil {
+		if err := rows.Scan(&t.ID, &t.GrossAmount, &t.DiscountAmount, &t.SubtotalAmount, &t.ServiceChargeAmount, &t.TaxAmount, &t.TaxInclusive,
+			&t.TotalAmount, &t.Status, &t.RefundedAmount, &t.ShiftID, &t.Cashier, &t.OutletID, &t.CreatedAt); err != nil {
 			return nil, err
 		}
+		t.NetAmount = t.TotalAmount - t.RefundedAmount
+		index[t.ID] = len(transactions)
 		transactions = append(transactions, t)
 	}
+	rows.Close()
+
+	if len(transactions) == 0 {
+		return transactions, nil
+	}
+
+	// Ambil detail semua transaksi di atas sekaligus, dengan filter yang sama.
+	detailRows, err := repo.db.Query(
+		"SELECT "+detailColumns+" FROM transaction_details WHERE transaction_id IN (SELECT id FROM transactions"+where+") ORDER BY id",
+		args...)
+	if err != nil {
+		return nil, err
+	}
+	defer detailRows.Close()
+
+	for detailRows.Next() {
+		d, err := scanDetail(detailRows)
+		if err != nil {
+			return nil, err
+		}
+		if i, ok := index[d.TransactionID]; ok {
+			transactions[i].Details = append(transactions[i].Details, d)
+		}
+	}
 
 	return transactions, nil
 }
 
+// historyFilter membuat klausa WHERE untuk filter tanggal & outlet riwayat transaksi
+// (tanggal kosong dan outletID 0 = tanpa filter).
+// Tanggal hari bisnis diubah dulu menjadi rentang waktu UTC, karena created_at disimpan dalam UTC.
+func (repo *TransactionRepository) historyFilter(start, end string, outletID int) (string, []interface{}, error) {
+	var conditions []string
+	var args []interface{}
+	if start != "" && end != "" {
+		startAt, endAt, err := repo.businessDayBounds(start, end)
+		if err != nil {
+			return "", nil, err
+		}
+		conditions = append(conditions, "created_at >= ? AND created_at < ?")
+		args = append(args, startAt, endAt)
+	}
+	if outletID > 0 {
+		conditions = append(conditions, "outlet_id = ?")
+		args = append(args, outletID)
+	}
+	if len(conditions) == 0 {
+		return "", nil, nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args, nil
+}
+
+// ForEachTransaction memanggil fn untuk setiap header transaksi (tanpa detail) langsung dari cursor database,
+// dengan filter tanggal & outlet yang sama seperti FindAll. Dipakai untuk export, supaya riwayat berbulan-bulan
+// tidak perlu ditampung di memory. CreatedAt dikonversi ke zona waktu toko.
+// Jika fn mengembalikan error, iterasi berhenti dan error tersebut dikembalikan.
+func (repo *TransactionRepository) ForEachTransaction(start, end string, outletID int, fn func(models.Transaction) error) error {
+	where, args, err := repo.historyFilter(start, end, outletID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := repo.db.Query(`SELECT id, gross_amount, discount_amount, subtotal_amount, service_charge_amount, tax_amount, tax_inclusive,
+		total_amount, COALESCE(paid_amount, 0), COALESCE(change, 0), COALESCE(payment_method, ''), status, refunded_amount,
+		COALESCE(shift_id, 0), COALESCE(cashier, ''), outlet_id, created_at
+		FROM transactions`+where+" ORDER BY created_at, id", args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t models.Transaction
+		if err := rows.Scan(&t.ID, &t.GrossAmount, &t.DiscountAmount, &t.SubtotalAmount, &t.ServiceChargeAmount, &t.TaxAmount, &t.TaxInclusive,
+			&t.TotalAmount, &t.PaidAmount, &t.Change, &t.PaymentMethod, &t.Status, &t.RefundedAmount,
+			&t.ShiftID, &t.Cashier, &t.OutletID, &t.CreatedAt); err != nil {
+			return err
+		}
+		t.NetAmount = t.TotalAmount - t.RefundedAmount
+		t.CreatedAt = repo.businessDay.Local(t.CreatedAt)
+		if err := fn(t); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// detailColumns adalah daftar kolom transaction_details yang dibaca oleh scanDetail.
+// COALESCE dipakai karena baris lama (sebelum snapshot) bisa saja masih NULL, misal produknya sudah dihapus.
+const detailColumns = `id, transaction_id, product_id, COALESCE(product_name, ''), COALESCE(category_id, 0), COALESCE(category_name, ''),
+	COALESCE(unit_price, 0), quantity, COALESCE(unit, ''), unit_factor, discount_amount, subtotal, service_charge_amount, tax_rate, tax_amount,
+	refunded_quantity, cogs`
+
+// scanDetail membaca satu baris hasil query `SELECT detailColumns ...`.
+func scanDetail(rows *sql.Rows) (models.TransactionDetail, error) {
+	var d models.TransactionDetail
+	err := rows.Scan(&d.ID, &d.TransactionID, &d.ProductID, &d.ProductName, &d.CategoryID, &d.CategoryName,
+		&d.UnitPrice, &d.Quantity, &d.Unit, &d.UnitFactor, &d.DiscountAmount, &d.Subtotal, &d.ServiceChargeAmount, &d.TaxRate, &d.TaxAmount, &d.RefundedQuantity, &d.COGS)
+	return d, err
+}
+
 // FindByID mengambil detail transaksi beserta item-nya (JOIN).
 func (repo *TransactionRepository) FindByID(id int) (*models.Transaction, error) {
 	// 1. Ambil Header Transaksi
 	var t models.Transaction
-	err := repo.db.QueryRow("SELECT id, total_amount, created_at FROM transactions WHERE id = ?", id).Scan(&t.ID, &t.TotalAmount, &t.CreatedAt)
+	var paymentMethod, voidReason, voidedBy sql.NullString
+	err := repo.db.QueryRow(`
+		SELECT id, gross_amount, discount_amount, subtotal_amount, service_charge_amount, tax_amount, tax_inclusive,
+			total_amount, COALESCE(paid_amount, 0), COALESCE(change, 0), payment_method,
+			status, refunded_amount, voided_at, void_reason, voided_by, COALESCE(shift_id, 0), COALESCE(cashier, ''), outlet_id, created_at
+		FROM transactions WHERE id = ?`, id).
+		Scan(&t.ID, &t.GrossAmount, &t.DiscountAmount, &t.SubtotalAmount, &t.ServiceChargeAmount, &t.TaxAmount, &t.TaxInclusive,
+			&t.TotalAmount, &t.PaidAmount, &t.Change, &paymentMethod,
+			&t.Status, &t.RefundedAmount, &t.VoidedAt, &voidReason, &voidedBy, &t.ShiftID, &t.Cashier, &t.OutletID, &t.CreatedAt)
 	if err == sql.ErrNoRows {
 		return nil, nil // Not found
 	}
 	if err != nil {
 		return nil, err
 	}
+	t.NetAmount = t.TotalAmount - t.RefundedAmount
+	t.PaymentMethod = paymentMethod.String
+	t.VoidReason = voidReason.String
+	t.VoidedBy = voidedBy.String
 
 	// 2. Ambil Details (Items)
-	rows, err := repo.db.Query("SELECT id, product_id, quantity, subtotal FROM transaction_details WHERE transaction_id = ?", id)
+	// Nama produk, kategori dan harga satuan sudah di-snapshot di transaction_details,
+	// jadi tidak perlu JOIN/query lagi ke tabel products.
+	rows, err := repo.db.Query("SELECT "+detailColumns+" FROM transaction_details WHERE transaction_id = ? ORDER BY id", id)
 	if err != nil {
 		return nil, err
 	}
@@ -217,20 +669,307 @@ func (repo *TransactionRepository) FindByID(id int) (*models.Transaction, error)
 
 	var details []models.TransactionDetail
 	for rows.Next() {
-		var d models.TransactionDetail
-		if err := rows.Scan(&d.ID, &d.ProductID, &d.Quantity, &d.Subtotal); err != nil {
+		d, err := scanDetail(rows)
+		if err != nil {
 			return nil, err
 		}
-		// Optional: Ambil nama produk jika perlu, tapi itu butuh JOIN lagi.
-		// Untuk efisiensi, kita bisa JOIN di query pertama atau query terpisah.
-		// Mari kita ambil nama produk sekalian biar lengkap.
-		var productName string
-		repo.db.QueryRow("SELECT name FROM products WHERE id = ?", d.ProductID).Scan(&productName)
-		d.ProductName = productName
-
 		details = append(details, d)
 	}
+	rows.Close()
 
 	t.Details = details
+
+	// 3. Ambil riwayat refund/void
+	refunds, err := repo.findRefunds(id)
+	if err != nil {
+		return nil, err
+	}
+	t.Refunds = refunds
+
+	// 4. Ambil promosi yang dipakai
+	promotions, err := repo.findAppliedPromotions(id)
+	if err != nil {
+		return nil, err
+	}
+	t.Promotions = promotions
+
+	// 5. Ambil rincian pembayaran
+	payments, err := repo.findPayments(id)
+	if err != nil {
+		return nil, err
+	}
+	t.Payments = payments
+
 	return &t, nil
 }
+
+// findPayments mengambil semua tender pembayaran milik satu transaksi.
+func (repo *TransactionRepository) findPayments(transactionID int) ([]models.Payment, error) {
+	rows, err := repo.db.Query(`
+		SELECT id, transaction_id, method, amount, tendered_amount, COALESCE(reference, ''), status,
+			COALESCE(provider_reference, ''), COALESCE(qr_string, ''), expires_at, paid_at, created_at
+		FROM payments
+		WHERE transaction_id = ?
+		ORDER BY id`, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payments []models.Payment
+	for rows.Next() {
+		var p models.Payment
+		if err := rows.Scan(&p.ID, &p.TransactionID, &p.Method, &p.Amount, &p.TenderedAmount, &p.Reference, &p.Status,
+			&p.ProviderReference, &p.QRString, &p.ExpiresAt, &p.PaidAt, &p.CreatedAt); err != nil {
+			return nil, err
+		}
+		payments = append(payments, p)
+	}
+	return payments, nil
+}
+
+// findAppliedPromotions mengambil promosi yang dipakai di satu transaksi.
+func (repo *TransactionRepository) findAppliedPromotions(transactionID int) ([]models.AppliedPromotion, error) {
+	rows, err := repo.db.Query(`
+		SELECT id, transaction_id, COALESCE(transaction_detail_id, 0), promotion_id, promotion_name, promotion_type, amount
+		FROM transaction_promotions
+		WHERE transaction_id = ?
+		ORDER BY id`, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var promotions []models.AppliedPromotion
+	for rows.Next() {
+		var p models.AppliedPromotion
+		if err := rows.Scan(&p.ID, &p.TransactionID, &p.TransactionDetailID, &p.PromotionID, &p.PromotionName, &p.PromotionType, &p.Amount); err != nil {
+			return nil, err
+		}
+		promotions = append(promotions, p)
+	}
+	return promotions, nil
+}
+
+// findRefunds mengambil semua catatan refund milik satu transaksi.
+func (repo *TransactionRepository) findRefunds(transactionID int) ([]models.Refund, error) {
+	rows, err := repo.db.Query(`
+		SELECT id, transaction_id, transaction_detail_id, product_id, quantity, amount, COALESCE(reason, ''), COALESCE(refunded_by, ''), created_at
+		FROM transaction_refunds
+		WHERE transaction_id = ?
+		ORDER BY id`, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var refunds []models.Refund
+	for rows.Next() {
+		var rf models.Refund
+		if err := rows.Scan(&rf.ID, &rf.TransactionID, &rf.TransactionDetailID, &rf.ProductID, &rf.Quantity, &rf.Amount, &rf.Reason, &rf.RefundedBy, &rf.CreatedAt); err != nil {
+			return nil, err
+		}
+		refunds = append(refunds, rf)
+	}
+	return refunds, nil
+}
+
+// VoidTransaction membatalkan seluruh transaksi.
+// Semua barang yang belum di-refund dikembalikan ke stok, lalu transaksi ditandai VOIDED.
+// Semua langkah dijalankan dalam satu Database Transaction: stok tidak boleh kembali kalau status gagal diubah, dan sebaliknya.
+func (repo *TransactionRepository) VoidTransaction(id int, reason, voidedBy string) (*models.Transaction, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// 1. Pastikan transaksi ada dan masih bisa di-void
+	var status string
+	err = tx.QueryRow("SELECT status FROM transactions WHERE id = ?", id).Scan(&status)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if status == models.TransactionStatusVoided {
+		return nil, NewValidationError("transaksi %d sudah di-void", id)
+	}
+	if status == models.TransactionStatusExpired {
+		return nil, NewValidationError("transaksi %d sudah kedaluwarsa, stok sudah dikembalikan", id)
+	}
+
+	// 2. Kumpulkan sisa barang per baris detail (yang belum di-refund)
+	rows, err := tx.Query("SELECT id, quantity - refunded_quantity FROM transaction_details WHERE transaction_id = ? AND quantity > refunded_quantity", id)
+	if err != nil {
+		return nil, err
+	}
+	remaining := make([]models.RefundItem, 0)
+	for rows.Next() {
+		var item models.RefundItem
+		if err := rows.Scan(&item.DetailID, &item.Quantity); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		remaining = append(remaining, item)
+	}
+	rows.Close()
+
+	// 3. Kembalikan stok untuk setiap sisa barang
+	totalRefund := 0
+	for _, item := range remaining {
+		amount, err := refundDetail(tx, id, item, reason, voidedBy)
+		if err != nil {
+			return nil, err
+		}
+		totalRefund += amount
+	}
+
+	// 4. Batalkan tagihan payment gateway yang belum dibayar
+	_, err = tx.Exec("UPDATE payments SET status = ? WHERE transaction_id = ? AND status = ?",
+		models.PaymentStatusCancelled, id, models.PaymentStatusPending)
+	if err != nil {
+		return nil, err
+	}
+
+	// 5. Tandai transaksi sebagai VOIDED
+	_, err = tx.Exec(`
+		UPDATE transactions
+		SET status = ?, refunded_amount = refunded_amount + ?, voided_at = CURRENT_TIMESTAMP, void_reason = ?, voided_by = ?
+		WHERE id = ?`,
+		models.TransactionStatusVoided, totalRefund, reason, voidedBy, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return repo.FindByID(id)
+}
+
+// RefundTransaction mengembalikan sebagian barang dari sebuah transaksi.
+// Stok dikembalikan, uang refund dihitung proporsional dari total baris, dan status transaksi diperbarui
+// menjadi PARTIALLY_REFUNDED atau REFUNDED (jika semua barang sudah kembali).
+func (repo *TransactionRepository) RefundTransaction(id int, items []models.RefundItem, reason, refundedBy string) (*models.Transaction, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var status string
+	err = tx.QueryRow("SELECT status FROM transactions WHERE id = ?", id).Scan(&status)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if status == models.TransactionStatusVoided || status == models.TransactionStatusRefunded {
+		return nil, NewValidationError("transaksi %d sudah %s, tidak bisa di-refund lagi", id, status)
+	}
+	if status == models.TransactionStatusPendingPayment || status == models.TransactionStatusExpired {
+		return nil, NewValidationError("transaksi %d berstatus %s (belum dibayar), tidak bisa di-refund", id, status)
+	}
+
+	totalRefund := 0
+	for _, item := range items {
+		amount, err := refundDetail(tx, id, item, reason, refundedBy)
+		if err != nil {
+			return nil, err
+		}
+		totalRefund += amount
+	}
+
+	// Cek apakah masih ada barang yang belum dikembalikan
+	var remainingQty int
+	err = tx.QueryRow("SELECT COALESCE(SUM(quantity - refunded_quantity), 0) FROM transaction_details WHERE transaction_id = ?", id).Scan(&remainingQty)
+	if err != nil {
+		return nil, err
+	}
+	newStatus := models.TransactionStatusPartiallyRefunded
+	if remainingQty == 0 {
+		newStatus = models.TransactionStatusRefunded
+	}
+
+	_, err = tx.Exec("UPDATE transactions SET status = ?, refunded_amount = refunded_amount + ? WHERE id = ?", newStatus, totalRefund, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return repo.FindByID(id)
+}
+
+// refundDetail mengembalikan sejumlah barang dari satu baris transaction_details di dalam Database Transaction tx.
+// Mengembalikan nominal uang yang harus dikembalikan ke pelanggan.
+func refundDetail(tx *sql.Tx, transactionID int, item models.RefundItem, reason, refundedBy string) (int, error) {
+	// lineTotal adalah yang dibayar pelanggan untuk baris ini (termasuk pajak & service charge)
+	// Quantity refund dalam satuan jual baris ini; stok kembali sebanyak quantity x unit_factor satuan dasar
+	var productID, quantity, unitFactor, refundedQty, lineTotal, cogs int
+	err := tx.QueryRow(`
+		SELECT product_id, quantity, unit_factor, refunded_quantity, subtotal + service_charge_amount + tax_amount, cogs
+		FROM transaction_details WHERE id = ? AND transaction_id = ?`, item.DetailID, transactionID).
+		Scan(&productID, &quantity, &unitFactor, &refundedQty, &lineTotal, &cogs)
+	if err == sql.ErrNoRows {
+		return 0, NewValidationError("detail id %d bukan bagian dari transaksi %d", item.DetailID, transactionID)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if item.Quantity <= 0 {
+		return 0, NewValidationError("quantity refund untuk detail id %d harus lebih dari 0", item.DetailID)
+	}
+	if item.Quantity > quantity-refundedQty {
+		return 0, NewValidationError("quantity refund untuk detail id %d melebihi sisa barang (sisa: %d)", item.DetailID, quantity-refundedQty)
+	}
+
+	// Hitung nominal refund secara proporsional (pajak & service charge ikut dikembalikan).
+	// Dihitung dari selisih kumulatif agar pembulatan tidak membuat total refund != total baris ketika semua barang dikembalikan.
+	amount := lineTotal*(refundedQty+item.Quantity)/quantity - lineTotal*refundedQty/quantity
+
+	if _, err := tx.Exec("UPDATE transaction_details SET refunded_quantity = refunded_quantity + ? WHERE id = ?", item.Quantity, item.DetailID); err != nil {
+		return 0, err
+	}
+
+	// Kembalikan stok produk (atau komponen paketnya) dengan harga pokok saat terjual
+	if err := returnDetailStock(tx, item.DetailID, productID, item.Quantity, quantity, unitFactor, cogs, transactionID, reason); err != nil {
+		return 0, err
+	}
+
+	_, err = tx.Exec("INSERT INTO transaction_refunds (transaction_id, transaction_detail_id, product_id, quantity, amount, reason, refunded_by) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		transactionID, item.DetailID, productID, item.Quantity, amount, reason, refundedBy)
+	if err != nil {
+		return 0, err
+	}
+
+	return amount, nil
+}
+
+// FindByIdempotencyKey mencari transaksi yang dibuat dengan Idempotency-Key tertentu.
+// Mengembalikan transaksi beserta requestHash yang disimpan saat checkout pertama kali,
+// atau (nil, "", nil) jika key belum pernah dipakai.
+func (repo *TransactionRepository) FindByIdempotencyKey(key string) (*models.Transaction, string, error) {
+	var id int
+	var requestHash sql.NullString
+	err := repo.db.QueryRow("SELECT id, request_hash FROM transactions WHERE idempotency_key = ?", key).Scan(&id, &requestHash)
+	if err == sql.ErrNoRows {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	t, err := repo.FindByID(id)
+	if err != nil {
+		return nil, "", err
+	}
+	return t, requestHash.String, nil
+}
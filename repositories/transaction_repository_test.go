@@ -0,0 +1,388 @@
+package repositories
+
+import (
+	"codeWithUmam/database"
+	"codeWithUmam/models"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// setupTransactionTestDB membuat database SQLite sementara dengan skema lengkap dari package database.
+// Berbeda dengan setupTestDB, di sini kita butuh semua tabel (products, transactions, dst)
+// sehingga lebih aman memakai migration asli daripada menulis ulang CREATE TABLE.
+func setupTransactionTestDB(t *testing.T) *sql.DB {
+	db, err := database.InitDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to init db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// seedProduct menambah satu produk dan mengembalikan ID-nya.
+func seedProduct(t *testing.T, db *sql.DB, name string, price, stock int) int {
+	p := &models.Product{Name: name, Price: price, Stock: stock}
+	if err := NewProductRepository(db).Create(p); err != nil {
+		t.Fatalf("failed to seed product: %v", err)
+	}
+	return p.ID
+}
+
+func productStock(t *testing.T, db *sql.DB, id int) int {
+	var stock int
+	if err := db.QueryRow("SELECT stock FROM products WHERE id = ?", id).Scan(&stock); err != nil {
+		t.Fatalf("failed to read stock: %v", err)
+	}
+	return stock
+}
+
+func TestTransactionRepository_VoidTransaction(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	repo := NewTransactionRepository(db, models.TaxConfig{}, models.CostingMethodAverage, models.BusinessDay{})
+	productID := seedProduct(t, db, "Kopi", 5000, 10)
+
+	trx, err := repo.CreateTransaction(models.CheckoutRequest{Items: []models.CheckoutItem{{ProductID: productID, Quantity: 3}}, PaidAmount: 20000, PaymentMethod: "CASH"}, CheckoutOptions{})
+	if err != nil {
+		t.Fatalf("CreateTransaction failed: %v", err)
+	}
+
+	voided, err := repo.VoidTransaction(trx.ID, "salah input", "supervisor")
+	if err != nil {
+		t.Fatalf("VoidTransaction failed: %v", err)
+	}
+
+	if voided.Status != models.TransactionStatusVoided {
+		t.Errorf("expected status VOIDED, got %s", voided.Status)
+	}
+	if voided.NetAmount != 0 {
+		t.Errorf("expected net amount 0, got %d", voided.NetAmount)
+	}
+	if voided.VoidedAt == nil || voided.VoidReason != "salah input" || voided.VoidedBy != "supervisor" {
+		t.Errorf("expected void audit fields to be set, got %+v", voided)
+	}
+	if stock := productStock(t, db, productID); stock != 10 {
+		t.Errorf("expected stock restored to 10, got %d", stock)
+	}
+
+	// Void kedua kali harus ditolak
+	_, err = repo.VoidTransaction(trx.ID, "lagi", "supervisor")
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Errorf("expected ValidationError on second void, got %v", err)
+	}
+
+	summary, err := repo.GetDailySalesSummary(0)
+	if err != nil {
+		t.Fatalf("GetDailySalesSummary failed: %v", err)
+	}
+	if summary.TotalRevenue != 0 || summary.TotalTransaksi != 0 {
+		t.Errorf("expected voided transaction excluded from summary, got %+v", summary)
+	}
+}
+
+func TestTransactionRepository_RefundTransaction(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	repo := NewTransactionRepository(db, models.TaxConfig{}, models.CostingMethodAverage, models.BusinessDay{})
+	productID := seedProduct(t, db, "Roti", 3000, 10)
+
+	trx, err := repo.CreateTransaction(models.CheckoutRequest{Items: []models.CheckoutItem{{ProductID: productID, Quantity: 4}}, PaidAmount: 20000, PaymentMethod: "CASH"}, CheckoutOptions{})
+	if err != nil {
+		t.Fatalf("CreateTransaction failed: %v", err)
+	}
+	detail, err := repo.FindByID(trx.ID)
+	if err != nil {
+		t.Fatalf("FindByID failed: %v", err)
+	}
+	detailID := detail.Details[0].ID
+
+	// Refund sebagian
+	refunded, err := repo.RefundTransaction(trx.ID, []models.RefundItem{{DetailID: detailID, Quantity: 1}}, "rusak", "kasir")
+	if err != nil {
+		t.Fatalf("RefundTransaction failed: %v", err)
+	}
+	if refunded.Status != models.TransactionStatusPartiallyRefunded {
+		t.Errorf("expected PARTIALLY_REFUNDED, got %s", refunded.Status)
+	}
+	if refunded.RefundedAmount != 3000 || refunded.NetAmount != 9000 {
+		t.Errorf("expected refunded 3000 / net 9000, got %d / %d", refunded.RefundedAmount, refunded.NetAmount)
+	}
+	if len(refunded.Refunds) != 1 {
+		t.Errorf("expected 1 refund record, got %d", len(refunded.Refunds))
+	}
+	if stock := productStock(t, db, productID); stock != 7 {
+		t.Errorf("expected stock 7, got %d", stock)
+	}
+
+	// Refund melebihi sisa harus ditolak dan tidak mengubah apa pun
+	_, err = repo.RefundTransaction(trx.ID, []models.RefundItem{{DetailID: detailID, Quantity: 4}}, "rusak", "kasir")
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Errorf("expected ValidationError, got %v", err)
+	}
+	if stock := productStock(t, db, productID); stock != 7 {
+		t.Errorf("expected stock unchanged at 7, got %d", stock)
+	}
+
+	// Refund sisanya -> REFUNDED
+	refunded, err = repo.RefundTransaction(trx.ID, []models.RefundItem{{DetailID: detailID, Quantity: 3}}, "rusak", "kasir")
+	if err != nil {
+		t.Fatalf("RefundTransaction failed: %v", err)
+	}
+	if refunded.Status != models.TransactionStatusRefunded || refunded.NetAmount != 0 {
+		t.Errorf("expected REFUNDED with net 0, got %s / %d", refunded.Status, refunded.NetAmount)
+	}
+
+	// Transaksi tidak ada
+	if _, err := repo.RefundTransaction(999, []models.RefundItem{{DetailID: detailID, Quantity: 1}}, "x", "y"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestTransactionRepository_DetailSnapshot(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	db, err := database.InitDB(dbPath)
+	if err != nil {
+		t.Fatalf("failed to init db: %v", err)
+	}
+	defer db.Close()
+	repo := NewTransactionRepository(db, models.TaxConfig{}, models.CostingMethodAverage, models.BusinessDay{})
+
+	category := &models.Category{Name: "Minuman"}
+	if err := NewCategoryRepository(db).Create(category); err != nil {
+		t.Fatalf("failed to seed category: %v", err)
+	}
+	product := &models.Product{Name: "Teh Botol", Price: 4000, Stock: 10, CategoryID: category.ID}
+	productRepo := NewProductRepository(db)
+	if err := productRepo.Create(product); err != nil {
+		t.Fatalf("failed to seed product: %v", err)
+	}
+
+	trx, err := repo.CreateTransaction(models.CheckoutRequest{Items: []models.CheckoutItem{{ProductID: product.ID, Quantity: 2}}, PaidAmount: 10000, PaymentMethod: "CASH"}, CheckoutOptions{})
+	if err != nil {
+		t.Fatalf("CreateTransaction failed: %v", err)
+	}
+
+	// Produk diganti nama & harga setelah transaksi: struk lama tidak boleh ikut berubah
+	product.Name = "Teh Botol Sosro"
+	product.Price = 5000
+	if err := productRepo.Update(product); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	got, err := repo.FindByID(trx.ID)
+	if err != nil {
+		t.Fatalf("FindByID failed: %v", err)
+	}
+	d := got.Details[0]
+	if d.ProductName != "Teh Botol" || d.UnitPrice != 4000 || d.CategoryName != "Minuman" {
+		t.Errorf("expected snapshot Teh Botol/4000/Minuman, got %s/%d/%s", d.ProductName, d.UnitPrice, d.CategoryName)
+	}
+
+	list, err := repo.FindAll("", "", 0)
+	if err != nil {
+		t.Fatalf("FindAll failed: %v", err)
+	}
+	if len(list) != 1 || len(list[0].Details) != 1 || list[0].Details[0].ProductName != "Teh Botol" {
+		t.Errorf("expected FindAll to include snapshot details, got %+v", list)
+	}
+
+	// Simulasikan baris lama (sebelum migration snapshot) lalu jalankan ulang InitDB untuk backfill
+	if _, err := db.Exec("UPDATE transaction_details SET unit_price = NULL, product_name = NULL, category_id = NULL, category_name = NULL"); err != nil {
+		t.Fatalf("failed to reset snapshot: %v", err)
+	}
+	db2, err := database.InitDB(dbPath)
+	if err != nil {
+		t.Fatalf("failed to re-init db: %v", err)
+	}
+	defer db2.Close()
+
+	got, err = NewTransactionRepository(db2, models.TaxConfig{}, models.CostingMethodAverage, models.BusinessDay{}).FindByID(trx.ID)
+	if err != nil {
+		t.Fatalf("FindByID failed: %v", err)
+	}
+	d = got.Details[0]
+	if d.UnitPrice != 4000 || d.ProductName != "Teh Botol Sosro" || d.CategoryName != "Minuman" {
+		t.Errorf("expected backfilled 4000/Teh Botol Sosro/Minuman, got %d/%s/%s", d.UnitPrice, d.ProductName, d.CategoryName)
+	}
+}
+
+func TestTransactionRepository_CreateTransactionWithPromotion(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	repo := NewTransactionRepository(db, models.TaxConfig{}, models.CostingMethodAverage, models.BusinessDay{})
+	productID := seedProduct(t, db, "Kopi", 10000, 10)
+
+	promo := &models.Promotion{Name: "Kopi 20%", Type: models.PromotionTypeItemPercentage, Value: 20, ProductID: productID, Active: true}
+	if err := NewPromotionRepository(db).Create(promo); err != nil {
+		t.Fatalf("failed to seed promotion: %v", err)
+	}
+
+	trx, err := repo.CreateTransaction(models.CheckoutRequest{Items: []models.CheckoutItem{{ProductID: productID, Quantity: 2}}, PaidAmount: 16000, PaymentMethod: "CASH"}, CheckoutOptions{})
+	if err != nil {
+		t.Fatalf("CreateTransaction failed: %v", err)
+	}
+
+	if trx.GrossAmount != 20000 || trx.DiscountAmount != 4000 || trx.TotalAmount != 16000 || trx.Change != 0 {
+		t.Errorf("expected gross 20000 / discount 4000 / total 16000 / change 0, got %+v", trx)
+	}
+	if len(trx.Promotions) != 1 || trx.Promotions[0].PromotionName != "Kopi 20%" || trx.Promotions[0].TransactionDetailID != trx.Details[0].ID {
+		t.Errorf("expected applied promotion recorded on the detail line, got %+v", trx.Promotions)
+	}
+
+	summary, err := repo.GetDailySalesSummary(0)
+	if err != nil {
+		t.Fatalf("GetDailySalesSummary failed: %v", err)
+	}
+	if summary.GrossRevenue != 20000 || summary.TotalRevenue != 16000 || summary.TotalDiscount != 4000 {
+		t.Errorf("expected gross 20000 / net 16000 / discount 4000, got %+v", summary)
+	}
+}
+
+func TestTransactionRepository_CreateTransactionWithTax(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	repo := NewTransactionRepository(db, models.TaxConfig{DefaultRate: 10, ServiceChargeRate: 5}, models.CostingMethodAverage, models.BusinessDay{})
+	productID := seedProduct(t, db, "Nasi Goreng", 20000, 10)
+
+	trx, err := repo.CreateTransaction(models.CheckoutRequest{Items: []models.CheckoutItem{{ProductID: productID, Quantity: 2}}, PaidAmount: 50000, PaymentMethod: "CASH"}, CheckoutOptions{})
+	if err != nil {
+		t.Fatalf("CreateTransaction failed: %v", err)
+	}
+
+	// 40.000 + service 5% (2.000) + pajak 10% x 42.000 (4.200) = 46.200
+	if trx.SubtotalAmount != 40000 || trx.ServiceChargeAmount != 2000 || trx.TaxAmount != 4200 || trx.TotalAmount != 46200 || trx.Change != 3800 {
+		t.Errorf("unexpected tax breakdown: %+v", trx)
+	}
+
+	// Refund 1 porsi: pajak & service charge ikut dikembalikan
+	refunded, err := repo.RefundTransaction(trx.ID, []models.RefundItem{{DetailID: trx.Details[0].ID, Quantity: 1}}, "salah pesan", "kasir")
+	if err != nil {
+		t.Fatalf("RefundTransaction failed: %v", err)
+	}
+	if refunded.RefundedAmount != 23100 {
+		t.Errorf("expected refund 23100, got %d", refunded.RefundedAmount)
+	}
+
+	summary, err := repo.GetDailySalesSummary(0)
+	if err != nil {
+		t.Fatalf("GetDailySalesSummary failed: %v", err)
+	}
+	if summary.TotalRevenue != 23100 || summary.NetSales != 20000 || summary.TotalServiceCharge != 1000 || summary.TotalTax != 2100 {
+		t.Errorf("unexpected summary after refund: %+v", summary)
+	}
+}
+
+func TestTransactionRepository_CreateTransactionSplitPayment(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	repo := NewTransactionRepository(db, models.TaxConfig{}, models.CostingMethodAverage, models.BusinessDay{})
+	productID := seedProduct(t, db, "Beras 5kg", 70000, 10)
+
+	trx, err := repo.CreateTransaction(models.CheckoutRequest{
+		Items: []models.CheckoutItem{{ProductID: productID, Quantity: 1}},
+		Payments: []models.PaymentInput{
+			{Method: "QRIS", Amount: 50000},
+			{Method: "CASH", Amount: 50000},
+		},
+	}, CheckoutOptions{})
+	if err != nil {
+		t.Fatalf("CreateTransaction failed: %v", err)
+	}
+
+	if trx.PaymentMethod != models.PaymentMethodSplit || trx.PaidAmount != 100000 || trx.Change != 30000 || len(trx.Payments) != 2 {
+		t.Errorf("unexpected split payment result: %+v", trx)
+	}
+
+	summary, err := repo.GetDailySalesSummary(0)
+	if err != nil {
+		t.Fatalf("GetDailySalesSummary failed: %v", err)
+	}
+	got := map[string]int{}
+	for _, ts := range summary.PaymentBreakdown {
+		got[ts.Method] = ts.Amount
+	}
+	if got["QRIS"] != 50000 || got["CASH"] != 20000 {
+		t.Errorf("expected QRIS 50000 / CASH 20000 in breakdown, got %+v", summary.PaymentBreakdown)
+	}
+}
+
+func TestTransactionRepository_PendingPaymentLifecycle(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	repo := NewTransactionRepository(db, models.TaxConfig{}, models.CostingMethodAverage, models.BusinessDay{})
+	productID := seedProduct(t, db, "Kopi Susu", 20000, 10)
+	async := CheckoutOptions{AsyncPaymentMethods: map[string]bool{"QRIS": true}}
+
+	checkoutQRIS := func(reference string) *models.Transaction {
+		trx, err := repo.CreateTransaction(models.CheckoutRequest{
+			Items:    []models.CheckoutItem{{ProductID: productID, Quantity: 2}},
+			Payments: []models.PaymentInput{{Method: "QRIS", Amount: 40000}},
+		}, async)
+		if err != nil {
+			t.Fatalf("CreateTransaction failed: %v", err)
+		}
+		if trx.Status != models.TransactionStatusPendingPayment || trx.Payments[0].Status != models.PaymentStatusPending {
+			t.Fatalf("expected pending transaction, got %s / %s", trx.Status, trx.Payments[0].Status)
+		}
+		charge := models.PaymentCharge{ProviderReference: reference, QRString: "qr", ExpiresAt: time.Now().Add(-time.Minute)}
+		if err := repo.AttachPaymentCharge(trx.Payments[0].ID, charge); err != nil {
+			t.Fatalf("AttachPaymentCharge failed: %v", err)
+		}
+		return trx
+	}
+
+	// Stok sudah dipesan selama menunggu pembayaran, tapi belum masuk laporan
+	checkoutQRIS("REF-PAID")
+	if got := productStock(t, db, productID); got != 8 {
+		t.Errorf("expected stock 8 while pending, got %d", got)
+	}
+	summary, err := repo.GetDailySalesSummary(0)
+	if err != nil {
+		t.Fatalf("GetDailySalesSummary failed: %v", err)
+	}
+	if summary.TotalRevenue != 0 || summary.TotalTransaksi != 0 {
+		t.Errorf("pending transaction must not be counted, got %+v", summary)
+	}
+
+	// PAID -> COMPLETED, callback ulang tetap aman
+	for i := 0; i < 2; i++ {
+		trx, err := repo.MarkPaymentPaid("REF-PAID")
+		if err != nil {
+			t.Fatalf("MarkPaymentPaid failed: %v", err)
+		}
+		if trx.Status != models.TransactionStatusCompleted || trx.Payments[0].PaidAt == nil {
+			t.Errorf("expected completed transaction, got %+v", trx)
+		}
+	}
+
+	// EXPIRED -> stok kembali
+	checkoutQRIS("REF-EXPIRED")
+	overdue, err := repo.FindOverduePayments(time.Now())
+	if err != nil {
+		t.Fatalf("FindOverduePayments failed: %v", err)
+	}
+	if len(overdue) != 1 || overdue[0] != "REF-EXPIRED" {
+		t.Fatalf("expected only REF-EXPIRED to be overdue, got %v", overdue)
+	}
+	trx, err := repo.ExpirePayment("REF-EXPIRED")
+	if err != nil {
+		t.Fatalf("ExpirePayment failed: %v", err)
+	}
+	if trx.Status != models.TransactionStatusExpired || trx.Payments[0].Status != models.PaymentStatusExpired {
+		t.Errorf("expected expired transaction, got %s / %s", trx.Status, trx.Payments[0].Status)
+	}
+	if got := productStock(t, db, productID); got != 8 {
+		t.Errorf("expected stock restored to 8, got %d", got)
+	}
+	var validationErr *ValidationError
+	if _, err := repo.MarkPaymentPaid("REF-EXPIRED"); !errors.As(err, &validationErr) {
+		t.Errorf("expected validation error paying an expired payment, got %v", err)
+	}
+
+	summary, err = repo.GetDailySalesSummary(0)
+	if err != nil {
+		t.Fatalf("GetDailySalesSummary failed: %v", err)
+	}
+	if summary.TotalRevenue != 40000 || summary.TotalTransaksi != 1 {
+		t.Errorf("expected only the paid transaction in report, got %+v", summary)
+	}
+}
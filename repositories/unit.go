@@ -0,0 +1,31 @@
+package repositories
+
+import (
+	"codeWithUmam/models"
+	"database/sql"
+	"strings"
+)
+
+// resolveProductUnit mencari satuan unit milik produk productID (tidak case-sensitive).
+// Unit kosong atau sama dengan satuan dasar menghasilkan satuan dasar dengan faktor 1 dan harga 0
+// (pemanggil memakai harga produk). Satuan yang tidak terdaftar -> ValidationError.
+func resolveProductUnit(q queryer, productID int, unit string) (models.ProductUnit, error) {
+	var baseUnit string
+	if err := q.QueryRow("SELECT unit FROM products WHERE id = ?", productID).Scan(&baseUnit); err != nil {
+		if err == sql.ErrNoRows {
+			return models.ProductUnit{}, NewValidationError("product id %d tidak ditemukan", productID)
+		}
+		return models.ProductUnit{}, err
+	}
+	if unit == "" || strings.EqualFold(unit, baseUnit) {
+		return models.ProductUnit{Name: baseUnit, Factor: 1}, nil
+	}
+
+	var u models.ProductUnit
+	err := q.QueryRow("SELECT id, name, factor, price FROM product_units WHERE product_id = ? AND name = ? COLLATE NOCASE", productID, unit).
+		Scan(&u.ID, &u.Name, &u.Factor, &u.Price)
+	if err == sql.ErrNoRows {
+		return models.ProductUnit{}, NewValidationError("satuan %s tidak terdaftar untuk produk id %d (satuan dasar: %s)", unit, productID, baseUnit)
+	}
+	return u, err
+}
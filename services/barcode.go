@@ -0,0 +1,91 @@
+package services
+
+import (
+	"codeWithUmam/repositories"
+	"strings"
+)
+
+// normalizeBarcode memvalidasi barcode EAN-8, UPC-A atau EAN-13 (termasuk check digit-nya).
+// UPC-A (12 digit) disimpan sebagai EAN-13 dengan awalan 0, karena scanner bisa mengirim salah satu dari keduanya
+// untuk barang yang sama; dengan begitu keduanya cocok ke produk yang sama.
+func normalizeBarcode(code string) (string, error) {
+	code = strings.TrimSpace(code)
+	if !isDigits(code) {
+		return "", repositories.NewValidationError("barcode %q harus berupa angka (EAN-8, UPC-A atau EAN-13)", code)
+	}
+	switch len(code) {
+	case 8, 12, 13:
+	default:
+		return "", repositories.NewValidationError("barcode %q harus 8 (EAN-8), 12 (UPC-A) atau 13 (EAN-13) digit", code)
+	}
+
+	last := len(code) - 1
+	if want := barcodeCheckDigit(code[:last]); int(code[last]-'0') != want {
+		return "", repositories.NewValidationError("check digit barcode %s salah (seharusnya %d)", code, want)
+	}
+
+	if len(code) == 12 {
+		code = "0" + code
+	}
+	return code, nil
+}
+
+// barcodeCheckDigit menghitung check digit EAN/UPC (modulo 10) dari digit-digit sebelum check digit:
+// dari kanan, digit diberi bobot 3, 1, 3, 1, ...
+func barcodeCheckDigit(digits string) int {
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if (len(digits)-1-i)%2 == 0 {
+			d *= 3
+		}
+		sum += d
+	}
+	return (10 - sum%10) % 10
+}
+
+// lookupCode menyiapkan hasil scan untuk dicari: kode yang bentuknya EAN/UPC (8, 12 atau 13 digit) harus
+// lolos check digit (salah scan langsung ketahuan); kode lain dianggap SKU dan dicari apa adanya.
+func lookupCode(code string) (string, error) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return "", repositories.NewValidationError("barcode wajib diisi")
+	}
+	if isDigits(code) && (len(code) == 8 || len(code) == 12 || len(code) == 13) {
+		return normalizeBarcode(code)
+	}
+	return code, nil
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeProductBarcodes memvalidasi daftar barcode produk dan membuang duplikat.
+// nil dibiarkan nil (artinya "barcode tidak diubah" saat update).
+func normalizeProductBarcodes(codes []string) ([]string, error) {
+	if codes == nil {
+		return nil, nil
+	}
+	normalized := make([]string, 0, len(codes))
+	seen := make(map[string]bool)
+	for _, code := range codes {
+		n, err := normalizeBarcode(code)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[n] {
+			seen[n] = true
+			normalized = append(normalized, n)
+		}
+	}
+	return normalized, nil
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"codeWithUmam/models"
+	"codeWithUmam/repositories"
+	"errors"
+	"testing"
+)
+
+func TestNormalizeBarcode(t *testing.T) {
+	valid := map[string]string{
+		"4006381333931":  "4006381333931", // EAN-13
+		"96385074":       "96385074",      // EAN-8
+		"036000291452":   "0036000291452", // UPC-A -> EAN-13
+		" 8992761111113": "8992761111113",
+	}
+	for code, want := range valid {
+		got, err := normalizeBarcode(code)
+		if err != nil || got != want {
+			t.Errorf("normalizeBarcode(%q) = %q, %v; want %q", code, got, err, want)
+		}
+	}
+
+	for _, code := range []string{"4006381333932", "96385075", "036000291453", "12345", "ABC-123", ""} {
+		var validationErr *repositories.ValidationError
+		if _, err := normalizeBarcode(code); !errors.As(err, &validationErr) {
+			t.Errorf("expected ValidationError for %q, got %v", code, err)
+		}
+	}
+
+	// Kode yang bukan bentuk EAN/UPC dianggap SKU; bentuk EAN dengan check digit salah ditolak
+	if got, err := lookupCode(" KOP-001 "); err != nil || got != "KOP-001" {
+		t.Errorf("expected SKU to pass through, got %q, %v", got, err)
+	}
+	if _, err := lookupCode("4006381333932"); err == nil {
+		t.Error("expected wrong check digit to be rejected")
+	}
+}
+
+func TestTransactionService_Checkout_BarcodeItems(t *testing.T) {
+	service, db := setupTransactionService(t)
+
+	// UPC-A disimpan sebagai EAN-13, jadi scan 12 atau 13 digit sama-sama cocok
+	product := &models.Product{Name: "Soda", SKU: "SODA-330", Price: 6000, Stock: 10, Barcodes: []string{"036000291452"}}
+	if err := NewProductService(repositories.NewProductRepository(db)).Create(product); err != nil {
+		t.Fatalf("failed to seed product: %v", err)
+	}
+
+	trx, err := service.Checkout(models.CheckoutRequest{
+		Items: []models.CheckoutItem{
+			{Barcode: "036000291452", Quantity: 1},
+			{Barcode: "0036000291452", Quantity: 1},
+			{Barcode: "SODA-330", Quantity: 1},
+		},
+		PaidAmount:    18000,
+		PaymentMethod: "CASH",
+	})
+	if err != nil {
+		t.Fatalf("Checkout failed: %v", err)
+	}
+	for _, d := range trx.Details {
+		if d.ProductID != product.ID {
+			t.Errorf("expected every scanned item to resolve to product %d, got %+v", product.ID, d)
+		}
+	}
+
+	invalid := [][]models.CheckoutItem{
+		{{ProductID: product.ID, Barcode: "036000291452", Quantity: 1}}, // product_id dan barcode sekaligus
+		{{Barcode: "036000291453", Quantity: 1}},                        // check digit salah
+		{{Barcode: "4006381333931", Quantity: 1}},                       // valid tapi tidak terdaftar
+	}
+	for _, items := range invalid {
+		var validationErr *repositories.ValidationError
+		if _, err := service.Checkout(models.CheckoutRequest{Items: items, PaidAmount: 6000, PaymentMethod: "CASH"}); !errors.As(err, &validationErr) {
+			t.Errorf("expected ValidationError for %+v, got %v", items, err)
+		}
+	}
+}
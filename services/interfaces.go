@@ -1,6 +1,10 @@
 package services
 
-import "codeWithUmam/models"
+import (
+	"codeWithUmam/models"
+	"io"
+	"time"
+)
 
 type CategoryService interface {
 	GetAll() ([]models.Category, error)
@@ -12,15 +16,99 @@ type CategoryService interface {
 
 type ProductService interface {
 	GetAll(name string) ([]models.Product, error)
+	Export(fn func(models.Product) error) error
+	Import(r io.Reader, dryRun bool) (*models.ProductImportResult, error)
+	GetByBarcode(code string) (*models.Product, error)
+	GetForLabels(categoryID int, ids []int) ([]models.Product, error)
+	GetLowStock(outletID int) ([]models.LowStockItem, error)
 	Create(product *models.Product) error
 	GetByID(id int) (*models.Product, error)
 	Update(product *models.Product) error
 	Delete(id int) error
+	CreateVariant(parentID int, variant *models.ProductVariant) error
+	UpdateVariant(parentID int, variant *models.ProductVariant) error
+	DeleteVariant(parentID, variantID int) error
+}
+
+type StockService interface {
+	GetMovements(productID int) ([]models.StockMovement, error)
+	CheckConsistency() (*models.StockConsistencyReport, error)
+	GetBatches(productID int) ([]models.StockBatch, error)
+	GetExpiringReport(days int) (*models.ExpiringStockReport, error)
 }
 
 type TransactionService interface {
 	Checkout(req models.CheckoutRequest) (*models.Transaction, error)
-	GetDailyReport() (*models.SalesSummary, error)
-	GetHistory(start, end string) ([]models.Transaction, error)
+	GetDailyReport(outletID int) (*models.SalesSummary, error)
+	GetProfitReport(from, to string, outletID int) (*models.ProfitReport, error)
+	GetBundleReport(from, to string, outletID int) (*models.BundleReport, error)
+	GetReorderReport(days, coverDays, outletID int) (*models.ReorderReport, error)
+	GetSalesReport(from, to, groupBy string, top, outletID int) (*models.SalesReport, error)
+	GetHistory(start, end string, outletID int) ([]models.Transaction, error)
+	ExportHistory(start, end string, outletID int, fn func(models.Transaction) error) error
 	GetDetail(id int) (*models.Transaction, error)
+	GetReceipt(id int) (*models.Transaction, error)
+	Void(id int, req models.VoidRequest) (*models.Transaction, error)
+	Refund(id int, req models.RefundRequest) (*models.Transaction, error)
+}
+
+type PaymentService interface {
+	HandleCallback(provider string, body []byte, signature string) (*models.Transaction, error)
+	ExpireOverduePayments(now time.Time) (int, error)
+}
+
+type PromotionService interface {
+	GetAll() ([]models.Promotion, error)
+	Create(promotion *models.Promotion) error
+	GetByID(id int) (*models.Promotion, error)
+	Update(promotion *models.Promotion) error
+	Delete(id int) error
+}
+
+type SupplierService interface {
+	GetAll() ([]models.Supplier, error)
+	Create(supplier *models.Supplier) error
+	GetByID(id int) (*models.Supplier, error)
+	Update(supplier *models.Supplier) error
+	Delete(id int) error
+}
+
+type PurchaseService interface {
+	GetAll(supplierID int, status string) ([]models.PurchaseOrder, error)
+	GetByID(id int) (*models.PurchaseOrder, error)
+	Create(req models.CreatePurchaseOrderRequest) (*models.PurchaseOrder, error)
+	Receive(orderID int, req models.ReceiveGoodsRequest) (*models.PurchaseOrder, error)
+	Cancel(orderID int) (*models.PurchaseOrder, error)
+}
+
+type StockOpnameService interface {
+	Open(req models.OpenStockOpnameRequest) (*models.StockOpname, error)
+	GetAll() ([]models.StockOpname, error)
+	GetByID(id int) (*models.StockOpname, error)
+	SubmitCounts(id int, req models.SubmitStockCountRequest) (*models.StockOpname, error)
+	Approve(id int, req models.ApproveStockOpnameRequest) (*models.StockOpname, error)
+	Cancel(id int) (*models.StockOpname, error)
+}
+
+type ShiftService interface {
+	Open(req models.OpenShiftRequest) (*models.Shift, error)
+	GetAll(status, cashier string) ([]models.Shift, error)
+	GetByID(id int) (*models.Shift, error)
+	Close(id int, req models.CloseShiftRequest) (*models.Shift, error)
+}
+
+type OutletService interface {
+	GetAll() ([]models.Outlet, error)
+	Create(outlet *models.Outlet) error
+	GetByID(id int) (*models.Outlet, error)
+	Update(outlet *models.Outlet) error
+	GetStock(outletID int) ([]models.OutletStockItem, error)
+}
+
+type StockTransferService interface {
+	GetAll(outletID int, status string) ([]models.StockTransfer, error)
+	GetByID(id int) (*models.StockTransfer, error)
+	Create(req models.CreateStockTransferRequest) (*models.StockTransfer, error)
+	Receive(id int, req models.ReceiveStockTransferRequest) (*models.StockTransfer, error)
+	Cancel(id int) (*models.StockTransfer, error)
 }
@@ -48,3 +48,159 @@ func (m *MockCategoryRepository) Delete(id int) error {
 	}
 	return nil
 }
+
+// MockPromotionRepository implements repositories.PromotionRepository for testing
+type MockPromotionRepository struct {
+	GetAllFunc  func() ([]models.Promotion, error)
+	CreateFunc  func(promotion *models.Promotion) error
+	GetByIDFunc func(id int) (*models.Promotion, error)
+	UpdateFunc  func(promotion *models.Promotion) error
+	DeleteFunc  func(id int) error
+}
+
+func (m *MockPromotionRepository) GetAll() ([]models.Promotion, error) {
+	if m.GetAllFunc != nil {
+		return m.GetAllFunc()
+	}
+	return nil, nil
+}
+
+func (m *MockPromotionRepository) Create(promotion *models.Promotion) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(promotion)
+	}
+	return nil
+}
+
+func (m *MockPromotionRepository) GetByID(id int) (*models.Promotion, error) {
+	if m.GetByIDFunc != nil {
+		return m.GetByIDFunc(id)
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *MockPromotionRepository) Update(promotion *models.Promotion) error {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(promotion)
+	}
+	return nil
+}
+
+func (m *MockPromotionRepository) Delete(id int) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(id)
+	}
+	return nil
+}
+
+// MockPurchaseOrderRepository implements repositories.PurchaseOrderRepository for testing
+type MockPurchaseOrderRepository struct {
+	GetAllFunc  func(supplierID int, status string) ([]models.PurchaseOrder, error)
+	CreateFunc  func(req models.CreatePurchaseOrderRequest) (*models.PurchaseOrder, error)
+	GetByIDFunc func(id int) (*models.PurchaseOrder, error)
+	ReceiveFunc func(orderID int, req models.ReceiveGoodsRequest) (*models.PurchaseOrder, error)
+	CancelFunc  func(orderID int) (*models.PurchaseOrder, error)
+}
+
+func (m *MockPurchaseOrderRepository) GetAll(supplierID int, status string) ([]models.PurchaseOrder, error) {
+	if m.GetAllFunc != nil {
+		return m.GetAllFunc(supplierID, status)
+	}
+	return nil, nil
+}
+
+func (m *MockPurchaseOrderRepository) Create(req models.CreatePurchaseOrderRequest) (*models.PurchaseOrder, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(req)
+	}
+	return &models.PurchaseOrder{}, nil
+}
+
+func (m *MockPurchaseOrderRepository) GetByID(id int) (*models.PurchaseOrder, error) {
+	if m.GetByIDFunc != nil {
+		return m.GetByIDFunc(id)
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *MockPurchaseOrderRepository) Receive(orderID int, req models.ReceiveGoodsRequest) (*models.PurchaseOrder, error) {
+	if m.ReceiveFunc != nil {
+		return m.ReceiveFunc(orderID, req)
+	}
+	return &models.PurchaseOrder{}, nil
+}
+
+func (m *MockPurchaseOrderRepository) Cancel(orderID int) (*models.PurchaseOrder, error) {
+	if m.CancelFunc != nil {
+		return m.CancelFunc(orderID)
+	}
+	return &models.PurchaseOrder{}, nil
+}
+
+// MockProductRepository implements repositories.ProductRepository for testing
+type MockProductRepository struct {
+	ImportFunc        func(rows []models.ProductImportRow, dryRun bool) (*models.ProductImportResult, error)
+	CreateFunc        func(product *models.Product) error
+	CreateVariantFunc func(parentID int, variant *models.ProductVariant) error
+}
+
+func (m *MockProductRepository) GetAll(name string) ([]models.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) ForEach(fn func(models.Product) error) error {
+	return nil
+}
+
+func (m *MockProductRepository) Create(product *models.Product) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(product)
+	}
+	return nil
+}
+
+func (m *MockProductRepository) GetByID(id int) (*models.Product, error) {
+	return nil, errors.New("not found")
+}
+
+func (m *MockProductRepository) GetByCode(code string) (*models.Product, error) {
+	return nil, errors.New("not found")
+}
+
+func (m *MockProductRepository) GetSelection(categoryID int, ids []int) ([]models.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetLowStock(outletID int) ([]models.LowStockItem, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) Update(product *models.Product) error {
+	return nil
+}
+
+func (m *MockProductRepository) Import(rows []models.ProductImportRow, dryRun bool) (*models.ProductImportResult, error) {
+	if m.ImportFunc != nil {
+		return m.ImportFunc(rows, dryRun)
+	}
+	return &models.ProductImportResult{DryRun: dryRun, TotalRows: len(rows)}, nil
+}
+
+func (m *MockProductRepository) Delete(id int) error {
+	return nil
+}
+
+func (m *MockProductRepository) CreateVariant(parentID int, variant *models.ProductVariant) error {
+	if m.CreateVariantFunc != nil {
+		return m.CreateVariantFunc(parentID, variant)
+	}
+	return nil
+}
+
+func (m *MockProductRepository) UpdateVariant(parentID int, variant *models.ProductVariant) error {
+	return nil
+}
+
+func (m *MockProductRepository) DeleteVariant(parentID, variantID int) error {
+	return nil
+}
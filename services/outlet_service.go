@@ -0,0 +1,61 @@
+package services
+
+import (
+	"codeWithUmam/models"
+	"codeWithUmam/repositories"
+	"strings"
+)
+
+// OutletServiceImpl berisi Bisnis Logic untuk data outlet (toko/cabang) dan stok per outlet.
+type OutletServiceImpl struct {
+	repo repositories.OutletRepository
+}
+
+func NewOutletService(repo repositories.OutletRepository) *OutletServiceImpl {
+	return &OutletServiceImpl{repo: repo}
+}
+
+func (s *OutletServiceImpl) GetAll() ([]models.Outlet, error) {
+	return s.repo.GetAll()
+}
+
+func (s *OutletServiceImpl) Create(outlet *models.Outlet) error {
+	if err := validateOutlet(outlet); err != nil {
+		return err
+	}
+	return s.repo.Create(outlet)
+}
+
+func (s *OutletServiceImpl) GetByID(id int) (*models.Outlet, error) {
+	return s.repo.GetByID(id)
+}
+
+// Update mengubah data outlet. Field active ikut diganti, jadi kirim active=true untuk outlet yang masih buka.
+func (s *OutletServiceImpl) Update(outlet *models.Outlet) error {
+	if err := validateOutlet(outlet); err != nil {
+		return err
+	}
+	return s.repo.Update(outlet)
+}
+
+// GetStock mengambil stok setiap produk di satu outlet beserta kiriman yang sedang menuju outlet tersebut.
+func (s *OutletServiceImpl) GetStock(outletID int) ([]models.OutletStockItem, error) {
+	return s.repo.GetStock(outletID)
+}
+
+// validateOutlet merapikan dan memvalidasi kode & nama outlet. Kode disimpan dalam huruf besar.
+func validateOutlet(outlet *models.Outlet) error {
+	outlet.Code = strings.ToUpper(strings.TrimSpace(outlet.Code))
+	outlet.Name = strings.TrimSpace(outlet.Name)
+	outlet.Address = strings.TrimSpace(outlet.Address)
+	if outlet.Code == "" {
+		return repositories.NewValidationError("kode outlet wajib diisi")
+	}
+	if strings.ContainsAny(outlet.Code, " \t") {
+		return repositories.NewValidationError("kode outlet tidak boleh mengandung spasi")
+	}
+	if outlet.Name == "" {
+		return repositories.NewValidationError("nama outlet wajib diisi")
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package services
+
+import (
+	"codeWithUmam/models"
+	"errors"
+)
+
+// ErrInvalidSignature dikembalikan provider jika tanda tangan webhook tidak cocok.
+// Handler menerjemahkannya menjadi HTTP 401 agar request palsu tidak bisa menandai transaksi lunas.
+var ErrInvalidSignature = errors.New("signature callback tidak valid")
+
+// PaymentProvider adalah abstraksi payment gateway untuk tender non-tunai (QRIS, e-wallet, dst).
+// Setiap provider melayani satu metode pembayaran. Tender dengan metode tersebut dibuat PENDING saat checkout,
+// lalu provider mengabarkan hasilnya (PAID/EXPIRED) lewat webhook.
+//
+// Gateway sungguhan cukup mengimplementasikan interface ini; untuk development dan test tersedia QRISSimulator.
+type PaymentProvider interface {
+	// Name dipakai di URL webhook: /api/payments/callback/{name}
+	Name() string
+	// Method adalah metode pembayaran yang dilayani, misal "QRIS".
+	Method() string
+	// CreateCharge membuat tagihan di gateway untuk satu tender PENDING.
+	CreateCharge(payment models.Payment) (*models.PaymentCharge, error)
+	// ParseCallback memverifikasi tanda tangan webhook lalu membaca isinya.
+	ParseCallback(body []byte, signature string) (*models.PaymentCallback, error)
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"codeWithUmam/models"
+	"codeWithUmam/repositories"
+	"log"
+	"time"
+)
+
+// PaymentServiceImpl memproses webhook payment gateway dan kedaluwarsa tagihan.
+type PaymentServiceImpl struct {
+	repo      *repositories.TransactionRepository
+	providers map[string]PaymentProvider // key: PaymentProvider.Name()
+}
+
+// NewPaymentService adalah Constructor.
+func NewPaymentService(repo *repositories.TransactionRepository, providers ...PaymentProvider) *PaymentServiceImpl {
+	byName := make(map[string]PaymentProvider, len(providers))
+	for _, p := range providers {
+		byName[p.Name()] = p
+	}
+	return &PaymentServiceImpl{repo: repo, providers: byName}
+}
+
+// HandleCallback memverifikasi webhook dari provider lalu menyelesaikan (PAID) atau membatalkan (EXPIRED) transaksinya.
+func (s *PaymentServiceImpl) HandleCallback(providerName string, body []byte, signature string) (*models.Transaction, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, repositories.ErrNotFound
+	}
+
+	callback, err := provider.ParseCallback(body, signature)
+	if err != nil {
+		return nil, err
+	}
+
+	switch callback.Status {
+	case models.PaymentStatusPaid:
+		return s.repo.MarkPaymentPaid(callback.ProviderReference)
+	case models.PaymentStatusExpired:
+		return s.repo.ExpirePayment(callback.ProviderReference)
+	default:
+		return nil, repositories.NewValidationError("status callback tidak dikenal: %s", callback.Status)
+	}
+}
+
+// ExpireOverduePayments membatalkan semua tagihan PENDING yang sudah lewat batas waktu.
+// Dipanggil berkala dari main, karena gateway tidak selalu mengirim callback EXPIRED.
+func (s *PaymentServiceImpl) ExpireOverduePayments(now time.Time) (int, error) {
+	references, err := s.repo.FindOverduePayments(now)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, reference := range references {
+		if _, err := s.repo.ExpirePayment(reference); err != nil {
+			// Lanjutkan ke tagihan lain; yang gagal akan dicoba lagi di putaran berikutnya
+			log.Printf("gagal meng-expire pembayaran %s: %v", reference, err)
+			continue
+		}
+		expired++
+	}
+	return expired, nil
+}
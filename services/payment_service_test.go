@@ -0,0 +1,86 @@
+package services
+
+import (
+	"codeWithUmam/database"
+	"codeWithUmam/models"
+	"codeWithUmam/repositories"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Alur lengkap QRIS async memakai QRISSimulator: checkout -> PENDING -> webhook PAID / kedaluwarsa.
+func TestPaymentService_QRISSimulatorFlow(t *testing.T) {
+	db, err := database.InitDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to init db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	simulator := NewQRISSimulator("rahasia", 15*time.Minute)
+	repo := repositories.NewTransactionRepository(db, models.TaxConfig{}, models.CostingMethodAverage, models.BusinessDay{})
+	transactionService := NewTransactionService(repo, simulator)
+	paymentService := NewPaymentService(repo, simulator)
+
+	product := &models.Product{Name: "Teh Botol", Price: 5000, Stock: 10}
+	if err := repositories.NewProductRepository(db).Create(product); err != nil {
+		t.Fatalf("failed to seed product: %v", err)
+	}
+	checkout := func() *models.Transaction {
+		trx, err := transactionService.Checkout(models.CheckoutRequest{
+			Items:    []models.CheckoutItem{{ProductID: product.ID, Quantity: 2}},
+			Payments: []models.PaymentInput{{Method: "QRIS", Amount: 10000}},
+		})
+		if err != nil {
+			t.Fatalf("checkout failed: %v", err)
+		}
+		return trx
+	}
+
+	// 1. Checkout QRIS -> menunggu pembayaran, QR sudah tersedia
+	trx := checkout()
+	payment := trx.Payments[0]
+	if trx.Status != models.TransactionStatusPendingPayment || payment.ProviderReference == "" || payment.QRString == "" || payment.ExpiresAt == nil {
+		t.Fatalf("expected pending QRIS charge, got %+v", trx)
+	}
+
+	// 2. Webhook dengan signature salah ditolak
+	body, _, err := simulator.Callback(payment.ProviderReference, models.PaymentStatusPaid)
+	if err != nil {
+		t.Fatalf("Callback failed: %v", err)
+	}
+	if _, err := paymentService.HandleCallback(simulator.Name(), body, "palsu"); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature, got %v", err)
+	}
+
+	// 3. Webhook PAID yang valid menyelesaikan transaksi
+	body, signature, _ := simulator.Callback(payment.ProviderReference, models.PaymentStatusPaid)
+	paid, err := paymentService.HandleCallback(simulator.Name(), body, signature)
+	if err != nil {
+		t.Fatalf("HandleCallback failed: %v", err)
+	}
+	if paid.Status != models.TransactionStatusCompleted || paid.Payments[0].Status != models.PaymentStatusPaid {
+		t.Errorf("expected completed transaction, got %s / %s", paid.Status, paid.Payments[0].Status)
+	}
+
+	// 4. Tagihan yang tidak dibayar kedaluwarsa, stoknya kembali
+	unpaid := checkout()
+	expired, err := paymentService.ExpireOverduePayments(time.Now().Add(time.Hour))
+	if err != nil || expired != 1 {
+		t.Fatalf("expected 1 expired payment, got %d (%v)", expired, err)
+	}
+	detail, err := transactionService.GetDetail(unpaid.ID)
+	if err != nil {
+		t.Fatalf("GetDetail failed: %v", err)
+	}
+	if detail.Status != models.TransactionStatusExpired {
+		t.Errorf("expected EXPIRED, got %s", detail.Status)
+	}
+
+	var stock int
+	db.QueryRow("SELECT stock FROM products WHERE id = ?", product.ID).Scan(&stock)
+	if stock != 8 {
+		t.Errorf("expected stock 8 (only the paid checkout), got %d", stock)
+	}
+}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"codeWithUmam/models"
+	"codeWithUmam/repositories"
+	"testing"
+)
+
+func TestProductService_Components(t *testing.T) {
+	service := NewProductService(&MockProductRepository{})
+
+	invalid := [][]models.ProductComponent{
+		{{ComponentID: 0, Quantity: 1}},
+		{{ComponentID: 2, Quantity: 0}},
+		{{ComponentID: 2, Quantity: 1}, {ComponentID: 2, Quantity: 3}}, // ganda
+	}
+	for _, components := range invalid {
+		p := &models.Product{Name: "Paket", Price: 10000, Components: components}
+		if _, ok := service.Create(p).(*repositories.ValidationError); !ok {
+			t.Errorf("expected ValidationError for %+v", components)
+		}
+	}
+
+	withVariants := &models.Product{Name: "Paket", Price: 10000,
+		Components: []models.ProductComponent{{ComponentID: 2, Quantity: 1}},
+		Variants:   []models.ProductVariant{{Name: "L", Price: 12000}}}
+	if _, ok := service.Create(withVariants).(*repositories.ValidationError); !ok {
+		t.Error("expected ValidationError for a bundle with variants")
+	}
+
+	valid := &models.Product{Name: "Paket", Price: 10000, Components: []models.ProductComponent{{ComponentID: 2, Quantity: 2}}}
+	if err := service.Create(valid); err != nil {
+		t.Errorf("Create failed: %v", err)
+	}
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"codeWithUmam/models"
+	"codeWithUmam/repositories"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// productImportColumns adalah kolom CSV yang dikenali import produk (nama header tidak case-sensitive, urutan bebas).
+// "category" boleh berisi nama kategori atau ID-nya; "category_id" dan "category_name" juga diterima.
+var productImportColumns = []string{"name", "price", "stock", "category", "category_id", "category_name", "sku"}
+
+// Import membaca CSV produk lalu menyimpannya (upsert by SKU) dalam satu Database Transaction.
+// Baris yang formatnya salah tidak dikirim ke Repository, tapi membuat seluruh import batal (tidak ada yang disimpan).
+// Dengan dryRun = true semua baris tetap divalidasi & disimulasikan, lalu di-rollback.
+func (s *ProductServiceImpl) Import(r io.Reader, dryRun bool) (*models.ProductImportResult, error) {
+	rows, rowErrors, err := parseProductCSV(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Jika sudah ada baris yang salah format, Repository cukup mensimulasikan sisanya (seperti dry run)
+	result, err := s.repo.Import(rows, dryRun || len(rowErrors) > 0)
+	if err != nil {
+		return nil, err
+	}
+	result.DryRun = dryRun
+	invalidLines := make(map[int]bool)
+	for _, rowErr := range rowErrors {
+		invalidLines[rowErr.Line] = true
+	}
+	result.TotalRows += len(invalidLines)
+	result.Errors = append(result.Errors, rowErrors...)
+	sort.SliceStable(result.Errors, func(i, j int) bool { return result.Errors[i].Line < result.Errors[j].Line })
+	return result, nil
+}
+
+// parseProductCSV mengubah file CSV menjadi baris import.
+// Error kembalian ketiga berarti file-nya sendiri tidak bisa dipakai (header salah / bukan CSV);
+// kesalahan per baris dikumpulkan di slice ProductImportError.
+func parseProductCSV(r io.Reader) ([]models.ProductImportRow, []models.ProductImportError, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1 // jumlah kolom per baris dicek sendiri supaya jadi error per baris
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil, repositories.NewValidationError("file CSV kosong")
+	}
+	if err != nil {
+		return nil, nil, repositories.NewValidationError("file CSV tidak valid: %v", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))) // BOM dari Excel
+		for _, known := range productImportColumns {
+			if name == known {
+				columns[name] = i
+			}
+		}
+	}
+	for _, required := range []string{"name", "price"} {
+		if _, ok := columns[required]; !ok {
+			return nil, nil, repositories.NewValidationError("kolom %s wajib ada di header CSV (kolom yang dikenali: %s)",
+				required, strings.Join(productImportColumns, ", "))
+		}
+	}
+	_, hasCategory := columns["category"]
+	_, hasCategoryID := columns["category_id"]
+	_, hasCategoryName := columns["category_name"]
+	if !hasCategory && !hasCategoryID && !hasCategoryName {
+		return nil, nil, repositories.NewValidationError("kolom category (nama atau ID kategori) wajib ada di header CSV")
+	}
+
+	var rows []models.ProductImportRow
+	var rowErrors []models.ProductImportError
+	skuLines := make(map[string]int)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, nil, err
+			}
+			rowErrors = append(rowErrors, models.ProductImportError{Line: parseErr.StartLine, Message: fmt.Sprintf("baris CSV tidak valid: %v", parseErr.Err)})
+			continue
+		}
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue // baris kosong (misal di akhir file) dilewati
+		}
+		line, _ := reader.FieldPos(0)
+
+		cell := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		row := models.ProductImportRow{Line: line, Name: cell("name"), SKU: cell("sku")}
+		addError := func(field, format string, args ...interface{}) {
+			rowErrors = append(rowErrors, models.ProductImportError{Line: line, Field: field, Message: fmt.Sprintf(format, args...)})
+		}
+		valid := true
+
+		if row.Name == "" {
+			addError("name", "nama produk wajib diisi")
+			valid = false
+		}
+		if cell("price") == "" {
+			addError("price", "harga wajib diisi")
+			valid = false
+		} else if row.Price, err = parseImportNumber(cell("price")); err != nil {
+			addError("price", "harga %s", err)
+			valid = false
+		}
+		if row.Stock, err = parseImportNumber(cell("stock")); err != nil {
+			addError("stock", "stok %s", err)
+			valid = false
+		}
+
+		// Kolom category: angka = ID kategori, selain itu nama. category_id / category_name dipakai jika category kosong.
+		switch category := cell("category"); {
+		case category != "":
+			if id, err := strconv.Atoi(category); err == nil {
+				row.CategoryID = id
+			} else {
+				row.CategoryName = category
+			}
+		case cell("category_id") != "":
+			if row.CategoryID, err = strconv.Atoi(cell("category_id")); err != nil {
+				addError("category_id", "ID kategori %q bukan angka", cell("category_id"))
+				valid = false
+			}
+		case cell("category_name") != "":
+			row.CategoryName = cell("category_name")
+		default:
+			addError("category", "kategori wajib diisi")
+			valid = false
+		}
+
+		if row.SKU != "" {
+			if first, ok := skuLines[row.SKU]; ok {
+				addError("sku", "SKU %s sudah dipakai di baris %d", row.SKU, first)
+				valid = false
+			} else {
+				skuLines[row.SKU] = line
+			}
+		}
+
+		if valid {
+			rows = append(rows, row)
+		}
+	}
+
+	if len(rows) == 0 && len(rowErrors) == 0 {
+		return nil, nil, repositories.NewValidationError("file CSV tidak berisi data produk")
+	}
+	return rows, rowErrors, nil
+}
+
+// parseImportNumber membaca bilangan bulat >= 0 dari sel CSV. Sel kosong dianggap 0.
+func parseImportNumber(raw string) (int, error) {
+	if raw == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("%q bukan bilangan bulat", raw)
+	}
+	if n < 0 {
+		return 0, fmt.Errorf("tidak boleh negatif")
+	}
+	return n, nil
+}
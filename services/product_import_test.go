@@ -0,0 +1,74 @@
+package services
+
+import (
+	"codeWithUmam/models"
+	"codeWithUmam/repositories"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestProductService_Import_ParseCSV(t *testing.T) {
+	var gotRows []models.ProductImportRow
+	var gotDryRun bool
+	mockRepo := &MockProductRepository{
+		ImportFunc: func(rows []models.ProductImportRow, dryRun bool) (*models.ProductImportResult, error) {
+			gotRows, gotDryRun = rows, dryRun
+			return &models.ProductImportResult{DryRun: dryRun, TotalRows: len(rows), Applied: !dryRun}, nil
+		},
+	}
+	service := NewProductService(mockRepo)
+
+	csvData := "\ufeffSKU,Name,Price,Stock,Category\n" +
+		"KOP-001,Kopi Sachet,2000,100,Kopi\n" +
+		",\"Teh, Melati\",3500,,2\n" +
+		"\n" +
+		"KOP-001,Kopi Dobel,abc,-1,\n" +
+		"ROT-001,,5000,10,Roti\n"
+
+	result, err := service.Import(strings.NewReader(csvData), false)
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+
+	want := []models.ProductImportRow{
+		{Line: 2, SKU: "KOP-001", Name: "Kopi Sachet", Price: 2000, Stock: 100, CategoryName: "Kopi"},
+		{Line: 3, Name: "Teh, Melati", Price: 3500, CategoryID: 2},
+	}
+	if len(gotRows) != len(want) {
+		t.Fatalf("expected %d valid rows, got %+v", len(want), gotRows)
+	}
+	for i := range want {
+		if gotRows[i] != want[i] {
+			t.Errorf("row %d: expected %+v, got %+v", i, want[i], gotRows[i])
+		}
+	}
+
+	// Ada baris salah -> Repository hanya mensimulasikan, hasil tidak disimpan
+	if !gotDryRun || result.Applied || result.DryRun {
+		t.Errorf("expected rollback because of invalid rows, got dryRun=%v result=%+v", gotDryRun, result)
+	}
+	fields := map[string]bool{}
+	for _, e := range result.Errors {
+		fields[e.Field] = true
+		if e.Line != 5 && e.Line != 6 {
+			t.Errorf("unexpected error line %d: %+v", e.Line, e)
+		}
+	}
+	for _, field := range []string{"price", "stock", "category", "sku", "name"} {
+		if !fields[field] {
+			t.Errorf("expected an error for field %s, got %+v", field, result.Errors)
+		}
+	}
+	if result.TotalRows != 4 {
+		t.Errorf("unexpected total rows %d", result.TotalRows)
+	}
+
+	invalidFiles := []string{"", "name,stock\nKopi,1\n", "name,price\nKopi,1000\n", "name,price,category\n"}
+	for _, data := range invalidFiles {
+		var validationErr *repositories.ValidationError
+		if _, err := service.Import(strings.NewReader(data), true); !errors.As(err, &validationErr) {
+			t.Errorf("expected ValidationError for %q, got %v", data, err)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"codeWithUmam/models"
 	"codeWithUmam/repositories"
+	"strings"
 )
 
 // ProductServiceImpl berisi Bisnis Logic aplikasi.
@@ -21,17 +22,228 @@ func (s *ProductServiceImpl) GetAll(name string) ([]models.Product, error) {
 	return s.repo.GetAll(name)
 }
 
+// Export mengirim semua produk satu per satu ke fn (streaming dari database, untuk CSV/XLSX).
+func (s *ProductServiceImpl) Export(fn func(models.Product) error) error {
+	return s.repo.ForEach(fn)
+}
+
 func (s *ProductServiceImpl) Create(product *models.Product) error {
 	// Contoh Bisnis Logic yang bisa ditambahkan:
 	// if product.Price < 0 { return error("Harga tidak boleh minus") }
+	if err := prepareProductCodes(product); err != nil {
+		return err
+	}
+	if product.Unit == "" {
+		product.Unit = models.DefaultProductUnit
+	}
+	if err := prepareVariants(product); err != nil {
+		return err
+	}
+	if err := prepareComponents(product); err != nil {
+		return err
+	}
 	return s.repo.Create(product)
 }
 
+// GetByBarcode mencari produk dari hasil scan barcode (EAN-8/EAN-13/UPC-A) atau SKU.
+func (s *ProductServiceImpl) GetByBarcode(code string) (*models.Product, error) {
+	code, err := lookupCode(code)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetByCode(code)
+}
+
+// maxLabelProducts membatasi jumlah label dalam satu PDF (sekitar 20 halaman A4).
+const maxLabelProducts = 500
+
+// GetForLabels mengambil produk yang akan dicetak label raknya: semua produk di satu kategori dan/atau daftar ID.
+func (s *ProductServiceImpl) GetForLabels(categoryID int, ids []int) ([]models.Product, error) {
+	if categoryID <= 0 && len(ids) == 0 {
+		return nil, repositories.NewValidationError("pilih category_id atau product_ids untuk dicetak labelnya")
+	}
+	if len(ids) > maxLabelProducts {
+		return nil, repositories.NewValidationError("maksimal %d label per cetak", maxLabelProducts)
+	}
+	products, err := s.repo.GetSelection(categoryID, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(products) == 0 {
+		return nil, repositories.NewValidationError("tidak ada produk yang cocok untuk dicetak labelnya")
+	}
+	if len(products) > maxLabelProducts {
+		return nil, repositories.NewValidationError("maksimal %d label per cetak, pilihan ini berisi %d produk", maxLabelProducts, len(products))
+	}
+	return products, nil
+}
+
+// GetLowStock mengambil produk & varian yang stoknya sudah mencapai batas minimum, di satu outlet (0 = total semua outlet).
+func (s *ProductServiceImpl) GetLowStock(outletID int) ([]models.LowStockItem, error) {
+	return s.repo.GetLowStock(outletID)
+}
+
+// validateStockThreshold memastikan batas stok minimum & jumlah pesan ulang (untuk name) tidak minus. nil = tidak diisi.
+func validateStockThreshold(name string, minStock, reorderQty *int) error {
+	if minStock != nil && *minStock < 0 {
+		return repositories.NewValidationError("stok minimum %s tidak boleh minus", name)
+	}
+	if reorderQty != nil && *reorderQty < 0 {
+		return repositories.NewValidationError("jumlah pesan ulang %s tidak boleh minus", name)
+	}
+	return nil
+}
+
+// prepareProductCodes merapikan SKU & satuan, memvalidasi batas stok, dan memvalidasi check digit setiap barcode sebelum disimpan.
+func prepareProductCodes(product *models.Product) error {
+	product.SKU = strings.TrimSpace(product.SKU)
+	if err := validateStockThreshold(product.Name, product.MinStock, product.ReorderQty); err != nil {
+		return err
+	}
+	if err := prepareProductUnits(product); err != nil {
+		return err
+	}
+	barcodes, err := normalizeProductBarcodes(product.Barcodes)
+	if err != nil {
+		return err
+	}
+	product.Barcodes = barcodes
+	return nil
+}
+
+// prepareProductUnits merapikan satuan dasar dan memvalidasi satuan alternatif:
+// nama wajib & unik, bukan satuan dasar, faktor minimal 2, harga tidak minus.
+// Satuan dasar kosong dibiarkan kosong: produk baru mendapat "pcs", update tetap memakai satuan yang tersimpan.
+func prepareProductUnits(product *models.Product) error {
+	product.Unit = strings.TrimSpace(product.Unit)
+	baseUnit := product.Unit
+	if baseUnit == "" {
+		baseUnit = "satuan dasar"
+	}
+	names := map[string]bool{}
+	if product.Unit != "" {
+		names[strings.ToLower(product.Unit)] = true
+	}
+	for i := range product.Units {
+		u := &product.Units[i]
+		u.Name = strings.TrimSpace(u.Name)
+		if u.Name == "" {
+			return repositories.NewValidationError("nama satuan wajib diisi")
+		}
+		if names[strings.ToLower(u.Name)] {
+			return repositories.NewValidationError("satuan %s ditulis lebih dari sekali atau sama dengan satuan dasar", u.Name)
+		}
+		names[strings.ToLower(u.Name)] = true
+		if u.Factor < 2 {
+			return repositories.NewValidationError("isi satuan %s harus minimal 2 %s", u.Name, baseUnit)
+		}
+		if u.Price < 0 {
+			return repositories.NewValidationError("harga satuan %s tidak boleh minus", u.Name)
+		}
+	}
+	return nil
+}
+
+// prepareVariants memvalidasi varian yang dikirim bersama produk baru: nama wajib & unik, barcode valid,
+// dan stok diisi per varian (bukan di produk induk).
+func prepareVariants(product *models.Product) error {
+	if len(product.Variants) == 0 {
+		return nil
+	}
+	if product.Stock != 0 {
+		return repositories.NewValidationError("produk dengan varian tidak punya stok sendiri, isi stok per varian")
+	}
+	names := make(map[string]bool)
+	for i := range product.Variants {
+		if err := prepareVariant(&product.Variants[i]); err != nil {
+			return err
+		}
+		key := strings.ToLower(product.Variants[i].Name)
+		if names[key] {
+			return repositories.NewValidationError("varian %s ditulis lebih dari sekali", product.Variants[i].Name)
+		}
+		names[key] = true
+	}
+	return nil
+}
+
+// prepareVariant merapikan nama & SKU varian dan memvalidasi barcode-nya.
+func prepareVariant(variant *models.ProductVariant) error {
+	variant.Name = strings.TrimSpace(variant.Name)
+	if variant.Name == "" {
+		return repositories.NewValidationError("nama varian wajib diisi")
+	}
+	if variant.Price < 0 {
+		return repositories.NewValidationError("harga varian %s tidak boleh minus", variant.Name)
+	}
+	if err := validateStockThreshold("varian "+variant.Name, variant.MinStock, variant.ReorderQty); err != nil {
+		return err
+	}
+	variant.SKU = strings.TrimSpace(variant.SKU)
+	barcodes, err := normalizeProductBarcodes(variant.Barcodes)
+	if err != nil {
+		return err
+	}
+	variant.Barcodes = barcodes
+	return nil
+}
+
+// prepareComponents memvalidasi komponen paket/resep: jumlah minimal 1 dan setiap komponen hanya ditulis sekali.
+// Paket tidak bisa punya varian sekaligus; stoknya selalu diambil dari komponen.
+func prepareComponents(product *models.Product) error {
+	if len(product.Components) == 0 {
+		return nil
+	}
+	if len(product.Variants) > 0 {
+		return repositories.NewValidationError("paket tidak bisa punya varian")
+	}
+	seen := make(map[int]bool)
+	for _, c := range product.Components {
+		if c.ComponentID <= 0 {
+			return repositories.NewValidationError("component_id wajib diisi")
+		}
+		if c.Quantity <= 0 {
+			return repositories.NewValidationError("jumlah komponen product id %d harus lebih dari 0", c.ComponentID)
+		}
+		if seen[c.ComponentID] {
+			return repositories.NewValidationError("komponen product id %d ditulis lebih dari sekali", c.ComponentID)
+		}
+		seen[c.ComponentID] = true
+	}
+	return nil
+}
+
+// CreateVariant menambah varian (misal ukuran L) ke produk parentID.
+func (s *ProductServiceImpl) CreateVariant(parentID int, variant *models.ProductVariant) error {
+	if err := prepareVariant(variant); err != nil {
+		return err
+	}
+	return s.repo.CreateVariant(parentID, variant)
+}
+
+// UpdateVariant mengubah nama, SKU, barcode, harga, atau stok satu varian.
+func (s *ProductServiceImpl) UpdateVariant(parentID int, variant *models.ProductVariant) error {
+	if err := prepareVariant(variant); err != nil {
+		return err
+	}
+	return s.repo.UpdateVariant(parentID, variant)
+}
+
+func (s *ProductServiceImpl) DeleteVariant(parentID, variantID int) error {
+	return s.repo.DeleteVariant(parentID, variantID)
+}
+
 func (s *ProductServiceImpl) GetByID(id int) (*models.Product, error) {
 	return s.repo.GetByID(id)
 }
 
 func (s *ProductServiceImpl) Update(product *models.Product) error {
+	if err := prepareProductCodes(product); err != nil {
+		return err
+	}
+	if err := prepareComponents(product); err != nil {
+		return err
+	}
 	return s.repo.Update(product)
 }
 
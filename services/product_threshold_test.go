@@ -0,0 +1,32 @@
+package services
+
+import (
+	"codeWithUmam/models"
+	"codeWithUmam/repositories"
+	"testing"
+)
+
+// intPtr dipakai untuk field angka opsional (nil = tidak diubah) seperti Product.MinStock.
+func intPtr(n int) *int {
+	return &n
+}
+
+func TestProductService_StockThreshold(t *testing.T) {
+	service := NewProductService(&MockProductRepository{})
+
+	invalid := []*models.Product{
+		{Name: "Kopi", Price: 30000, MinStock: intPtr(-1)},
+		{Name: "Kopi", Price: 30000, ReorderQty: intPtr(-5)},
+		{Name: "Es Teh", Price: 5000, Variants: []models.ProductVariant{{Name: "L", Price: 7000, MinStock: intPtr(-1)}}},
+	}
+	for _, p := range invalid {
+		if _, ok := service.Create(p).(*repositories.ValidationError); !ok {
+			t.Errorf("expected ValidationError for %+v", p)
+		}
+	}
+
+	valid := &models.Product{Name: "Kopi", Price: 30000, Stock: 10, MinStock: intPtr(5), ReorderQty: intPtr(24)}
+	if err := service.Create(valid); err != nil {
+		t.Errorf("Create failed: %v", err)
+	}
+}
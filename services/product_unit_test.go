@@ -0,0 +1,33 @@
+package services
+
+import (
+	"codeWithUmam/models"
+	"codeWithUmam/repositories"
+	"testing"
+)
+
+func TestProductService_Units(t *testing.T) {
+	service := NewProductService(&MockProductRepository{})
+
+	product := &models.Product{Name: "Mie Instan", Price: 3000, Units: []models.ProductUnit{{Name: " karton ", Factor: 40}}}
+	if err := service.Create(product); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if product.Unit != models.DefaultProductUnit || product.Units[0].Name != "karton" {
+		t.Errorf("expected default base unit and trimmed unit name, got %q %+v", product.Unit, product.Units)
+	}
+
+	invalid := [][]models.ProductUnit{
+		{{Name: "", Factor: 5}},
+		{{Name: "PCS", Factor: 5}},                            // sama dengan satuan dasar
+		{{Name: "pak", Factor: 5}, {Name: "Pak", Factor: 10}}, // ganda
+		{{Name: "pak", Factor: 1}},
+		{{Name: "pak", Factor: 5, Price: -1}},
+	}
+	for _, units := range invalid {
+		p := &models.Product{Name: "Mie Instan", Price: 3000, Unit: "pcs", Units: units}
+		if _, ok := service.Update(p).(*repositories.ValidationError); !ok {
+			t.Errorf("expected ValidationError for %+v", units)
+		}
+	}
+}
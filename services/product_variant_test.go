@@ -0,0 +1,46 @@
+package services
+
+import (
+	"codeWithUmam/models"
+	"codeWithUmam/repositories"
+	"testing"
+)
+
+func TestProductService_CreateWithVariants(t *testing.T) {
+	var saved *models.Product
+	service := NewProductService(&MockProductRepository{CreateFunc: func(product *models.Product) error {
+		saved = product
+		return nil
+	}})
+
+	product := &models.Product{Name: "Es Teh", Price: 5000, Variants: []models.ProductVariant{
+		{Name: " S ", SKU: " TEH-S ", Price: 4000, Stock: 10},
+		{Name: "L", Price: 6000, Barcodes: []string{"036000291452"}},
+	}}
+	if err := service.Create(product); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if v := saved.Variants[0]; v.Name != "S" || v.SKU != "TEH-S" {
+		t.Errorf("expected variant name & SKU to be trimmed, got %+v", v)
+	}
+	if got := saved.Variants[1].Barcodes; len(got) != 1 || got[0] != "0036000291452" {
+		t.Errorf("expected UPC-A variant barcode to be stored as EAN-13, got %v", got)
+	}
+
+	invalid := []*models.Product{
+		{Name: "Es Teh", Stock: 5, Variants: []models.ProductVariant{{Name: "S"}}},                           // stok di induk
+		{Name: "Es Teh", Variants: []models.ProductVariant{{Name: "S"}, {Name: "s"}}},                        // nama varian ganda
+		{Name: "Es Teh", Variants: []models.ProductVariant{{Name: " "}}},                                     // nama kosong
+		{Name: "Es Teh", Variants: []models.ProductVariant{{Name: "S", Price: -1}}},                          // harga minus
+		{Name: "Es Teh", Variants: []models.ProductVariant{{Name: "S", Barcodes: []string{"036000291453"}}}}, // check digit salah
+	}
+	for _, p := range invalid {
+		if _, ok := service.Create(p).(*repositories.ValidationError); !ok {
+			t.Errorf("expected ValidationError for %+v", p.Variants)
+		}
+	}
+
+	if _, ok := service.CreateVariant(1, &models.ProductVariant{Name: ""}).(*repositories.ValidationError); !ok {
+		t.Error("expected ValidationError for variant without name")
+	}
+}
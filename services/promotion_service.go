@@ -0,0 +1,79 @@
+package services
+
+import (
+	"codeWithUmam/models"
+	"codeWithUmam/repositories"
+	"strings"
+)
+
+// PromotionServiceImpl berisi Bisnis Logic untuk promosi/diskon.
+// Validasi aturan promosi dilakukan di sini SEBELUM disimpan, supaya checkout tidak pernah
+// bertemu promosi yang tidak masuk akal (misal diskon 150%).
+type PromotionServiceImpl struct {
+	repo repositories.PromotionRepository
+}
+
+func NewPromotionService(repo repositories.PromotionRepository) *PromotionServiceImpl {
+	return &PromotionServiceImpl{repo: repo}
+}
+
+func (s *PromotionServiceImpl) GetAll() ([]models.Promotion, error) {
+	return s.repo.GetAll()
+}
+
+func (s *PromotionServiceImpl) Create(promotion *models.Promotion) error {
+	if err := validatePromotion(promotion); err != nil {
+		return err
+	}
+	return s.repo.Create(promotion)
+}
+
+func (s *PromotionServiceImpl) GetByID(id int) (*models.Promotion, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *PromotionServiceImpl) Update(promotion *models.Promotion) error {
+	if err := validatePromotion(promotion); err != nil {
+		return err
+	}
+	return s.repo.Update(promotion)
+}
+
+func (s *PromotionServiceImpl) Delete(id int) error {
+	return s.repo.Delete(id)
+}
+
+// validatePromotion memastikan kombinasi field promosi sesuai dengan tipenya.
+func validatePromotion(p *models.Promotion) error {
+	if strings.TrimSpace(p.Name) == "" {
+		return repositories.NewValidationError("nama promosi wajib diisi")
+	}
+
+	switch p.Type {
+	case models.PromotionTypeItemPercentage, models.PromotionTypeCartPercentage:
+		if p.Value <= 0 || p.Value > 100 {
+			return repositories.NewValidationError("value untuk promosi persen harus 1-100")
+		}
+	case models.PromotionTypeItemNominal, models.PromotionTypeCartNominal:
+		if p.Value <= 0 {
+			return repositories.NewValidationError("value untuk promosi nominal harus lebih dari 0")
+		}
+	case models.PromotionTypeBuyXGetY:
+		if p.BuyQty <= 0 || p.GetQty <= 0 {
+			return repositories.NewValidationError("buy_qty dan get_qty wajib diisi untuk promosi BUY_X_GET_Y")
+		}
+	default:
+		return repositories.NewValidationError("tipe promosi %q tidak dikenal", p.Type)
+	}
+
+	if p.IsCartLevel() && (p.ProductID != 0 || p.CategoryID != 0) {
+		return repositories.NewValidationError("promosi keranjang tidak boleh dibatasi produk/kategori")
+	}
+	if p.MinSpend < 0 {
+		return repositories.NewValidationError("min_spend tidak boleh minus")
+	}
+	if p.StartAt != nil && p.EndAt != nil && p.EndAt.Before(*p.StartAt) {
+		return repositories.NewValidationError("end_at harus setelah start_at")
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"codeWithUmam/models"
+	"codeWithUmam/repositories"
+	"errors"
+	"testing"
+)
+
+func TestPromotionService_Create_Validation(t *testing.T) {
+	created := 0
+	mockRepo := &MockPromotionRepository{
+		CreateFunc: func(p *models.Promotion) error {
+			created++
+			return nil
+		},
+	}
+	service := NewPromotionService(mockRepo)
+
+	invalid := []models.Promotion{
+		{Name: "", Type: models.PromotionTypeItemPercentage, Value: 10},
+		{Name: "Diskon 150%", Type: models.PromotionTypeItemPercentage, Value: 150},
+		{Name: "Beli gratis", Type: models.PromotionTypeBuyXGetY, BuyQty: 2},
+		{Name: "Keranjang kopi", Type: models.PromotionTypeCartNominal, Value: 5000, ProductID: 1},
+		{Name: "Aneh", Type: "FREE_MONEY", Value: 1},
+	}
+	for _, p := range invalid {
+		var validationErr *repositories.ValidationError
+		if err := service.Create(&p); !errors.As(err, &validationErr) {
+			t.Errorf("expected ValidationError for %+v, got %v", p, err)
+		}
+	}
+	if created != 0 {
+		t.Errorf("invalid promotions must not reach the repository, got %d creates", created)
+	}
+
+	valid := &models.Promotion{Name: "Beli 2 Gratis 1", Type: models.PromotionTypeBuyXGetY, BuyQty: 2, GetQty: 1, Active: true}
+	if err := service.Create(valid); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created != 1 {
+		t.Errorf("expected 1 create, got %d", created)
+	}
+}
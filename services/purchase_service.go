@@ -0,0 +1,75 @@
+package services
+
+import (
+	"codeWithUmam/models"
+	"codeWithUmam/repositories"
+	"strings"
+	"time"
+)
+
+// PurchaseServiceImpl berisi Bisnis Logic pembelian barang ke supplier (purchase order & penerimaan barang).
+// Validasi bentuk request dilakukan di sini; validasi yang butuh data (sisa pesanan, status PO) ada di Repository
+// karena harus dicek di dalam Database Transaction yang sama dengan perubahan stok.
+type PurchaseServiceImpl struct {
+	repo repositories.PurchaseOrderRepository
+}
+
+func NewPurchaseService(repo repositories.PurchaseOrderRepository) *PurchaseServiceImpl {
+	return &PurchaseServiceImpl{repo: repo}
+}
+
+func (s *PurchaseServiceImpl) GetAll(supplierID int, status string) ([]models.PurchaseOrder, error) {
+	return s.repo.GetAll(supplierID, status)
+}
+
+func (s *PurchaseServiceImpl) GetByID(id int) (*models.PurchaseOrder, error) {
+	return s.repo.GetByID(id)
+}
+
+// Create membuat purchase order baru.
+func (s *PurchaseServiceImpl) Create(req models.CreatePurchaseOrderRequest) (*models.PurchaseOrder, error) {
+	if req.SupplierID <= 0 {
+		return nil, repositories.NewValidationError("supplier_id wajib diisi")
+	}
+	if len(req.Items) == 0 {
+		return nil, repositories.NewValidationError("item purchase order tidak boleh kosong")
+	}
+	for _, item := range req.Items {
+		if item.Quantity <= 0 {
+			return nil, repositories.NewValidationError("quantity untuk product id %d harus lebih dari 0", item.ProductID)
+		}
+		if item.UnitCost < 0 {
+			return nil, repositories.NewValidationError("unit_cost untuk product id %d tidak boleh minus", item.ProductID)
+		}
+	}
+	return s.repo.Create(req)
+}
+
+// Receive mencatat kedatangan barang (boleh sebagian) dan menambah stok.
+func (s *PurchaseServiceImpl) Receive(orderID int, req models.ReceiveGoodsRequest) (*models.PurchaseOrder, error) {
+	if len(req.Items) == 0 {
+		return nil, repositories.NewValidationError("item penerimaan barang tidak boleh kosong")
+	}
+	for i := range req.Items {
+		item := &req.Items[i]
+		if item.Quantity <= 0 {
+			return nil, repositories.NewValidationError("quantity untuk item id %d harus lebih dari 0", item.PurchaseOrderItemID)
+		}
+		if item.UnitCost < 0 {
+			return nil, repositories.NewValidationError("unit_cost untuk item id %d tidak boleh minus", item.PurchaseOrderItemID)
+		}
+		item.BatchNumber = strings.TrimSpace(item.BatchNumber)
+		item.ExpiryDate = strings.TrimSpace(item.ExpiryDate)
+		if item.ExpiryDate != "" {
+			if _, err := time.Parse("2006-01-02", item.ExpiryDate); err != nil {
+				return nil, repositories.NewValidationError("expiry_date untuk item id %d harus berformat YYYY-MM-DD", item.PurchaseOrderItemID)
+			}
+		}
+	}
+	return s.repo.Receive(orderID, req)
+}
+
+// Cancel membatalkan sisa pesanan yang belum datang.
+func (s *PurchaseServiceImpl) Cancel(orderID int) (*models.PurchaseOrder, error) {
+	return s.repo.Cancel(orderID)
+}
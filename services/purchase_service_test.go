@@ -0,0 +1,52 @@
+package services
+
+import (
+	"codeWithUmam/models"
+	"codeWithUmam/repositories"
+	"errors"
+	"testing"
+)
+
+func TestPurchaseService_Validation(t *testing.T) {
+	calls := 0
+	mockRepo := &MockPurchaseOrderRepository{
+		CreateFunc: func(req models.CreatePurchaseOrderRequest) (*models.PurchaseOrder, error) {
+			calls++
+			return &models.PurchaseOrder{}, nil
+		},
+		ReceiveFunc: func(orderID int, req models.ReceiveGoodsRequest) (*models.PurchaseOrder, error) {
+			calls++
+			return &models.PurchaseOrder{}, nil
+		},
+	}
+	service := NewPurchaseService(mockRepo)
+
+	invalidOrders := []models.CreatePurchaseOrderRequest{
+		{Items: []models.PurchaseOrderItemRequest{{ProductID: 1, Quantity: 1}}},
+		{SupplierID: 1},
+		{SupplierID: 1, Items: []models.PurchaseOrderItemRequest{{ProductID: 1, Quantity: 0, UnitCost: 1000}}},
+		{SupplierID: 1, Items: []models.PurchaseOrderItemRequest{{ProductID: 1, Quantity: 5, UnitCost: -1}}},
+	}
+	for _, req := range invalidOrders {
+		var validationErr *repositories.ValidationError
+		if _, err := service.Create(req); !errors.As(err, &validationErr) {
+			t.Errorf("expected ValidationError for %+v, got %v", req, err)
+		}
+	}
+
+	invalidReceipts := []models.ReceiveGoodsRequest{
+		{},
+		{Items: []models.ReceiveGoodsItemRequest{{PurchaseOrderItemID: 1, Quantity: -2}}},
+		{Items: []models.ReceiveGoodsItemRequest{{PurchaseOrderItemID: 1, Quantity: 2, ExpiryDate: "31-12-2027"}}},
+	}
+	for _, req := range invalidReceipts {
+		var validationErr *repositories.ValidationError
+		if _, err := service.Receive(1, req); !errors.As(err, &validationErr) {
+			t.Errorf("expected ValidationError for %+v, got %v", req, err)
+		}
+	}
+
+	if calls != 0 {
+		t.Errorf("invalid requests must not reach the repository, got %d calls", calls)
+	}
+}
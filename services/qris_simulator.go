@@ -0,0 +1,80 @@
+package services
+
+import (
+	"codeWithUmam/models"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// QRISSimulator adalah PaymentProvider QRIS yang berjalan di dalam proses aplikasi (tanpa gateway sungguhan).
+// Dipakai untuk development dan test: tagihan dibuat lokal, dan "pembayaran" dipicu lewat Callback
+// yang menghasilkan webhook bertanda tangan persis seperti yang akan dikirim gateway.
+type QRISSimulator struct {
+	secret []byte
+	ttl    time.Duration
+
+	mu  sync.Mutex
+	seq int
+}
+
+// NewQRISSimulator membuat simulator dengan secret untuk menandatangani webhook
+// dan ttl sebagai batas waktu pelanggan membayar.
+func NewQRISSimulator(secret string, ttl time.Duration) *QRISSimulator {
+	return &QRISSimulator{secret: []byte(secret), ttl: ttl}
+}
+
+func (s *QRISSimulator) Name() string {
+	return "qris-simulator"
+}
+
+func (s *QRISSimulator) Method() string {
+	return "QRIS"
+}
+
+// CreateCharge membuat referensi unik dan isi QR tiruan untuk tender.
+func (s *QRISSimulator) CreateCharge(payment models.Payment) (*models.PaymentCharge, error) {
+	s.mu.Lock()
+	s.seq++
+	seq := s.seq
+	s.mu.Unlock()
+
+	reference := fmt.Sprintf("SIMQRIS-%d-%d-%d", payment.TransactionID, payment.ID, seq)
+	return &models.PaymentCharge{
+		ProviderReference: reference,
+		QRString:          fmt.Sprintf("00020101021226SIMULATOR%s5303360540%d5802ID6304", reference, payment.Amount),
+		ExpiresAt:         time.Now().Add(s.ttl),
+	}, nil
+}
+
+// ParseCallback memverifikasi HMAC-SHA256 dari body webhook.
+func (s *QRISSimulator) ParseCallback(body []byte, signature string) (*models.PaymentCallback, error) {
+	if !hmac.Equal([]byte(s.sign(body)), []byte(signature)) {
+		return nil, ErrInvalidSignature
+	}
+	var callback models.PaymentCallback
+	if err := json.Unmarshal(body, &callback); err != nil {
+		return nil, fmt.Errorf("isi callback tidak valid: %v", err)
+	}
+	return &callback, nil
+}
+
+// Callback menghasilkan body dan signature webhook untuk referensi tersebut,
+// seolah-olah pelanggan sudah membayar (status PAID) atau tagihannya kedaluwarsa (status EXPIRED).
+func (s *QRISSimulator) Callback(reference, status string) ([]byte, string, error) {
+	body, err := json.Marshal(models.PaymentCallback{ProviderReference: reference, Status: status})
+	if err != nil {
+		return nil, "", err
+	}
+	return body, s.sign(body), nil
+}
+
+func (s *QRISSimulator) sign(body []byte) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
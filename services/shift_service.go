@@ -0,0 +1,58 @@
+package services
+
+import (
+	"codeWithUmam/models"
+	"codeWithUmam/repositories"
+	"strings"
+)
+
+// ShiftServiceImpl berisi Bisnis Logic shift kasir (buka laci, tutup laci, Z-report).
+type ShiftServiceImpl struct {
+	repo repositories.ShiftRepository
+}
+
+func NewShiftService(repo repositories.ShiftRepository) *ShiftServiceImpl {
+	return &ShiftServiceImpl{repo: repo}
+}
+
+// Open membuka shift baru dengan modal awal di laci.
+func (s *ShiftServiceImpl) Open(req models.OpenShiftRequest) (*models.Shift, error) {
+	req.Cashier = strings.TrimSpace(req.Cashier)
+	if req.Cashier == "" {
+		return nil, repositories.NewValidationError("cashier wajib diisi")
+	}
+	if req.OpeningFloat < 0 {
+		return nil, repositories.NewValidationError("opening_float tidak boleh minus")
+	}
+	return s.repo.Open(req)
+}
+
+// GetAll mengambil daftar shift, opsional difilter status (OPEN/CLOSED) dan kasir.
+func (s *ShiftServiceImpl) GetAll(status, cashier string) ([]models.Shift, error) {
+	status = strings.ToUpper(strings.TrimSpace(status))
+	if status != "" && status != models.ShiftStatusOpen && status != models.ShiftStatusClosed {
+		return nil, repositories.NewValidationError("status harus %s atau %s", models.ShiftStatusOpen, models.ShiftStatusClosed)
+	}
+	return s.repo.GetAll(status, strings.TrimSpace(cashier))
+}
+
+func (s *ShiftServiceImpl) GetByID(id int) (*models.Shift, error) {
+	return s.repo.GetByID(id)
+}
+
+// Close menutup shift dan membekukan Z-report-nya.
+func (s *ShiftServiceImpl) Close(id int, req models.CloseShiftRequest) (*models.Shift, error) {
+	if req.CountedCash < 0 {
+		return nil, repositories.NewValidationError("counted_cash tidak boleh minus")
+	}
+	for i, c := range req.Counted {
+		req.Counted[i].Method = strings.ToUpper(strings.TrimSpace(c.Method))
+		if req.Counted[i].Method == "" {
+			return nil, repositories.NewValidationError("method hasil hitung wajib diisi")
+		}
+		if c.Amount < 0 {
+			return nil, repositories.NewValidationError("hasil hitung %s tidak boleh minus", req.Counted[i].Method)
+		}
+	}
+	return s.repo.Close(id, req)
+}
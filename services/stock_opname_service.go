@@ -0,0 +1,61 @@
+package services
+
+import (
+	"codeWithUmam/models"
+	"codeWithUmam/repositories"
+	"strings"
+)
+
+// StockOpnameServiceImpl berisi Bisnis Logic stok opname (hitung fisik).
+type StockOpnameServiceImpl struct {
+	repo repositories.StockOpnameRepository
+}
+
+func NewStockOpnameService(repo repositories.StockOpnameRepository) *StockOpnameServiceImpl {
+	return &StockOpnameServiceImpl{repo: repo}
+}
+
+// Open membuka sesi stok opname baru.
+func (s *StockOpnameServiceImpl) Open(req models.OpenStockOpnameRequest) (*models.StockOpname, error) {
+	if req.CategoryID < 0 {
+		return nil, repositories.NewValidationError("category_id tidak valid")
+	}
+	return s.repo.Open(req)
+}
+
+func (s *StockOpnameServiceImpl) GetAll() ([]models.StockOpname, error) {
+	return s.repo.GetAll()
+}
+
+func (s *StockOpnameServiceImpl) GetByID(id int) (*models.StockOpname, error) {
+	return s.repo.GetByID(id)
+}
+
+// SubmitCounts menyimpan hasil hitungan satu kasir. Nama kasir wajib diisi karena hitungan dijumlahkan per kasir.
+func (s *StockOpnameServiceImpl) SubmitCounts(id int, req models.SubmitStockCountRequest) (*models.StockOpname, error) {
+	req.CountedBy = strings.TrimSpace(req.CountedBy)
+	if req.CountedBy == "" {
+		return nil, repositories.NewValidationError("counted_by wajib diisi")
+	}
+	if len(req.Items) == 0 {
+		return nil, repositories.NewValidationError("hasil hitungan tidak boleh kosong")
+	}
+	for _, item := range req.Items {
+		if item.Quantity < 0 {
+			return nil, repositories.NewValidationError("hitungan product id %d tidak boleh minus", item.ProductID)
+		}
+	}
+	return s.repo.SubmitCounts(id, req)
+}
+
+// Approve memposting selisih hasil hitung ke stok.
+func (s *StockOpnameServiceImpl) Approve(id int, req models.ApproveStockOpnameRequest) (*models.StockOpname, error) {
+	if strings.TrimSpace(req.ApprovedBy) == "" {
+		return nil, repositories.NewValidationError("approved_by wajib diisi")
+	}
+	return s.repo.Approve(id, req.ApprovedBy)
+}
+
+func (s *StockOpnameServiceImpl) Cancel(id int) (*models.StockOpname, error) {
+	return s.repo.Cancel(id)
+}
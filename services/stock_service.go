@@ -0,0 +1,72 @@
+package services
+
+import (
+	"codeWithUmam/models"
+	"codeWithUmam/repositories"
+	"time"
+)
+
+// StockServiceImpl menyediakan akses baca ke ledger stok dan batch kedaluwarsa.
+// businessDay dipakai untuk menentukan "hari ini" saat menilai batch yang sudah kedaluwarsa.
+type StockServiceImpl struct {
+	repo        repositories.StockMovementRepository
+	businessDay models.BusinessDay
+}
+
+func NewStockService(repo repositories.StockMovementRepository, businessDay models.BusinessDay) *StockServiceImpl {
+	return &StockServiceImpl{repo: repo, businessDay: businessDay}
+}
+
+// Batas rentang laporan stok yang akan kedaluwarsa (hari ke depan).
+const (
+	defaultExpiringDays = 30
+	maxExpiringDays     = 365
+)
+
+// GetMovements mengambil riwayat pergerakan stok satu produk beserta saldo berjalannya.
+func (s *StockServiceImpl) GetMovements(productID int) ([]models.StockMovement, error) {
+	if productID <= 0 {
+		return nil, repositories.NewValidationError("product_id wajib diisi")
+	}
+	return s.repo.GetByProduct(productID)
+}
+
+// CheckConsistency membandingkan products.stock dengan hasil hitung ulang dari ledger.
+func (s *StockServiceImpl) CheckConsistency() (*models.StockConsistencyReport, error) {
+	return s.repo.CheckConsistency()
+}
+
+// GetBatches mengambil batch produk yang masih bersisa (urut FEFO), dengan tanda batch yang sudah kedaluwarsa.
+func (s *StockServiceImpl) GetBatches(productID int) ([]models.StockBatch, error) {
+	if productID <= 0 {
+		return nil, repositories.NewValidationError("product_id wajib diisi")
+	}
+	return s.repo.GetBatches(productID, s.businessDay.Date(time.Now()))
+}
+
+// GetExpiringReport mengambil stok yang kedaluwarsa dalam days hari ke depan (0 = default 30), termasuk yang sudah lewat,
+// dikelompokkan per kategori.
+func (s *StockServiceImpl) GetExpiringReport(days int) (*models.ExpiringStockReport, error) {
+	if days == 0 {
+		days = defaultExpiringDays
+	}
+	if days < 0 || days > maxExpiringDays {
+		return nil, repositories.NewValidationError("days harus antara 1 dan %d", maxExpiringDays)
+	}
+
+	today := s.businessDay.Date(time.Now())
+	start, err := time.Parse("2006-01-02", today)
+	if err != nil {
+		return nil, err
+	}
+	report := &models.ExpiringStockReport{Today: today, Until: start.AddDate(0, 0, days).Format("2006-01-02"), Days: days}
+	report.Categories, err = s.repo.GetExpiringBatches(report.Today, report.Until)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range report.Categories {
+		report.Quantity += c.Quantity
+		report.Value += c.Value
+	}
+	return report, nil
+}
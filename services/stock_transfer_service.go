@@ -0,0 +1,66 @@
+package services
+
+import (
+	"codeWithUmam/models"
+	"codeWithUmam/repositories"
+)
+
+// StockTransferServiceImpl berisi Bisnis Logic transfer stok antar outlet.
+// Validasi bentuk request dilakukan di sini; validasi yang butuh data (stok outlet asal, status transfer) ada di Repository
+// karena harus dicek di dalam Database Transaction yang sama dengan perubahan stok.
+type StockTransferServiceImpl struct {
+	repo repositories.StockTransferRepository
+}
+
+func NewStockTransferService(repo repositories.StockTransferRepository) *StockTransferServiceImpl {
+	return &StockTransferServiceImpl{repo: repo}
+}
+
+// GetAll mengambil daftar transfer, opsional difilter outlet (asal atau tujuan) dan status.
+func (s *StockTransferServiceImpl) GetAll(outletID int, status string) ([]models.StockTransfer, error) {
+	switch status {
+	case "", models.StockTransferStatusInTransit, models.StockTransferStatusReceived, models.StockTransferStatusCancelled:
+	default:
+		return nil, repositories.NewValidationError("status harus salah satu dari %s, %s, %s",
+			models.StockTransferStatusInTransit, models.StockTransferStatusReceived, models.StockTransferStatusCancelled)
+	}
+	return s.repo.GetAll(outletID, status)
+}
+
+func (s *StockTransferServiceImpl) GetByID(id int) (*models.StockTransfer, error) {
+	return s.repo.GetByID(id)
+}
+
+// Create mengirim stok dari outlet asal; barangnya berstatus di perjalanan sampai diterima outlet tujuan.
+func (s *StockTransferServiceImpl) Create(req models.CreateStockTransferRequest) (*models.StockTransfer, error) {
+	if req.FromOutletID <= 0 || req.ToOutletID <= 0 {
+		return nil, repositories.NewValidationError("from_outlet_id dan to_outlet_id wajib diisi")
+	}
+	if req.FromOutletID == req.ToOutletID {
+		return nil, repositories.NewValidationError("outlet asal dan tujuan tidak boleh sama")
+	}
+	if len(req.Items) == 0 {
+		return nil, repositories.NewValidationError("item transfer tidak boleh kosong")
+	}
+	seen := make(map[int]bool)
+	for _, item := range req.Items {
+		if item.Quantity <= 0 {
+			return nil, repositories.NewValidationError("quantity untuk product id %d harus lebih dari 0", item.ProductID)
+		}
+		if seen[item.ProductID] {
+			return nil, repositories.NewValidationError("product id %d muncul lebih dari sekali", item.ProductID)
+		}
+		seen[item.ProductID] = true
+	}
+	return s.repo.Create(req)
+}
+
+// Receive mencatat transfer sudah sampai dan menambah stok outlet tujuan.
+func (s *StockTransferServiceImpl) Receive(id int, req models.ReceiveStockTransferRequest) (*models.StockTransfer, error) {
+	return s.repo.Receive(id, req.ReceivedBy)
+}
+
+// Cancel membatalkan transfer yang masih di perjalanan dan mengembalikan stoknya ke outlet asal.
+func (s *StockTransferServiceImpl) Cancel(id int) (*models.StockTransfer, error) {
+	return s.repo.Cancel(id)
+}
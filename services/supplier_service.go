@@ -0,0 +1,42 @@
+package services
+
+import (
+	"codeWithUmam/models"
+	"codeWithUmam/repositories"
+	"strings"
+)
+
+// SupplierServiceImpl berisi Bisnis Logic untuk data supplier.
+type SupplierServiceImpl struct {
+	repo repositories.SupplierRepository
+}
+
+func NewSupplierService(repo repositories.SupplierRepository) *SupplierServiceImpl {
+	return &SupplierServiceImpl{repo: repo}
+}
+
+func (s *SupplierServiceImpl) GetAll() ([]models.Supplier, error) {
+	return s.repo.GetAll()
+}
+
+func (s *SupplierServiceImpl) Create(supplier *models.Supplier) error {
+	if strings.TrimSpace(supplier.Name) == "" {
+		return repositories.NewValidationError("nama supplier wajib diisi")
+	}
+	return s.repo.Create(supplier)
+}
+
+func (s *SupplierServiceImpl) GetByID(id int) (*models.Supplier, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *SupplierServiceImpl) Update(supplier *models.Supplier) error {
+	if strings.TrimSpace(supplier.Name) == "" {
+		return repositories.NewValidationError("nama supplier wajib diisi")
+	}
+	return s.repo.Update(supplier)
+}
+
+func (s *SupplierServiceImpl) Delete(id int) error {
+	return s.repo.Delete(id)
+}
@@ -3,63 +3,305 @@ package services
 import (
 	"codeWithUmam/models"
 	"codeWithUmam/repositories"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 )
 
+// ErrIdempotencyKeyReused dikembalikan ketika Idempotency-Key yang sama dipakai untuk isi checkout yang berbeda.
+// Handler menerjemahkannya menjadi HTTP 409 Conflict.
+var ErrIdempotencyKeyReused = errors.New("Idempotency-Key sudah dipakai untuk request checkout yang berbeda")
+
 // TransactionServiceImpl adalah implementasi dari interface TransactionService.
 // Struct ini menjembatani antara Handler (HTTP) dan Repository (Database).
 type TransactionServiceImpl struct {
-	repo *repositories.TransactionRepository
+	repo      *repositories.TransactionRepository
+	providers map[string]PaymentProvider // key: metode pembayaran, misal "QRIS"
 }
 
 // NewTransactionService adalah Constructor.
-// Menerima dependency Repository (Dependency Injection).
-func NewTransactionService(repo *repositories.TransactionRepository) *TransactionServiceImpl {
-	return &TransactionServiceImpl{repo: repo}
+// Menerima dependency Repository (Dependency Injection) dan payment gateway (opsional).
+// Metode pembayaran tanpa provider dianggap langsung lunas saat checkout (misal EDC yang dikonfirmasi kasir).
+func NewTransactionService(repo *repositories.TransactionRepository, providers ...PaymentProvider) *TransactionServiceImpl {
+	byMethod := make(map[string]PaymentProvider, len(providers))
+	for _, p := range providers {
+		byMethod[p.Method()] = p
+	}
+	return &TransactionServiceImpl{repo: repo, providers: byMethod}
 }
 
 // Checkout menangani logika pembelian.
+// Perhitungan total & kembalian diserahkan ke Repository, karena Repository yang pegang data harga (Single Source of Truth).
+//
+// Jika req.IdempotencyKey diisi (tablet kasir me-retry request yang sama karena Wi-Fi putus),
+// checkout kedua tidak membuat transaksi baru: transaksi pertama dikembalikan apa adanya.
+// Key yang sama tapi isi request berbeda ditolak dengan ErrIdempotencyKeyReused.
 func (s *TransactionServiceImpl) Checkout(req models.CheckoutRequest) (*models.Transaction, error) {
-	// Logic Hitung Total (Idealnya logic ini ada sebelum create transaction, tapi untuk simple approach kita hitung estimasi dulu atau biarkan repo yang handle total real)
-	// Masalah: Kita butuh Total Amount Real untuk hitung Change.
-	// Solusi: Kita minta Repo hitung dulu (dry run) atau kita lakukan kalkulasi di service (ini butuh akses harga produk).
-	// Alternatif Cepat: Kita percaya bahwa Repo akan melakukan validasi akhir. Tapi Service harus bisa hitung Change.
-	// AGAR AMAN & SESUAI PLAN:
-	// Repository CreateTransaction akan kita ubah sedikit flow-nya: Hitung total -> Validasi Bayar -> Baru Insert.
-	// Di sini Service pass control ke Repo.
+	req.Cashier = strings.TrimSpace(req.Cashier)
+	items, err := normalizeCheckoutItems(req.Items)
+	if err != nil {
+		return nil, err
+	}
+	req.Items = items
+	if req.IdempotencyKey == "" {
+		return s.createTransaction(req, "")
+	}
+	if len(req.IdempotencyKey) > maxIdempotencyKeyLength {
+		return nil, repositories.NewValidationError("Idempotency-Key maksimal %d karakter", maxIdempotencyKeyLength)
+	}
+
+	requestHash, err := checkoutFingerprint(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. Key sudah pernah dipakai? Kembalikan transaksi lama.
+	if existing, err := s.replayCheckout(req.IdempotencyKey, requestHash); existing != nil || err != nil {
+		return existing, err
+	}
+
+	// 2. Key baru: proses checkout seperti biasa.
+	transaction, err := s.createTransaction(req, requestHash)
+	if errors.Is(err, repositories.ErrDuplicateIdempotencyKey) {
+		// Kalah balapan dengan request retry lain yang masuk bersamaan dan sudah commit duluan.
+		return s.replayCheckout(req.IdempotencyKey, requestHash)
+	}
+	return transaction, err
+}
+
+// normalizeCheckoutItems memastikan setiap item menunjuk produk lewat product_id/variant_id ATAU barcode (hasil scan),
+// dan memvalidasi check digit barcode EAN/UPC sebelum dicari di database.
+func normalizeCheckoutItems(items []models.CheckoutItem) ([]models.CheckoutItem, error) {
+	normalized := make([]models.CheckoutItem, len(items))
+	for i, item := range items {
+		if item.Barcode != "" {
+			if item.ProductID != 0 || item.VariantID != 0 {
+				return nil, repositories.NewValidationError("item ke-%d: isi product_id/variant_id atau barcode, bukan keduanya", i+1)
+			}
+			code, err := lookupCode(item.Barcode)
+			if err != nil {
+				return nil, err
+			}
+			item.Barcode = code
+		}
+		normalized[i] = item
+	}
+	return normalized, nil
+}
+
+// createTransaction menyimpan transaksi, lalu membuat tagihan di payment gateway untuk setiap tender PENDING.
+// Tagihan dibuat SETELAH Database Transaction selesai agar panggilan jaringan ke gateway tidak menahan lock database.
+// Jika gateway gagal, transaksi langsung dibatalkan (EXPIRED) supaya stoknya kembali.
+func (s *TransactionServiceImpl) createTransaction(req models.CheckoutRequest, requestHash string) (*models.Transaction, error) {
+	opts := repositories.CheckoutOptions{RequestHash: requestHash, AsyncPaymentMethods: map[string]bool{}}
+	for method := range s.providers {
+		opts.AsyncPaymentMethods[method] = true
+	}
+
+	transaction, err := s.repo.CreateTransaction(req, opts)
+	if err != nil || transaction.Status != models.TransactionStatusPendingPayment {
+		return transaction, err
+	}
+
+	for _, p := range transaction.Payments {
+		if p.Status != models.PaymentStatusPending {
+			continue
+		}
+		charge, err := s.providers[p.Method].CreateCharge(p)
+		if err == nil {
+			err = s.repo.AttachPaymentCharge(p.ID, *charge)
+		}
+		if err != nil {
+			if _, expireErr := s.repo.ExpireTransaction(transaction.ID); expireErr != nil {
+				return nil, fmt.Errorf("gagal membuat tagihan %s (%v) dan gagal membatalkan transaksi: %v", p.Method, err, expireErr)
+			}
+			return nil, fmt.Errorf("gagal membuat tagihan %s: %v", p.Method, err)
+		}
+	}
+
+	return s.repo.FindByID(transaction.ID)
+}
+
+// maxIdempotencyKeyLength membatasi panjang header Idempotency-Key (UUID cukup 36 karakter).
+const maxIdempotencyKeyLength = 255
+
+// replayCheckout mengembalikan transaksi yang dibuat dengan key ini, atau (nil, nil) jika key belum pernah dipakai.
+func (s *TransactionServiceImpl) replayCheckout(key, requestHash string) (*models.Transaction, error) {
+	existing, storedHash, err := s.repo.FindByIdempotencyKey(key)
+	if err != nil || existing == nil {
+		return nil, err
+	}
+	if storedHash != requestHash {
+		return nil, ErrIdempotencyKeyReused
+	}
+	return existing, nil
+}
+
+// checkoutFingerprint menghasilkan sidik jari (SHA-256) dari isi CheckoutRequest.
+// IdempotencyKey tidak ikut di-hash karena bertanda `json:"-"`.
+func checkoutFingerprint(req models.CheckoutRequest) (string, error) {
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// GetDailyReport mengambil rekap laporan harian (outletID 0 = semua outlet).
+func (s *TransactionServiceImpl) GetDailyReport(outletID int) (*models.SalesSummary, error) {
+	return s.repo.GetDailySalesSummary(outletID)
+}
+
+// GetProfitReport mengambil laporan laba kotor untuk rentang tanggal from..to (YYYY-MM-DD).
+func (s *TransactionServiceImpl) GetProfitReport(from, to string, outletID int) (*models.ProfitReport, error) {
+	from, to, err := reportRange(from, to, s.repo.BusinessDay().Date(time.Now()))
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetProfitReport(from, to, outletID)
+}
+
+// GetBundleReport mengambil laporan penjualan paket/resep & pemakaian komponennya untuk rentang tanggal from..to (YYYY-MM-DD).
+func (s *TransactionServiceImpl) GetBundleReport(from, to string, outletID int) (*models.BundleReport, error) {
+	from, to, err := reportRange(from, to, s.repo.BusinessDay().Date(time.Now()))
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetBundleReport(from, to, outletID)
+}
+
+// Batas periode penjualan & lama stok yang direncanakan di saran pesan ulang (dalam hari).
+const (
+	defaultReorderDays      = 30
+	defaultReorderCoverDays = 14
+	maxReorderDays          = 365
+)
+
+// GetReorderReport mengambil saran pesan ulang dari rata-rata penjualan harian selama days hari bisnis terakhir
+// (termasuk hari ini), untuk stok coverDays hari ke depan. Nilai <= 0 berarti default 30 dan 14 hari.
+func (s *TransactionServiceImpl) GetReorderReport(days, coverDays, outletID int) (*models.ReorderReport, error) {
+	if days <= 0 {
+		days = defaultReorderDays
+	}
+	if coverDays <= 0 {
+		coverDays = defaultReorderCoverDays
+	}
+	if days > maxReorderDays || coverDays > maxReorderDays {
+		return nil, repositories.NewValidationError("days dan cover_days maksimal %d", maxReorderDays)
+	}
+
+	to := s.repo.BusinessDay().Date(time.Now())
+	toDate, err := time.Parse("2006-01-02", to)
+	if err != nil {
+		return nil, err
+	}
+	from := toDate.AddDate(0, 0, -(days - 1)).Format("2006-01-02")
+	return s.repo.GetReorderReport(from, to, coverDays, outletID)
+}
 
-	// Tapi tunggu, repo CreateTransaction sekarang terima (paidAmount, change). Repo tidak tahu totalnya berapa SEBELUM query harga produk.
-	// Jadi Service Idealnya:
-	// 1. Get Product Prices (Repo.GetPrices?) --> Ribet
-	// 2. Repo.CreateTransaction yang menghitung kembalian.
+// Batas jumlah produk terlaris di laporan penjualan.
+const (
+	defaultTopProducts = 10
+	maxTopProducts     = 100
+)
 
-	// Mari kita serahkan logika "Hitung Kembalian" ke Repository saja, karena Repository yang pegang Data Harga (Single Source of Truth).
-	// Kita kirim paidAmount ke Repo. Repo yang akan return error jika kurang, dan repo yang akan hitung change.
+// GetSalesReport mengambil laporan penjualan untuk rentang tanggal from..to (YYYY-MM-DD).
+// groupBy kosong berarti per hari; top <= 0 berarti 10 produk terlaris.
+func (s *TransactionServiceImpl) GetSalesReport(from, to, groupBy string, top, outletID int) (*models.SalesReport, error) {
+	from, to, err := reportRange(from, to, s.repo.BusinessDay().Date(time.Now()))
+	if err != nil {
+		return nil, err
+	}
 
-	// Namuun, signature Repo tadi: CreateTransaction(items, paidAmount, change, method)
-	// Berarti Repo dikasih tau "Change-nya sekian". Ini aneh kalau Repo belum tau Totalnya.
+	switch groupBy {
+	case "":
+		groupBy = models.SalesGroupByDay
+	case models.SalesGroupByHour, models.SalesGroupByDay, models.SalesGroupByWeek, models.SalesGroupByMonth,
+		models.SalesGroupByCategory, models.SalesGroupByPaymentMethod, models.SalesGroupByProduct:
+	default:
+		return nil, repositories.NewValidationError("group_by harus salah satu dari hour, day, week, month, category, payment_method, product")
+	}
 
-	// KOREKSI APPROACH:
-	// Biarkan Repository yang menghitung Change. Kita pass 0 sebagai dummy change, nanti repo update.
-	// ATAU Service hitung Change? Service gak tau harga.
-	// OK, Repo yang hitung Change. Signature Repo tadi sudah terlanjur `change int`.
-	// Mari kita sesuaikan Repo implementation dikit nanti atau kita pass 0 di sini, dan Repo yang override.
+	if top <= 0 {
+		top = defaultTopProducts
+	}
+	if top > maxTopProducts {
+		return nil, repositories.NewValidationError("top maksimal %d", maxTopProducts)
+	}
 
-	// Mari kita update Repository Implementation LAGI nanti biar dia yang hitung Change (Total - Paid).
-	// Untuk sekarang, kita panggil Repo.
+	return s.repo.GetSalesReport(from, to, groupBy, top, outletID)
+}
 
-	return s.repo.CreateTransaction(req.Items, req.PaidAmount, 0, req.PaymentMethod)
-	// Note: Repo akan kita update lagi untuk menghitung Change otomatis.
+// reportRange memvalidasi rentang tanggal laporan (format YYYY-MM-DD, inklusif).
+// Tanggal yang kosong diisi today (hari bisnis saat ini), sama seperti /api/report/hari-ini.
+func reportRange(from, to, today string) (string, string, error) {
+	if from == "" {
+		from = today
+	}
+	if to == "" {
+		to = today
+	}
+	fromDate, err := time.Parse("2006-01-02", from)
+	if err != nil {
+		return "", "", repositories.NewValidationError("from harus berformat YYYY-MM-DD")
+	}
+	toDate, err := time.Parse("2006-01-02", to)
+	if err != nil {
+		return "", "", repositories.NewValidationError("to harus berformat YYYY-MM-DD")
+	}
+	if toDate.Before(fromDate) {
+		return "", "", repositories.NewValidationError("to tidak boleh sebelum from")
+	}
+	return from, to, nil
 }
 
-// GetDailyReport mengambil rekap laporan harian.
-func (s *TransactionServiceImpl) GetDailyReport() (*models.SalesSummary, error) {
-	return s.repo.GetDailySalesSummary()
+func (s *TransactionServiceImpl) GetHistory(start, end string, outletID int) ([]models.Transaction, error) {
+	return s.repo.FindAll(start, end, outletID)
 }
 
-func (s *TransactionServiceImpl) GetHistory(start, end string) ([]models.Transaction, error) {
-	return s.repo.FindAll(start, end)
+// ExportHistory mengirim header transaksi satu per satu ke fn (streaming dari database, untuk CSV/XLSX).
+func (s *TransactionServiceImpl) ExportHistory(start, end string, outletID int, fn func(models.Transaction) error) error {
+	return s.repo.ForEachTransaction(start, end, outletID, fn)
 }
 
 func (s *TransactionServiceImpl) GetDetail(id int) (*models.Transaction, error) {
 	return s.repo.FindByID(id)
 }
+
+// GetReceipt mengambil transaksi untuk dicetak sebagai struk, dengan jam transaksi dalam zona waktu toko.
+func (s *TransactionServiceImpl) GetReceipt(id int) (*models.Transaction, error) {
+	transaction, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if transaction == nil {
+		return nil, repositories.ErrNotFound
+	}
+	transaction.CreatedAt = s.repo.BusinessDay().Local(transaction.CreatedAt)
+	return transaction, nil
+}
+
+// Void membatalkan seluruh transaksi dan mengembalikan stok.
+// Alasan void wajib diisi agar bisa diaudit.
+func (s *TransactionServiceImpl) Void(id int, req models.VoidRequest) (*models.Transaction, error) {
+	if strings.TrimSpace(req.Reason) == "" {
+		return nil, repositories.NewValidationError("alasan void wajib diisi")
+	}
+	return s.repo.VoidTransaction(id, req.Reason, req.VoidedBy)
+}
+
+// Refund mengembalikan sebagian barang dari transaksi.
+func (s *TransactionServiceImpl) Refund(id int, req models.RefundRequest) (*models.Transaction, error) {
+	if len(req.Items) == 0 {
+		return nil, repositories.NewValidationError("item refund tidak boleh kosong")
+	}
+	if strings.TrimSpace(req.Reason) == "" {
+		return nil, repositories.NewValidationError("alasan refund wajib diisi")
+	}
+	return s.repo.RefundTransaction(id, req.Items, req.Reason, req.RefundedBy)
+}
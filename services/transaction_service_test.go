@@ -0,0 +1,65 @@
+package services
+
+import (
+	"codeWithUmam/database"
+	"codeWithUmam/models"
+	"codeWithUmam/repositories"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// TransactionServiceImpl bergantung langsung pada *repositories.TransactionRepository (bukan interface),
+// jadi test di sini memakai database SQLite sementara, bukan mock.
+func setupTransactionService(t *testing.T) (*TransactionServiceImpl, *sql.DB) {
+	db, err := database.InitDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to init db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return NewTransactionService(repositories.NewTransactionRepository(db, models.TaxConfig{}, models.CostingMethodAverage, models.BusinessDay{})), db
+}
+
+func TestTransactionService_Checkout_IdempotencyKey(t *testing.T) {
+	service, db := setupTransactionService(t)
+
+	product := &models.Product{Name: "Kopi", Price: 5000, Stock: 10}
+	if err := repositories.NewProductRepository(db).Create(product); err != nil {
+		t.Fatalf("failed to seed product: %v", err)
+	}
+
+	req := models.CheckoutRequest{
+		Items:          []models.CheckoutItem{{ProductID: product.ID, Quantity: 2}},
+		PaidAmount:     10000,
+		PaymentMethod:  "CASH",
+		IdempotencyKey: "tablet-1-0001",
+	}
+
+	first, err := service.Checkout(req)
+	if err != nil {
+		t.Fatalf("first checkout failed: %v", err)
+	}
+
+	// Retry dengan key & isi yang sama -> transaksi yang sama, stok tidak berkurang lagi
+	replay, err := service.Checkout(req)
+	if err != nil {
+		t.Fatalf("replay checkout failed: %v", err)
+	}
+	if replay.ID != first.ID || replay.TotalAmount != first.TotalAmount {
+		t.Errorf("expected replay to return transaction %d, got %d", first.ID, replay.ID)
+	}
+
+	var stock, count int
+	db.QueryRow("SELECT stock FROM products WHERE id = ?", product.ID).Scan(&stock)
+	db.QueryRow("SELECT COUNT(*) FROM transactions").Scan(&count)
+	if stock != 8 || count != 1 {
+		t.Errorf("expected stock 8 and 1 transaction, got stock %d and %d transactions", stock, count)
+	}
+
+	// Key sama, isi berbeda -> ditolak
+	req.Items[0].Quantity = 1
+	if _, err := service.Checkout(req); !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Errorf("expected ErrIdempotencyKeyReused, got %v", err)
+	}
+}